@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/chadit/CloudMCP/internal/config"
+	"github.com/chadit/CloudMCP/internal/credentials"
+)
+
+// Static errors for err113 compliance.
+var errUsage = errors.New("usage")
+
+const credsUsage = `Usage: cloud-mcp creds <command> [arguments]
+
+Manage the encrypted credential store. Unlock it with
+CLOUD_MCP_CREDENTIALS_PASSPHRASE or CLOUD_MCP_CREDENTIALS_KEY_FILE.
+
+Commands:
+  add <name>       Store a secret read from standard input
+  list             List stored secret names
+  remove <name>    Delete a secret
+  keygen <path>    Write a new random key file
+`
+
+// runCreds runs the creds subcommand and returns the process exit code.
+func runCreds(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if err := creds(args, stdin, stdout); err != nil {
+		if errors.Is(err, errUsage) {
+			fmt.Fprint(stderr, credsUsage)
+			return 2
+		}
+		fmt.Fprintf(stderr, "cloud-mcp creds: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+// creds dispatches a creds command.
+func creds(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	command, args := args[0], args[1:]
+	if command == "keygen" {
+		if len(args) != 1 {
+			return errUsage
+		}
+		if err := credentials.GenerateKeyFile(args[0]); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "Wrote key file %s\n", args[0])
+		return nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	store, err := credentials.Open(credentials.Options{
+		Path:       cfg.CredentialsFile,
+		Passphrase: cfg.CredentialsPassphrase,
+		KeyFile:    cfg.CredentialsKeyFile,
+	})
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "add":
+		if len(args) != 1 {
+			return errUsage
+		}
+		// Read the secret from standard input, so it never appears in shell
+		// history or the process list.
+		secret, err := credentials.ReadSecret(stdin)
+		if err != nil {
+			return err
+		}
+		if err := store.Put(args[0], secret); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "Stored %s in %s\n", args[0], cfg.CredentialsFile)
+	case "list":
+		if len(args) != 0 {
+			return errUsage
+		}
+		w := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tUPDATED")
+		for _, info := range store.List() {
+			fmt.Fprintf(w, "%s\t%s\n", info.Name, info.UpdatedAt.Format(time.RFC3339))
+		}
+		return w.Flush()
+	case "remove":
+		if len(args) != 1 {
+			return errUsage
+		}
+		if err := store.Remove(args[0]); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "Removed %s\n", args[0])
+	default:
+		return errUsage
+	}
+
+	return nil
+}
@@ -10,11 +10,16 @@ import (
 	"syscall"
 
 	"github.com/chadit/CloudMCP/internal/config"
+	"github.com/chadit/CloudMCP/internal/providers"
 	"github.com/chadit/CloudMCP/internal/server"
 	"github.com/chadit/CloudMCP/internal/version"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "creds" {
+		os.Exit(runCreds(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+	}
+
 	exitCode := run()
 	os.Exit(exitCode)
 }
@@ -55,6 +60,22 @@ func run() int {
 		return 1
 	}
 
+	// Keep resolved secrets out of the log
+	log.SetOutput(srv.LogWriter(os.Stderr))
+
+	// Add enabled cloud providers; they are initialized when serving starts
+	enabled, err := providers.New(cfg.Providers)
+	if err != nil {
+		log.Printf("Failed to create providers: %v", err)
+		return 1
+	}
+	for _, provider := range enabled {
+		if err := srv.AddProvider(provider); err != nil {
+			log.Printf("Failed to add provider %s: %v", provider.Name(), err)
+			return 1
+		}
+	}
+
 	if err := srv.Start(ctx); err != nil {
 		log.Printf("Server error: %v", err)
 		return 1
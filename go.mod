@@ -5,6 +5,7 @@ go 1.24
 require (
 	github.com/mark3labs/mcp-go v0.32.0
 	github.com/stretchr/testify v1.10.0 // for testing
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -14,5 +15,4 @@ require (
 	github.com/rogpeppe/go-internal v1.13.1 // indirect
 	github.com/spf13/cast v1.9.2 // indirect
 	github.com/yosida95/uritemplate/v3 v3.0.2 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
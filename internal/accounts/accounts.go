@@ -0,0 +1,246 @@
+// Package accounts keeps the registry of configured cloud accounts and each
+// client session's active account per provider.
+package accounts
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+// Static errors for err113 compliance.
+var (
+	ErrAccountNotFound = errors.New("account not found")
+	ErrInvalidAccounts = errors.New("invalid accounts file")
+)
+
+// file is the layout of the accounts file.
+type file struct {
+	Accounts []contracts.Account `yaml:"accounts"`
+}
+
+// Registry holds the configured accounts and the accounts each session has
+// switched to. It is safe for concurrent use.
+type Registry struct {
+	mu       sync.Mutex
+	accounts []contracts.Account
+	active   map[string]map[string]string // session -> provider -> alias
+}
+
+// NewRegistry creates a registry of accounts. Each provider may have at most
+// one default account; when none is marked, its first account is the default.
+func NewRegistry(accounts []contracts.Account) (*Registry, error) {
+	seen := make(map[string]bool)
+	defaults := make(map[string]bool)
+	normalized := make([]contracts.Account, 0, len(accounts))
+	for i, account := range accounts {
+		if account.Provider == "" || account.Alias == "" {
+			return nil, fmt.Errorf("%w: account %d needs a provider and an alias", ErrInvalidAccounts, i+1)
+		}
+
+		id := account.Provider + "/" + account.Alias
+		if seen[id] {
+			return nil, fmt.Errorf("%w: duplicate account %s", ErrInvalidAccounts, id)
+		}
+		seen[id] = true
+
+		if account.Default {
+			if defaults[account.Provider] {
+				return nil, fmt.Errorf("%w: more than one default account for %s", ErrInvalidAccounts, account.Provider)
+			}
+			defaults[account.Provider] = true
+		}
+
+		normalized = append(normalized, account)
+	}
+
+	for i, account := range normalized {
+		if !defaults[account.Provider] {
+			normalized[i].Default = true
+			defaults[account.Provider] = true
+		}
+	}
+
+	return &Registry{
+		accounts: normalized,
+		active:   make(map[string]map[string]string),
+	}, nil
+}
+
+// Load reads the accounts file at path. A missing file or empty path gives an
+// empty registry.
+//
+//	accounts:
+//	  - provider: linode
+//	    alias: prod
+//	    credential: linode-prod
+//	    region: us-east
+//	    default: true
+func Load(path string) (*Registry, error) {
+	if path == "" {
+		return NewRegistry(nil)
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return NewRegistry(nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read accounts file: %w", err)
+	}
+
+	var parsed file
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidAccounts, path, err)
+	}
+
+	return NewRegistry(parsed.Accounts)
+}
+
+// Add adds accounts a provider discovered in its own configuration, such as
+// the contexts of a kubeconfig, and returns those added. Accounts with the
+// alias of a configured account are skipped, so the accounts file wins. The
+// first account marked default, or else the first added, becomes the
+// provider's default when it has none.
+func (r *Registry) Add(provider string, discovered []contracts.Account) []contracts.Account {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hasDefault := false
+	taken := make(map[string]bool)
+	for _, account := range r.accounts {
+		if account.Provider == provider {
+			taken[account.Alias] = true
+			hasDefault = hasDefault || account.Default
+		}
+	}
+
+	var added []contracts.Account
+	for _, account := range discovered {
+		if account.Alias == "" || taken[account.Alias] {
+			continue
+		}
+		taken[account.Alias] = true
+
+		account.Provider = provider
+		account.Default = account.Default && !hasDefault
+		hasDefault = hasDefault || account.Default
+		added = append(added, account)
+	}
+	if len(added) > 0 && !hasDefault {
+		added[0].Default = true
+	}
+	r.accounts = append(r.accounts, added...)
+
+	return added
+}
+
+// List returns the accounts of a provider, or all accounts when provider is
+// empty, sorted by provider and alias.
+func (r *Registry) List(provider string) []contracts.Account {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var list []contracts.Account
+	for _, account := range r.accounts {
+		if provider == "" || account.Provider == provider {
+			list = append(list, account)
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Provider != list[j].Provider {
+			return list[i].Provider < list[j].Provider
+		}
+		return list[i].Alias < list[j].Alias
+	})
+
+	return list
+}
+
+// Get returns the account of a provider with the given alias.
+func (r *Registry) Get(provider, alias string) (contracts.Account, error) {
+	r.mu.Lock()
+	for _, account := range r.accounts {
+		if account.Provider == provider && account.Alias == alias {
+			r.mu.Unlock()
+			return account, nil
+		}
+	}
+	r.mu.Unlock()
+
+	return contracts.Account{}, fmt.Errorf("%w: %s has no account %q (available: %s)",
+		ErrAccountNotFound, provider, alias, r.aliases(provider))
+}
+
+// Active returns the account a session uses for a provider: the one it
+// switched to, otherwise the provider's default. It reports false when the
+// provider has no accounts.
+func (r *Registry) Active(session, provider string) (contracts.Account, bool) {
+	r.mu.Lock()
+	alias, switched := r.active[session][provider]
+	r.mu.Unlock()
+
+	if switched {
+		if account, err := r.Get(provider, alias); err == nil {
+			return account, true
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, account := range r.accounts {
+		if account.Provider == provider && account.Default {
+			return account, true
+		}
+	}
+
+	return contracts.Account{}, false
+}
+
+// Switch sets the session's active account for a provider.
+func (r *Registry) Switch(session, provider, alias string) (contracts.Account, error) {
+	account, err := r.Get(provider, alias)
+	if err != nil {
+		return contracts.Account{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.active[session] == nil {
+		r.active[session] = make(map[string]string)
+	}
+	r.active[session][provider] = alias
+
+	return account, nil
+}
+
+// Forget drops a session's active accounts, for example when it disconnects.
+func (r *Registry) Forget(session string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.active, session)
+}
+
+// aliases formats a provider's account aliases for error messages.
+func (r *Registry) aliases(provider string) string {
+	var aliases []string
+	for _, account := range r.List(provider) {
+		aliases = append(aliases, account.Alias)
+	}
+	if len(aliases) == 0 {
+		return "none"
+	}
+
+	return strings.Join(aliases, ", ")
+}
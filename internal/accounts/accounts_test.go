@@ -0,0 +1,129 @@
+package accounts_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chadit/CloudMCP/internal/accounts"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const accountsYAML = `accounts:
+  - provider: linode
+    alias: staging
+    credential: linode-staging
+  - provider: linode
+    alias: prod
+    credential: linode-prod
+    region: us-east
+    default: true
+  - provider: aws
+    alias: personal
+    region: eu-west-1
+`
+
+func loadRegistry(t *testing.T, content string) (*accounts.Registry, error) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "accounts.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return accounts.Load(path)
+}
+
+func TestLoad_DefaultsAndListing(t *testing.T) {
+	t.Parallel()
+
+	registry, err := loadRegistry(t, accountsYAML)
+	require.NoError(t, err)
+
+	linode := registry.List("linode")
+	require.Len(t, linode, 2)
+	require.Equal(t, "prod", linode[0].Alias, "sorted by alias")
+	require.Len(t, registry.List(""), 3)
+
+	active, ok := registry.Active("s1", "linode")
+	require.True(t, ok)
+	require.Equal(t, "prod", active.Alias, "marked default")
+
+	active, ok = registry.Active("s1", "aws")
+	require.True(t, ok)
+	require.Equal(t, "personal", active.Alias, "the only account is the default")
+
+	_, ok = registry.Active("s1", "gcp")
+	require.False(t, ok)
+
+	missing, err := accounts.Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.NoError(t, err)
+	require.Empty(t, missing.List(""))
+}
+
+func TestLoad_RejectsInvalidRegistries(t *testing.T) {
+	t.Parallel()
+
+	for _, content := range []string{
+		"accounts:\n  - provider: linode\n",
+		"accounts:\n  - {provider: linode, alias: a}\n  - {provider: linode, alias: a}\n",
+		"accounts:\n  - {provider: linode, alias: a, default: true}\n  - {provider: linode, alias: b, default: true}\n",
+		"accounts: [",
+	} {
+		_, err := loadRegistry(t, content)
+		require.ErrorIs(t, err, accounts.ErrInvalidAccounts, content)
+	}
+}
+
+func TestSwitch_IsPerSession(t *testing.T) {
+	t.Parallel()
+
+	registry, err := accounts.NewRegistry([]contracts.Account{
+		{Provider: "linode", Alias: "prod"},
+		{Provider: "linode", Alias: "staging"},
+	})
+	require.NoError(t, err)
+
+	account, err := registry.Switch("s1", "linode", "staging")
+	require.NoError(t, err)
+	require.Equal(t, "staging", account.Alias)
+
+	active, _ := registry.Active("s1", "linode")
+	require.Equal(t, "staging", active.Alias)
+	active, _ = registry.Active("s2", "linode")
+	require.Equal(t, "prod", active.Alias, "other sessions keep the default")
+
+	_, err = registry.Switch("s1", "linode", "nope")
+	require.ErrorIs(t, err, accounts.ErrAccountNotFound)
+
+	registry.Forget("s1")
+	active, _ = registry.Active("s1", "linode")
+	require.Equal(t, "prod", active.Alias)
+}
+
+func TestAdd_KeepsConfiguredAccounts(t *testing.T) {
+	t.Parallel()
+
+	registry, err := loadRegistry(t, accountsYAML)
+	require.NoError(t, err)
+
+	added := registry.Add("linode", []contracts.Account{{Alias: "prod"}, {Alias: "dev", Default: true}})
+	require.Equal(t, []contracts.Account{{Provider: "linode", Alias: "dev"}}, added,
+		"configured aliases and defaults win")
+	active, ok := registry.Active("s1", "linode")
+	require.True(t, ok)
+	require.Equal(t, "prod", active.Alias)
+
+	added = registry.Add("kubernetes", []contracts.Account{{Alias: "kind"}, {Alias: "prod-eu", Default: true}})
+	require.Len(t, added, 2)
+	active, ok = registry.Active("s1", "kubernetes")
+	require.True(t, ok)
+	require.Equal(t, "prod-eu", active.Alias)
+
+	_, err = registry.Switch("s1", "kubernetes", "kind")
+	require.NoError(t, err)
+	require.Len(t, registry.List("kubernetes"), 2)
+
+	added = registry.Add("docker", []contracts.Account{{Alias: "local"}, {Alias: "remote"}})
+	require.True(t, added[0].Default, "the first account is the default")
+}
@@ -0,0 +1,255 @@
+package awsauth
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+// DefaultProfile is the profile used when none is selected.
+const DefaultProfile = "default"
+
+// Static errors for err113 compliance.
+var (
+	ErrNoCredentials      = errors.New("no AWS credentials found")
+	ErrProfileNotFound    = errors.New("AWS profile not found")
+	ErrInvalidCredentials = errors.New("invalid AWS credentials")
+)
+
+// Credentials are AWS access keys.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey contracts.Secret
+	SessionToken    contracts.Secret
+
+	// Source describes where the credentials were found, for diagnostics.
+	Source string
+}
+
+// valid reports whether both halves of the access key are set.
+func (c Credentials) valid() bool {
+	return c.AccessKeyID != "" && c.SecretAccessKey != ""
+}
+
+// ParseCredentials parses access keys kept as a single secret, either JSON
+// with access_key_id, secret_access_key and optional session_token fields, or
+// ACCESS_KEY_ID:SECRET_ACCESS_KEY[:SESSION_TOKEN].
+func ParseCredentials(secret contracts.Secret) (Credentials, error) {
+	value := strings.TrimSpace(secret.Reveal())
+
+	var creds Credentials
+	if strings.HasPrefix(value, "{") {
+		var decoded struct {
+			AccessKeyID     string `json:"access_key_id"`
+			SecretAccessKey string `json:"secret_access_key"`
+			SessionToken    string `json:"session_token"`
+		}
+		if err := json.Unmarshal([]byte(value), &decoded); err != nil {
+			return Credentials{}, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+		}
+		creds = Credentials{
+			AccessKeyID:     decoded.AccessKeyID,
+			SecretAccessKey: contracts.Secret(decoded.SecretAccessKey),
+			SessionToken:    contracts.Secret(decoded.SessionToken),
+		}
+	} else {
+		parts := strings.SplitN(value, ":", 3)
+		creds.AccessKeyID = parts[0]
+		if len(parts) > 1 {
+			creds.SecretAccessKey = contracts.Secret(parts[1])
+		}
+		if len(parts) > 2 {
+			creds.SessionToken = contracts.Secret(parts[2])
+		}
+	}
+
+	if !creds.valid() {
+		return Credentials{}, fmt.Errorf("%w: an access key ID and secret access key are required", ErrInvalidCredentials)
+	}
+	creds.Source = "credential store"
+
+	return creds, nil
+}
+
+// Environment resolves credentials and regions the way the AWS CLI does, from
+// environment variables and the shared credentials and config files.
+type Environment struct {
+	// Getenv reads environment variables. Nil uses os.Getenv.
+	Getenv func(key string) string
+}
+
+// Credentials returns the credentials for profile. With no profile, the
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN variables are
+// used if set, and otherwise the AWS_PROFILE or default profile. Profiles are
+// read from the shared credentials file, then the config file.
+func (e Environment) Credentials(profile string) (Credentials, error) {
+	if profile == "" {
+		creds := Credentials{
+			AccessKeyID:     e.getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: contracts.Secret(e.getenv("AWS_SECRET_ACCESS_KEY")),
+			SessionToken:    contracts.Secret(e.getenv("AWS_SESSION_TOKEN")),
+			Source:          "environment",
+		}
+		if creds.valid() {
+			return creds, nil
+		}
+		profile = e.profile()
+	}
+
+	found := false
+	for _, file := range []struct {
+		path    string
+		section string
+	}{
+		{e.credentialsFile(), profile},
+		{e.configFile(), configSection(profile)},
+	} {
+		values, ok, err := readSection(file.path, file.section)
+		if err != nil {
+			return Credentials{}, err
+		}
+		if !ok {
+			continue
+		}
+		found = true
+
+		creds := Credentials{
+			AccessKeyID:     values["aws_access_key_id"],
+			SecretAccessKey: contracts.Secret(values["aws_secret_access_key"]),
+			SessionToken:    contracts.Secret(values["aws_session_token"]),
+			Source:          fmt.Sprintf("profile %s in %s", profile, file.path),
+		}
+		if creds.valid() {
+			return creds, nil
+		}
+	}
+
+	if !found && profile != DefaultProfile {
+		return Credentials{}, fmt.Errorf("%w: %s", ErrProfileNotFound, profile)
+	}
+
+	return Credentials{}, fmt.Errorf("%w: set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY or configure profile %s", ErrNoCredentials, profile)
+}
+
+// Region returns the region from AWS_REGION, AWS_DEFAULT_REGION or the
+// profile's config, or empty if none is set.
+func (e Environment) Region(profile string) string {
+	for _, key := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if region := e.getenv(key); region != "" {
+			return region
+		}
+	}
+	if profile == "" {
+		profile = e.profile()
+	}
+
+	values, _, _ := readSection(e.configFile(), configSection(profile))
+
+	return values["region"]
+}
+
+// profile returns the selected profile.
+func (e Environment) profile() string {
+	if profile := e.getenv("AWS_PROFILE"); profile != "" {
+		return profile
+	}
+
+	return DefaultProfile
+}
+
+func (e Environment) getenv(key string) string {
+	if e.Getenv != nil {
+		return e.Getenv(key)
+	}
+
+	return os.Getenv(key)
+}
+
+// credentialsFile returns the shared credentials file path.
+func (e Environment) credentialsFile() string {
+	if path := e.getenv("AWS_SHARED_CREDENTIALS_FILE"); path != "" {
+		return path
+	}
+
+	return e.awsDir("credentials")
+}
+
+// configFile returns the shared config file path.
+func (e Environment) configFile() string {
+	if path := e.getenv("AWS_CONFIG_FILE"); path != "" {
+		return path
+	}
+
+	return e.awsDir("config")
+}
+
+// awsDir returns the path of a file in ~/.aws, or empty without a home directory.
+func (e Environment) awsDir(name string) string {
+	home := e.getenv("HOME")
+	if home == "" {
+		home = e.getenv("USERPROFILE")
+	}
+	if home == "" {
+		return ""
+	}
+
+	return filepath.Join(home, ".aws", name)
+}
+
+// configSection returns the config file section of a profile.
+func configSection(profile string) string {
+	if profile == DefaultProfile {
+		return DefaultProfile
+	}
+
+	return "profile " + profile
+}
+
+// readSection reads the keys of one section of an INI file. A missing file
+// has no sections.
+func readSection(path, section string) (map[string]string, bool, error) {
+	if path == "" {
+		return nil, false, nil
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var values map[string]string
+	current := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			current = strings.Join(strings.Fields(line[1:len(line)-1]), " ")
+			if current == section && values == nil {
+				values = make(map[string]string)
+			}
+		case current == section:
+			key, value, ok := strings.Cut(line, "=")
+			if ok {
+				values[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return values, values != nil, nil
+}
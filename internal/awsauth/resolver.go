@@ -0,0 +1,94 @@
+package awsauth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	// ProfilePrefix marks an account credential naming a shared config
+	// profile rather than a stored secret, as in "profile:prod".
+	ProfilePrefix = "profile:"
+
+	// DefaultRegion is used when neither the call, the account, the settings
+	// nor the AWS environment name a region.
+	DefaultRegion = "us-east-1"
+)
+
+// Resolver selects the credentials and region of a provider call from the
+// call's account, the provider settings and the AWS environment. It reads the
+// credential_ref, profile and region settings.
+type Resolver struct {
+	Config contracts.ProviderConfig
+	Env    Environment
+}
+
+// Profile returns the shared config profile selected for the call: the
+// account's profile: credential, otherwise the profile setting.
+func (r Resolver) Profile(ctx context.Context) string {
+	if account, ok := contracts.AccountFromContext(ctx); ok {
+		if profile, ok := strings.CutPrefix(account.Credential, ProfilePrefix); ok {
+			return profile
+		}
+	}
+
+	return r.Config.Setting("profile", "")
+}
+
+// Credentials resolves the keys for a call. An account credential is either a
+// profile: reference or a stored secret holding the keys. Without one, the
+// credential_ref setting is used if set, then the environment, shared
+// credentials and config files. The secret key and session token are
+// registered with the provider's secrets, so they are redacted wherever they
+// were read from.
+func (r Resolver) Credentials(ctx context.Context) (Credentials, error) {
+	credentials, err := r.credentials(ctx)
+	if err != nil {
+		return Credentials{}, err
+	}
+	contracts.RegisterSecret(r.Config.Secrets, credentials.SecretAccessKey)
+	contracts.RegisterSecret(r.Config.Secrets, credentials.SessionToken)
+
+	return credentials, nil
+}
+
+func (r Resolver) credentials(ctx context.Context) (Credentials, error) {
+	ref := r.Config.Setting("credential_ref", "")
+	if account, ok := contracts.AccountFromContext(ctx); ok && account.Credential != "" {
+		ref = account.Credential
+	}
+	if ref == "" || strings.HasPrefix(ref, ProfilePrefix) {
+		return r.Env.Credentials(r.Profile(ctx))
+	}
+
+	if r.Config.Secrets == nil {
+		return Credentials{}, providerkit.ErrNoSecrets
+	}
+	secret, err := r.Config.Secrets.Resolve(ctx, ref)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to resolve credential %q: %w", ref, err)
+	}
+
+	return ParseCredentials(secret)
+}
+
+// Region returns the region for a call: the region argument, the account's
+// region, the region setting, the AWS environment or profile, or
+// DefaultRegion.
+func (r Resolver) Region(ctx context.Context, request mcp.CallToolRequest) string {
+	fallback := r.Config.Setting("region", "")
+	if fallback == "" {
+		fallback = r.Env.Region(r.Profile(ctx))
+	}
+	if fallback == "" {
+		fallback = DefaultRegion
+	}
+
+	return providerkit.Region(ctx, request, fallback)
+}
@@ -0,0 +1,300 @@
+// Package awsauth implements AWS Signature Version 4 request signing and the
+// standard credential chain: environment variables, then the shared
+// credentials and config files.
+package awsauth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Algorithm is the SigV4 signing algorithm name.
+	Algorithm = "AWS4-HMAC-SHA256"
+
+	// UnsignedPayload is the payload hash of requests whose body is not signed,
+	// as used by presigned S3 URLs.
+	UnsignedPayload = "UNSIGNED-PAYLOAD"
+
+	// EmptyPayloadHash is the SHA-256 of an empty body.
+	EmptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	// MaxPresignExpiry is the longest validity of a presigned URL.
+	MaxPresignExpiry = 7 * 24 * time.Hour
+
+	amzDateFormat   = "20060102T150405Z"
+	shortDateFormat = "20060102"
+)
+
+// Static errors for err113 compliance.
+var (
+	ErrInvalidExpiry    = errors.New("presign expiry must be between 1 second and 7 days")
+	ErrUnreplayableBody = errors.New("cannot sign a request body that cannot be replayed")
+)
+
+// unsignedHeaders are never signed because proxies and transports may change them.
+var unsignedHeaders = map[string]bool{
+	"authorization":   true,
+	"user-agent":      true,
+	"x-amzn-trace-id": true,
+	"expect":          true,
+	"connection":      true,
+	"accept-encoding": true,
+}
+
+// Signer signs requests to one service in one region.
+type Signer struct {
+	Credentials Credentials
+	Region      string
+	Service     string
+
+	// Now returns the signing time. Nil uses time.Now.
+	Now func() time.Time
+}
+
+// Sign adds the SigV4 Authorization header and the X-Amz-Date and, for
+// temporary credentials, X-Amz-Security-Token headers to req. The payload hash
+// is taken from the X-Amz-Content-Sha256 header when set, otherwise computed
+// from the body. S3 requests always carry that header.
+func (s Signer) Sign(req *http.Request) error {
+	now := s.now()
+
+	payloadHash := req.Header.Get("X-Amz-Content-Sha256")
+	if payloadHash == "" {
+		hash, err := bodyHash(req)
+		if err != nil {
+			return err
+		}
+		payloadHash = hash
+		if s.Service == "s3" {
+			req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+		}
+	}
+
+	req.Header.Set("X-Amz-Date", now.Format(amzDateFormat))
+	if token := s.Credentials.SessionToken.Reveal(); token != "" {
+		req.Header.Set("X-Amz-Security-Token", token)
+	}
+
+	headers, signedHeaders := canonicalHeaders(req)
+	canonical := strings.Join([]string{
+		req.Method,
+		s.canonicalPath(req.URL),
+		canonicalQuery(req.URL.Query()),
+		headers,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := s.scope(now)
+	signature := s.signature(now, scope, canonical)
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		Algorithm, s.Credentials.AccessKeyID, scope, signedHeaders, signature))
+
+	return nil
+}
+
+// Presign returns a URL for req that is valid for expires without further
+// credentials. Only the host header is signed and the payload is unsigned.
+func (s Signer) Presign(req *http.Request, expires time.Duration) (string, error) {
+	if expires < time.Second || expires > MaxPresignExpiry {
+		return "", ErrInvalidExpiry
+	}
+
+	now := s.now()
+	scope := s.scope(now)
+
+	query := req.URL.Query()
+	query.Set("X-Amz-Algorithm", Algorithm)
+	query.Set("X-Amz-Credential", s.Credentials.AccessKeyID+"/"+scope)
+	query.Set("X-Amz-Date", now.Format(amzDateFormat))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires/time.Second)))
+	query.Set("X-Amz-SignedHeaders", "host")
+	if token := s.Credentials.SessionToken.Reveal(); token != "" {
+		query.Set("X-Amz-Security-Token", token)
+	}
+
+	canonical := strings.Join([]string{
+		req.Method,
+		s.canonicalPath(req.URL),
+		canonicalQuery(query),
+		"host:" + host(req) + "\n",
+		"host",
+		UnsignedPayload,
+	}, "\n")
+
+	query.Set("X-Amz-Signature", s.signature(now, scope, canonical))
+
+	signed := *req.URL
+	signed.RawQuery = canonicalQuery(query)
+
+	return signed.String(), nil
+}
+
+// now returns the signing time in UTC.
+func (s Signer) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+
+	return time.Now().UTC()
+}
+
+// scope returns the credential scope for the signing day.
+func (s Signer) scope(now time.Time) string {
+	return strings.Join([]string{now.Format(shortDateFormat), s.Region, s.Service, "aws4_request"}, "/")
+}
+
+// signature signs the canonical request.
+func (s Signer) signature(now time.Time, scope, canonical string) string {
+	stringToSign := strings.Join([]string{Algorithm, now.Format(amzDateFormat), scope, hashHex([]byte(canonical))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.Credentials.SecretAccessKey.Reveal()), now.Format(shortDateFormat))
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, s.Service)
+	key = hmacSHA256(key, "aws4_request")
+
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// canonicalPath encodes the request path. S3 encodes object keys once; every
+// other service encodes the already escaped path a second time.
+func (s Signer) canonicalPath(u *url.URL) string {
+	path := u.EscapedPath()
+	if s.Service == "s3" {
+		path = u.Path
+	}
+	if path == "" {
+		return "/"
+	}
+
+	return EscapePath(path)
+}
+
+// canonicalQuery encodes query parameters sorted by name, then value.
+func canonicalQuery(query url.Values) string {
+	pairs := make([]string, 0, len(query))
+	for key, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(key)+"="+uriEncode(value))
+		}
+	}
+	slices.Sort(pairs)
+
+	return strings.Join(pairs, "&")
+}
+
+// canonicalHeaders returns the canonical header block and the signed header
+// list: the host and every request header not in unsignedHeaders.
+func canonicalHeaders(req *http.Request) (string, string) {
+	values := map[string]string{"host": host(req)}
+	for name, list := range req.Header {
+		name = strings.ToLower(name)
+		if unsignedHeaders[name] {
+			continue
+		}
+		trimmed := make([]string, len(list))
+		for i, value := range list {
+			trimmed[i] = strings.Join(strings.Fields(value), " ")
+		}
+		values[name] = strings.Join(trimmed, ",")
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var block strings.Builder
+	for _, name := range names {
+		block.WriteString(name + ":" + values[name] + "\n")
+	}
+
+	return block.String(), strings.Join(names, ";")
+}
+
+// host returns the value of the Host header of req.
+func host(req *http.Request) string {
+	if req.Host != "" {
+		return req.Host
+	}
+
+	return req.URL.Host
+}
+
+// bodyHash returns the hex SHA-256 of the request body, leaving the body
+// readable.
+func bodyHash(req *http.Request) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return EmptyPayloadHash, nil
+	}
+	if req.GetBody == nil {
+		return "", ErrUnreplayableBody
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return "", fmt.Errorf("awsauth: failed to read body: %w", err)
+	}
+	defer body.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, body); err != nil {
+		return "", fmt.Errorf("awsauth: failed to hash body: %w", err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// PayloadHash returns the hex SHA-256 of a request body.
+func PayloadHash(body []byte) string {
+	return hashHex(body)
+}
+
+// EscapePath encodes each segment of an S3 object path the way it is signed,
+// so the path sent matches the canonical request exactly.
+func EscapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// uriEncode percent-encodes everything except RFC 3986 unreserved characters.
+func uriEncode(s string) string {
+	var b strings.Builder
+	for i := range len(s) {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+
+	return b.String()
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
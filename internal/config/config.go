@@ -2,23 +2,226 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 )
 
+const (
+	// DefaultProgressInterval is the minimum time between two progress notifications for one tool call.
+	DefaultProgressInterval = 250 * time.Millisecond
+
+	// DefaultMaxResultBytes is the inline text budget of a single tool result.
+	DefaultMaxResultBytes = 32 << 10
+
+	// IdempotencyStoreMemory keeps idempotency records in memory only.
+	IdempotencyStoreMemory = "memory"
+
+	// IdempotencyStoreFile persists idempotency records to IdempotencyFile.
+	IdempotencyStoreFile = "file"
+)
+
+// ErrInvalidIdempotencyStore is returned for unknown idempotency store kinds.
+var ErrInvalidIdempotencyStore = errors.New("invalid idempotency store")
+
 // Config holds the minimal configuration for CloudMCP server.
 type Config struct {
 	ServerName string
 	LogLevel   string
+
+	// ProgressInterval throttles progress notifications sent for a single tool call.
+	// Zero selects DefaultProgressInterval.
+	ProgressInterval time.Duration
+
+	// StateDir is the directory for local server state such as the job store.
+	StateDir string
+
+	// JobsFile is where background job state is persisted. Empty keeps jobs in memory only.
+	JobsFile string
+
+	// JobWorkers bounds how many background jobs run concurrently. Zero selects the default.
+	JobWorkers int
+
+	// CursorKey signs list tool pagination cursors. Empty generates a random key
+	// per process, so cursors do not survive restarts.
+	CursorKey string
+
+	// MaxResultBytes is the inline text budget of a tool result. Larger results
+	// are truncated and the full text is offered as a resource. Zero selects
+	// DefaultMaxResultBytes.
+	MaxResultBytes int
+
+	// ResultTTL is how long offloaded results remain readable. Zero selects the default.
+	ResultTTL time.Duration
+
+	// ResultStoreBytes bounds the memory used by offloaded results. Zero selects the default.
+	ResultStoreBytes int
+
+	// IdempotencyStore selects where idempotency records are kept: memory or
+	// file. Empty selects memory.
+	IdempotencyStore string
+
+	// IdempotencyFile is where the file store persists idempotency records.
+	IdempotencyFile string
+
+	// IdempotencyWindow is how long results of calls with an idempotency key
+	// are remembered. Zero selects the default.
+	IdempotencyWindow time.Duration
+
+	// RateLimits is a comma-separated list of token-bucket limits such as
+	// "tool:*=30/m,provider:aws=20/s,session=120/m". Empty disables limits.
+	RateLimits string
+
+	// AccountsFile is the YAML registry of cloud accounts. A missing file means
+	// no accounts are configured.
+	AccountsFile string
+
+	// CredentialsFile is the encrypted credential store.
+	CredentialsFile string
+
+	// CredentialsPassphrase unlocks a passphrase-sealed credential store.
+	CredentialsPassphrase string
+
+	// CredentialsKeyFile unlocks a key-file-sealed credential store.
+	CredentialsKeyFile string
+
+	// Providers lists the cloud providers to enable, in initialization order.
+	Providers []string
+
+	// ProviderSettings holds each enabled provider's CLOUD_MCP_<NAME>_*
+	// environment variables, keyed by provider name and lower-case suffix.
+	ProviderSettings map[string]map[string]string
 }
 
 // Load loads configuration from environment variables with sensible defaults.
 func Load() (*Config, error) {
+	progressInterval, err := getEnvDurationOrDefault("CLOUD_MCP_PROGRESS_INTERVAL", DefaultProgressInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	jobWorkers, err := getEnvIntOrDefault("CLOUD_MCP_JOB_WORKERS", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	maxResultBytes, err := getEnvIntOrDefault("CLOUD_MCP_MAX_RESULT_BYTES", DefaultMaxResultBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	resultTTL, err := getEnvDurationOrDefault("CLOUD_MCP_RESULT_TTL", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	resultStoreBytes, err := getEnvIntOrDefault("CLOUD_MCP_RESULT_STORE_BYTES", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	idempotencyWindow, err := getEnvDurationOrDefault("CLOUD_MCP_IDEMPOTENCY_WINDOW", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	providers := splitList(os.Getenv("CLOUD_MCP_PROVIDERS"))
+
+	stateDir := getEnvOrDefault("CLOUD_MCP_STATE_DIR", defaultStateDir())
+	idempotencyFile := getEnvOrDefault("CLOUD_MCP_IDEMPOTENCY_FILE", stateFile(stateDir, "idempotency.json"))
+
+	defaultIdempotencyStore := IdempotencyStoreMemory
+	if idempotencyFile != "" {
+		defaultIdempotencyStore = IdempotencyStoreFile
+	}
+	idempotencyStore := getEnvOrDefault("CLOUD_MCP_IDEMPOTENCY_STORE", defaultIdempotencyStore)
+	if idempotencyStore != IdempotencyStoreMemory && idempotencyStore != IdempotencyStoreFile {
+		return nil, fmt.Errorf("%w: %q (expected %s or %s)",
+			ErrInvalidIdempotencyStore, idempotencyStore, IdempotencyStoreMemory, IdempotencyStoreFile)
+	}
+
 	return &Config{
-		ServerName: getEnvOrDefault("CLOUD_MCP_SERVER_NAME", "CloudMCP Minimal"),
-		LogLevel:   getEnvOrDefault("LOG_LEVEL", "info"),
+		ServerName:       getEnvOrDefault("CLOUD_MCP_SERVER_NAME", "CloudMCP Minimal"),
+		LogLevel:         getEnvOrDefault("LOG_LEVEL", "info"),
+		ProgressInterval: progressInterval,
+		StateDir:         stateDir,
+		JobsFile:         getEnvOrDefault("CLOUD_MCP_JOBS_FILE", stateFile(stateDir, "jobs.json")),
+		JobWorkers:       jobWorkers,
+		CursorKey:        os.Getenv("CLOUD_MCP_CURSOR_KEY"),
+		MaxResultBytes:   maxResultBytes,
+		ResultTTL:        resultTTL,
+		ResultStoreBytes: resultStoreBytes,
+
+		IdempotencyStore:  idempotencyStore,
+		IdempotencyFile:   idempotencyFile,
+		IdempotencyWindow: idempotencyWindow,
+
+		RateLimits: os.Getenv("CLOUD_MCP_RATE_LIMITS"),
+
+		AccountsFile: getEnvOrDefault("CLOUD_MCP_ACCOUNTS_FILE", stateFile(stateDir, "accounts.yaml")),
+
+		CredentialsFile:       getEnvOrDefault("CLOUD_MCP_CREDENTIALS_FILE", stateFile(stateDir, "credentials.enc")),
+		CredentialsPassphrase: os.Getenv("CLOUD_MCP_CREDENTIALS_PASSPHRASE"),
+		CredentialsKeyFile:    os.Getenv("CLOUD_MCP_CREDENTIALS_KEY_FILE"),
+
+		Providers:        providers,
+		ProviderSettings: providerSettings(providers, os.Environ()),
 	}, nil
 }
 
+// defaultStateDir returns the per-user CloudMCP state directory, or empty if
+// the platform has no user configuration directory.
+func defaultStateDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "cloudmcp")
+}
+
+// stateFile returns the path of a file in the state directory, or empty if
+// there is no state directory.
+func stateFile(stateDir, name string) string {
+	if stateDir == "" {
+		return ""
+	}
+	return filepath.Join(stateDir, name)
+}
+
+// splitList splits a comma-separated list, dropping blanks and surrounding spaces.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// providerSettings collects CLOUD_MCP_<NAME>_* variables from environ for each
+// provider, keyed by the lower-case variable suffix. Dashes in provider names
+// match underscores in variable names.
+func providerSettings(providers []string, environ []string) map[string]map[string]string {
+	settings := make(map[string]map[string]string, len(providers))
+	for _, name := range providers {
+		prefix := "CLOUD_MCP_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		values := make(map[string]string)
+		for _, entry := range environ {
+			key, value, _ := strings.Cut(entry, "=")
+			if suffix, ok := strings.CutPrefix(key, prefix); ok && suffix != "" {
+				values[strings.ToLower(suffix)] = value
+			}
+		}
+		settings[name] = values
+	}
+	return settings
+}
+
 // getEnvOrDefault returns environment variable value or default if not set.
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -26,3 +229,33 @@ func getEnvOrDefault(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvDurationOrDefault parses a duration environment variable or returns the default if not set.
+func getEnvDurationOrDefault(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+
+	return duration, nil
+}
+
+// getEnvIntOrDefault parses an integer environment variable or returns the default if not set.
+func getEnvIntOrDefault(key string, defaultValue int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+
+	return parsed, nil
+}
@@ -3,6 +3,7 @@ package config_test
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -153,3 +154,31 @@ func TestLoad_EmptyEnvironmentValues(t *testing.T) {
 	require.Equal(t, "CloudMCP Minimal", cfg.ServerName, "Should use default when env var is empty")
 	require.Equal(t, "info", cfg.LogLevel, "Should use default when env var is empty")
 }
+
+func TestLoad_IdempotencyStore(t *testing.T) {
+	t.Setenv("CLOUD_MCP_STATE_DIR", t.TempDir())
+	t.Setenv("CLOUD_MCP_IDEMPOTENCY_STORE", "")
+	t.Setenv("CLOUD_MCP_IDEMPOTENCY_WINDOW", "1h")
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	require.Equal(t, config.IdempotencyStoreFile, cfg.IdempotencyStore, "file store is the default with a state directory")
+	require.NotEmpty(t, cfg.IdempotencyFile)
+	require.Equal(t, time.Hour, cfg.IdempotencyWindow)
+
+	t.Setenv("CLOUD_MCP_IDEMPOTENCY_STORE", "redis")
+	_, err = config.Load()
+	require.ErrorIs(t, err, config.ErrInvalidIdempotencyStore)
+}
+
+func TestLoad_ProviderSettings(t *testing.T) {
+	t.Setenv("CLOUD_MCP_PROVIDERS", "linode, digital-ocean,")
+	t.Setenv("CLOUD_MCP_LINODE_API_URL", "https://linode.test")
+	t.Setenv("CLOUD_MCP_DIGITAL_OCEAN_TOKEN_REF", "do-token")
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	require.Equal(t, []string{"linode", "digital-ocean"}, cfg.Providers)
+	require.Equal(t, "https://linode.test", cfg.ProviderSettings["linode"]["api_url"])
+	require.Equal(t, "do-token", cfg.ProviderSettings["digital-ocean"]["token_ref"])
+}
@@ -0,0 +1,135 @@
+package credentials_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chadit/CloudMCP/internal/credentials"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const token = "lin-token-0123456789abcdef"
+
+func TestStore_PassphraseRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "credentials.enc")
+	opts := credentials.Options{Path: path, Passphrase: "correct horse"}
+
+	store, err := credentials.Open(opts)
+	require.NoError(t, err)
+	require.NoError(t, store.Put("linode-prod", token))
+	require.NoError(t, store.Put("linode-staging", "other-token"))
+	require.NoError(t, store.Remove("linode-staging"))
+	require.ErrorIs(t, store.Put("bad name", token), credentials.ErrInvalidName)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), token, "the file holds no plaintext")
+	require.NotContains(t, string(data), "linode-prod", "names are encrypted too")
+
+	reopened, err := credentials.Open(opts)
+	require.NoError(t, err)
+	secret, err := reopened.Get("linode-prod")
+	require.NoError(t, err)
+	require.Equal(t, token, secret.Reveal())
+	require.Len(t, reopened.List(), 1)
+	require.Equal(t, "linode-prod", reopened.List()[0].Name)
+
+	_, err = credentials.Open(credentials.Options{Path: path, Passphrase: "wrong"})
+	require.ErrorIs(t, err, credentials.ErrDecrypt)
+
+	_, err = credentials.Open(credentials.Options{Path: path})
+	require.ErrorIs(t, err, credentials.ErrNoKey)
+}
+
+func TestStore_KeyFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key")
+	require.NoError(t, credentials.GenerateKeyFile(keyFile))
+	require.Error(t, credentials.GenerateKeyFile(keyFile), "existing key files are not overwritten")
+
+	path := filepath.Join(dir, "credentials.enc")
+	store, err := credentials.Open(credentials.Options{Path: path, KeyFile: keyFile})
+	require.NoError(t, err)
+	require.NoError(t, store.Put("do-token", token))
+
+	reopened, err := credentials.Open(credentials.Options{Path: path, KeyFile: keyFile})
+	require.NoError(t, err)
+	secret, err := reopened.Get("do-token")
+	require.NoError(t, err)
+	require.Equal(t, token, secret.Reveal())
+
+	_, err = credentials.Open(credentials.Options{Path: path, Passphrase: "guess"})
+	require.ErrorIs(t, err, credentials.ErrKeyMismatch)
+
+	otherKey := filepath.Join(dir, "other")
+	require.NoError(t, credentials.GenerateKeyFile(otherKey))
+	_, err = credentials.Open(credentials.Options{Path: path, KeyFile: otherKey})
+	require.ErrorIs(t, err, credentials.ErrDecrypt)
+}
+
+func TestSecret_NeverFormatsItsValue(t *testing.T) {
+	t.Parallel()
+
+	secret := contracts.Secret(token)
+	data, err := json.Marshal(map[string]any{"token": secret})
+	require.NoError(t, err)
+
+	for _, text := range []string{
+		fmt.Sprint(secret),
+		fmt.Sprintf("%s %v %q %#v %+v", secret, secret, secret, secret, struct{ Token contracts.Secret }{secret}),
+		string(data),
+	} {
+		require.NotContains(t, text, token)
+		require.Contains(t, text, "[REDACTED]")
+	}
+}
+
+func TestResolver_ResolvesAndRedacts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.enc")
+	opts := credentials.Options{Path: path, Passphrase: "pass"}
+
+	resolver := credentials.NewResolver(opts)
+	_, err := resolver.Resolve(t.Context(), "linode-prod")
+	require.ErrorIs(t, err, credentials.ErrSecretNotFound, "no store yet")
+
+	store, err := credentials.Open(opts)
+	require.NoError(t, err)
+	require.NoError(t, store.Put("linode-prod", token))
+
+	t.Setenv("TEST_DO_TOKEN", "dop_v1_secretvalue")
+	resolver = credentials.NewResolver(opts)
+
+	secret, err := resolver.Resolve(t.Context(), "linode-prod")
+	require.NoError(t, err)
+	require.Equal(t, token, secret.Reveal())
+
+	secret, err = resolver.Resolve(t.Context(), "env:TEST_DO_TOKEN")
+	require.NoError(t, err)
+	require.Equal(t, "dop_v1_secretvalue", secret.Reveal())
+
+	_, err = resolver.Resolve(t.Context(), "env:TEST_UNSET_TOKEN")
+	require.ErrorIs(t, err, credentials.ErrSecretNotFound)
+
+	contracts.RegisterSecret(resolver, "kubeconfig-token")
+
+	redactor := resolver.Redactor()
+	require.Equal(t, "Authorization: Bearer [REDACTED] and [REDACTED]",
+		redactor.Redact("Authorization: Bearer "+token+" and dop_v1_secretvalue"))
+	require.Equal(t, "Bearer [REDACTED]", redactor.Redact("Bearer kubeconfig-token"), "registered secrets are redacted")
+
+	var buf bytes.Buffer
+	logger := log.New(redactor.Writer(&buf), "", 0)
+	logger.Printf("request failed with token %s", token)
+	require.Equal(t, "request failed with token [REDACTED]\n", buf.String())
+}
@@ -0,0 +1,161 @@
+package credentials
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	// envPrefix marks references to environment variables.
+	envPrefix = "env:"
+
+	// minRedactLength is the shortest value redacted from output. Shorter
+	// values would mangle unrelated text.
+	minRedactLength = 4
+)
+
+// Resolver resolves credential references for providers. The store is opened
+// on first use, so servers that never reference it need no passphrase. Every
+// resolved value is added to the redactor.
+type Resolver struct {
+	opts     Options
+	redactor *Redactor
+
+	mu    sync.Mutex
+	store *Store
+}
+
+// NewResolver creates a resolver for the store described by opts.
+func NewResolver(opts Options) *Resolver {
+	return &Resolver{opts: opts, redactor: NewRedactor()}
+}
+
+// Resolve returns the secret for ref: env:NAME reads an environment variable,
+// anything else names an entry in the credential store.
+func (r *Resolver) Resolve(_ context.Context, ref string) (contracts.Secret, error) {
+	var secret contracts.Secret
+	if name, ok := strings.CutPrefix(ref, envPrefix); ok {
+		value := os.Getenv(name)
+		if value == "" {
+			return "", fmt.Errorf("%w: environment variable %s is not set", ErrSecretNotFound, name)
+		}
+		secret = contracts.Secret(value)
+	} else {
+		store, err := r.open()
+		if err != nil {
+			return "", err
+		}
+		if secret, err = store.Get(ref); err != nil {
+			return "", err
+		}
+	}
+
+	r.redactor.Add(secret)
+
+	return secret, nil
+}
+
+// Register adds a secret read outside the resolver to the redactor. It
+// implements contracts.SecretRegistry.
+func (r *Resolver) Register(secret contracts.Secret) {
+	r.redactor.Add(secret)
+}
+
+// Redactor returns the redactor holding every value resolved so far.
+func (r *Resolver) Redactor() *Redactor {
+	return r.redactor
+}
+
+// open unlocks the store once.
+func (r *Resolver) open() (*Store, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.store != nil {
+		return r.store, nil
+	}
+
+	if _, err := os.Stat(r.opts.Path); r.opts.Path == "" || errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: no credential store at %q; add secrets with cloud-mcp creds add", ErrSecretNotFound, r.opts.Path)
+	}
+
+	store, err := Open(r.opts)
+	if err != nil {
+		return nil, err
+	}
+	r.store = store
+
+	return store, nil
+}
+
+// Redactor replaces known secret values in text. It is safe for concurrent use.
+type Redactor struct {
+	mu     sync.RWMutex
+	values []string
+}
+
+// NewRedactor creates an empty redactor.
+func NewRedactor() *Redactor {
+	return &Redactor{}
+}
+
+// Add registers a secret value to redact.
+func (r *Redactor) Add(secret contracts.Secret) {
+	value := secret.Reveal()
+	if len(value) < minRedactLength {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.values {
+		if existing == value {
+			return
+		}
+	}
+	r.values = append(r.values, value)
+	// Longest first, so a secret containing another is replaced whole
+	sort.Slice(r.values, func(i, j int) bool { return len(r.values[i]) > len(r.values[j]) })
+}
+
+// Redact replaces every known secret value in s with [REDACTED].
+func (r *Redactor) Redact(s string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, value := range r.values {
+		s = strings.ReplaceAll(s, value, contracts.Secret(value).String())
+	}
+
+	return s
+}
+
+// Writer returns a writer that redacts secrets before writing to w. Each
+// write is redacted on its own, which suits the log package's one write per line.
+func (r *Redactor) Writer(w io.Writer) io.Writer {
+	return redactingWriter{redactor: r, w: w}
+}
+
+// redactingWriter redacts each write.
+type redactingWriter struct {
+	redactor *Redactor
+	w        io.Writer
+}
+
+// Write redacts p and writes it, reporting the length of p on success.
+func (rw redactingWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(rw.w, rw.redactor.Redact(string(p))); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
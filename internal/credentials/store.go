@@ -0,0 +1,330 @@
+// Package credentials keeps provider secrets in an encrypted local file and
+// resolves credential references to them. The file is sealed with AES-256-GCM
+// under a key derived from a passphrase (PBKDF2-SHA256) or read from a key file.
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chadit/CloudMCP/internal/statefile"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	// formatVersion is the version of the encrypted file layout.
+	formatVersion = 1
+
+	// kdfPBKDF2 derives the key from a passphrase.
+	kdfPBKDF2 = "pbkdf2-sha256"
+
+	// kdfKeyFile reads the key from a key file.
+	kdfKeyFile = "keyfile"
+
+	// pbkdf2Iterations follows current OWASP guidance for PBKDF2-SHA256.
+	pbkdf2Iterations = 600_000
+
+	keySize  = 32
+	saltSize = 16
+)
+
+// Static errors for err113 compliance.
+var (
+	ErrNoKey            = errors.New("credential store is locked: set CLOUD_MCP_CREDENTIALS_PASSPHRASE or CLOUD_MCP_CREDENTIALS_KEY_FILE")
+	ErrDecrypt          = errors.New("cannot decrypt credential store: wrong passphrase or key, or the file is corrupt")
+	ErrInvalidKeyFile   = errors.New("key file must hold 32 bytes, raw or hex encoded")
+	ErrKeyMismatch      = errors.New("credential store was sealed with a different kind of key")
+	ErrSecretNotFound   = errors.New("secret not found")
+	ErrInvalidName      = errors.New("credential names may only contain letters, digits, '.', '_' and '-'")
+	ErrUnsupportedStore = errors.New("unsupported credential store format")
+	ErrNoSecret         = errors.New("no secret on standard input")
+)
+
+// validName matches credential names.
+var validName = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// Options locates and unlocks a store. A new store is sealed with the key
+// file if one is given, otherwise with the passphrase; an existing store needs
+// the same kind of key it was sealed with.
+type Options struct {
+	Path       string
+	Passphrase string
+	KeyFile    string
+}
+
+// Info describes a stored secret without its value.
+type Info struct {
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// entry is a stored secret.
+type entry struct {
+	Value     string    `json:"value"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// envelope is the on-disk layout. Only the ciphertext carries secrets.
+type envelope struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations,omitempty"`
+	Salt       []byte `json:"salt,omitempty"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Store is an unlocked credential store. It is safe for concurrent use.
+type Store struct {
+	path       string
+	kdf        string
+	iterations int
+	salt       []byte
+	aead       cipher.AEAD
+
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+// Open unlocks the store at opts.Path, creating an empty one in memory if the
+// file does not exist yet. It is written on the first Put.
+func Open(opts Options) (*Store, error) {
+	var env envelope
+	exists, err := statefile.ReadJSON(opts.Path, &env)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credential store: %w", err)
+	}
+
+	s := &Store{path: opts.Path, entries: make(map[string]entry)}
+	if !exists {
+		s.kdf = kdfKeyFile
+		if opts.KeyFile == "" {
+			s.kdf = kdfPBKDF2
+			s.iterations = pbkdf2Iterations
+			s.salt = make([]byte, saltSize)
+			if _, err := rand.Read(s.salt); err != nil {
+				return nil, fmt.Errorf("failed to generate salt: %w", err)
+			}
+		}
+	} else {
+		if env.Version != formatVersion {
+			return nil, fmt.Errorf("%w: version %d", ErrUnsupportedStore, env.Version)
+		}
+		s.kdf, s.iterations, s.salt = env.KDF, env.Iterations, env.Salt
+	}
+
+	key, err := s.deriveKey(opts)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	if s.aead, err = cipher.NewGCM(block); err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	if exists {
+		plaintext, err := s.aead.Open(nil, env.Nonce, env.Ciphertext, s.additionalData())
+		if err != nil {
+			return nil, ErrDecrypt
+		}
+		if err := json.Unmarshal(plaintext, &s.entries); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrDecrypt, err)
+		}
+	}
+
+	return s, nil
+}
+
+// deriveKey returns the store key from the passphrase or key file.
+func (s *Store) deriveKey(opts Options) ([]byte, error) {
+	switch {
+	case opts.Passphrase == "" && opts.KeyFile == "":
+		return nil, ErrNoKey
+	case s.kdf == kdfPBKDF2 && opts.Passphrase != "":
+		key, err := pbkdf2.Key(sha256.New, opts.Passphrase, s.salt, s.iterations, keySize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive key: %w", err)
+		}
+		return key, nil
+	case s.kdf == kdfKeyFile && opts.KeyFile != "":
+		return readKeyFile(opts.KeyFile)
+	case s.kdf == kdfPBKDF2:
+		return nil, fmt.Errorf("%w: it needs a passphrase", ErrKeyMismatch)
+	case s.kdf == kdfKeyFile:
+		return nil, fmt.Errorf("%w: it needs a key file", ErrKeyMismatch)
+	default:
+		return nil, fmt.Errorf("%w: key derivation %q", ErrUnsupportedStore, s.kdf)
+	}
+}
+
+// readKeyFile reads a 32-byte key stored raw or hex encoded.
+func readKeyFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	if len(data) == keySize {
+		return data, nil
+	}
+
+	key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != keySize {
+		return nil, ErrInvalidKeyFile
+	}
+
+	return key, nil
+}
+
+// GenerateKeyFile writes a new random hex-encoded key to path, readable only
+// by the owner. It refuses to overwrite an existing file.
+func GenerateKeyFile(path string) error {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create key file: %w", err)
+	}
+	if _, err := file.WriteString(hex.EncodeToString(key) + "\n"); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write key file: %w", err)
+	}
+
+	return file.Close()
+}
+
+// List returns the stored secrets without their values, sorted by name.
+func (s *Store) List() []Info {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	infos := make([]Info, 0, len(s.entries))
+	for name, e := range s.entries {
+		infos = append(infos, Info{Name: name, CreatedAt: e.CreatedAt, UpdatedAt: e.UpdatedAt})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+
+	return infos
+}
+
+// Get returns the named secret.
+func (s *Store) Get(name string) (contracts.Secret, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+	}
+
+	return contracts.Secret(e.Value), nil
+}
+
+// Put adds or replaces a secret and saves the store.
+func (s *Store) Put(name string, value contracts.Secret) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	e, ok := s.entries[name]
+	if !ok {
+		e.CreatedAt = now
+	}
+	e.Value = value.Reveal()
+	e.UpdatedAt = now
+	s.entries[name] = e
+
+	return s.saveLocked()
+}
+
+// ReadSecret reads a secret to store from all of r, so multi-line values such
+// as JSON keys are kept whole. A single trailing newline, as left by echo or
+// an editor, is dropped.
+func ReadSecret(r io.Reader) (contracts.Secret, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret: %w", err)
+	}
+
+	secret, ok := strings.CutSuffix(string(data), "\n")
+	if ok {
+		secret = strings.TrimSuffix(secret, "\r")
+	}
+	if strings.TrimSpace(secret) == "" {
+		return "", ErrNoSecret
+	}
+
+	return contracts.Secret(secret), nil
+}
+
+// Remove deletes a secret and saves the store.
+func (s *Store) Remove(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.entries[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+	}
+	delete(s.entries, name)
+
+	return s.saveLocked()
+}
+
+// saveLocked seals the entries under a fresh nonce and writes the file. The
+// caller must hold s.mu.
+func (s *Store) saveLocked() error {
+	plaintext, err := json.Marshal(s.entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode credentials: %w", err)
+	}
+
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	env := envelope{
+		Version:    formatVersion,
+		KDF:        s.kdf,
+		Iterations: s.iterations,
+		Salt:       s.salt,
+		Nonce:      nonce,
+		Ciphertext: s.aead.Seal(nil, nonce, plaintext, s.additionalData()),
+	}
+	if err := statefile.WriteJSON(s.path, env); err != nil {
+		return fmt.Errorf("failed to write credential store: %w", err)
+	}
+
+	return nil
+}
+
+// additionalData binds the ciphertext to the key derivation parameters, so
+// they cannot be altered without failing authentication.
+func (s *Store) additionalData() []byte {
+	return fmt.Appendf(nil, "cloudmcp-credentials:v%d:%s:%d:%x", formatVersion, s.kdf, s.iterations, s.salt)
+}
@@ -0,0 +1,362 @@
+// Package format renders structured tool output in the shape a client asks
+// for. Tools return data with NewResult and declare the standard "format"
+// argument with WithParam; the server renders the data centrally, so every
+// tool supports the same formats without formatting code of its own.
+package format
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"gopkg.in/yaml.v3"
+)
+
+// Format names an output format.
+type Format string
+
+// Supported output formats.
+const (
+	JSON        Format = "json"
+	CompactJSON Format = "json-compact"
+	Markdown    Format = "markdown"
+	CSV         Format = "csv"
+	YAML        Format = "yaml"
+)
+
+const (
+	// Param is the standard format argument.
+	Param = "format"
+
+	// metaKey holds structured data on a result until the server renders it.
+	metaKey = "cloudmcp/structured"
+)
+
+// ErrUnsupportedFormat is returned for unknown format names.
+var ErrUnsupportedFormat = errors.New("unsupported format")
+
+// Formats lists the supported formats in documentation order.
+func Formats() []Format {
+	return []Format{JSON, CompactJSON, Markdown, CSV, YAML}
+}
+
+// Parse validates a format name. Empty selects JSON.
+func Parse(name string) (Format, error) {
+	if name == "" {
+		return JSON, nil
+	}
+
+	for _, f := range Formats() {
+		if string(f) == name {
+			return f, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %q (expected one of %s)", ErrUnsupportedFormat, name, strings.Join(formatNames(), ", "))
+}
+
+// WithParam adds the standard optional format argument to a tool definition.
+func WithParam() mcp.ToolOption {
+	return mcp.WithString(Param,
+		mcp.Description("Output format: json (pretty, default), json-compact, markdown (table), csv or yaml"),
+		mcp.Enum(formatNames()...),
+	)
+}
+
+// NewResult returns a tool result carrying structured data. The text content
+// is pretty JSON so the result is complete even when not rendered by the server.
+func NewResult(data any) (*mcp.CallToolResult, error) {
+	text, err := Render(data, JSON)
+	if err != nil {
+		return nil, err
+	}
+
+	result := mcp.NewToolResultText(text)
+	result.Meta = map[string]any{metaKey: data}
+
+	return result, nil
+}
+
+// Take removes and returns the structured data attached by NewResult.
+func Take(result *mcp.CallToolResult) (any, bool) {
+	if result == nil || result.Meta == nil {
+		return nil, false
+	}
+
+	data, ok := result.Meta[metaKey]
+	if !ok {
+		return nil, false
+	}
+
+	delete(result.Meta, metaKey)
+	if len(result.Meta) == 0 {
+		result.Meta = nil
+	}
+
+	return data, true
+}
+
+// Render formats data. Markdown and CSV render arrays of objects as tables
+// (an object with an "items" array renders its items) and single objects as
+// field/value tables; nested values appear as compact JSON.
+func Render(data any, f Format) (string, error) {
+	switch f {
+	case JSON:
+		out, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		return string(out), nil
+	case CompactJSON:
+		out, err := json.Marshal(data)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		return string(out), nil
+	case YAML:
+		return renderYAML(data)
+	case Markdown, CSV:
+		table, err := tabulate(data)
+		if err != nil {
+			return "", err
+		}
+		if f == CSV {
+			return table.csv()
+		}
+		return table.markdown(), nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedFormat, f)
+	}
+}
+
+// table is a rendered grid of cells.
+type table struct {
+	header []string
+	rows   [][]string
+	footer []footnote
+}
+
+// footnote is a field of a page other than its items, such as next_cursor.
+type footnote struct {
+	key, value string
+}
+
+// tabulate converts data into a table.
+func tabulate(data any) (table, error) {
+	value, err := normalize(data)
+	if err != nil {
+		return table{}, err
+	}
+
+	if object, ok := value.(*orderedMap); ok {
+		if items, ok := object.values["items"].([]any); ok {
+			t := rowsTable(items)
+			for _, key := range object.keys {
+				if key != "items" {
+					t.footer = append(t.footer, footnote{key: key, value: cell(object.values[key])})
+				}
+			}
+			return t, nil
+		}
+
+		t := table{header: []string{"field", "value"}}
+		for _, key := range object.keys {
+			t.rows = append(t.rows, []string{key, cell(object.values[key])})
+		}
+		return t, nil
+	}
+
+	if items, ok := value.([]any); ok {
+		return rowsTable(items), nil
+	}
+
+	return table{header: []string{"value"}, rows: [][]string{{cell(value)}}}, nil
+}
+
+// rowsTable builds a table with one row per item. Columns are the union of
+// object keys in first-seen order; non-object items use a single value column.
+func rowsTable(items []any) table {
+	var columns []string
+	seen := make(map[string]bool)
+	for _, item := range items {
+		object, ok := item.(*orderedMap)
+		if !ok {
+			continue
+		}
+		for _, key := range object.keys {
+			if !seen[key] {
+				seen[key] = true
+				columns = append(columns, key)
+			}
+		}
+	}
+
+	if len(columns) == 0 {
+		t := table{header: []string{"value"}}
+		for _, item := range items {
+			t.rows = append(t.rows, []string{cell(item)})
+		}
+		return t
+	}
+
+	t := table{header: columns}
+	for _, item := range items {
+		row := make([]string, len(columns))
+		if object, ok := item.(*orderedMap); ok {
+			for i, column := range columns {
+				if value, ok := object.values[column]; ok {
+					row[i] = cell(value)
+				}
+			}
+		} else {
+			row[0] = cell(item)
+		}
+		t.rows = append(t.rows, row)
+	}
+
+	return t
+}
+
+// markdown renders the table as GitHub-flavored Markdown.
+func (t table) markdown() string {
+	var b strings.Builder
+
+	writeRow := func(cells []string) {
+		b.WriteString("|")
+		for _, c := range cells {
+			b.WriteString(" ")
+			b.WriteString(escapeMarkdown(c))
+			b.WriteString(" |")
+		}
+		b.WriteString("\n")
+	}
+
+	writeRow(t.header)
+	b.WriteString("|")
+	for range t.header {
+		b.WriteString(" --- |")
+	}
+	b.WriteString("\n")
+	for _, row := range t.rows {
+		writeRow(row)
+	}
+
+	if len(t.footer) > 0 {
+		b.WriteString("\n")
+		for _, note := range t.footer {
+			b.WriteString(note.key + ": " + note.value + "\n")
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// csv renders the table as RFC 4180 CSV. Footer fields, such as the
+// next_cursor of a page, follow the rows as "# key=value" comment lines, which
+// CSV readers that honor comments skip.
+func (t table) csv() (string, error) {
+	var buf bytes.Buffer
+
+	w := csv.NewWriter(&buf)
+	if err := w.Write(t.header); err != nil {
+		return "", fmt.Errorf("failed to encode CSV: %w", err)
+	}
+	if err := w.WriteAll(t.rows); err != nil {
+		return "", fmt.Errorf("failed to encode CSV: %w", err)
+	}
+	for _, note := range t.footer {
+		buf.WriteString("# " + note.key + "=" + note.value + "\n")
+	}
+
+	return strings.TrimRight(buf.String(), "\n"), nil
+}
+
+// renderYAML renders data as YAML, keeping JSON field names and order.
+func renderYAML(data any) (string, error) {
+	value, err := normalize(data)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(yamlNode(value)); err != nil {
+		return "", fmt.Errorf("failed to encode YAML: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("failed to encode YAML: %w", err)
+	}
+
+	return strings.TrimRight(buf.String(), "\n"), nil
+}
+
+// yamlNode converts a normalized value into a YAML node tree.
+func yamlNode(value any) *yaml.Node {
+	switch v := value.(type) {
+	case *orderedMap:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		for _, key := range v.keys {
+			node.Content = append(node.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: key},
+				yamlNode(v.values[key]),
+			)
+		}
+		return node
+	case []any:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, item := range v {
+			node.Content = append(node.Content, yamlNode(item))
+		}
+		return node
+	case json.Number:
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: v.String()}
+	default:
+		node := &yaml.Node{}
+		if err := node.Encode(v); err != nil {
+			return &yaml.Node{Kind: yaml.ScalarNode, Value: fmt.Sprint(v)}
+		}
+		return node
+	}
+}
+
+// cell renders a value for a table cell.
+func cell(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return fmt.Sprint(v)
+	default:
+		out, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(out)
+	}
+}
+
+// escapeMarkdown makes a cell safe inside a Markdown table row.
+func escapeMarkdown(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	s = strings.ReplaceAll(s, "\r\n", "<br>")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+// formatNames returns the supported format names.
+func formatNames() []string {
+	formats := Formats()
+	names := make([]string, len(formats))
+	for i, f := range formats {
+		names[i] = string(f)
+	}
+	return names
+}
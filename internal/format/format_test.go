@@ -0,0 +1,125 @@
+package format_test
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chadit/CloudMCP/internal/format"
+)
+
+type instance struct {
+	Name   string   `json:"name"`
+	Status string   `json:"status"`
+	CPUs   int      `json:"cpus"`
+	Tags   []string `json:"tags,omitempty"`
+}
+
+type instancePage struct {
+	Items      []instance `json:"items"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+func TestRender_AllFormats(t *testing.T) {
+	t.Parallel()
+
+	data := []instance{
+		{Name: "web-1", Status: "running", CPUs: 2, Tags: []string{"prod"}},
+		{Name: "db|1", Status: "stopped", CPUs: 8},
+	}
+
+	tests := map[format.Format]string{
+		format.JSON: `[
+  {
+    "name": "web-1",
+    "status": "running",
+    "cpus": 2,
+    "tags": [
+      "prod"
+    ]
+  },
+  {
+    "name": "db|1",
+    "status": "stopped",
+    "cpus": 8
+  }
+]`,
+		format.CompactJSON: `[{"name":"web-1","status":"running","cpus":2,"tags":["prod"]},{"name":"db|1","status":"stopped","cpus":8}]`,
+		format.Markdown: "| name | status | cpus | tags |\n" +
+			"| --- | --- | --- | --- |\n" +
+			"| web-1 | running | 2 | [\"prod\"] |\n" +
+			"| db\\|1 | stopped | 8 |  |",
+		format.CSV: "name,status,cpus,tags\n" +
+			"web-1,running,2,\"[\"\"prod\"\"]\"\n" +
+			"db|1,stopped,8,",
+		format.YAML: "- name: web-1\n" +
+			"  status: running\n" +
+			"  cpus: 2\n" +
+			"  tags:\n" +
+			"    - prod\n" +
+			"- name: db|1\n" +
+			"  status: stopped\n" +
+			"  cpus: 8",
+	}
+
+	for f, want := range tests {
+		got, err := format.Render(data, f)
+		require.NoError(t, err, "format %s", f)
+		require.Equal(t, want, got, "format %s", f)
+	}
+}
+
+func TestRender_PageItemsAndSingleObjects(t *testing.T) {
+	t.Parallel()
+
+	page := instancePage{Items: []instance{{Name: "web-1", Status: "running", CPUs: 2}}, NextCursor: "abc"}
+	got, err := format.Render(page, format.Markdown)
+	require.NoError(t, err)
+	require.Equal(t, "| name | status | cpus |\n| --- | --- | --- |\n| web-1 | running | 2 |\n\nnext_cursor: abc", got)
+
+	got, err = format.Render(page, format.CSV)
+	require.NoError(t, err)
+	require.Equal(t, "name,status,cpus\nweb-1,running,2\n# next_cursor=abc", got, "the cursor survives CSV")
+
+	got, err = format.Render(instance{Name: "web-1", Status: "running", CPUs: 2}, format.CSV)
+	require.NoError(t, err)
+	require.Equal(t, "field,value\nname,web-1\nstatus,running\ncpus,2", got)
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	f, err := format.Parse("")
+	require.NoError(t, err)
+	require.Equal(t, format.JSON, f, "empty selects JSON")
+
+	for _, want := range format.Formats() {
+		f, err = format.Parse(string(want))
+		require.NoError(t, err)
+		require.Equal(t, want, f)
+	}
+
+	_, err = format.Parse("xml")
+	require.ErrorIs(t, err, format.ErrUnsupportedFormat)
+}
+
+func TestNewResult_TakeReturnsData(t *testing.T) {
+	t.Parallel()
+
+	data := instance{Name: "web-1"}
+	result, err := format.NewResult(data)
+	require.NoError(t, err)
+	require.Len(t, result.Content, 1)
+	text, ok := result.Content[0].(mcp.TextContent)
+	require.True(t, ok)
+	require.Contains(t, text.Text, `"name": "web-1"`, "text content is complete without rendering")
+
+	taken, ok := format.Take(result)
+	require.True(t, ok)
+	require.Equal(t, data, taken)
+	require.Nil(t, result.Meta, "structured data is removed from the result")
+
+	_, ok = format.Take(mcp.NewToolResultText("plain"))
+	require.False(t, ok)
+}
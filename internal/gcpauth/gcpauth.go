@@ -0,0 +1,259 @@
+// Package gcpauth implements Google service-account authentication: JWTs
+// signed locally with the account's private key and exchanged for OAuth 2.0
+// access tokens, following RFC 7523.
+package gcpauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	// DefaultTokenURL is Google's OAuth 2.0 token endpoint.
+	DefaultTokenURL = "https://oauth2.googleapis.com/token"
+
+	// CloudPlatformScope grants access to all Google Cloud APIs, limited by
+	// the service account's IAM roles.
+	CloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+	// JWTBearerGrant is the grant type of a JWT assertion exchange.
+	JWTBearerGrant = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+
+	// assertionLifetime is the validity of a signed assertion, the longest
+	// Google accepts.
+	assertionLifetime = time.Hour
+
+	// refreshMargin renews tokens this long before they expire.
+	refreshMargin = time.Minute
+
+	// maxTokenResponse bounds how much of a token response is read.
+	maxTokenResponse = 1 << 20
+)
+
+// Static errors for err113 compliance.
+var (
+	ErrInvalidKey = errors.New("invalid service account key")
+	ErrNoToken    = errors.New("token endpoint returned no access token")
+)
+
+// ServiceAccountKey is a service-account JSON key as downloaded from the
+// Google Cloud console.
+//
+//nolint:tagliatelle // JSON field names match the key file.
+type ServiceAccountKey struct {
+	Type         string `json:"type"`
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+
+	privateKey *rsa.PrivateKey
+}
+
+// ParseKey parses a service-account JSON key and its PEM private key.
+func ParseKey(secret contracts.Secret) (*ServiceAccountKey, error) {
+	var decoded struct {
+		ServiceAccountKey
+
+		PrivateKey string `json:"private_key"`
+	}
+	if err := json.Unmarshal([]byte(secret.Reveal()), &decoded); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKey, err)
+	}
+
+	key := decoded.ServiceAccountKey
+	if key.Type != "service_account" || key.ClientEmail == "" {
+		return nil, fmt.Errorf("%w: expected a service_account key with a client_email", ErrInvalidKey)
+	}
+
+	block, _ := pem.Decode([]byte(decoded.PrivateKey))
+	if block == nil {
+		return nil, fmt.Errorf("%w: private_key is not PEM encoded", ErrInvalidKey)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKey, err)
+	}
+	privateKey, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: private_key is not an RSA key", ErrInvalidKey)
+	}
+	key.privateKey = privateKey
+
+	return &key, nil
+}
+
+// Assertion returns a JWT signed with RS256 asserting the service account's
+// identity to audience, the token endpoint, for scope.
+func (k *ServiceAccountKey) Assertion(audience, scope string, now time.Time) (string, error) {
+	header := map[string]string{"alg": "RS256", "typ": "JWT", "kid": k.PrivateKeyID}
+	claims := map[string]any{
+		"iss":   k.ClientEmail,
+		"scope": scope,
+		"aud":   audience,
+		"iat":   now.Unix(),
+		"exp":   now.Add(assertionLifetime).Unix(),
+	}
+
+	segments := make([]string, 0, 3)
+	for _, part := range []any{header, claims} {
+		data, err := json.Marshal(part)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode JWT: %w", err)
+		}
+		segments = append(segments, base64.RawURLEncoding.EncodeToString(data))
+	}
+
+	digest := sha256.Sum256([]byte(strings.Join(segments, ".")))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, k.privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign JWT: %w", err)
+	}
+
+	return strings.Join(append(segments, base64.RawURLEncoding.EncodeToString(signature)), "."), nil
+}
+
+// TokenSource exchanges assertions for access tokens and caches each token
+// until shortly before it expires. It is safe for concurrent use.
+type TokenSource struct {
+	Key *ServiceAccountKey
+
+	// TokenURL is the token endpoint. Empty uses the key's token_uri, then
+	// DefaultTokenURL.
+	TokenURL string
+
+	// Scope is the requested scope. Empty uses CloudPlatformScope.
+	Scope string
+
+	HTTP *http.Client
+
+	// Now returns the current time. Nil uses time.Now.
+	Now func() time.Time
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// Token returns a valid access token, exchanging a new assertion when the
+// cached token is missing or about to expire.
+func (s *TokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if s.token != "" && now.Add(refreshMargin).Before(s.expiry) {
+		return s.token, nil
+	}
+
+	token, lifetime, err := s.exchange(ctx, now)
+	if err != nil {
+		return "", err
+	}
+	s.token, s.expiry = token, now.Add(lifetime)
+
+	return token, nil
+}
+
+// exchange trades a fresh assertion for an access token and its lifetime.
+func (s *TokenSource) exchange(ctx context.Context, now time.Time) (string, time.Duration, error) {
+	tokenURL := s.TokenURL
+	if tokenURL == "" {
+		tokenURL = s.Key.TokenURI
+	}
+	if tokenURL == "" {
+		tokenURL = DefaultTokenURL
+	}
+	scope := s.Scope
+	if scope == "" {
+		scope = CloudPlatformScope
+	}
+
+	assertion, err := s.Key.Assertion(tokenURL, scope, now)
+	if err != nil {
+		return "", 0, err
+	}
+
+	form := url.Values{"grant_type": {JWTBearerGrant}, "assertion": {assertion}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := s.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxTokenResponse))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read token response: %w", err)
+	}
+
+	var decoded struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int    `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	_ = json.Unmarshal(data, &decoded)
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		status := resp.StatusCode
+		if decoded.Error == "invalid_grant" || decoded.Error == "invalid_client" {
+			// a revoked key or a clock too far off; either way the credential is bad
+			status = http.StatusUnauthorized
+		}
+		message := strings.TrimSpace(decoded.Error + ": " + decoded.ErrorDescription)
+		if decoded.Error == "" {
+			message = strings.TrimSpace(string(data))
+		}
+
+		return "", 0, &providerkit.APIError{Provider: "Google OAuth", Status: status, Message: message}
+	}
+	if decoded.AccessToken == "" {
+		return "", 0, ErrNoToken
+	}
+
+	lifetime := time.Duration(decoded.ExpiresIn) * time.Second
+	if lifetime <= 0 {
+		lifetime = assertionLifetime
+	}
+
+	return decoded.AccessToken, lifetime, nil
+}
+
+func (s *TokenSource) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+
+	return time.Now()
+}
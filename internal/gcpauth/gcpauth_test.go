@@ -0,0 +1,168 @@
+package gcpauth_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chadit/CloudMCP/internal/gcpauth"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+// newKey returns a service-account JSON key and its RSA key.
+func newKey(t *testing.T, tokenURI string) (contracts.Secret, *rsa.PrivateKey) {
+	t.Helper()
+
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     "demo-project",
+		"private_key_id": "key-1",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"client_email":   "deployer@demo-project.iam.gserviceaccount.com",
+		"token_uri":      tokenURI,
+	})
+	require.NoError(t, err)
+
+	return contracts.Secret(data), private
+}
+
+// verifyJWT checks an RS256 JWT against public and returns its claims.
+func verifyJWT(t *testing.T, token string, public *rsa.PublicKey) map[string]any {
+	t.Helper()
+
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 3)
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	require.NoError(t, rsa.VerifyPKCS1v15(public, crypto.SHA256, digest[:], signature))
+
+	var header map[string]string
+	data, _ := base64.RawURLEncoding.DecodeString(parts[0])
+	require.NoError(t, json.Unmarshal(data, &header))
+	require.Equal(t, map[string]string{"alg": "RS256", "typ": "JWT", "kid": "key-1"}, header)
+
+	var claims map[string]any
+	data, _ = base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, json.Unmarshal(data, &claims))
+
+	return claims
+}
+
+func TestParseKey(t *testing.T) {
+	t.Parallel()
+
+	secret, _ := newKey(t, "")
+	key, err := gcpauth.ParseKey(secret)
+	require.NoError(t, err)
+	require.Equal(t, "demo-project", key.ProjectID)
+	require.Equal(t, "deployer@demo-project.iam.gserviceaccount.com", key.ClientEmail)
+
+	for _, invalid := range []string{
+		`not json`,
+		`{"type": "authorized_user", "client_email": "a@b"}`,
+		`{"type": "service_account", "client_email": "a@b", "private_key": "not pem"}`,
+	} {
+		_, err := gcpauth.ParseKey(contracts.Secret(invalid))
+		require.ErrorIs(t, err, gcpauth.ErrInvalidKey, invalid)
+	}
+}
+
+func TestAssertion_SignedWithServiceAccountKey(t *testing.T) {
+	t.Parallel()
+
+	secret, private := newKey(t, "")
+	key, err := gcpauth.ParseKey(secret)
+	require.NoError(t, err)
+
+	now := time.Unix(1700000000, 0)
+	assertion, err := key.Assertion("https://oauth2.example/token", gcpauth.CloudPlatformScope, now)
+	require.NoError(t, err)
+
+	claims := verifyJWT(t, assertion, &private.PublicKey)
+	require.Equal(t, "deployer@demo-project.iam.gserviceaccount.com", claims["iss"])
+	require.Equal(t, "https://oauth2.example/token", claims["aud"])
+	require.Equal(t, gcpauth.CloudPlatformScope, claims["scope"])
+	require.InDelta(t, 1700000000, claims["iat"], 0)
+	require.InDelta(t, 1700003600, claims["exp"], 0)
+}
+
+func TestTokenSource_ExchangesAndCaches(t *testing.T) {
+	t.Parallel()
+
+	var exchanges atomic.Int32
+	var private *rsa.PrivateKey
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, gcpauth.JWTBearerGrant, r.PostForm.Get("grant_type"))
+		claims := verifyJWT(t, r.PostForm.Get("assertion"), &private.PublicKey)
+		require.Equal(t, "http://"+r.Host+"/token", claims["aud"], "the audience is the token endpoint")
+
+		n := exchanges.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "token-" + string(rune('0'+n)), "expires_in": 3600, "token_type": "Bearer"})
+	}))
+	t.Cleanup(srv.Close)
+
+	var secret contracts.Secret
+	secret, private = newKey(t, srv.URL+"/token")
+	key, err := gcpauth.ParseKey(secret)
+	require.NoError(t, err)
+
+	now := time.Unix(1700000000, 0)
+	source := &gcpauth.TokenSource{Key: key, HTTP: srv.Client(), Now: func() time.Time { return now }}
+
+	token, err := source.Token(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, "token-1", token)
+
+	now = now.Add(58 * time.Minute)
+	token, err = source.Token(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, "token-1", token, "tokens are cached until shortly before expiry")
+
+	now = now.Add(90 * time.Second)
+	token, err = source.Token(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, "token-2", token)
+	require.Equal(t, int32(2), exchanges.Load())
+}
+
+func TestTokenSource_InvalidGrantIsUnauthorized(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": "invalid_grant", "error_description": "Invalid JWT Signature."}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	secret, _ := newKey(t, "https://oauth2.googleapis.com/token")
+	key, err := gcpauth.ParseKey(secret)
+	require.NoError(t, err)
+
+	source := &gcpauth.TokenSource{Key: key, TokenURL: srv.URL, HTTP: srv.Client()}
+	_, err = source.Token(t.Context())
+	require.ErrorIs(t, err, providerkit.ErrUnauthorized)
+	require.Contains(t, err.Error(), "invalid_grant: Invalid JWT Signature.")
+}
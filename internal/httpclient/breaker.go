@@ -0,0 +1,201 @@
+package httpclient
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// BreakerState is the state of a circuit breaker.
+type BreakerState string
+
+// Circuit breaker states.
+const (
+	// StateClosed lets requests through and counts consecutive failures.
+	StateClosed BreakerState = "closed"
+
+	// StateOpen rejects requests until the open timeout has passed.
+	StateOpen BreakerState = "open"
+
+	// StateHalfOpen lets a single probe request through to test recovery.
+	StateHalfOpen BreakerState = "half-open"
+)
+
+// BreakerStatus is a snapshot of one host's breaker for diagnostics.
+type BreakerStatus struct {
+	Host                string       `json:"host"`
+	State               BreakerState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	Requests            int64        `json:"requests"`
+	Failures            int64        `json:"failures"`
+	Retries             int64        `json:"retries"`
+	OpenedAt            *time.Time   `json:"opened_at,omitempty"`
+	RetryAt             *time.Time   `json:"retry_at,omitempty"`
+	LastError           string       `json:"last_error,omitempty"`
+}
+
+// Breakers holds one circuit breaker per host. A single registry is shared by
+// all provider clients so diagnostics see every host the server talks to.
+type Breakers struct {
+	threshold   int
+	openTimeout time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*breaker
+}
+
+// breaker is the state of one host. Fields are guarded by Breakers.mu.
+type breaker struct {
+	state     BreakerState
+	failures  int
+	openedAt  time.Time
+	probing   bool
+	requests  int64
+	total     int64
+	retries   int64
+	lastError string
+}
+
+// NewBreakers creates a breaker registry. A breaker opens after threshold
+// consecutive failures and allows a probe once openTimeout has passed. Zero
+// values select DefaultFailureThreshold and DefaultOpenTimeout.
+func NewBreakers(threshold int, openTimeout time.Duration) *Breakers {
+	if threshold <= 0 {
+		threshold = DefaultFailureThreshold
+	}
+	if openTimeout <= 0 {
+		openTimeout = DefaultOpenTimeout
+	}
+
+	return &Breakers{
+		threshold:   threshold,
+		openTimeout: openTimeout,
+		hosts:       make(map[string]*breaker),
+	}
+}
+
+// allow reports whether a request to host may proceed. When it may not, it
+// returns how long until the breaker allows a probe.
+func (b *Breakers) allow(host string) (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	br := b.hostLocked(host)
+	switch br.state {
+	case StateOpen:
+		wait := time.Until(br.openedAt.Add(b.openTimeout))
+		if wait > 0 {
+			return false, wait
+		}
+		br.state = StateHalfOpen
+		br.probing = true
+	case StateHalfOpen:
+		if br.probing {
+			return false, b.openTimeout
+		}
+		br.probing = true
+	case StateClosed:
+	}
+
+	br.requests++
+	return true, 0
+}
+
+// success records a healthy response from host and closes its breaker.
+func (b *Breakers) success(host string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	br := b.hostLocked(host)
+	br.state = StateClosed
+	br.failures = 0
+	br.probing = false
+}
+
+// failure records a failed request to host and opens its breaker when the
+// threshold is reached or a half-open probe fails.
+func (b *Breakers) failure(host, reason string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	br := b.hostLocked(host)
+	br.failures++
+	br.total++
+	br.lastError = reason
+	br.probing = false
+	if br.state == StateHalfOpen || br.failures >= b.threshold {
+		br.state = StateOpen
+		br.openedAt = time.Now()
+	}
+}
+
+// retried records a retry of a request to host.
+func (b *Breakers) retried(host string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.hostLocked(host).retries++
+}
+
+// release ends a request that neither succeeded nor failed, such as one
+// cancelled by the caller, so a half-open breaker can probe again.
+func (b *Breakers) release(host string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.hostLocked(host).probing = false
+}
+
+// State returns the current state of the breaker for host.
+func (b *Breakers) State(host string) BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	br, ok := b.hosts[host]
+	if !ok {
+		return StateClosed
+	}
+
+	return br.state
+}
+
+// Snapshot returns the status of every known host, sorted by host.
+func (b *Breakers) Snapshot() []BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	statuses := make([]BreakerStatus, 0, len(b.hosts))
+	for host, br := range b.hosts {
+		status := BreakerStatus{
+			Host:                host,
+			State:               br.state,
+			ConsecutiveFailures: br.failures,
+			Requests:            br.requests,
+			Failures:            br.total,
+			Retries:             br.retries,
+			LastError:           br.lastError,
+		}
+		if br.state != StateClosed {
+			openedAt := br.openedAt.UTC()
+			retryAt := openedAt.Add(b.openTimeout)
+			status.OpenedAt = &openedAt
+			status.RetryAt = &retryAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Host < statuses[j].Host })
+
+	return statuses
+}
+
+// hostLocked returns the breaker for host, creating it. The caller must hold b.mu.
+func (b *Breakers) hostLocked(host string) *breaker {
+	br, ok := b.hosts[host]
+	if !ok {
+		br = &breaker{state: StateClosed}
+		b.hosts[host] = br
+	}
+
+	return br
+}
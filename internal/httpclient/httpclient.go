@@ -0,0 +1,312 @@
+// Package httpclient is the resilient HTTP layer shared by provider clients.
+// Requests are retried on throttling and server errors with jittered
+// exponential backoff, Retry-After is honored, and a circuit breaker per host
+// stops hammering an API that is down.
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries is how many times a request is retried after the first attempt.
+	DefaultMaxRetries = 3
+
+	// DefaultBaseDelay is the backoff ceiling before the first retry; it doubles per retry.
+	DefaultBaseDelay = 250 * time.Millisecond
+
+	// DefaultMaxDelay caps the backoff between two attempts.
+	DefaultMaxDelay = 10 * time.Second
+
+	// DefaultMaxRetryAfter is the longest Retry-After the client waits for.
+	// Longer waits return the throttled response to the caller instead.
+	DefaultMaxRetryAfter = 60 * time.Second
+
+	// DefaultTimeout bounds a whole request including retries.
+	DefaultTimeout = 2 * time.Minute
+
+	// DefaultFailureThreshold is how many consecutive failures open a breaker.
+	DefaultFailureThreshold = 5
+
+	// DefaultOpenTimeout is how long an open breaker rejects requests before probing.
+	DefaultOpenTimeout = 30 * time.Second
+
+	// IdempotencyKeyHeader marks a request as safe to retry whatever its method.
+	IdempotencyKeyHeader = "Idempotency-Key"
+)
+
+// ErrCircuitOpen is returned when a host's circuit breaker rejects a request.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// Options configures a client. Zero values select the defaults.
+type Options struct {
+	// MaxRetries is how many times a request is retried. Negative disables retries.
+	MaxRetries int
+
+	// BaseDelay and MaxDelay bound the jittered exponential backoff.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+
+	// MaxRetryAfter is the longest Retry-After the client waits for.
+	MaxRetryAfter time.Duration
+
+	// Timeout bounds a whole request including retries.
+	Timeout time.Duration
+
+	// Breakers is the shared per-host breaker registry. Nil gives the client
+	// a private registry with default settings.
+	Breakers *Breakers
+
+	// Transport performs single attempts. Nil selects http.DefaultTransport.
+	Transport http.RoundTripper
+}
+
+// New returns an HTTP client that retries and trips breakers as configured.
+func New(opts Options) *http.Client {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+
+	return &http.Client{
+		Transport: NewTransport(opts),
+		Timeout:   opts.Timeout,
+	}
+}
+
+// Transport is an http.RoundTripper adding retries and circuit breaking.
+type Transport struct {
+	base          http.RoundTripper
+	breakers      *Breakers
+	maxRetries    int
+	baseDelay     time.Duration
+	maxDelay      time.Duration
+	maxRetryAfter time.Duration
+}
+
+// NewTransport creates a transport from opts. Timeout is ignored; it applies
+// to the http.Client.
+func NewTransport(opts Options) *Transport {
+	t := &Transport{
+		base:          opts.Transport,
+		breakers:      opts.Breakers,
+		maxRetries:    opts.MaxRetries,
+		baseDelay:     opts.BaseDelay,
+		maxDelay:      opts.MaxDelay,
+		maxRetryAfter: opts.MaxRetryAfter,
+	}
+	if t.base == nil {
+		t.base = http.DefaultTransport
+	}
+	if t.breakers == nil {
+		t.breakers = NewBreakers(0, 0)
+	}
+	switch {
+	case t.maxRetries == 0:
+		t.maxRetries = DefaultMaxRetries
+	case t.maxRetries < 0:
+		t.maxRetries = 0
+	}
+	if t.baseDelay <= 0 {
+		t.baseDelay = DefaultBaseDelay
+	}
+	if t.maxDelay <= 0 {
+		t.maxDelay = DefaultMaxDelay
+	}
+	if t.maxRetryAfter <= 0 {
+		t.maxRetryAfter = DefaultMaxRetryAfter
+	}
+
+	return t
+}
+
+// WithBase returns a copy of t that sends single attempts through base, for
+// clients needing their own TLS settings while sharing retries and breakers.
+func (t *Transport) WithBase(base http.RoundTripper) *Transport {
+	clone := *t
+	clone.base = base
+
+	return &clone
+}
+
+// RoundTrip sends req, retrying throttled and failed attempts. 429 responses
+// are retried for every method, since the server did not process the request.
+// 5xx responses and network errors are retried only for idempotent methods or
+// requests carrying an Idempotency-Key header.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		if ok, wait := t.breakers.allow(host); !ok {
+			return nil, fmt.Errorf("%w for %s: retry in %s", ErrCircuitOpen, host, wait.Round(time.Second))
+		}
+
+		attemptReq, err := rewind(req, attempt)
+		if err != nil {
+			t.breakers.release(host)
+			return nil, err
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if err != nil && ctx.Err() != nil {
+			t.breakers.release(host)
+			return nil, err
+		}
+
+		reason := failureReason(resp, err)
+		switch {
+		case reason == "":
+			t.breakers.success(host)
+			return resp, nil
+		case resp != nil && resp.StatusCode == http.StatusTooManyRequests:
+			// Throttling means the host is up; it does not count against the breaker.
+			t.breakers.release(host)
+		default:
+			t.breakers.failure(host, reason)
+		}
+
+		delay, retry := t.retryDelay(req, resp, err, attempt)
+		if !retry {
+			return resp, err
+		}
+
+		t.breakers.retried(host)
+		log.Printf("Retrying %s %s in %s (attempt %d of %d): %s",
+			req.Method, req.URL.Redacted(), delay.Round(time.Millisecond), attempt+2, t.maxRetries+1, reason)
+
+		if resp != nil {
+			drain(resp)
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryDelay decides whether a failed attempt is retried and after how long.
+func (t *Transport) retryDelay(req *http.Request, resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	if attempt >= t.maxRetries || !canRewind(req) {
+		return 0, false
+	}
+
+	if err == nil {
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests:
+		case resp.StatusCode >= http.StatusInternalServerError && retrySafe(req):
+		default:
+			return 0, false
+		}
+	} else if !retrySafe(req) {
+		return 0, false
+	}
+
+	delay := t.backoff(attempt)
+	if resp != nil {
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if retryAfter > t.maxRetryAfter {
+				return 0, false
+			}
+			delay = max(delay, retryAfter)
+		}
+	}
+
+	return delay, true
+}
+
+// backoff returns a full-jitter exponential delay for the given attempt.
+func (t *Transport) backoff(attempt int) time.Duration {
+	ceiling := t.baseDelay << min(attempt, 30)
+	if ceiling <= 0 || ceiling > t.maxDelay {
+		ceiling = t.maxDelay
+	}
+
+	return time.Duration(rand.Int64N(int64(ceiling) + 1))
+}
+
+// failureReason describes why an attempt failed, or returns empty for success.
+func failureReason(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+		return resp.Status
+	}
+
+	return ""
+}
+
+// retrySafe reports whether repeating req cannot duplicate its effect.
+func retrySafe(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return req.Header.Get(IdempotencyKeyHeader) != ""
+}
+
+// canRewind reports whether req's body can be sent again.
+func canRewind(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewind returns the request to send for an attempt, with a fresh body on retries.
+func rewind(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 || req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("failed to rewind request body: %w", err)
+	}
+
+	clone := req.Clone(req.Context())
+	clone.Body = body
+
+	return clone, nil
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0), true
+	}
+
+	return 0, false
+}
+
+// drain discards the rest of a response body so the connection can be reused.
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	_ = resp.Body.Close()
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("retry cancelled: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,156 @@
+package httpclient_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chadit/CloudMCP/internal/httpclient"
+)
+
+// flakyServer fails the first failures requests with status, then succeeds.
+func flakyServer(t *testing.T, failures int32, status int, header http.Header) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if hits.Add(1) <= failures {
+			for key, values := range header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(status)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &hits
+}
+
+func fastOptions(breakers *httpclient.Breakers) httpclient.Options {
+	return httpclient.Options{
+		MaxRetries: 3,
+		BaseDelay:  time.Millisecond,
+		MaxDelay:   5 * time.Millisecond,
+		Breakers:   breakers,
+	}
+}
+
+func hostOf(t *testing.T, srv *httptest.Server) string {
+	t.Helper()
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	return u.Host
+}
+
+func TestClient_RetriesServerErrors(t *testing.T) {
+	t.Parallel()
+
+	srv, hits := flakyServer(t, 2, http.StatusBadGateway, nil)
+	client := httpclient.New(fastOptions(nil))
+
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, int32(3), hits.Load())
+}
+
+func TestClient_DoesNotRetryUnsafePostOnServerError(t *testing.T) {
+	t.Parallel()
+
+	for _, status := range []int{http.StatusInternalServerError, http.StatusServiceUnavailable} {
+		srv, hits := flakyServer(t, 2, status, nil)
+		client := httpclient.New(fastOptions(nil))
+
+		resp, err := client.Post(srv.URL, "application/json", strings.NewReader(`{}`))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, status, resp.StatusCode)
+		require.Equal(t, int32(1), hits.Load(), "a POST may have been processed")
+
+		req, err := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(`{}`))
+		require.NoError(t, err)
+		req.Header.Set(httpclient.IdempotencyKeyHeader, "k1")
+		resp, err = client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode, "idempotent POSTs are retried")
+		require.Equal(t, int32(3), hits.Load())
+	}
+}
+
+func TestClient_HonorsRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	srv, hits := flakyServer(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": {"1"}})
+	client := httpclient.New(fastOptions(nil))
+
+	start := time.Now()
+	resp, err := client.Post(srv.URL, "application/json", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode, "throttled requests are retried for any method")
+	require.Equal(t, int32(2), hits.Load())
+	require.GreaterOrEqual(t, time.Since(start), time.Second)
+}
+
+func TestClient_RetryAfterBeyondLimitReturnsResponse(t *testing.T) {
+	t.Parallel()
+
+	srv, hits := flakyServer(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": {"3600"}})
+	client := httpclient.New(fastOptions(nil))
+
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	require.Equal(t, int32(1), hits.Load())
+}
+
+func TestBreaker_OpensAndRecovers(t *testing.T) {
+	t.Parallel()
+
+	srv, hits := flakyServer(t, 2, http.StatusInternalServerError, nil)
+	breakers := httpclient.NewBreakers(2, 50*time.Millisecond)
+	opts := fastOptions(breakers)
+	opts.MaxRetries = -1
+	client := httpclient.New(opts)
+	host := hostOf(t, srv)
+
+	for range 2 {
+		resp, err := client.Get(srv.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	require.Equal(t, httpclient.StateOpen, breakers.State(host))
+
+	_, err := client.Get(srv.URL)
+	require.ErrorIs(t, err, httpclient.ErrCircuitOpen)
+	require.Equal(t, int32(2), hits.Load(), "open breaker rejects without calling the host")
+
+	status := breakers.Snapshot()
+	require.Len(t, status, 1)
+	require.Equal(t, host, status[0].Host)
+	require.Equal(t, int64(2), status[0].Failures)
+	require.NotNil(t, status[0].RetryAt)
+
+	time.Sleep(60 * time.Millisecond)
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode, "probe after the open timeout")
+	require.Equal(t, httpclient.StateClosed, breakers.State(host))
+}
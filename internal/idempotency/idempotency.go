@@ -0,0 +1,254 @@
+// Package idempotency lets mutating tools be retried safely. A client passes
+// an idempotency key; the first call with that key runs the tool and its
+// result is remembered for a window, and repeats within the window get the
+// remembered result instead of running the tool again.
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	// Param is the standard idempotency key argument.
+	Param = "idempotency_key"
+
+	// DefaultWindow is how long results are remembered.
+	DefaultWindow = 24 * time.Hour
+
+	// MaxKeyLength bounds the length of an idempotency key.
+	MaxKeyLength = 255
+)
+
+// Static errors for err113 compliance.
+var (
+	ErrInvalidKey = errors.New("invalid idempotency key")
+	ErrKeyReused  = errors.New("idempotency key was already used with different arguments")
+)
+
+// Record is a remembered tool result.
+type Record struct {
+	Account     string          `json:"account,omitempty"`
+	Tool        string          `json:"tool"`
+	Key         string          `json:"key"`
+	Fingerprint string          `json:"fingerprint"`
+	Result      json.RawMessage `json:"result"`
+	CreatedAt   time.Time       `json:"created_at"`
+	ExpiresAt   time.Time       `json:"expires_at"`
+}
+
+// Expired reports whether the record is outside its window at now.
+func (r Record) Expired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
+
+// Store remembers records. Keys are scoped by account and tool, so two tools,
+// or one tool run against two accounts, may use the same key independently.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns the unexpired record for an account, tool and key, if any.
+	Get(account, tool, key string) (Record, bool, error)
+
+	// Put stores a record, replacing any previous record for its tool and key.
+	Put(record Record) error
+}
+
+// WithParam adds the standard optional idempotency key argument to a tool definition.
+func WithParam() mcp.ToolOption {
+	return mcp.WithString(Param,
+		mcp.Description("Optional client-chosen key that makes retries safe: repeating a call with the same key and arguments returns the first result instead of running again"),
+		mcp.MaxLength(MaxKeyLength),
+	)
+}
+
+// KeyFrom extracts the idempotency key from tool arguments. It reports false
+// when no key was given.
+func KeyFrom(args map[string]any) (string, bool, error) {
+	raw, ok := args[Param]
+	if !ok || raw == nil {
+		return "", false, nil
+	}
+
+	key, ok := raw.(string)
+	if !ok {
+		return "", false, fmt.Errorf("%w: must be a string", ErrInvalidKey)
+	}
+	if key == "" || len(key) > MaxKeyLength {
+		return "", false, fmt.Errorf("%w: must be 1 to %d characters", ErrInvalidKey, MaxKeyLength)
+	}
+
+	return key, true, nil
+}
+
+type contextKey struct{}
+
+// WithKey attaches the idempotency key of the current call to ctx, so tools can
+// forward it to provider APIs that accept client tokens.
+func WithKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, contextKey{}, key)
+}
+
+// KeyFromContext returns the idempotency key of the current call, if any.
+func KeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(contextKey{}).(string)
+	return key, ok
+}
+
+// Guard runs tool calls at most once per key within the window.
+type Guard struct {
+	store  Store
+	window time.Duration
+
+	mu       sync.Mutex
+	inflight map[string]chan struct{}
+}
+
+// NewGuard creates a guard backed by store. A zero window selects DefaultWindow.
+func NewGuard(store Store, window time.Duration) *Guard {
+	if window <= 0 {
+		window = DefaultWindow
+	}
+
+	return &Guard{
+		store:    store,
+		window:   window,
+		inflight: make(map[string]chan struct{}),
+	}
+}
+
+// Window returns how long results are remembered.
+func (g *Guard) Window() time.Duration {
+	return g.window
+}
+
+// Do returns the remembered result for tool and key, or runs call and
+// remembers its result. Results are scoped to the account attached to ctx, so
+// a repeat after switching accounts runs against the new account. args are
+// the call arguments without the key; a repeat with different arguments fails
+// with ErrKeyReused. Concurrent calls with the same key wait for the first to
+// finish. Errors and error results are not remembered, so a failed call can be
+// retried with the same key.
+func (g *Guard) Do(
+	ctx context.Context,
+	tool, key string,
+	args map[string]any,
+	call func(context.Context) (*mcp.CallToolResult, error),
+) (*mcp.CallToolResult, error) {
+	fingerprint, err := fingerprintOf(args)
+	if err != nil {
+		return nil, err
+	}
+
+	account := accountOf(ctx)
+	release, err := g.acquire(ctx, recordID(account, tool, key))
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	record, found, err := g.store.Get(account, tool, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read idempotency store: %w", err)
+	}
+	if found {
+		if record.Fingerprint != fingerprint {
+			return nil, fmt.Errorf("%w: %s", ErrKeyReused, key)
+		}
+
+		raw := record.Result
+		result, err := mcp.ParseCallToolResult(&raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode remembered result: %w", err)
+		}
+
+		return result, nil
+	}
+
+	result, err := call(ctx)
+	if err != nil || result == nil || result.IsError || ctx.Err() != nil {
+		return result, err
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		log.Printf("Failed to encode result of %s for idempotency key %s: %v", tool, key, err)
+		return result, nil
+	}
+
+	now := time.Now().UTC()
+	record = Record{
+		Account:     account,
+		Tool:        tool,
+		Key:         key,
+		Fingerprint: fingerprint,
+		Result:      data,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(g.window),
+	}
+	if err := g.store.Put(record); err != nil {
+		log.Printf("Failed to remember result of %s for idempotency key %s: %v", tool, key, err)
+	}
+
+	return result, nil
+}
+
+// acquire waits until no other call holds id, then holds it until release is called.
+func (g *Guard) acquire(ctx context.Context, id string) (func(), error) {
+	for {
+		g.mu.Lock()
+		wait, busy := g.inflight[id]
+		if !busy {
+			done := make(chan struct{})
+			g.inflight[id] = done
+			g.mu.Unlock()
+
+			return func() {
+				g.mu.Lock()
+				delete(g.inflight, id)
+				g.mu.Unlock()
+				close(done)
+			}, nil
+		}
+		g.mu.Unlock()
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for call with the same idempotency key: %w", ctx.Err())
+		}
+	}
+}
+
+// accountOf names the account a call runs against, or returns empty for
+// calls without one.
+func accountOf(ctx context.Context) string {
+	account, ok := contracts.AccountFromContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	return account.Provider + "/" + account.Alias
+}
+
+// fingerprintOf hashes call arguments. JSON object keys are encoded in
+// sorted order, so equal arguments always hash equally.
+func fingerprintOf(args map[string]any) (string, error) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return "", fmt.Errorf("failed to fingerprint arguments: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
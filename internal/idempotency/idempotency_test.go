@@ -0,0 +1,189 @@
+package idempotency_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chadit/CloudMCP/internal/idempotency"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+// counter returns a call that counts its runs and reports the run number.
+func counter(runs *atomic.Int32) func(context.Context) (*mcp.CallToolResult, error) {
+	return func(context.Context) (*mcp.CallToolResult, error) {
+		n := runs.Add(1)
+		return mcp.NewToolResultText(fmt.Sprintf("created instance %d", n)), nil
+	}
+}
+
+func resultText(t *testing.T, result *mcp.CallToolResult) string {
+	t.Helper()
+
+	require.NotNil(t, result)
+	require.Len(t, result.Content, 1)
+	text, ok := result.Content[0].(mcp.TextContent)
+	require.True(t, ok)
+
+	return text.Text
+}
+
+func TestGuard_RepeatsReturnRememberedResult(t *testing.T) {
+	t.Parallel()
+
+	guard := idempotency.NewGuard(idempotency.NewMemoryStore(), time.Hour)
+	args := map[string]any{"region": "us-east"}
+	var runs atomic.Int32
+
+	first, err := guard.Do(t.Context(), "instances_create", "key-1", args, counter(&runs))
+	require.NoError(t, err)
+	second, err := guard.Do(t.Context(), "instances_create", "key-1", args, counter(&runs))
+	require.NoError(t, err)
+
+	require.Equal(t, int32(1), runs.Load(), "repeat does not run the tool again")
+	require.Equal(t, resultText(t, first), resultText(t, second))
+
+	_, err = guard.Do(t.Context(), "volumes_create", "key-1", args, counter(&runs))
+	require.NoError(t, err)
+	require.Equal(t, int32(2), runs.Load(), "keys are scoped by tool")
+
+	_, err = guard.Do(t.Context(), "instances_create", "key-1", map[string]any{"region": "eu-west"}, counter(&runs))
+	require.ErrorIs(t, err, idempotency.ErrKeyReused)
+	require.Equal(t, int32(2), runs.Load())
+}
+
+func TestGuard_ScopedByAccount(t *testing.T) {
+	t.Parallel()
+
+	guard := idempotency.NewGuard(idempotency.NewMemoryStore(), time.Hour)
+	prod := contracts.WithAccount(t.Context(), contracts.Account{Provider: "linode", Alias: "prod"})
+	staging := contracts.WithAccount(t.Context(), contracts.Account{Provider: "linode", Alias: "staging"})
+	var runs atomic.Int32
+
+	first, err := guard.Do(prod, "instances_create", "key-1", nil, counter(&runs))
+	require.NoError(t, err)
+	second, err := guard.Do(staging, "instances_create", "key-1", nil, counter(&runs))
+	require.NoError(t, err)
+	require.Equal(t, int32(2), runs.Load(), "another account runs the tool")
+	require.NotEqual(t, resultText(t, first), resultText(t, second))
+
+	repeat, err := guard.Do(prod, "instances_create", "key-1", nil, counter(&runs))
+	require.NoError(t, err)
+	require.Equal(t, resultText(t, first), resultText(t, repeat))
+	require.Equal(t, int32(2), runs.Load())
+}
+
+func TestGuard_FailuresAreNotRemembered(t *testing.T) {
+	t.Parallel()
+
+	guard := idempotency.NewGuard(idempotency.NewMemoryStore(), time.Hour)
+	var runs atomic.Int32
+	fail := func(context.Context) (*mcp.CallToolResult, error) {
+		runs.Add(1)
+		return mcp.NewToolResultError("quota exceeded"), nil
+	}
+
+	for range 2 {
+		result, err := guard.Do(t.Context(), "instances_create", "key-1", nil, fail)
+		require.NoError(t, err)
+		require.True(t, result.IsError)
+	}
+	require.Equal(t, int32(2), runs.Load(), "a failed call can be retried with the same key")
+}
+
+func TestGuard_WindowExpires(t *testing.T) {
+	t.Parallel()
+
+	guard := idempotency.NewGuard(idempotency.NewMemoryStore(), 20*time.Millisecond)
+	var runs atomic.Int32
+
+	_, err := guard.Do(t.Context(), "instances_create", "key-1", nil, counter(&runs))
+	require.NoError(t, err)
+	time.Sleep(30 * time.Millisecond)
+	_, err = guard.Do(t.Context(), "instances_create", "key-1", nil, counter(&runs))
+	require.NoError(t, err)
+
+	require.Equal(t, int32(2), runs.Load(), "keys are forgotten after the window")
+}
+
+func TestGuard_ConcurrentRepeatsRunOnce(t *testing.T) {
+	t.Parallel()
+
+	guard := idempotency.NewGuard(idempotency.NewMemoryStore(), time.Hour)
+	var runs atomic.Int32
+	slow := func(ctx context.Context) (*mcp.CallToolResult, error) {
+		time.Sleep(20 * time.Millisecond)
+		return counter(&runs)(ctx)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := guard.Do(context.Background(), "instances_create", "key-1", nil, slow)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, int32(1), runs.Load())
+}
+
+func TestFileStore_SurvivesRestart(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "idempotency.json")
+	store, err := idempotency.NewFileStore(path)
+	require.NoError(t, err)
+
+	var runs atomic.Int32
+	prod := contracts.WithAccount(t.Context(), contracts.Account{Provider: "linode", Alias: "prod"})
+	first, err := idempotency.NewGuard(store, time.Hour).Do(prod, "instances_create", "key-1", nil, counter(&runs))
+	require.NoError(t, err)
+
+	reopened, err := idempotency.NewFileStore(path)
+	require.NoError(t, err)
+	guard := idempotency.NewGuard(reopened, time.Hour)
+	second, err := guard.Do(prod, "instances_create", "key-1", nil, counter(&runs))
+	require.NoError(t, err)
+
+	require.Equal(t, int32(1), runs.Load())
+	require.Equal(t, resultText(t, first), resultText(t, second))
+
+	staging := contracts.WithAccount(t.Context(), contracts.Account{Provider: "linode", Alias: "staging"})
+	_, err = guard.Do(staging, "instances_create", "key-1", nil, counter(&runs))
+	require.NoError(t, err)
+	require.Equal(t, int32(2), runs.Load(), "restored records keep their account")
+}
+
+func TestKeyFrom(t *testing.T) {
+	t.Parallel()
+
+	key, ok, err := idempotency.KeyFrom(map[string]any{"idempotency_key": "abc"})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "abc", key)
+
+	_, ok, err = idempotency.KeyFrom(map[string]any{})
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	for _, invalid := range []any{"", 42, string(make([]byte, idempotency.MaxKeyLength+1))} {
+		_, _, err = idempotency.KeyFrom(map[string]any{"idempotency_key": invalid})
+		require.ErrorIs(t, err, idempotency.ErrInvalidKey)
+	}
+}
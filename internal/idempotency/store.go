@@ -0,0 +1,113 @@
+package idempotency
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/chadit/CloudMCP/internal/statefile"
+)
+
+// MemoryStore keeps records in memory. Records are lost on restart.
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]Record
+}
+
+// NewMemoryStore creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]Record)}
+}
+
+// Get returns the unexpired record for an account, tool and key, if any.
+func (s *MemoryStore) Get(account, tool, key string) (Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[recordID(account, tool, key)]
+	if !ok || record.Expired(time.Now()) {
+		return Record{}, false, nil
+	}
+
+	return record, true, nil
+}
+
+// Put stores a record and drops expired ones.
+func (s *MemoryStore) Put(record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.putLocked(record)
+	return nil
+}
+
+// putLocked stores a record and drops expired ones. The caller must hold s.mu.
+func (s *MemoryStore) putLocked(record Record) {
+	now := time.Now()
+	for id, existing := range s.records {
+		if existing.Expired(now) {
+			delete(s.records, id)
+		}
+	}
+
+	s.records[recordID(record.Account, record.Tool, record.Key)] = record
+}
+
+// snapshotLocked returns the stored records. The caller must hold s.mu.
+func (s *MemoryStore) snapshotLocked() []Record {
+	records := make([]Record, 0, len(s.records))
+	for _, record := range s.records {
+		records = append(records, record)
+	}
+
+	return records
+}
+
+// FileStore keeps records in memory and persists them to a JSON file, so
+// remembered results survive server restarts.
+type FileStore struct {
+	path   string
+	memory *MemoryStore
+}
+
+// NewFileStore opens the store at path, loading unexpired records from it.
+func NewFileStore(path string) (*FileStore, error) {
+	var records []Record
+	if _, err := statefile.ReadJSON(path, &records); err != nil {
+		return nil, fmt.Errorf("failed to load idempotency records: %w", err)
+	}
+
+	memory := NewMemoryStore()
+	now := time.Now()
+	for _, record := range records {
+		if !record.Expired(now) {
+			memory.records[recordID(record.Account, record.Tool, record.Key)] = record
+		}
+	}
+
+	return &FileStore{path: path, memory: memory}, nil
+}
+
+// Get returns the unexpired record for an account, tool and key, if any.
+func (s *FileStore) Get(account, tool, key string) (Record, bool, error) {
+	return s.memory.Get(account, tool, key)
+}
+
+// Put stores a record and writes the store to disk. A failed write keeps the
+// record in memory so it still applies until restart.
+func (s *FileStore) Put(record Record) error {
+	s.memory.mu.Lock()
+	defer s.memory.mu.Unlock()
+
+	s.memory.putLocked(record)
+	if err := statefile.WriteJSON(s.path, s.memory.snapshotLocked()); err != nil {
+		return fmt.Errorf("failed to persist idempotency records to %s: %w", s.path, err)
+	}
+
+	return nil
+}
+
+// recordID scopes a key to its account and tool.
+func recordID(account, tool, key string) string {
+	return account + "\x00" + tool + "\x00" + key
+}
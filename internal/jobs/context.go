@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Param is the standard argument asking a tool to run as a background job.
+const Param = "background"
+
+// WithParam adds the standard optional background argument to a tool
+// definition. The server consumes it and runs the call as a job.
+func WithParam() mcp.ToolOption {
+	return mcp.WithBoolean(Param,
+		mcp.Description("Run the call as a background job and return its ID at once; follow it with job_status and fetch the outcome with job_result"),
+	)
+}
+
+// managerKey is the context key for the job Manager.
+type managerKey struct{}
+
+// WithManager returns a copy of ctx carrying the job manager.
+func WithManager(ctx context.Context, m *Manager) context.Context {
+	return context.WithValue(ctx, managerKey{}, m)
+}
+
+// FromContext returns the job manager attached to ctx, or nil when background
+// jobs are unavailable.
+func FromContext(ctx context.Context) *Manager {
+	m, _ := ctx.Value(managerKey{}).(*Manager)
+	return m
+}
+
+// StartedResult is the tool result returned when work has been moved to a job.
+func StartedResult(job Job) *mcp.CallToolResult {
+	return mcp.NewToolResultText(fmt.Sprintf(
+		"Started background job %s (%s). Use job_status to follow progress and job_result to fetch the result.",
+		job.ID, job.Kind,
+	))
+}
@@ -0,0 +1,459 @@
+// Package jobs runs long-lived tool work in the background. A tool submits a
+// job, returns its ID to the client immediately, and the client polls the
+// built-in job tools for status and results. Job state is persisted to a local
+// file so finished jobs survive server restarts.
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chadit/CloudMCP/internal/statefile"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+// State is the lifecycle state of a job.
+type State string
+
+// Job states. Succeeded, failed and cancelled are terminal.
+const (
+	StatePending   State = "pending"
+	StateRunning   State = "running"
+	StateSucceeded State = "succeeded"
+	StateFailed    State = "failed"
+	StateCancelled State = "cancelled"
+)
+
+const (
+	// DefaultWorkers is the number of jobs that may run concurrently.
+	DefaultWorkers = 4
+
+	// DefaultMaxFinished is the number of finished jobs retained before the oldest are pruned.
+	DefaultMaxFinished = 200
+
+	// interruptedMessage is recorded for jobs that were running when the server stopped.
+	interruptedMessage = "interrupted by server shutdown"
+)
+
+// Static errors for err113 compliance.
+var (
+	ErrJobNotFound     = errors.New("job not found")
+	ErrJobFinished     = errors.New("job already finished")
+	ErrJobNotFinished  = errors.New("job has not finished")
+	ErrManagerClosed   = errors.New("job manager is closed")
+	ErrFuncNil         = errors.New("job function cannot be nil")
+	ErrJobPanicked     = errors.New("job panicked")
+	ErrInvalidPosition = errors.New("invalid job list position")
+)
+
+// Terminal reports whether the state is final.
+func (s State) Terminal() bool {
+	return s == StateSucceeded || s == StateFailed || s == StateCancelled
+}
+
+// Job is a snapshot of a background job.
+type Job struct {
+	ID         string          `json:"id"`
+	Kind       string          `json:"kind"`
+	State      State           `json:"state"`
+	Progress   float64         `json:"progress,omitempty"`
+	Total      float64         `json:"total,omitempty"`
+	Message    string          `json:"message,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	Result     json.RawMessage `json:"result,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	StartedAt  *time.Time      `json:"started_at,omitempty"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+}
+
+// Func is the work performed by a job. Its context is cancelled by job_cancel
+// or server shutdown, and carries a ProgressReporter that updates the job.
+// The returned value is stored as the job result and must marshal to JSON.
+type Func func(ctx context.Context) (any, error)
+
+// Options configures a Manager.
+type Options struct {
+	// Workers bounds how many jobs run at once. Zero selects DefaultWorkers.
+	Workers int
+
+	// Path is the file job state is persisted to. Empty disables persistence.
+	Path string
+
+	// MaxFinished bounds retained finished jobs. Zero selects DefaultMaxFinished.
+	MaxFinished int
+}
+
+// Manager schedules jobs on a bounded worker pool and tracks their state.
+type Manager struct {
+	path        string
+	maxFinished int
+	slots       chan struct{}
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	jobs    map[string]*Job
+	cancels map[string]context.CancelFunc
+	closed  bool
+}
+
+// NewManager creates a manager and restores persisted jobs. Jobs that were
+// still pending or running when the previous process stopped cannot resume,
+// so they are restored as failed.
+func NewManager(opts Options) (*Manager, error) {
+	if opts.Workers <= 0 {
+		opts.Workers = DefaultWorkers
+	}
+	if opts.MaxFinished <= 0 {
+		opts.MaxFinished = DefaultMaxFinished
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Manager{
+		path:        opts.Path,
+		maxFinished: opts.MaxFinished,
+		slots:       make(chan struct{}, opts.Workers),
+		ctx:         ctx,
+		cancel:      cancel,
+		jobs:        make(map[string]*Job),
+		cancels:     make(map[string]context.CancelFunc),
+	}
+
+	if err := m.load(); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Submit schedules fn as a new job of the given kind (usually the tool name)
+// and returns its initial snapshot without waiting for it to start. The job's
+// context keeps the values of ctx, such as the call's account and credential
+// resolver, but not its cancellation: the job outlives the call that started
+// it and stops only on Cancel or Close.
+func (m *Manager) Submit(ctx context.Context, kind string, fn Func) (Job, error) {
+	if fn == nil {
+		return Job{}, ErrFuncNil
+	}
+
+	id, err := newID()
+	if err != nil {
+		return Job{}, err
+	}
+
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return Job{}, ErrManagerClosed
+	}
+
+	job := &Job{ID: id, Kind: kind, State: StatePending, CreatedAt: time.Now().UTC()}
+	jobCtx, cancelJob := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(m.ctx, cancelJob)
+	m.jobs[id] = job
+	m.cancels[id] = func() {
+		stop()
+		cancelJob()
+	}
+	snapshot := *job
+	m.wg.Add(1)
+	m.persistLocked()
+	m.mu.Unlock()
+
+	go m.run(jobCtx, id, fn)
+
+	return snapshot, nil
+}
+
+// Get returns a snapshot of the job with the given ID.
+func (m *Manager) Get(id string) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return Job{}, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+
+	return *job, nil
+}
+
+// List returns snapshots of all known jobs, newest first.
+func (m *Manager) List() []Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	jobs := make([]Job, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		jobs = append(jobs, *job)
+	}
+	sortNewestFirst(jobs)
+
+	return jobs
+}
+
+// ListAfter returns the jobs that follow position in the newest-first order
+// of List. An empty position returns every job.
+func (m *Manager) ListAfter(position string) ([]Job, error) {
+	jobs := m.List()
+	if position == "" {
+		return jobs, nil
+	}
+
+	stamp, id, ok := strings.Cut(position, " ")
+	created, err := time.Parse(time.RFC3339Nano, stamp)
+	if !ok || err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidPosition, position)
+	}
+
+	start := sort.Search(len(jobs), func(i int) bool {
+		job := jobs[i]
+		return job.CreatedAt.Before(created) || (job.CreatedAt.Equal(created) && job.ID < id)
+	})
+
+	return jobs[start:], nil
+}
+
+// Position identifies where a job sits in the order of List. Unlike an index
+// it stays valid as jobs are submitted and pruned, so a list can be resumed
+// after it with ListAfter.
+func (j Job) Position() string {
+	return j.CreatedAt.Format(time.RFC3339Nano) + " " + j.ID
+}
+
+// Cancel requests cancellation. Pending jobs are cancelled immediately; running
+// jobs have their context cancelled and become cancelled once their function returns.
+func (m *Manager) Cancel(id string) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return Job{}, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	if job.State.Terminal() {
+		return *job, fmt.Errorf("%w: %s is %s", ErrJobFinished, id, job.State)
+	}
+
+	if cancel, ok := m.cancels[id]; ok {
+		cancel()
+	}
+	if job.State == StatePending {
+		m.finishLocked(job, StateCancelled, "cancelled before start", nil)
+	} else {
+		job.Message = "cancellation requested"
+	}
+
+	return *job, nil
+}
+
+// Close cancels all unfinished jobs, waits for their functions to return and
+// persists the final state. Submitting after Close fails with ErrManagerClosed.
+func (m *Manager) Close() {
+	m.mu.Lock()
+	m.closed = true
+	m.mu.Unlock()
+
+	m.cancel()
+	m.wg.Wait()
+}
+
+// run waits for a worker slot, executes fn and records the outcome.
+func (m *Manager) run(ctx context.Context, id string, fn Func) {
+	defer m.wg.Done()
+
+	select {
+	case m.slots <- struct{}{}:
+		defer func() { <-m.slots }()
+	case <-ctx.Done():
+		m.complete(id, nil, ctx.Err())
+		return
+	}
+
+	m.mu.Lock()
+	job, ok := m.jobs[id]
+	if !ok || job.State != StatePending {
+		// Cancelled while waiting for a slot.
+		m.mu.Unlock()
+		return
+	}
+	now := time.Now().UTC()
+	job.State = StateRunning
+	job.StartedAt = &now
+	m.persistLocked()
+	m.mu.Unlock()
+
+	result, err := m.call(ctx, id, fn)
+	m.complete(id, result, err)
+}
+
+// call invokes fn, converting a panic into a job failure so one faulty job
+// cannot take down the server.
+func (m *Manager) call(ctx context.Context, id string, fn Func) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrJobPanicked, r)
+		}
+	}()
+
+	return fn(contracts.WithProgressReporter(ctx, &jobProgress{manager: m, id: id}))
+}
+
+// complete records the outcome of a job function.
+func (m *Manager) complete(id string, result any, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok || job.State.Terminal() {
+		return
+	}
+
+	switch {
+	case err == nil:
+		raw, marshalErr := json.Marshal(result)
+		if marshalErr != nil {
+			m.finishLocked(job, StateFailed, "failed to encode result: "+marshalErr.Error(), nil)
+			return
+		}
+		m.finishLocked(job, StateSucceeded, "", raw)
+	case m.closed && errors.Is(err, context.Canceled):
+		m.finishLocked(job, StateFailed, interruptedMessage, nil)
+	case errors.Is(err, context.Canceled):
+		m.finishLocked(job, StateCancelled, err.Error(), nil)
+	default:
+		m.finishLocked(job, StateFailed, err.Error(), nil)
+	}
+}
+
+// finishLocked moves a job to a terminal state. The caller must hold m.mu.
+func (m *Manager) finishLocked(job *Job, state State, errMessage string, result json.RawMessage) {
+	now := time.Now().UTC()
+	job.State = state
+	job.Error = errMessage
+	job.Result = result
+	job.FinishedAt = &now
+	if state == StateSucceeded && job.Total > 0 {
+		job.Progress = job.Total
+	}
+
+	if cancel, ok := m.cancels[job.ID]; ok {
+		cancel()
+		delete(m.cancels, job.ID)
+	}
+
+	m.pruneLocked()
+	m.persistLocked()
+}
+
+// pruneLocked drops the oldest finished jobs beyond the retention limit.
+func (m *Manager) pruneLocked() {
+	finished := make([]Job, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		if job.State.Terminal() {
+			finished = append(finished, *job)
+		}
+	}
+	if len(finished) <= m.maxFinished {
+		return
+	}
+
+	sortNewestFirst(finished)
+	for _, job := range finished[m.maxFinished:] {
+		delete(m.jobs, job.ID)
+	}
+}
+
+// persistLocked writes all jobs to disk. Failures are logged rather than
+// returned because the in-memory state remains authoritative for this process.
+func (m *Manager) persistLocked() {
+	if m.path == "" {
+		return
+	}
+
+	jobs := make([]Job, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		jobs = append(jobs, *job)
+	}
+	sortNewestFirst(jobs)
+
+	if err := statefile.WriteJSON(m.path, jobs); err != nil {
+		log.Printf("Failed to persist jobs to %s: %v", m.path, err)
+	}
+}
+
+// load restores persisted jobs.
+func (m *Manager) load() error {
+	if m.path == "" {
+		return nil
+	}
+
+	var jobs []Job
+	if _, err := statefile.ReadJSON(m.path, &jobs); err != nil {
+		return fmt.Errorf("failed to load jobs: %w", err)
+	}
+
+	now := time.Now().UTC()
+	for i := range jobs {
+		job := jobs[i]
+		if !job.State.Terminal() {
+			job.State = StateFailed
+			job.Error = interruptedMessage
+			job.FinishedAt = &now
+		}
+		m.jobs[job.ID] = &job
+	}
+
+	return nil
+}
+
+// jobProgress records progress reported by a job function on the job itself.
+type jobProgress struct {
+	manager *Manager
+	id      string
+}
+
+func (p *jobProgress) Report(progress, total float64, message string) {
+	p.manager.mu.Lock()
+	defer p.manager.mu.Unlock()
+
+	if job, ok := p.manager.jobs[p.id]; ok && job.State == StateRunning {
+		job.Progress = progress
+		job.Total = total
+		if message != "" {
+			job.Message = message
+		}
+	}
+}
+
+// sortNewestFirst orders jobs by creation time, newest first, with ID as a tiebreaker.
+func sortNewestFirst(jobs []Job) {
+	sort.Slice(jobs, func(i, j int) bool {
+		if jobs[i].CreatedAt.Equal(jobs[j].CreatedAt) {
+			return jobs[i].ID > jobs[j].ID
+		}
+		return jobs[i].CreatedAt.After(jobs[j].CreatedAt)
+	})
+}
+
+// newID returns a random job identifier.
+func newID() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate job id: %w", err)
+	}
+
+	return "job_" + hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,272 @@
+package jobs_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chadit/CloudMCP/internal/jobs"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+var errBoom = errors.New("boom")
+
+// waitForState polls until the job reaches the wanted state.
+func waitForState(t *testing.T, m *jobs.Manager, id string, want jobs.State) jobs.Job {
+	t.Helper()
+
+	var job jobs.Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = m.Get(id)
+		return err == nil && job.State == want
+	}, 5*time.Second, 5*time.Millisecond, "job %s never reached %s", id, want)
+
+	return job
+}
+
+func newManager(t *testing.T, opts jobs.Options) *jobs.Manager {
+	t.Helper()
+
+	m, err := jobs.NewManager(opts)
+	require.NoError(t, err)
+	t.Cleanup(m.Close)
+
+	return m
+}
+
+func TestManager_SubmitSucceeds(t *testing.T) {
+	t.Parallel()
+
+	m := newManager(t, jobs.Options{})
+
+	job, err := m.Submit(t.Context(), "test", func(ctx context.Context) (any, error) {
+		contracts.ProgressFromContext(ctx).Report(1, 2, "halfway")
+		return map[string]string{"status": "ok"}, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, jobs.StatePending, job.State)
+	require.Equal(t, "test", job.Kind)
+
+	done := waitForState(t, m, job.ID, jobs.StateSucceeded)
+	require.JSONEq(t, `{"status":"ok"}`, string(done.Result))
+	require.InDelta(t, 2, done.Progress, 0, "successful jobs complete their progress")
+	require.NotNil(t, done.StartedAt)
+	require.NotNil(t, done.FinishedAt)
+}
+
+func TestManager_FailureAndPanic(t *testing.T) {
+	t.Parallel()
+
+	m := newManager(t, jobs.Options{})
+
+	failing, err := m.Submit(t.Context(), "fail", func(context.Context) (any, error) { return nil, errBoom })
+	require.NoError(t, err)
+	require.Equal(t, "boom", waitForState(t, m, failing.ID, jobs.StateFailed).Error)
+
+	panicking, err := m.Submit(t.Context(), "panic", func(context.Context) (any, error) { panic("oops") })
+	require.NoError(t, err)
+	require.Contains(t, waitForState(t, m, panicking.ID, jobs.StateFailed).Error, "oops")
+}
+
+func TestManager_BoundedConcurrency(t *testing.T) {
+	t.Parallel()
+
+	m := newManager(t, jobs.Options{Workers: 1})
+
+	release := make(chan struct{})
+	first, err := m.Submit(t.Context(), "block", func(ctx context.Context) (any, error) {
+		<-release
+		return nil, nil
+	})
+	require.NoError(t, err)
+	waitForState(t, m, first.ID, jobs.StateRunning)
+
+	second, err := m.Submit(t.Context(), "queued", func(context.Context) (any, error) { return "second", nil })
+	require.NoError(t, err)
+
+	// With one worker the second job cannot start until the first finishes.
+	time.Sleep(50 * time.Millisecond)
+	queued, err := m.Get(second.ID)
+	require.NoError(t, err)
+	require.Equal(t, jobs.StatePending, queued.State)
+
+	close(release)
+	waitForState(t, m, first.ID, jobs.StateSucceeded)
+	waitForState(t, m, second.ID, jobs.StateSucceeded)
+}
+
+func TestManager_SubmitOutlivesCall(t *testing.T) {
+	t.Parallel()
+
+	m := newManager(t, jobs.Options{})
+	callCtx, endCall := context.WithCancel(t.Context())
+
+	started := make(chan struct{})
+	job, err := m.Submit(callCtx, "wait", func(ctx context.Context) (any, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	require.NoError(t, err)
+	<-started
+	endCall()
+
+	time.Sleep(20 * time.Millisecond)
+	running, err := m.Get(job.ID)
+	require.NoError(t, err)
+	require.Equal(t, jobs.StateRunning, running.State, "the job is not cancelled with the call")
+
+	m.Close()
+	failed, err := m.Get(job.ID)
+	require.NoError(t, err)
+	require.Equal(t, jobs.StateFailed, failed.State, "the job stops with the manager")
+	require.Equal(t, "interrupted by server shutdown", failed.Error)
+
+	_, err = m.Submit(t.Context(), "late", func(context.Context) (any, error) { return nil, nil })
+	require.ErrorIs(t, err, jobs.ErrManagerClosed)
+}
+
+func TestManager_JobKeepsCallValues(t *testing.T) {
+	t.Parallel()
+
+	m := newManager(t, jobs.Options{})
+	callCtx := contracts.WithAccount(t.Context(), contracts.Account{Provider: "cloud", Alias: "staging"})
+
+	job, err := m.Submit(callCtx, "whoami", func(ctx context.Context) (any, error) {
+		account, ok := contracts.AccountFromContext(ctx)
+		require.True(t, ok)
+		return account.Alias, nil
+	})
+	require.NoError(t, err)
+
+	done := waitForState(t, m, job.ID, jobs.StateSucceeded)
+	require.JSONEq(t, `"staging"`, string(done.Result))
+}
+
+func TestManager_Cancel(t *testing.T) {
+	t.Parallel()
+
+	m := newManager(t, jobs.Options{Workers: 1})
+
+	running, err := m.Submit(t.Context(), "block", func(ctx context.Context) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	require.NoError(t, err)
+	waitForState(t, m, running.ID, jobs.StateRunning)
+
+	pending, err := m.Submit(t.Context(), "never", func(context.Context) (any, error) { return nil, nil })
+	require.NoError(t, err)
+
+	cancelled, err := m.Cancel(pending.ID)
+	require.NoError(t, err)
+	require.Equal(t, jobs.StateCancelled, cancelled.State, "pending jobs cancel immediately")
+
+	_, err = m.Cancel(running.ID)
+	require.NoError(t, err)
+	waitForState(t, m, running.ID, jobs.StateCancelled)
+
+	_, err = m.Cancel(running.ID)
+	require.ErrorIs(t, err, jobs.ErrJobFinished)
+
+	_, err = m.Cancel("job_missing")
+	require.ErrorIs(t, err, jobs.ErrJobNotFound)
+}
+
+func TestManager_PersistsAcrossRestart(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state", "jobs.json")
+
+	first, err := jobs.NewManager(jobs.Options{Path: path})
+	require.NoError(t, err)
+
+	finished, err := first.Submit(t.Context(), "done", func(context.Context) (any, error) { return 42, nil })
+	require.NoError(t, err)
+	waitForState(t, first, finished.ID, jobs.StateSucceeded)
+
+	interrupted, err := first.Submit(t.Context(), "long", func(ctx context.Context) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	require.NoError(t, err)
+	waitForState(t, first, interrupted.ID, jobs.StateRunning)
+	first.Close()
+
+	_, err = first.Submit(t.Context(), "late", func(context.Context) (any, error) { return nil, nil })
+	require.ErrorIs(t, err, jobs.ErrManagerClosed)
+
+	second := newManager(t, jobs.Options{Path: path})
+	restored, err := second.Get(finished.ID)
+	require.NoError(t, err)
+	require.Equal(t, jobs.StateSucceeded, restored.State)
+	require.JSONEq(t, "42", string(restored.Result))
+
+	restored, err = second.Get(interrupted.ID)
+	require.NoError(t, err)
+	require.Equal(t, jobs.StateFailed, restored.State)
+	require.Contains(t, restored.Error, "interrupted")
+	require.Len(t, second.List(), 2)
+}
+
+func TestManager_PrunesFinishedJobs(t *testing.T) {
+	t.Parallel()
+
+	m := newManager(t, jobs.Options{MaxFinished: 2})
+
+	var last jobs.Job
+	for range 4 {
+		job, err := m.Submit(t.Context(), "quick", func(context.Context) (any, error) { return nil, nil })
+		require.NoError(t, err)
+		waitForState(t, m, job.ID, jobs.StateSucceeded)
+		last = job
+	}
+
+	list := m.List()
+	require.Len(t, list, 2)
+	require.Equal(t, last.ID, list[0].ID, "newest job is retained and listed first")
+}
+
+func TestManager_ListAfterPosition(t *testing.T) {
+	t.Parallel()
+
+	m := newManager(t, jobs.Options{})
+	submit := func() {
+		job, err := m.Submit(t.Context(), "quick", func(context.Context) (any, error) { return nil, nil })
+		require.NoError(t, err)
+		waitForState(t, m, job.ID, jobs.StateSucceeded)
+	}
+	ids := func(list []jobs.Job) []string {
+		out := make([]string, 0, len(list))
+		for _, job := range list {
+			out = append(out, job.ID)
+		}
+		return out
+	}
+
+	for range 3 {
+		submit()
+	}
+	list := m.List()
+
+	after, err := m.ListAfter(list[0].Position())
+	require.NoError(t, err)
+	require.Equal(t, ids(list[1:]), ids(after))
+
+	submit()
+	after, err = m.ListAfter(list[0].Position())
+	require.NoError(t, err)
+	require.Equal(t, ids(list[1:]), ids(after), "newer jobs do not shift a position")
+
+	after, err = m.ListAfter(list[2].Position())
+	require.NoError(t, err)
+	require.Empty(t, after)
+
+	_, err = m.ListAfter("not-a-position")
+	require.ErrorIs(t, err, jobs.ErrInvalidPosition)
+}
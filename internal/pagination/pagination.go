@@ -0,0 +1,326 @@
+// Package pagination provides cursor-based pagination for list-style tools.
+//
+// List tools accept the standard "limit" and "cursor" arguments and return a
+// Page whose next_cursor is passed back to fetch the following page. Cursors
+// are opaque to clients: they carry provider page state (offsets, page numbers
+// or continuation tokens) and are signed so they cannot be forged or replayed
+// against a different tool or query.
+package pagination
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// DefaultLimit is the page size used when the caller does not pass a limit.
+	DefaultLimit = 50
+
+	// MaxLimit is the largest page size a caller may request.
+	MaxLimit = 500
+
+	// LimitParam is the standard page size argument.
+	LimitParam = "limit"
+
+	// CursorParam is the standard cursor argument.
+	CursorParam = "cursor"
+
+	// keySize is the length of generated signing keys.
+	keySize = 32
+)
+
+// Static errors for err113 compliance.
+var (
+	ErrInvalidCursor  = errors.New("invalid cursor")
+	ErrCursorMismatch = errors.New("cursor was issued for a different tool or query")
+	ErrInvalidLimit   = errors.New("limit must be a positive integer")
+	ErrNoCodec        = errors.New("pagination is not available in this context")
+)
+
+// State is the provider page state carried inside a cursor. Tools use
+// whichever fields match their upstream API.
+type State struct {
+	// Offset is the number of items already returned, for in-memory lists.
+	Offset int `json:"o,omitempty"`
+
+	// Page is the next provider page number, for page-numbered APIs.
+	Page int `json:"p,omitempty"`
+
+	// Token is the provider continuation token, for token-based APIs.
+	Token string `json:"t,omitempty"`
+
+	// Skip is the number of items to skip within the provider page when a
+	// requested limit ended mid-page.
+	Skip int `json:"s,omitempty"`
+
+	// PageSize is the provider page size Page refers to, so page numbers stay
+	// valid when the caller changes the limit between calls.
+	PageSize int `json:"z,omitempty"`
+}
+
+// Page is the standard result shape of a list tool.
+//
+//nolint:tagliatelle // JSON field names maintain API compatibility with snake_case.
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor"`
+}
+
+// Request is the parsed pagination input of one list call.
+type Request struct {
+	// Limit is the requested page size, bounded by MaxLimit.
+	Limit int
+
+	// State is the decoded cursor state; zero for the first page.
+	State State
+
+	tool        string
+	fingerprint string
+}
+
+// payload is the signed content of a cursor.
+type payload struct {
+	Tool        string `json:"k"`
+	Fingerprint string `json:"f"`
+	State       State  `json:"s"`
+}
+
+// Codec encodes and verifies signed cursors.
+type Codec struct {
+	key []byte
+}
+
+// NewCodec creates a codec signing cursors with key. An empty key selects a
+// random key, so cursors remain valid only for the lifetime of the process.
+func NewCodec(key []byte) (*Codec, error) {
+	if len(key) == 0 {
+		key = make([]byte, keySize)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate cursor key: %w", err)
+		}
+	}
+
+	return &Codec{key: key}, nil
+}
+
+// WithParams adds the standard limit and cursor arguments to a tool definition.
+func WithParams() mcp.ToolOption {
+	return func(tool *mcp.Tool) {
+		mcp.WithNumber(LimitParam,
+			mcp.Description(fmt.Sprintf("Maximum number of items to return (default %d, max %d)", DefaultLimit, MaxLimit)),
+			mcp.Min(1),
+			mcp.Max(MaxLimit),
+		)(tool)
+		mcp.WithString(CursorParam,
+			mcp.Description("Opaque next_cursor value from a previous call, to fetch the following page"),
+		)(tool)
+	}
+}
+
+// Parse reads the limit and cursor arguments of a call to the named tool. The
+// remaining arguments form the query a cursor is bound to, so a cursor cannot
+// be reused after the caller changes filters.
+func (c *Codec) Parse(tool string, args map[string]any) (Request, error) {
+	req := Request{Limit: DefaultLimit, tool: tool, fingerprint: fingerprint(args)}
+
+	if raw, ok := args[LimitParam]; ok && raw != nil {
+		limit, err := parseLimit(raw)
+		if err != nil {
+			return Request{}, err
+		}
+		req.Limit = min(limit, MaxLimit)
+	}
+
+	cursor, _ := args[CursorParam].(string)
+	if cursor == "" {
+		return req, nil
+	}
+
+	decoded, err := c.decode(cursor)
+	if err != nil {
+		return Request{}, err
+	}
+	if decoded.Tool != tool || decoded.Fingerprint != req.fingerprint {
+		return Request{}, ErrCursorMismatch
+	}
+	req.State = decoded.State
+
+	return req, nil
+}
+
+// Next encodes a cursor that resumes the request's query at state.
+func (c *Codec) Next(req Request, state State) string {
+	data, err := json.Marshal(payload{Tool: req.tool, Fingerprint: req.fingerprint, State: state})
+	if err != nil {
+		// payload contains only strings and integers and always marshals.
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data) + "." + base64.RawURLEncoding.EncodeToString(c.sign(data))
+}
+
+// decode verifies and decodes a cursor.
+func (c *Codec) decode(cursor string) (payload, error) {
+	body, signature, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return payload{}, ErrInvalidCursor
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return payload{}, ErrInvalidCursor
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, c.sign(data)) {
+		return payload{}, ErrInvalidCursor
+	}
+
+	var decoded payload
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return payload{}, ErrInvalidCursor
+	}
+
+	return decoded, nil
+}
+
+// sign returns the HMAC-SHA256 of data.
+func (c *Codec) sign(data []byte) []byte {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// Slice paginates an in-memory list using the cursor offset.
+func Slice[T any](c *Codec, req Request, items []T) Page[T] {
+	start := min(max(req.State.Offset, 0), len(items))
+	end := min(start+req.Limit, len(items))
+
+	page := Page[T]{Items: items[start:end]}
+	if end < len(items) {
+		page.NextCursor = c.Next(req, State{Offset: end})
+	}
+
+	return page
+}
+
+// ProviderPage returns the provider page number and page size to fetch for a
+// page-numbered API. The page size is the one the cursor was issued with, or
+// the limit clamped to the range the API accepts.
+func (r Request) ProviderPage(minSize, maxSize int) (int, int) {
+	page := max(r.State.Page, 1)
+	if r.State.PageSize > 0 {
+		return page, r.State.PageSize
+	}
+
+	return page, min(max(r.Limit, minSize), maxSize)
+}
+
+// FromProviderPage paginates one page of a page-numbered API. items is the
+// provider page fetched with ProviderPage, and more reports whether later
+// provider pages exist. When the limit ends mid-page, the next cursor resumes
+// within the same provider page.
+func FromProviderPage[T any](c *Codec, req Request, pageSize int, items []T, more bool) Page[T] {
+	page, _ := req.ProviderPage(pageSize, pageSize)
+	start := min(max(req.State.Skip, 0), len(items))
+	end := min(start+req.Limit, len(items))
+
+	result := Page[T]{Items: items[start:end]}
+	switch {
+	case end < len(items):
+		result.NextCursor = c.Next(req, State{Page: page, Skip: end, PageSize: pageSize})
+	case more:
+		result.NextCursor = c.Next(req, State{Page: page + 1, PageSize: pageSize})
+	}
+
+	return result
+}
+
+// TokenPage returns the provider continuation token and page size to fetch
+// for a token-based API. The page size is the one the cursor was issued with,
+// or the limit clamped to the range the API accepts.
+func (r Request) TokenPage(minSize, maxSize int) (string, int) {
+	if r.State.PageSize > 0 {
+		return r.State.Token, r.State.PageSize
+	}
+
+	return r.State.Token, min(max(r.Limit, minSize), maxSize)
+}
+
+// FromToken paginates a token-based API page. items is the provider page
+// fetched with TokenPage, and next is the provider's continuation token, empty
+// on the last page. When the limit ends mid-page, the next cursor refetches the
+// same provider page and skips what was already returned.
+func FromToken[T any](c *Codec, req Request, pageSize int, items []T, next string) Page[T] {
+	start := min(max(req.State.Skip, 0), len(items))
+	end := min(start+req.Limit, len(items))
+
+	result := Page[T]{Items: items[start:end]}
+	switch {
+	case end < len(items):
+		result.NextCursor = c.Next(req, State{Token: req.State.Token, Skip: end, PageSize: pageSize})
+	case next != "":
+		result.NextCursor = c.Next(req, State{Token: next, PageSize: pageSize})
+	}
+
+	return result
+}
+
+// parseLimit accepts a JSON number or Go integer holding a positive whole number.
+func parseLimit(raw any) (int, error) {
+	switch limit := raw.(type) {
+	case int:
+		if limit >= 1 {
+			return limit, nil
+		}
+	case float64:
+		if limit >= 1 && limit == math.Trunc(limit) && limit <= math.MaxInt32 {
+			return int(limit), nil
+		}
+	}
+
+	return 0, ErrInvalidLimit
+}
+
+// fingerprint hashes the non-pagination arguments of a call.
+func fingerprint(args map[string]any) string {
+	keys := make([]string, 0, len(args))
+	for key := range args {
+		if key != LimitParam && key != CursorParam {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	for _, key := range keys {
+		value, _ := json.Marshal(args[key])
+		fmt.Fprintf(hash, "%s=%s;", key, value)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(hash.Sum(nil)[:12])
+}
+
+// codecKey is the context key for the cursor Codec.
+type codecKey struct{}
+
+// WithCodec returns a copy of ctx carrying the cursor codec.
+func WithCodec(ctx context.Context, c *Codec) context.Context {
+	return context.WithValue(ctx, codecKey{}, c)
+}
+
+// FromContext returns the cursor codec attached to ctx, or nil.
+func FromContext(ctx context.Context) *Codec {
+	c, _ := ctx.Value(codecKey{}).(*Codec)
+	return c
+}
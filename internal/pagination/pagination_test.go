@@ -0,0 +1,181 @@
+package pagination_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chadit/CloudMCP/internal/pagination"
+)
+
+func newCodec(t *testing.T, key string) *pagination.Codec {
+	t.Helper()
+
+	codec, err := pagination.NewCodec([]byte(key))
+	require.NoError(t, err)
+
+	return codec
+}
+
+func TestSlice_WalksAllPages(t *testing.T) {
+	t.Parallel()
+
+	codec := newCodec(t, "test-key")
+	items := []int{1, 2, 3, 4, 5, 6, 7}
+	args := map[string]any{"limit": float64(3), "state": "running"}
+
+	var collected []int
+	pages := 0
+	for {
+		req, err := codec.Parse("things_list", args)
+		require.NoError(t, err)
+
+		page := pagination.Slice(codec, req, items)
+		collected = append(collected, page.Items...)
+		pages++
+
+		if page.NextCursor == "" {
+			break
+		}
+		args["cursor"] = page.NextCursor
+	}
+
+	require.Equal(t, items, collected)
+	require.Equal(t, 3, pages)
+}
+
+func TestParse_Limits(t *testing.T) {
+	t.Parallel()
+
+	codec := newCodec(t, "test-key")
+
+	req, err := codec.Parse("things_list", map[string]any{})
+	require.NoError(t, err)
+	require.Equal(t, pagination.DefaultLimit, req.Limit)
+
+	req, err = codec.Parse("things_list", map[string]any{"limit": float64(10_000)})
+	require.NoError(t, err)
+	require.Equal(t, pagination.MaxLimit, req.Limit, "limit is clamped to the maximum")
+
+	for _, invalid := range []any{float64(0), float64(-1), float64(2.5), "ten"} {
+		_, err = codec.Parse("things_list", map[string]any{"limit": invalid})
+		require.ErrorIs(t, err, pagination.ErrInvalidLimit, "limit %v", invalid)
+	}
+}
+
+func TestParse_RejectsTamperedAndForeignCursors(t *testing.T) {
+	t.Parallel()
+
+	codec := newCodec(t, "test-key")
+	req, err := codec.Parse("things_list", map[string]any{"state": "running"})
+	require.NoError(t, err)
+	cursor := codec.Next(req, pagination.State{Page: 2, Token: "abc"})
+
+	resumed, err := codec.Parse("things_list", map[string]any{"state": "running", "cursor": cursor})
+	require.NoError(t, err)
+	require.Equal(t, pagination.State{Page: 2, Token: "abc"}, resumed.State)
+
+	_, err = codec.Parse("other_list", map[string]any{"state": "running", "cursor": cursor})
+	require.ErrorIs(t, err, pagination.ErrCursorMismatch, "cursor is bound to its tool")
+
+	_, err = codec.Parse("things_list", map[string]any{"state": "failed", "cursor": cursor})
+	require.ErrorIs(t, err, pagination.ErrCursorMismatch, "cursor is bound to its query")
+
+	body, signature, _ := strings.Cut(cursor, ".")
+	forged := body + "x." + signature
+	_, err = codec.Parse("things_list", map[string]any{"state": "running", "cursor": forged})
+	require.ErrorIs(t, err, pagination.ErrInvalidCursor)
+
+	otherKey := newCodec(t, "other-key")
+	_, err = otherKey.Parse("things_list", map[string]any{"state": "running", "cursor": cursor})
+	require.ErrorIs(t, err, pagination.ErrInvalidCursor, "cursors from another key are rejected")
+
+	_, err = codec.Parse("things_list", map[string]any{"cursor": "not-a-cursor"})
+	require.ErrorIs(t, err, pagination.ErrInvalidCursor)
+}
+
+func TestWithParams_AddsStandardArguments(t *testing.T) {
+	t.Parallel()
+
+	tool := mcp.NewTool("things_list", pagination.WithParams())
+
+	require.Contains(t, tool.InputSchema.Properties, pagination.LimitParam)
+	require.Contains(t, tool.InputSchema.Properties, pagination.CursorParam)
+	require.Empty(t, tool.InputSchema.Required, "pagination arguments are optional")
+}
+
+func TestFromProviderPage_WalksPagesWithSmallLimits(t *testing.T) {
+	t.Parallel()
+
+	codec := newCodec(t, "test-key")
+	upstream := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+	const providerMin, providerMax = 5, 100
+
+	args := map[string]any{"limit": float64(3)}
+	var collected []int
+	sizes := map[int]bool{}
+	for range 20 {
+		req, err := codec.Parse("things_list", args)
+		require.NoError(t, err)
+
+		page, size := req.ProviderPage(providerMin, providerMax)
+		sizes[size] = true
+		start := (page - 1) * size
+		end := min(start+size, len(upstream))
+		result := pagination.FromProviderPage(codec, req, size, upstream[start:end], end < len(upstream))
+		require.LessOrEqual(t, len(result.Items), req.Limit)
+		collected = append(collected, result.Items...)
+
+		if result.NextCursor == "" {
+			break
+		}
+		args = map[string]any{"limit": float64(2 + len(collected)%3), "cursor": result.NextCursor}
+	}
+
+	require.Equal(t, upstream, collected, "no items are skipped or repeated when the limit changes")
+	require.Equal(t, map[int]bool{providerMin: true}, sizes, "the provider page size is fixed by the first call")
+}
+
+func TestFromToken_ResumesWithinProviderPages(t *testing.T) {
+	t.Parallel()
+
+	codec := newCodec(t, "test-key")
+	upstream := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}
+
+	// fetch serves upstream in pages addressed by the offset as token
+	fetch := func(token string, size int) ([]int, string) {
+		start := 0
+		if token != "" {
+			start, _ = strconv.Atoi(token)
+		}
+		end := min(start+size, len(upstream))
+		if end == len(upstream) {
+			return upstream[start:end], ""
+		}
+		return upstream[start:end], strconv.Itoa(end)
+	}
+
+	args := map[string]any{"limit": float64(3)}
+	var collected []int
+	for range 20 {
+		req, err := codec.Parse("things_list", args)
+		require.NoError(t, err)
+
+		token, size := req.TokenPage(5, 100)
+		require.Equal(t, 5, size)
+		items, next := fetch(token, size)
+		result := pagination.FromToken(codec, req, size, items, next)
+		require.LessOrEqual(t, len(result.Items), 3)
+		collected = append(collected, result.Items...)
+
+		if result.NextCursor == "" {
+			break
+		}
+		args = map[string]any{"limit": float64(3), "cursor": result.NextCursor}
+	}
+
+	require.Equal(t, upstream, collected)
+}
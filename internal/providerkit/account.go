@@ -0,0 +1,101 @@
+package providerkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	// TokenRefSetting is the provider setting naming the credential used when
+	// a call has no account, as in CLOUD_MCP_LINODE_TOKEN_REF.
+	TokenRefSetting = "token_ref"
+
+	// RegionParam is the standard region argument.
+	RegionParam = "region"
+)
+
+// Static errors for err113 compliance.
+var (
+	ErrNoCredential = errors.New("no credential configured")
+	ErrNoSecrets    = errors.New("credential resolution is not available")
+)
+
+// Credential returns the secret a call authenticates with: the credential of
+// the call's account, otherwise the token_ref setting, otherwise fallbackRef.
+func Credential(ctx context.Context, cfg contracts.ProviderConfig, fallbackRef string) (contracts.Secret, error) {
+	ref := cfg.Setting(TokenRefSetting, fallbackRef)
+	if account, ok := contracts.AccountFromContext(ctx); ok && account.Credential != "" {
+		ref = account.Credential
+	}
+	if ref == "" {
+		return "", ErrNoCredential
+	}
+	if cfg.Secrets == nil {
+		return "", ErrNoSecrets
+	}
+
+	secret, err := cfg.Secrets.Resolve(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve credential %q: %w", ref, err)
+	}
+
+	return secret, nil
+}
+
+// Region returns the region for a call: the region argument, otherwise the
+// account's default region, otherwise fallback.
+func Region(ctx context.Context, request mcp.CallToolRequest, fallback string) string {
+	if region := request.GetString(RegionParam, ""); region != "" {
+		return region
+	}
+	if account, ok := contracts.AccountFromContext(ctx); ok && account.Region != "" {
+		return account.Region
+	}
+
+	return fallback
+}
+
+// DefaultAccountContext attaches the provider's default account to ctx, for
+// calls made outside a tool call such as health checks.
+func DefaultAccountContext(ctx context.Context, cfg contracts.ProviderConfig) context.Context {
+	for _, account := range cfg.Accounts {
+		if account.Default {
+			return contracts.WithAccount(ctx, account)
+		}
+	}
+
+	return ctx
+}
+
+// ParsePage reads the pagination arguments of a list call. Cursors are bound
+// to the call's account as well as its filters, so a cursor cannot be replayed
+// against another account.
+func ParsePage(ctx context.Context, request mcp.CallToolRequest) (*pagination.Codec, pagination.Request, error) {
+	cursors := pagination.FromContext(ctx)
+	if cursors == nil {
+		return nil, pagination.Request{}, pagination.ErrNoCodec
+	}
+
+	args := request.GetArguments()
+	if account, ok := contracts.AccountFromContext(ctx); ok {
+		args = maps.Clone(args)
+		if args == nil {
+			args = map[string]any{}
+		}
+		args[contracts.AccountParam] = account.Alias
+	}
+
+	req, err := cursors.Parse(request.Params.Name, args)
+	if err != nil {
+		return nil, pagination.Request{}, err
+	}
+
+	return cursors, req, nil
+}
@@ -0,0 +1,135 @@
+package providerkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/jobs"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	// DefaultPollInterval is the delay between two polls of a provider action.
+	DefaultPollInterval = 2 * time.Second
+
+	// PollIntervalSetting overrides DefaultPollInterval, as in
+	// CLOUD_MCP_DIGITALOCEAN_POLL_INTERVAL=5s.
+	PollIntervalSetting = "poll_interval"
+
+	// WaitParam is the standard argument choosing whether a mutating tool
+	// waits for the provider action it started.
+	WaitParam = "wait"
+)
+
+// ErrActionFailed is returned when a provider action finishes unsuccessfully.
+var ErrActionFailed = errors.New("action failed")
+
+// ActionStatus is a snapshot of a long-running provider action.
+type ActionStatus struct {
+	// Done reports whether the action has finished, successfully or not.
+	Done bool
+
+	// Failed reports whether a finished action was unsuccessful.
+	Failed bool
+
+	// Percent is the completed percentage, or zero if the provider does not
+	// report one.
+	Percent float64
+
+	// Message describes the action's state.
+	Message string
+}
+
+// PollFunc fetches the current status of an action.
+type PollFunc func(ctx context.Context) (ActionStatus, error)
+
+// PollInterval returns the poll_interval setting, or DefaultPollInterval.
+func PollInterval(cfg contracts.ProviderConfig) time.Duration {
+	interval, err := time.ParseDuration(cfg.Setting(PollIntervalSetting, ""))
+	if err != nil || interval <= 0 {
+		return DefaultPollInterval
+	}
+
+	return interval
+}
+
+// WithWaitParam adds the standard optional wait argument, and the background
+// argument that moves the wait into a background job.
+func WithWaitParam() mcp.ToolOption {
+	wait := mcp.WithBoolean(WaitParam,
+		mcp.Description("Wait for the action to finish, reporting progress (default true)"),
+		mcp.DefaultBool(true),
+	)
+	background := jobs.WithParam()
+
+	return func(tool *mcp.Tool) {
+		wait(tool)
+		background(tool)
+	}
+}
+
+// Wait polls an action every interval until it finishes or ctx is done,
+// reporting each status through the call's progress reporter. A failed action
+// returns its final status with ErrActionFailed.
+func Wait(ctx context.Context, interval time.Duration, poll PollFunc) (ActionStatus, error) {
+	progress := contracts.ProgressFromContext(ctx)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for polls := 1; ; polls++ {
+		select {
+		case <-ctx.Done():
+			return ActionStatus{}, fmt.Errorf("stopped waiting for action: %w", ctx.Err())
+		case <-timer.C:
+		}
+
+		status, err := poll(ctx)
+		if err != nil {
+			return ActionStatus{}, err
+		}
+
+		switch {
+		case status.Done:
+			progress.Report(100, 100, status.Message)
+		case status.Percent > 0:
+			progress.Report(status.Percent, 100, status.Message)
+		default:
+			// Without a percentage, report the poll count as indeterminate progress
+			progress.Report(float64(polls), 0, status.Message)
+		}
+
+		if status.Done {
+			if status.Failed {
+				return status, fmt.Errorf("%w: %s", ErrActionFailed, status.Message)
+			}
+			return status, nil
+		}
+
+		timer.Reset(interval)
+	}
+}
+
+// CreatedResult returns a resource a tool has created as a successful result.
+// An error from the steps after the create, such as waiting for the resource
+// to become ready, is reported next to the resource instead of as an error
+// result: the resource exists either way, and an error result would hide its
+// ID from the caller and from the idempotency guard, so a retry would create
+// a duplicate.
+func CreatedResult(resource any, err error) (*mcp.CallToolResult, error) {
+	if err != nil {
+		return format.NewResult(createdResource{Resource: resource, Error: err.Error()})
+	}
+
+	return format.NewResult(resource)
+}
+
+// createdResource is a created resource whose follow-up steps failed.
+type createdResource struct {
+	Resource any    `json:"resource"`
+	Error    string `json:"error"`
+}
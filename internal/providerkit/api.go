@@ -0,0 +1,261 @@
+// Package providerkit holds the building blocks shared by cloud provider
+// implementations: a JSON API client with uniform error mapping, credential
+// and region resolution for the account a call runs against, and an adapter
+// turning MCP tool definitions into contracts.Tool values.
+package providerkit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+// maxResponseBytes bounds how much of an API response is read.
+const maxResponseBytes = 32 << 20
+
+// Static errors for err113 compliance. API errors wrap one of these according
+// to their HTTP status, so tools can handle them uniformly across providers.
+var (
+	ErrInvalidRequest = errors.New("invalid request")
+	ErrUnauthorized   = errors.New("authentication failed, check the account's credential")
+	ErrForbidden      = errors.New("permission denied")
+	ErrNotFound       = errors.New("not found")
+	ErrConflict       = errors.New("conflict")
+	ErrRateLimited    = errors.New("rate limited by the provider")
+	ErrUpstream       = errors.New("provider API error")
+)
+
+// APIError is an error response from a provider API.
+type APIError struct {
+	Provider string
+	Status   int
+	Message  string
+}
+
+// Error implements error.
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s API: %v (HTTP %d)", e.Provider, e.Unwrap(), e.Status)
+	}
+
+	return fmt.Sprintf("%s API: %v (HTTP %d): %s", e.Provider, e.Unwrap(), e.Status, e.Message)
+}
+
+// Unwrap returns the sentinel error matching the status.
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.Status == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case e.Status == http.StatusForbidden:
+		return ErrForbidden
+	case e.Status == http.StatusNotFound:
+		return ErrNotFound
+	case e.Status == http.StatusConflict:
+		return ErrConflict
+	case e.Status == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.Status >= http.StatusBadRequest && e.Status < http.StatusInternalServerError:
+		return ErrInvalidRequest
+	default:
+		return ErrUpstream
+	}
+}
+
+// API is a client for a JSON REST API.
+type API struct {
+	// Provider names the provider in error messages.
+	Provider string
+
+	// BaseURL is prefixed to request paths.
+	BaseURL string
+
+	// HTTP sends requests. It is normally the shared resilient client.
+	HTTP *http.Client
+
+	// Authorize adds credentials to a request. It may be nil for public APIs.
+	Authorize func(ctx context.Context, req *http.Request) error
+
+	// ErrorMessage extracts a readable message from an error response body.
+	// Nil uses the trimmed body.
+	ErrorMessage func(body []byte) string
+}
+
+// TokenAPI describes a REST API authenticated with a token per account, such
+// as a bearer token.
+type TokenAPI struct {
+	// Provider names the provider in error messages.
+	Provider string
+
+	// DefaultURL is the API endpoint, overridden by the api_url setting.
+	DefaultURL string
+
+	// DefaultTokenRef is the credential used when neither the call's account
+	// nor the token_ref setting names one.
+	DefaultTokenRef string
+
+	// Header carries the token as is. Empty sends it as a bearer token in the
+	// Authorization header.
+	Header string
+
+	// ErrorMessage extracts a readable message from an error response body.
+	ErrorMessage func(body []byte) string
+}
+
+// NewTokenAPI returns a client for spec using the provider's HTTP client, or
+// http.DefaultClient. The token is resolved on every request from the call's
+// account, so each account can use its own.
+func NewTokenAPI(cfg contracts.ProviderConfig, spec TokenAPI) *API {
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &API{
+		Provider: spec.Provider,
+		BaseURL:  cfg.Setting("api_url", spec.DefaultURL),
+		HTTP:     httpClient,
+		Authorize: func(ctx context.Context, req *http.Request) error {
+			token, err := Credential(ctx, cfg, spec.DefaultTokenRef)
+			if err != nil {
+				return fmt.Errorf("%s: %w", spec.Provider, err)
+			}
+			if spec.Header != "" {
+				req.Header.Set(spec.Header, token.Reveal())
+			} else {
+				req.Header.Set("Authorization", "Bearer "+token.Reveal())
+			}
+
+			return nil
+		},
+		ErrorMessage: spec.ErrorMessage,
+	}
+}
+
+// Call describes one API request.
+type Call struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+
+	// Body is encoded as JSON when not nil. It is sent as application/json
+	// unless Header sets another JSON content type, such as a patch format.
+	Body any
+
+	// Out receives the decoded JSON response when not nil.
+	Out any
+
+	// Status receives the response status code when not nil, for APIs that
+	// signal progress with 202 Accepted.
+	Status *int
+
+	// Raw receives the undecoded body of a successful response when not nil,
+	// for responses that may not be JSON.
+	Raw *[]byte
+
+	// Stream reads the body of a successful response as it arrives when not
+	// nil, for long responses such as progress streams. Its error is returned
+	// as is.
+	Stream func(body io.Reader) error
+}
+
+// Do sends a request and decodes the JSON response into out.
+func (a *API) Do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	_, err := a.Call(ctx, Call{Method: method, Path: path, Query: query, Body: body, Out: out})
+	return err
+}
+
+// Call sends a request, decodes the JSON response into call.Out and returns
+// the response headers. Error statuses return an *APIError.
+func (a *API) Call(ctx context.Context, call Call) (http.Header, error) {
+	target := strings.TrimRight(a.BaseURL, "/") + call.Path
+	if len(call.Query) > 0 {
+		target += "?" + call.Query.Encode()
+	}
+
+	var body io.Reader
+	if call.Body != nil {
+		data, err := json.Marshal(call.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s request: %w", a.Provider, err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, call.Method, target, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s request: %w", a.Provider, err)
+	}
+	for key, values := range call.Header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	if call.Body != nil && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if a.Authorize != nil {
+		if err := a.Authorize(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := a.HTTP.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s API request failed: %w", a.Provider, err)
+	}
+	defer resp.Body.Close()
+
+	if call.Stream != nil && resp.StatusCode < http.StatusBadRequest {
+		if call.Status != nil {
+			*call.Status = resp.StatusCode
+		}
+		return resp.Header, call.Stream(resp.Body)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s response: %w", a.Provider, err)
+	}
+
+	if call.Status != nil {
+		*call.Status = resp.StatusCode
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return resp.Header, &APIError{Provider: a.Provider, Status: resp.StatusCode, Message: a.errorMessage(data)}
+	}
+
+	if call.Raw != nil {
+		*call.Raw = data
+	}
+	if call.Out != nil && len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, call.Out); err != nil {
+			return resp.Header, fmt.Errorf("failed to decode %s response: %w", a.Provider, err)
+		}
+	}
+
+	return resp.Header, nil
+}
+
+// errorMessage extracts the message of an error response.
+func (a *API) errorMessage(body []byte) string {
+	if a.ErrorMessage != nil {
+		if message := a.ErrorMessage(body); message != "" {
+			return message
+		}
+	}
+
+	message := strings.TrimSpace(string(body))
+	if len(message) > 500 {
+		message = message[:500] + "..."
+	}
+
+	return message
+}
@@ -0,0 +1,33 @@
+package providerkit
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ConfirmParam is the standard argument a destructive tool requires to repeat
+// the name of the resource it acts on.
+const ConfirmParam = "confirm"
+
+// WithConfirmParam adds the standard required confirm argument. what names
+// the value to repeat, such as "the object key".
+func WithConfirmParam(what string) mcp.ToolOption {
+	return mcp.WithString(ConfirmParam, mcp.Required(),
+		mcp.Description(fmt.Sprintf("Repeat %s to confirm the call", what)))
+}
+
+// Confirm checks the confirm argument of a destructive call against the names
+// of the resource it resolved to. Tools pick resources by ID or by a name the
+// model may have mixed up, so repeating the name makes the model state which
+// resource it means before anything irreversible happens. When the argument
+// matches none of names, Confirm returns an error result naming names[0] and
+// saying the call was not verb, such as "deleted"; otherwise it returns nil.
+func Confirm(request mcp.CallToolRequest, verb, what string, names ...string) *mcp.CallToolResult {
+	if confirm := request.GetString(ConfirmParam, ""); confirm != "" && slices.Contains(names, confirm) {
+		return nil
+	}
+
+	return mcp.NewToolResultError(fmt.Sprintf("not %s: %s must be %s %q", verb, ConfirmParam, what, names[0]))
+}
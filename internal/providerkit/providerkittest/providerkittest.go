@@ -0,0 +1,163 @@
+// Package providerkittest provides fixtures shared by provider tests: a secret
+// resolver backed by a map, a progress recorder, a stub API server, and
+// helpers to initialize providers, build call contexts and call tools.
+package providerkittest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+// cursorKey signs the pagination cursors of test calls.
+const cursorKey = "test-key"
+
+// Secrets resolves credential references from a map.
+type Secrets map[string]string
+
+// Resolve returns the secret for ref, or ErrNoCredential.
+func (s Secrets) Resolve(_ context.Context, ref string) (contracts.Secret, error) {
+	value, ok := s[ref]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", providerkit.ErrNoCredential, ref)
+	}
+
+	return contracts.Secret(value), nil
+}
+
+// Update is a recorded progress update.
+type Update struct {
+	Progress float64
+	Total    float64
+	Message  string
+}
+
+// Progress records the progress updates reported by a tool.
+type Progress struct {
+	mu      sync.Mutex
+	updates []Update
+}
+
+// Report records an update.
+func (p *Progress) Report(progress, total float64, message string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.updates = append(p.updates, Update{Progress: progress, Total: total, Message: message})
+}
+
+// Updates returns the recorded updates in order.
+func (p *Progress) Updates() []Update {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]Update(nil), p.updates...)
+}
+
+// Values returns the progress of each recorded update.
+func (p *Progress) Values() []float64 {
+	var values []float64
+	for _, update := range p.Updates() {
+		values = append(values, update.Progress)
+	}
+
+	return values
+}
+
+// Messages returns the message of each recorded update.
+func (p *Progress) Messages() []string {
+	var messages []string
+	for _, update := range p.Updates() {
+		messages = append(messages, update.Message)
+	}
+
+	return messages
+}
+
+// CallContext returns a tool call context with a cursor codec and no account.
+func CallContext(t testing.TB) context.Context {
+	t.Helper()
+
+	codec, err := pagination.NewCodec([]byte(cursorKey))
+	require.NoError(t, err)
+
+	return pagination.WithCodec(t.Context(), codec)
+}
+
+// AccountContext returns a tool call context for account.
+func AccountContext(t testing.TB, account contracts.Account) context.Context {
+	t.Helper()
+
+	return contracts.WithAccount(CallContext(t), account)
+}
+
+// Serve starts a test server for a stub API, closed when the test ends.
+func Serve(t testing.TB, handler http.Handler) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+// WriteJSON writes v as a JSON response with status.
+func WriteJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// Setup initializes provider with cfg and returns its tools by name.
+func Setup(t testing.TB, provider contracts.Provider, cfg contracts.ProviderConfig) map[string]contracts.Tool {
+	t.Helper()
+
+	require.NoError(t, provider.Initialize(t.Context(), cfg))
+
+	return Tools(provider)
+}
+
+// Tools returns a provider's tools by name.
+func Tools(provider contracts.Provider) map[string]contracts.Tool {
+	tools := make(map[string]contracts.Tool)
+	for _, tool := range provider.Tools() {
+		tools[tool.Name()] = tool
+	}
+
+	return tools
+}
+
+// Call executes a tool and returns its result text and error flag.
+func Call(ctx context.Context, t testing.TB, tool contracts.Tool, params map[string]any) (string, bool) {
+	t.Helper()
+
+	result, err := tool.Execute(ctx, params)
+	require.NoError(t, err)
+	require.Len(t, result.Content, 1)
+	text, ok := result.Content[0].(mcp.TextContent)
+	require.True(t, ok)
+
+	return text.Text, result.IsError
+}
+
+// CallOK executes a tool and returns its result text, failing the test on an
+// error result.
+func CallOK(ctx context.Context, t testing.TB, tool contracts.Tool, params map[string]any) string {
+	t.Helper()
+
+	text, isError := Call(ctx, t, tool, params)
+	require.False(t, isError, text)
+
+	return text
+}
@@ -0,0 +1,56 @@
+package providerkit
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+)
+
+// Handler handles a call to a provider tool.
+type Handler func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+
+// Tool adapts an MCP tool definition and handler to contracts.Tool. The full
+// definition, including annotations, is advertised to clients.
+type Tool struct {
+	definition mcp.Tool
+	handler    Handler
+}
+
+// NewTool creates a provider tool.
+func NewTool(definition mcp.Tool, handler Handler) *Tool {
+	return &Tool{definition: definition, handler: handler}
+}
+
+// Name returns the tool name.
+func (t *Tool) Name() string { return t.definition.Name }
+
+// Description returns the tool description.
+func (t *Tool) Description() string { return t.definition.Description }
+
+// InputSchema returns the tool's input schema.
+func (t *Tool) InputSchema() any { return t.definition.InputSchema }
+
+// Definition returns the complete MCP tool definition.
+func (t *Tool) Definition() mcp.Tool { return t.definition }
+
+// Execute calls the handler with the arguments as a tools/call request.
+func (t *Tool) Execute(ctx context.Context, params map[string]any) (*mcp.CallToolResult, error) {
+	request := mcp.CallToolRequest{}
+	request.Params.Name = t.definition.Name
+	request.Params.Arguments = params
+
+	return t.handler(ctx, request)
+}
+
+// Result returns structured data as a tool result, or the error as an error
+// result. Provider errors are reported to the model rather than failing the
+// protocol request.
+func Result(data any, err error) (*mcp.CallToolResult, error) {
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return format.NewResult(data)
+}
@@ -0,0 +1,119 @@
+// Package aws implements the AWS provider: EC2 instances, AMIs, security
+// groups, EBS volumes and key pairs over the EC2 query API, signed natively
+// with SigV4.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/awsauth"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	// Name is the provider name used in settings and accounts.
+	Name = "aws"
+
+	// DefaultRegion is used when neither the call, the account, the settings
+	// nor the AWS environment name a region.
+	DefaultRegion = awsauth.DefaultRegion
+
+	// ProfilePrefix marks an account credential naming a shared config
+	// profile rather than a stored secret, as in "profile:prod".
+	ProfilePrefix = awsauth.ProfilePrefix
+)
+
+// Provider is the AWS provider.
+type Provider struct {
+	cfg  contracts.ProviderConfig
+	http *http.Client
+	auth awsauth.Resolver
+}
+
+// New creates an uninitialized AWS provider.
+func New() contracts.Provider {
+	return &Provider{}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return Name
+}
+
+// Initialize stores the configuration. Credentials are resolved per call, so
+// each account can use its own keys or profile.
+func (p *Provider) Initialize(_ context.Context, cfg contracts.ProviderConfig) error {
+	p.cfg = cfg
+	p.auth = awsauth.Resolver{Config: cfg}
+	p.http = cfg.HTTPClient
+	if p.http == nil {
+		p.http = http.DefaultClient
+	}
+
+	return nil
+}
+
+// Tools returns the AWS tools.
+func (p *Provider) Tools() []contracts.Tool {
+	return []contracts.Tool{
+		p.listInstancesTool(),
+		p.getInstanceTool(),
+		p.runInstancesTool(),
+		p.instanceStateTool("start", "StartInstances", "Starts stopped EC2 instances", false),
+		p.instanceStateTool("stop", "StopInstances", "Stops running EC2 instances", true),
+		p.instanceStateTool("reboot", "RebootInstances", "Reboots EC2 instances", true),
+		p.instanceStateTool("terminate", "TerminateInstances", "Terminates EC2 instances and deletes their instance-store and delete-on-termination volumes. This cannot be undone.", true),
+		p.listImagesTool(),
+		p.listSecurityGroupsTool(),
+		p.createSecurityGroupTool(),
+		p.ingressTool("authorize", "AuthorizeSecurityGroupIngress", "Allows inbound traffic to a security group"),
+		p.ingressTool("revoke", "RevokeSecurityGroupIngress", "Removes an inbound rule from a security group"),
+		p.deleteSecurityGroupTool(),
+		p.listVolumesTool(),
+		p.createVolumeTool(),
+		p.attachVolumeTool(),
+		p.detachVolumeTool(),
+		p.deleteVolumeTool(),
+		p.listKeyPairsTool(),
+		p.importKeyPairTool(),
+		p.deleteKeyPairTool(),
+	}
+}
+
+// HealthCheck verifies the default account's credentials with DescribeRegions.
+func (p *Provider) HealthCheck(ctx context.Context) error {
+	ctx = providerkit.DefaultAccountContext(ctx, p.cfg)
+
+	return p.ec2(ctx, p.region(ctx, mcp.CallToolRequest{}), "DescribeRegions", url.Values{}, nil)
+}
+
+// Shutdown has nothing to release.
+func (p *Provider) Shutdown(context.Context) error {
+	return nil
+}
+
+// credentials resolves the keys for a call.
+func (p *Provider) credentials(ctx context.Context) (awsauth.Credentials, error) {
+	creds, err := p.auth.Credentials(ctx)
+	if err != nil {
+		return awsauth.Credentials{}, fmt.Errorf("aws: %w", err)
+	}
+
+	return creds, nil
+}
+
+// region returns the region for a call.
+func (p *Provider) region(ctx context.Context, request mcp.CallToolRequest) string {
+	return p.auth.Region(ctx, request)
+}
+
+// withRegion adds the optional region argument.
+func withRegion() mcp.ToolOption {
+	return mcp.WithString(providerkit.RegionParam, mcp.Description("AWS region, such as eu-west-1 (default: the account's region)"))
+}
@@ -0,0 +1,273 @@
+package aws_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chadit/CloudMCP/internal/awsauth"
+	"github.com/chadit/CloudMCP/internal/idempotency"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/internal/providerkit/providerkittest"
+	"github.com/chadit/CloudMCP/internal/providers/aws"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	accessKeyID     = "AKIDEXAMPLE"
+	secretAccessKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+)
+
+// fakeEC2 is a stub of the EC2 query API. It rejects requests whose SigV4
+// signature does not match the one computed with the test credentials.
+type fakeEC2 struct {
+	mu        sync.Mutex
+	instances int
+	params    map[string]url.Values
+	regions   []string
+}
+
+func (f *fakeEC2) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	body, _ := io.ReadAll(r.Body)
+	if err := verifySignature(r, body); err != nil {
+		writeError(w, http.StatusForbidden, "SignatureDoesNotMatch", err.Error())
+		return
+	}
+	params, _ := url.ParseQuery(string(body))
+	action := params.Get("Action")
+	f.params[action] = params
+	f.regions = append(f.regions, strings.Split(r.Header.Get("Authorization"), "/")[2])
+
+	switch action {
+	case "DescribeInstances":
+		if id := params.Get("InstanceId.1"); id != "" {
+			if id == "i-missing" {
+				writeError(w, http.StatusBadRequest, "InvalidInstanceID.NotFound", "The instance ID 'i-missing' does not exist")
+				return
+			}
+			fmt.Fprintf(w, "<DescribeInstancesResponse><reservationSet><item><instancesSet><item><instanceId>%s</instanceId></item></instancesSet></item></reservationSet></DescribeInstancesResponse>", id)
+			return
+		}
+		start, _ := strconv.Atoi(params.Get("NextToken"))
+		size, _ := strconv.Atoi(params.Get("MaxResults"))
+		end := min(start+size, f.instances)
+		var items strings.Builder
+		for i := start; i < end; i++ {
+			fmt.Fprintf(&items, "<item><instanceId>i-%d</instanceId><instanceState><name>running</name></instanceState></item>", i)
+		}
+		next := ""
+		if end < f.instances {
+			next = "<nextToken>" + strconv.Itoa(end) + "</nextToken>"
+		}
+		fmt.Fprintf(w, "<DescribeInstancesResponse><reservationSet><item><instancesSet>%s</instancesSet></item></reservationSet>%s</DescribeInstancesResponse>", items.String(), next)
+	case "RunInstances":
+		fmt.Fprint(w, "<RunInstancesResponse><instancesSet><item><instanceId>i-new</instanceId><instanceState><name>pending</name></instanceState></item></instancesSet></RunInstancesResponse>")
+	case "StopInstances":
+		fmt.Fprint(w, "<StopInstancesResponse><instancesSet><item><instanceId>i-1</instanceId><currentState><name>stopping</name></currentState><previousState><name>running</name></previousState></item></instancesSet></StopInstancesResponse>")
+	case "DescribeRegions":
+		fmt.Fprint(w, "<DescribeRegionsResponse><regionInfo/></DescribeRegionsResponse>")
+	default:
+		writeError(w, http.StatusBadRequest, "InvalidAction", "The action "+action+" is not valid for this web service.")
+	}
+}
+
+// verifySignature re-signs the request with the test credentials at the time
+// it claims to have been signed, copying only the headers it signed.
+func verifySignature(r *http.Request, body []byte) error {
+	authorization := r.Header.Get("Authorization")
+	_, signedPart, ok := strings.Cut(authorization, "SignedHeaders=")
+	if !ok {
+		return fmt.Errorf("missing SignedHeaders in %q", authorization)
+	}
+	signedHeaders, _, _ := strings.Cut(signedPart, ",")
+	region := strings.Split(authorization, "/")[2]
+
+	signed, err := time.Parse("20060102T150405Z", r.Header.Get("X-Amz-Date"))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(r.Method, "http://"+r.Host+r.URL.RequestURI(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for _, name := range strings.Split(signedHeaders, ";") {
+		if name != "host" && name != "x-amz-date" {
+			req.Header.Set(name, r.Header.Get(name))
+		}
+	}
+
+	signer := awsauth.Signer{
+		Credentials: awsauth.Credentials{AccessKeyID: accessKeyID, SecretAccessKey: secretAccessKey},
+		Region:      region,
+		Service:     "ec2",
+		Now:         func() time.Time { return signed },
+	}
+	if err := signer.Sign(req); err != nil {
+		return err
+	}
+	if want := req.Header.Get("Authorization"); want != authorization {
+		return fmt.Errorf("signature mismatch: got %q, want %q", authorization, want)
+	}
+
+	return nil
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Response><Errors><Error><Code>%s</Code><Message>%s</Message></Error></Errors><RequestID>req-1</RequestID></Response>", code, message)
+}
+
+// setup starts a stub EC2 endpoint and returns the provider and its tools by name.
+func setup(t *testing.T, instances int) (*fakeEC2, contracts.Provider, map[string]contracts.Tool) {
+	t.Helper()
+
+	api := &fakeEC2{instances: instances, params: make(map[string]url.Values)}
+	srv := providerkittest.Serve(t, api)
+
+	provider := aws.New()
+	tools := providerkittest.Setup(t, provider, contracts.ProviderConfig{
+		Settings:   map[string]string{"ec2_endpoint": srv.URL, "region": "eu-west-1"},
+		HTTPClient: srv.Client(),
+		Secrets: providerkittest.Secrets{
+			"aws-prod":  accessKeyID + ":" + secretAccessKey,
+			"aws-stale": accessKeyID + ":revoked",
+		},
+		Accounts: []contracts.Account{{Provider: aws.Name, Alias: "prod", Credential: "aws-prod", Default: true}},
+	})
+
+	return api, provider, tools
+}
+
+var prod = contracts.Account{Provider: aws.Name, Alias: "prod", Credential: "aws-prod"}
+
+func TestProvider_HealthCheckUsesDefaultAccount(t *testing.T) {
+	t.Parallel()
+
+	api, provider, tools := setup(t, 0)
+	require.NoError(t, provider.HealthCheck(t.Context()))
+	require.Equal(t, []string{"eu-west-1"}, api.regions)
+	require.Len(t, tools, 21)
+}
+
+func TestInstancesList_PaginatesWithNextToken(t *testing.T) {
+	t.Parallel()
+
+	api, _, tools := setup(t, 12)
+	ctx := providerkittest.AccountContext(t, prod)
+
+	var ids []string
+	params := map[string]any{"limit": float64(4), "state": "running"}
+	for {
+		text, isError := providerkittest.Call(ctx, t, tools["aws_ec2_instances_list"], params)
+		require.False(t, isError, text)
+
+		var page pagination.Page[aws.Instance]
+		require.NoError(t, json.Unmarshal([]byte(text), &page))
+		for _, instance := range page.Items {
+			ids = append(ids, instance.ID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		params["cursor"] = page.NextCursor
+	}
+
+	require.Len(t, ids, 12)
+	require.Equal(t, "i-0", ids[0])
+	require.Equal(t, "i-11", ids[11])
+	require.Equal(t, "instance-state-name", api.params["DescribeInstances"].Get("Filter.1.Name"))
+	require.Equal(t, "5", api.params["DescribeInstances"].Get("MaxResults"), "EC2 needs at least 5 results per page")
+}
+
+func TestRunInstances_ParamsAndClientToken(t *testing.T) {
+	t.Parallel()
+
+	api, _, tools := setup(t, 0)
+	ctx := idempotency.WithKey(providerkittest.AccountContext(t, prod), "launch-web-1")
+
+	text, isError := providerkittest.Call(ctx, t, tools["aws_ec2_instances_run"], map[string]any{
+		"image_id": "ami-123", "instance_type": "t3.micro", "name": "web",
+		"security_group_ids": []any{"sg-1", "sg-2"}, "user_data": "#!/bin/sh", "region": "us-west-2",
+	})
+	require.False(t, isError, text)
+	require.Contains(t, text, "i-new")
+
+	params := api.params["RunInstances"]
+	require.Equal(t, "launch-web-1", params.Get("ClientToken"))
+	require.Equal(t, "1", params.Get("MinCount"))
+	require.Equal(t, "sg-2", params.Get("SecurityGroupId.2"))
+	require.Equal(t, "IyEvYmluL3No", params.Get("UserData"))
+	require.Equal(t, "Name", params.Get("TagSpecification.1.Tag.1.Key"))
+	require.Equal(t, "us-west-2", api.regions[0], "the region argument selects the signing region")
+
+	text, isError = providerkittest.Call(ctx, t, tools["aws_ec2_instances_stop"], map[string]any{"instance_ids": []any{"i-1"}, "force": true})
+	require.False(t, isError, text)
+	require.Contains(t, text, `"current_state": "stopping"`)
+	require.Equal(t, "true", api.params["StopInstances"].Get("Force"))
+}
+
+func TestErrors_MappedFromCodes(t *testing.T) {
+	t.Parallel()
+
+	_, _, tools := setup(t, 0)
+
+	text, isError := providerkittest.Call(providerkittest.AccountContext(t, prod), t, tools["aws_ec2_instance_get"], map[string]any{"instance_id": "i-missing"})
+	require.True(t, isError)
+	require.Contains(t, text, providerkit.ErrNotFound.Error())
+	require.Contains(t, text, "InvalidInstanceID.NotFound")
+
+	text, isError = providerkittest.Call(providerkittest.AccountContext(t, prod), t, tools["aws_ec2_images_list"], map[string]any{})
+	require.True(t, isError)
+	require.Contains(t, text, "InvalidAction")
+
+	stale := contracts.Account{Provider: aws.Name, Alias: "stale", Credential: "aws-stale"}
+	text, isError = providerkittest.Call(providerkittest.AccountContext(t, stale), t, tools["aws_ec2_instance_get"], map[string]any{"instance_id": "i-1"})
+	require.True(t, isError)
+	require.Contains(t, text, providerkit.ErrUnauthorized.Error())
+}
+
+//nolint:paralleltest // Sets environment variables.
+func TestCredentials_FromProfileAccount(t *testing.T) {
+	dir := t.TempDir()
+	credentials := filepath.Join(dir, "credentials")
+	require.NoError(t, os.WriteFile(credentials, []byte(fmt.Sprintf(
+		"[default]\naws_access_key_id = AKIDWRONG\naws_secret_access_key = wrong\n\n[ops]\naws_access_key_id = %s\naws_secret_access_key = %s\n",
+		accessKeyID, secretAccessKey)), 0o600))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", credentials)
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_PROFILE", "")
+
+	_, _, tools := setup(t, 1)
+
+	ops := contracts.Account{Provider: aws.Name, Alias: "ops", Credential: aws.ProfilePrefix + "ops"}
+	text, isError := providerkittest.Call(providerkittest.AccountContext(t, ops), t, tools["aws_ec2_instance_get"], map[string]any{"instance_id": "i-0"})
+	require.False(t, isError, text)
+
+	unknown := contracts.Account{Provider: aws.Name, Alias: "dev", Credential: aws.ProfilePrefix + "dev"}
+	text, isError = providerkittest.Call(providerkittest.AccountContext(t, unknown), t, tools["aws_ec2_instance_get"], map[string]any{"instance_id": "i-0"})
+	require.True(t, isError)
+	require.Contains(t, text, awsauth.ErrProfileNotFound.Error())
+
+	defaultProfile := contracts.Account{Provider: aws.Name, Alias: "default"}
+	text, isError = providerkittest.Call(providerkittest.AccountContext(t, defaultProfile), t, tools["aws_ec2_instance_get"], map[string]any{"instance_id": "i-0"})
+	require.True(t, isError)
+	require.Contains(t, text, "SignatureDoesNotMatch", "the default profile holds other keys")
+}
@@ -0,0 +1,312 @@
+package aws
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/idempotency"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+)
+
+// Tag is an EC2 resource tag.
+type Tag struct {
+	Key   string `json:"key"   xml:"key"`
+	Value string `json:"value" xml:"value"`
+}
+
+// GroupRef refers to a security group.
+//
+//nolint:tagliatelle // XML element names match the EC2 API.
+type GroupRef struct {
+	ID   string `json:"id"   xml:"groupId"`
+	Name string `json:"name" xml:"groupName"`
+}
+
+// Instance is an EC2 instance.
+//
+//nolint:tagliatelle // XML element names match the EC2 API.
+type Instance struct {
+	ID               string     `json:"id"                          xml:"instanceId"`
+	State            string     `json:"state"                       xml:"instanceState>name"`
+	Type             string     `json:"type"                        xml:"instanceType"`
+	ImageID          string     `json:"image_id"                    xml:"imageId"`
+	KeyName          string     `json:"key_name,omitempty"          xml:"keyName"`
+	AvailabilityZone string     `json:"availability_zone"           xml:"placement>availabilityZone"`
+	PrivateIP        string     `json:"private_ip,omitempty"        xml:"privateIpAddress"`
+	PublicIP         string     `json:"public_ip,omitempty"         xml:"ipAddress"`
+	VPCID            string     `json:"vpc_id,omitempty"            xml:"vpcId"`
+	SubnetID         string     `json:"subnet_id,omitempty"         xml:"subnetId"`
+	SecurityGroups   []GroupRef `json:"security_groups"             xml:"groupSet>item"`
+	LaunchTime       string     `json:"launch_time"                 xml:"launchTime"`
+	Tags             []Tag      `json:"tags"                        xml:"tagSet>item"`
+}
+
+// describeInstancesResponse is the body of DescribeInstances.
+//
+//nolint:tagliatelle // XML element names match the EC2 API.
+type describeInstancesResponse struct {
+	Reservations []struct {
+		Instances []Instance `xml:"instancesSet>item"`
+	} `xml:"reservationSet>item"`
+	NextToken string `xml:"nextToken"`
+}
+
+// instances flattens the reservations of a DescribeInstances response.
+func (r describeInstancesResponse) instances() []Instance {
+	instances := []Instance{}
+	for _, reservation := range r.Reservations {
+		instances = append(instances, reservation.Instances...)
+	}
+
+	return instances
+}
+
+// StateChange is an instance state transition.
+//
+//nolint:tagliatelle // XML element names match the EC2 API.
+type StateChange struct {
+	InstanceID    string `json:"instance_id"    xml:"instanceId"`
+	PreviousState string `json:"previous_state" xml:"previousState>name"`
+	CurrentState  string `json:"current_state"  xml:"currentState>name"`
+}
+
+func (p *Provider) listInstancesTool() *providerkit.Tool {
+	tool := mcp.NewTool("aws_ec2_instances_list",
+		mcp.WithDescription("Lists EC2 instances in a region"),
+		withRegion(),
+		mcp.WithString("state",
+			mcp.Description("Only list instances in this state (optional)"),
+			mcp.Enum("pending", "running", "stopping", "stopped", "shutting-down", "terminated"),
+		),
+		mcp.WithString("tag", mcp.Description("Only list instances with this tag, as Key=Value or Key (optional)")),
+		mcp.WithString("vpc_id", mcp.Description("Only list instances in this VPC (optional)")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		params := url.Values{}
+		f := filters{params: params}
+		f.add("instance-state-name", request.GetString("state", ""))
+		f.add("vpc-id", request.GetString("vpc_id", ""))
+		if tag := request.GetString("tag", ""); tag != "" {
+			f.addTag(tag)
+		}
+
+		return providerkit.Result(describe(ctx, p, request, "DescribeInstances", params, 5, 1000,
+			func(resp *describeInstancesResponse) ([]Instance, string) { return resp.instances(), resp.NextToken }))
+	})
+}
+
+// describe fetches one page of a paginated Describe action. items extracts the
+// page's items and continuation token from the decoded response.
+func describe[T, R any](ctx context.Context, p *Provider, request mcp.CallToolRequest, action string, params url.Values,
+	minSize, maxSize int, items func(*R) ([]T, string),
+) (pagination.Page[T], error) {
+	request.Params.Arguments = withRegionArg(ctx, p, request)
+	cursors, req, err := providerkit.ParsePage(ctx, request)
+	if err != nil {
+		return pagination.Page[T]{}, err
+	}
+
+	token, size := req.TokenPage(minSize, maxSize)
+	params.Set("MaxResults", strconv.Itoa(size))
+	if token != "" {
+		params.Set("NextToken", token)
+	}
+
+	var resp R
+	if err := p.ec2(ctx, p.region(ctx, request), action, params, &resp); err != nil {
+		return pagination.Page[T]{}, err
+	}
+	page, next := items(&resp)
+
+	return pagination.FromToken(cursors, req, size, page, next), nil
+}
+
+// withRegionArg returns the call's arguments with the resolved region, so a
+// cursor stays bound to the region it was issued for even when the region
+// came from the account.
+func withRegionArg(ctx context.Context, p *Provider, request mcp.CallToolRequest) map[string]any {
+	args := make(map[string]any, len(request.GetArguments())+1)
+	for key, value := range request.GetArguments() {
+		args[key] = value
+	}
+	args[providerkit.RegionParam] = p.region(ctx, request)
+
+	return args
+}
+
+func (p *Provider) getInstanceTool() *providerkit.Tool {
+	tool := mcp.NewTool("aws_ec2_instance_get",
+		mcp.WithDescription("Returns an EC2 instance"),
+		mcp.WithString("instance_id", mcp.Required(), mcp.Description("Instance ID, such as i-0123456789abcdef0")),
+		withRegion(),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id, err := request.RequireString("instance_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		var resp describeInstancesResponse
+		if err := p.ec2(ctx, p.region(ctx, request), "DescribeInstances", url.Values{"InstanceId.1": {id}}, &resp); err != nil {
+			return providerkit.Result(nil, err)
+		}
+		instances := resp.instances()
+		if len(instances) == 0 {
+			return providerkit.Result(nil, fmt.Errorf("instance %s: %w", id, providerkit.ErrNotFound))
+		}
+
+		return providerkit.Result(instances[0], nil)
+	})
+}
+
+// stringItems is the item schema of string array arguments.
+var stringItems = map[string]any{"type": "string"}
+
+func (p *Provider) runInstancesTool() *providerkit.Tool {
+	tool := mcp.NewTool("aws_ec2_instances_run",
+		mcp.WithDescription("Launches EC2 instances. An idempotency_key is also passed to EC2 as the client token."),
+		mcp.WithString("image_id", mcp.Required(), mcp.Description("AMI ID")),
+		mcp.WithString("instance_type", mcp.Required(), mcp.Description("Instance type, such as t3.micro")),
+		withRegion(),
+		mcp.WithNumber("count", mcp.Description("Number of instances (default 1)"), mcp.Min(1)),
+		mcp.WithString("key_name", mcp.Description("Key pair for SSH access (optional)")),
+		mcp.WithArray("security_group_ids", mcp.Items(stringItems), mcp.Description("Security groups (optional)")),
+		mcp.WithString("subnet_id", mcp.Description("Subnet to launch into (optional)")),
+		mcp.WithString("name", mcp.Description("Value of the Name tag (optional)")),
+		mcp.WithObject("tags", mcp.Description("Additional tags, as a string map (optional)")),
+		mcp.WithString("user_data", mcp.Description("User data script, plain text (optional)")),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		imageID, err := request.RequireString("image_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		instanceType, err := request.RequireString("instance_type")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		count := strconv.Itoa(max(request.GetInt("count", 1), 1))
+
+		params := url.Values{
+			"ImageId":      {imageID},
+			"InstanceType": {instanceType},
+			"MinCount":     {count},
+			"MaxCount":     {count},
+		}
+		setIfNotEmpty(params, "KeyName", request.GetString("key_name", ""))
+		setIfNotEmpty(params, "SubnetId", request.GetString("subnet_id", ""))
+		setIfNotEmpty(params, "ClientToken", clientToken(ctx))
+		addList(params, "SecurityGroupId", request.GetStringSlice("security_group_ids", nil))
+		if userData := request.GetString("user_data", ""); userData != "" {
+			params.Set("UserData", base64.StdEncoding.EncodeToString([]byte(userData)))
+		}
+
+		tags := stringMap(request.GetArguments()["tags"])
+		if name := request.GetString("name", ""); name != "" {
+			if tags == nil {
+				tags = map[string]string{}
+			}
+			tags["Name"] = name
+		}
+		addTags(params, "instance", tags)
+
+		var resp struct {
+			Instances []Instance `xml:"instancesSet>item"`
+		}
+		err = p.ec2(ctx, p.region(ctx, request), "RunInstances", params, &resp)
+
+		return providerkit.Result(resp.Instances, err)
+	})
+}
+
+// instanceStateTool creates a tool changing the state of instances.
+func (p *Provider) instanceStateTool(verb, action, description string, destructive bool) *providerkit.Tool {
+	options := []mcp.ToolOption{
+		mcp.WithDescription(description),
+		mcp.WithArray("instance_ids", mcp.Required(), mcp.Items(stringItems), mcp.Description("Instance IDs")),
+		withRegion(),
+	}
+	if verb == "stop" {
+		options = append(options, mcp.WithBoolean("force", mcp.Description("Force the stop without a clean shutdown (default false)")))
+	}
+	options = append(options,
+		mcp.WithDestructiveHintAnnotation(destructive),
+		mcp.WithIdempotentHintAnnotation(verb != "reboot"),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+	tool := mcp.NewTool("aws_ec2_instances_"+verb, options...)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ids, err := request.RequireStringSlice("instance_ids")
+		if err != nil || len(ids) == 0 {
+			return mcp.NewToolResultError("instance_ids must list at least one instance"), nil
+		}
+
+		params := url.Values{}
+		addList(params, "InstanceId", ids)
+		if request.GetBool("force", false) {
+			params.Set("Force", "true")
+		}
+
+		var resp struct {
+			Changes []StateChange `xml:"instancesSet>item"`
+		}
+		if err := p.ec2(ctx, p.region(ctx, request), action, params, &resp); err != nil {
+			return providerkit.Result(nil, err)
+		}
+		if resp.Changes == nil {
+			// RebootInstances reports no state transitions
+			for _, id := range ids {
+				resp.Changes = append(resp.Changes, StateChange{InstanceID: id, CurrentState: "rebooting"})
+			}
+		}
+
+		return providerkit.Result(resp.Changes, nil)
+	})
+}
+
+// setIfNotEmpty sets a parameter if value is not empty.
+func setIfNotEmpty(params url.Values, key, value string) {
+	if value != "" {
+		params.Set(key, value)
+	}
+}
+
+// stringMap converts an object argument into a string map.
+func stringMap(raw any) map[string]string {
+	object, ok := raw.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	values := make(map[string]string, len(object))
+	for key, value := range object {
+		values[key] = fmt.Sprint(value)
+	}
+
+	return values
+}
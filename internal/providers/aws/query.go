@@ -0,0 +1,187 @@
+package aws
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/chadit/CloudMCP/internal/awsauth"
+	"github.com/chadit/CloudMCP/internal/idempotency"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+)
+
+const (
+	// ec2APIVersion is the EC2 query API version.
+	ec2APIVersion = "2016-11-15"
+
+	// maxResponseBytes bounds how much of an API response is read.
+	maxResponseBytes = 32 << 20
+
+	// maxClientToken is the longest EC2 client token.
+	maxClientToken = 64
+)
+
+// ec2ErrorResponse is the body of an EC2 error response.
+//
+//nolint:tagliatelle // XML element names match the EC2 API.
+type ec2ErrorResponse struct {
+	Errors []struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	} `xml:"Errors>Error"`
+	RequestID string `xml:"RequestID"`
+}
+
+// endpoint returns the EC2 endpoint of region. The ec2_endpoint setting
+// overrides it for every region, for local stand-ins of the API.
+func (p *Provider) endpoint(region string) string {
+	if endpoint := p.cfg.Setting("ec2_endpoint", ""); endpoint != "" {
+		return strings.TrimRight(endpoint, "/")
+	}
+
+	return "https://ec2." + region + ".amazonaws.com"
+}
+
+// ec2 calls an EC2 query API action and decodes the XML response into out.
+func (p *Provider) ec2(ctx context.Context, region, action string, params url.Values, out any) error {
+	creds, err := p.credentials(ctx)
+	if err != nil {
+		return err
+	}
+
+	params.Set("Action", action)
+	params.Set("Version", ec2APIVersion)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint(region)+"/", strings.NewReader(params.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create EC2 request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	signer := awsauth.Signer{Credentials: creds, Region: region, Service: "ec2"}
+	if err := signer.Sign(req); err != nil {
+		return fmt.Errorf("failed to sign EC2 request: %w", err)
+	}
+
+	resp, err := p.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("EC2 %s failed: %w", action, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return fmt.Errorf("failed to read EC2 response: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return ec2Error(resp.StatusCode, data)
+	}
+	if out != nil {
+		if err := xml.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("failed to decode EC2 %s response: %w", action, err)
+		}
+	}
+
+	return nil
+}
+
+// ec2Error converts an EC2 error response into a *providerkit.APIError. EC2
+// reports most failures as 400, so the error code selects the status used
+// for mapping: missing resources map to not found, throttling to rate limited.
+func ec2Error(status int, body []byte) error {
+	var decoded ec2ErrorResponse
+	if err := xml.Unmarshal(body, &decoded); err != nil || len(decoded.Errors) == 0 {
+		return &providerkit.APIError{Provider: Name, Status: status, Message: strings.TrimSpace(string(body))}
+	}
+
+	code := decoded.Errors[0].Code
+	switch {
+	case strings.HasSuffix(code, "NotFound") || strings.HasSuffix(code, ".NotFound"):
+		status = http.StatusNotFound
+	case code == "AuthFailure" || code == "InvalidClientTokenId" || code == "SignatureDoesNotMatch":
+		status = http.StatusUnauthorized
+	case code == "UnauthorizedOperation":
+		status = http.StatusForbidden
+	case code == "RequestLimitExceeded" || code == "Throttling":
+		status = http.StatusTooManyRequests
+	case code == "IncorrectState" || code == "IncorrectInstanceState" || code == "DependencyViolation" || code == "VolumeInUse":
+		status = http.StatusConflict
+	}
+
+	return &providerkit.APIError{Provider: Name, Status: status, Message: code + ": " + decoded.Errors[0].Message}
+}
+
+// addList adds values as the numbered parameters prefix.1, prefix.2, ...
+func addList(params url.Values, prefix string, values []string) {
+	for i, value := range values {
+		params.Set(prefix+"."+strconv.Itoa(i+1), value)
+	}
+}
+
+// filters builds the numbered Filter parameters of Describe calls.
+type filters struct {
+	params url.Values
+	count  int
+}
+
+// add adds a filter if value is not empty.
+func (f *filters) add(name, value string) {
+	if value == "" {
+		return
+	}
+
+	f.count++
+	prefix := "Filter." + strconv.Itoa(f.count)
+	f.params.Set(prefix+".Name", name)
+	f.params.Set(prefix+".Value.1", value)
+}
+
+// addTag adds a tag:Key filter from a Key=Value argument.
+func (f *filters) addTag(tag string) {
+	if key, value, ok := strings.Cut(tag, "="); ok {
+		f.add("tag:"+key, value)
+		return
+	}
+	f.add("tag-key", tag)
+}
+
+// addTags adds a TagSpecification for resourceType.
+func addTags(params url.Values, resourceType string, tags map[string]string) {
+	if len(tags) == 0 {
+		return
+	}
+
+	params.Set("TagSpecification.1.ResourceType", resourceType)
+	for i, key := range slices.Sorted(maps.Keys(tags)) {
+		prefix := "TagSpecification.1.Tag." + strconv.Itoa(i+1)
+		params.Set(prefix+".Key", key)
+		params.Set(prefix+".Value", tags[key])
+	}
+}
+
+// clientToken derives the EC2 client token from the call's idempotency key, so
+// a retried call is also deduplicated by EC2 itself. Long keys are hashed to
+// fit the 64-character limit.
+func clientToken(ctx context.Context) string {
+	key, ok := idempotency.KeyFromContext(ctx)
+	if !ok {
+		return ""
+	}
+	if len(key) <= maxClientToken {
+		return key
+	}
+
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
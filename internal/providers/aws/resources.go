@@ -0,0 +1,516 @@
+package aws
+
+import (
+	"context"
+	"encoding/base64"
+	"net/url"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/idempotency"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+)
+
+// Image is an Amazon Machine Image.
+//
+//nolint:tagliatelle // XML element names match the EC2 API.
+type Image struct {
+	ID           string `json:"id"                    xml:"imageId"`
+	Name         string `json:"name"                  xml:"name"`
+	Description  string `json:"description,omitempty" xml:"description"`
+	State        string `json:"state"                 xml:"imageState"`
+	OwnerID      string `json:"owner_id"              xml:"imageOwnerId"`
+	Architecture string `json:"architecture"          xml:"architecture"`
+	Platform     string `json:"platform,omitempty"    xml:"platformDetails"`
+	Public       bool   `json:"public"                xml:"isPublic"`
+	CreationDate string `json:"creation_date"         xml:"creationDate"`
+	Tags         []Tag  `json:"tags"                  xml:"tagSet>item"`
+}
+
+// SecurityGroup is an EC2 security group.
+//
+//nolint:tagliatelle // XML element names match the EC2 API.
+type SecurityGroup struct {
+	ID          string       `json:"id"          xml:"groupId"`
+	Name        string       `json:"name"        xml:"groupName"`
+	Description string       `json:"description" xml:"groupDescription"`
+	VPCID       string       `json:"vpc_id"      xml:"vpcId"`
+	Ingress     []Permission `json:"ingress"     xml:"ipPermissions>item"`
+	Egress      []Permission `json:"egress"      xml:"ipPermissionsEgress>item"`
+	Tags        []Tag        `json:"tags"        xml:"tagSet>item"`
+}
+
+// Permission is a security group rule.
+//
+//nolint:tagliatelle // XML element names match the EC2 API.
+type Permission struct {
+	Protocol string   `json:"protocol"            xml:"ipProtocol"`
+	FromPort *int     `json:"from_port,omitempty" xml:"fromPort"`
+	ToPort   *int     `json:"to_port,omitempty"   xml:"toPort"`
+	CIDRs    []string `json:"cidrs,omitempty"     xml:"ipRanges>item>cidrIp"`
+	IPv6     []string `json:"ipv6_cidrs,omitempty" xml:"ipv6Ranges>item>cidrIpv6"`
+}
+
+// Volume is an EBS volume.
+//
+//nolint:tagliatelle // XML element names match the EC2 API.
+type Volume struct {
+	ID               string       `json:"id"                    xml:"volumeId"`
+	Size             int          `json:"size_gb"               xml:"size"`
+	Type             string       `json:"type"                  xml:"volumeType"`
+	State            string       `json:"state"                 xml:"status"`
+	AvailabilityZone string       `json:"availability_zone"     xml:"availabilityZone"`
+	SnapshotID       string       `json:"snapshot_id,omitempty" xml:"snapshotId"`
+	Encrypted        bool         `json:"encrypted"             xml:"encrypted"`
+	Iops             int          `json:"iops,omitempty"        xml:"iops"`
+	CreateTime       string       `json:"create_time"           xml:"createTime"`
+	Attachments      []Attachment `json:"attachments"           xml:"attachmentSet>item"`
+	Tags             []Tag        `json:"tags"                  xml:"tagSet>item"`
+}
+
+// Attachment is the attachment of a volume to an instance.
+//
+//nolint:tagliatelle // XML element names match the EC2 API.
+type Attachment struct {
+	VolumeID   string `json:"volume_id"   xml:"volumeId"`
+	InstanceID string `json:"instance_id" xml:"instanceId"`
+	Device     string `json:"device"      xml:"device"`
+	State      string `json:"state"       xml:"status"`
+}
+
+// KeyPair is an EC2 key pair. Only public material is ever returned.
+//
+//nolint:tagliatelle // XML element names match the EC2 API.
+type KeyPair struct {
+	ID          string `json:"id"          xml:"keyPairId"`
+	Name        string `json:"name"        xml:"keyName"`
+	Fingerprint string `json:"fingerprint" xml:"keyFingerprint"`
+	Type        string `json:"type"        xml:"keyType"`
+	CreateTime  string `json:"create_time" xml:"createTime"`
+	Tags        []Tag  `json:"tags"        xml:"tagSet>item"`
+}
+
+// deleted is the result of a delete tool.
+type deleted struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+func (p *Provider) listImagesTool() *providerkit.Tool {
+	tool := mcp.NewTool("aws_ec2_images_list",
+		mcp.WithDescription("Lists AMIs. By default only images owned by the account are listed."),
+		withRegion(),
+		mcp.WithArray("owners", mcp.Items(stringItems),
+			mcp.Description("Owners to list images of: account IDs, self, amazon or aws-marketplace (default self)")),
+		mcp.WithString("name", mcp.Description("Image name filter, * is a wildcard (optional)")),
+		mcp.WithString("architecture", mcp.Description("Only list images of this architecture (optional)"),
+			mcp.Enum("i386", "x86_64", "arm64", "x86_64_mac", "arm64_mac")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		owners := request.GetStringSlice("owners", nil)
+		if len(owners) == 0 {
+			owners = []string{"self"}
+		}
+
+		params := url.Values{}
+		addList(params, "Owner", owners)
+		f := filters{params: params}
+		f.add("name", request.GetString("name", ""))
+		f.add("architecture", request.GetString("architecture", ""))
+
+		return providerkit.Result(describe(ctx, p, request, "DescribeImages", params, 5, 1000,
+			func(resp *struct {
+				Images    []Image `xml:"imagesSet>item"`
+				NextToken string  `xml:"nextToken"`
+			},
+			) ([]Image, string) {
+				return resp.Images, resp.NextToken
+			}))
+	})
+}
+
+func (p *Provider) listSecurityGroupsTool() *providerkit.Tool {
+	tool := mcp.NewTool("aws_ec2_security_groups_list",
+		mcp.WithDescription("Lists EC2 security groups and their rules"),
+		withRegion(),
+		mcp.WithString("vpc_id", mcp.Description("Only list groups of this VPC (optional)")),
+		mcp.WithString("name", mcp.Description("Group name filter, * is a wildcard (optional)")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		params := url.Values{}
+		f := filters{params: params}
+		f.add("vpc-id", request.GetString("vpc_id", ""))
+		f.add("group-name", request.GetString("name", ""))
+
+		return providerkit.Result(describe(ctx, p, request, "DescribeSecurityGroups", params, 5, 1000,
+			func(resp *struct {
+				Groups    []SecurityGroup `xml:"securityGroupInfo>item"`
+				NextToken string          `xml:"nextToken"`
+			},
+			) ([]SecurityGroup, string) {
+				return resp.Groups, resp.NextToken
+			}))
+	})
+}
+
+func (p *Provider) createSecurityGroupTool() *providerkit.Tool {
+	tool := mcp.NewTool("aws_ec2_security_group_create",
+		mcp.WithDescription("Creates a security group. New groups allow no inbound traffic until rules are authorized."),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Group name")),
+		mcp.WithString("description", mcp.Required(), mcp.Description("Group description")),
+		withRegion(),
+		mcp.WithString("vpc_id", mcp.Description("VPC of the group (default: the default VPC)")),
+		mcp.WithObject("tags", mcp.Description("Tags, as a string map (optional)")),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, err := request.RequireString("name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		description, err := request.RequireString("description")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		params := url.Values{"GroupName": {name}, "GroupDescription": {description}}
+		setIfNotEmpty(params, "VpcId", request.GetString("vpc_id", ""))
+		addTags(params, "security-group", stringMap(request.GetArguments()["tags"]))
+
+		var resp struct {
+			GroupID string `xml:"groupId"`
+		}
+		err = p.ec2(ctx, p.region(ctx, request), "CreateSecurityGroup", params, &resp)
+
+		return providerkit.Result(GroupRef{ID: resp.GroupID, Name: name}, err)
+	})
+}
+
+// ingressTool creates a tool authorizing or revoking an inbound rule.
+func (p *Provider) ingressTool(verb, action, description string) *providerkit.Tool {
+	tool := mcp.NewTool("aws_ec2_security_group_ingress_"+verb,
+		mcp.WithDescription(description),
+		mcp.WithString("group_id", mcp.Required(), mcp.Description("Security group ID")),
+		mcp.WithString("protocol", mcp.Required(), mcp.Description("tcp, udp, icmp, or -1 for all traffic")),
+		mcp.WithString("cidr", mcp.Required(), mcp.Description("Source IPv4 range, such as 203.0.113.0/24")),
+		withRegion(),
+		mcp.WithNumber("from_port", mcp.Description("First port of the range, or the ICMP type (omit for -1)")),
+		mcp.WithNumber("to_port", mcp.Description("Last port of the range, or the ICMP code (default from_port)")),
+		mcp.WithString("rule_description", mcp.Description("Rule description (optional, authorize only)")),
+		mcp.WithDestructiveHintAnnotation(verb == "revoke"),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		groupID, err := request.RequireString("group_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		protocol, err := request.RequireString("protocol")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		cidr, err := request.RequireString("cidr")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		params := url.Values{
+			"GroupId":                           {groupID},
+			"IpPermissions.1.IpProtocol":        {protocol},
+			"IpPermissions.1.IpRanges.1.CidrIp": {cidr},
+		}
+		if from := request.GetInt("from_port", -1); from >= 0 {
+			params.Set("IpPermissions.1.FromPort", strconv.Itoa(from))
+			params.Set("IpPermissions.1.ToPort", strconv.Itoa(request.GetInt("to_port", from)))
+		}
+		if verb == "authorize" {
+			setIfNotEmpty(params, "IpPermissions.1.IpRanges.1.Description", request.GetString("rule_description", ""))
+		}
+
+		var resp struct {
+			Return bool `xml:"return"`
+		}
+		err = p.ec2(ctx, p.region(ctx, request), action, params, &resp)
+
+		return providerkit.Result(map[string]any{"group_id": groupID, "success": resp.Return}, err)
+	})
+}
+
+func (p *Provider) deleteSecurityGroupTool() *providerkit.Tool {
+	return p.deleteTool("aws_ec2_security_group_delete",
+		"Deletes a security group that no instance or other group references", "group_id", "GroupId", "DeleteSecurityGroup")
+}
+
+func (p *Provider) listVolumesTool() *providerkit.Tool {
+	tool := mcp.NewTool("aws_ebs_volumes_list",
+		mcp.WithDescription("Lists EBS volumes and their attachments"),
+		withRegion(),
+		mcp.WithString("state", mcp.Description("Only list volumes in this state (optional)"),
+			mcp.Enum("creating", "available", "in-use", "deleting", "deleted", "error")),
+		mcp.WithString("instance_id", mcp.Description("Only list volumes attached to this instance (optional)")),
+		mcp.WithString("tag", mcp.Description("Only list volumes with this tag, as Key=Value or Key (optional)")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		params := url.Values{}
+		f := filters{params: params}
+		f.add("status", request.GetString("state", ""))
+		f.add("attachment.instance-id", request.GetString("instance_id", ""))
+		if tag := request.GetString("tag", ""); tag != "" {
+			f.addTag(tag)
+		}
+
+		return providerkit.Result(describe(ctx, p, request, "DescribeVolumes", params, 5, 500,
+			func(resp *struct {
+				Volumes   []Volume `xml:"volumeSet>item"`
+				NextToken string   `xml:"nextToken"`
+			},
+			) ([]Volume, string) {
+				return resp.Volumes, resp.NextToken
+			}))
+	})
+}
+
+func (p *Provider) createVolumeTool() *providerkit.Tool {
+	tool := mcp.NewTool("aws_ebs_volume_create",
+		mcp.WithDescription("Creates an EBS volume, empty or from a snapshot. An idempotency_key is also passed to EC2 as the client token."),
+		mcp.WithString("availability_zone", mcp.Required(), mcp.Description("Availability zone, such as us-east-1a")),
+		withRegion(),
+		mcp.WithNumber("size_gb", mcp.Description("Size in GiB (required unless snapshot_id is given)"), mcp.Min(1)),
+		mcp.WithString("snapshot_id", mcp.Description("Snapshot to create the volume from (optional)")),
+		mcp.WithString("volume_type", mcp.Description("Volume type (default gp3)"),
+			mcp.Enum("gp2", "gp3", "io1", "io2", "st1", "sc1", "standard")),
+		mcp.WithBoolean("encrypted", mcp.Description("Encrypt the volume with the default KMS key (default false)")),
+		mcp.WithObject("tags", mcp.Description("Tags, as a string map (optional)")),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		zone, err := request.RequireString("availability_zone")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		size := request.GetInt("size_gb", 0)
+		snapshot := request.GetString("snapshot_id", "")
+		if size <= 0 && snapshot == "" {
+			return mcp.NewToolResultError("size_gb or snapshot_id is required"), nil
+		}
+
+		params := url.Values{
+			"AvailabilityZone": {zone},
+			"VolumeType":       {request.GetString("volume_type", "gp3")},
+		}
+		if size > 0 {
+			params.Set("Size", strconv.Itoa(size))
+		}
+		setIfNotEmpty(params, "SnapshotId", snapshot)
+		setIfNotEmpty(params, "ClientToken", clientToken(ctx))
+		if request.GetBool("encrypted", false) {
+			params.Set("Encrypted", "true")
+		}
+		addTags(params, "volume", stringMap(request.GetArguments()["tags"]))
+
+		var volume Volume
+		err = p.ec2(ctx, p.region(ctx, request), "CreateVolume", params, &volume)
+
+		return providerkit.Result(volume, err)
+	})
+}
+
+func (p *Provider) attachVolumeTool() *providerkit.Tool {
+	tool := mcp.NewTool("aws_ebs_volume_attach",
+		mcp.WithDescription("Attaches an EBS volume to an instance in the same availability zone"),
+		mcp.WithString("volume_id", mcp.Required(), mcp.Description("Volume ID")),
+		mcp.WithString("instance_id", mcp.Required(), mcp.Description("Instance ID")),
+		mcp.WithString("device", mcp.Required(), mcp.Description("Device name, such as /dev/sdf")),
+		withRegion(),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		params := url.Values{}
+		for _, arg := range [][2]string{{"volume_id", "VolumeId"}, {"instance_id", "InstanceId"}, {"device", "Device"}} {
+			value, err := request.RequireString(arg[0])
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			params.Set(arg[1], value)
+		}
+
+		var attachment Attachment
+		err := p.ec2(ctx, p.region(ctx, request), "AttachVolume", params, &attachment)
+
+		return providerkit.Result(attachment, err)
+	})
+}
+
+func (p *Provider) detachVolumeTool() *providerkit.Tool {
+	tool := mcp.NewTool("aws_ebs_volume_detach",
+		mcp.WithDescription("Detaches an EBS volume. Unmount it first; a forced detach can lose data."),
+		mcp.WithString("volume_id", mcp.Required(), mcp.Description("Volume ID")),
+		withRegion(),
+		mcp.WithString("instance_id", mcp.Description("Instance to detach from (optional)")),
+		mcp.WithBoolean("force", mcp.Description("Force the detach (default false)")),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		volumeID, err := request.RequireString("volume_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		params := url.Values{"VolumeId": {volumeID}}
+		setIfNotEmpty(params, "InstanceId", request.GetString("instance_id", ""))
+		if request.GetBool("force", false) {
+			params.Set("Force", "true")
+		}
+
+		var attachment Attachment
+		err = p.ec2(ctx, p.region(ctx, request), "DetachVolume", params, &attachment)
+
+		return providerkit.Result(attachment, err)
+	})
+}
+
+func (p *Provider) deleteVolumeTool() *providerkit.Tool {
+	return p.deleteTool("aws_ebs_volume_delete",
+		"Deletes a detached EBS volume and its data. This cannot be undone.", "volume_id", "VolumeId", "DeleteVolume")
+}
+
+func (p *Provider) listKeyPairsTool() *providerkit.Tool {
+	tool := mcp.NewTool("aws_ec2_key_pairs_list",
+		mcp.WithDescription("Lists EC2 key pairs"),
+		withRegion(),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// DescribeKeyPairs is not paginated, so pages are cut from the full list
+		request.Params.Arguments = withRegionArg(ctx, p, request)
+		cursors, req, err := providerkit.ParsePage(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		var resp struct {
+			KeyPairs []KeyPair `xml:"keySet>item"`
+		}
+		if err := p.ec2(ctx, p.region(ctx, request), "DescribeKeyPairs", url.Values{}, &resp); err != nil {
+			return providerkit.Result(nil, err)
+		}
+		if resp.KeyPairs == nil {
+			resp.KeyPairs = []KeyPair{}
+		}
+
+		return providerkit.Result(pagination.Slice(cursors, req, resp.KeyPairs), nil)
+	})
+}
+
+func (p *Provider) importKeyPairTool() *providerkit.Tool {
+	tool := mcp.NewTool("aws_ec2_key_pair_import",
+		mcp.WithDescription("Imports an SSH public key as an EC2 key pair. Key pairs whose private key AWS generates are not supported, so no secret is ever returned."),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Key pair name")),
+		mcp.WithString("public_key", mcp.Required(), mcp.Description("Public key in OpenSSH format")),
+		withRegion(),
+		mcp.WithObject("tags", mcp.Description("Tags, as a string map (optional)")),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, err := request.RequireString("name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		publicKey, err := request.RequireString("public_key")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		params := url.Values{
+			"KeyName":           {name},
+			"PublicKeyMaterial": {base64.StdEncoding.EncodeToString([]byte(publicKey))},
+		}
+		addTags(params, "key-pair", stringMap(request.GetArguments()["tags"]))
+
+		var keyPair KeyPair
+		err = p.ec2(ctx, p.region(ctx, request), "ImportKeyPair", params, &keyPair)
+
+		return providerkit.Result(keyPair, err)
+	})
+}
+
+func (p *Provider) deleteKeyPairTool() *providerkit.Tool {
+	return p.deleteTool("aws_ec2_key_pair_delete",
+		"Deletes an EC2 key pair. Instances launched with it keep their authorized key.", "key_name", "KeyName", "DeleteKeyPair")
+}
+
+// deleteTool creates a tool deleting a resource by a single identifier.
+func (p *Provider) deleteTool(name, description, param, field, action string) *providerkit.Tool {
+	tool := mcp.NewTool(name,
+		mcp.WithDescription(description),
+		mcp.WithString(param, mcp.Required(), mcp.Description("Resource to delete")),
+		withRegion(),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id, err := request.RequireString(param)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		err = p.ec2(ctx, p.region(ctx, request), action, url.Values{field: {id}}, nil)
+
+		return providerkit.Result(deleted{ID: id, Status: "deleted"}, err)
+	})
+}
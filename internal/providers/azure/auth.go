@@ -0,0 +1,223 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	// DefaultAuthorityURL is the Microsoft Entra ID (Azure AD) endpoint.
+	DefaultAuthorityURL = "https://login.microsoftonline.com"
+
+	// DefaultScope requests a token for Azure Resource Manager.
+	DefaultScope = "https://management.azure.com/.default"
+
+	// refreshMargin renews tokens this long before they expire.
+	refreshMargin = 2 * time.Minute
+
+	// maxTokenResponse bounds how much of a token response is read.
+	maxTokenResponse = 1 << 20
+)
+
+// ErrInvalidCredential is returned for service principal secrets that lack
+// a tenant, client ID or client secret.
+var ErrInvalidCredential = errors.New("invalid Azure credential: tenant_id, client_id and client_secret are required")
+
+// ServicePrincipal is the client credential of a service principal, as stored
+// in the credential store or the AZURE_* environment variables.
+type ServicePrincipal struct {
+	TenantID       string
+	ClientID       string
+	ClientSecret   contracts.Secret
+	SubscriptionID string
+}
+
+// parseServicePrincipal parses a stored credential: JSON with tenant_id,
+// client_id, client_secret and optional subscription_id fields, or the output
+// of az ad sp create-for-rbac with tenant, appId and password.
+func parseServicePrincipal(secret contracts.Secret) (ServicePrincipal, error) {
+	var decoded struct {
+		TenantID       string `json:"tenant_id"`
+		ClientID       string `json:"client_id"`
+		ClientSecret   string `json:"client_secret"`
+		SubscriptionID string `json:"subscription_id"`
+		Tenant         string `json:"tenant"`
+		AppID          string `json:"appId"`
+		Password       string `json:"password"`
+	}
+	if err := json.Unmarshal([]byte(secret.Reveal()), &decoded); err != nil {
+		return ServicePrincipal{}, fmt.Errorf("%w: %w", ErrInvalidCredential, err)
+	}
+
+	sp := ServicePrincipal{
+		TenantID:       firstNonEmpty(decoded.TenantID, decoded.Tenant),
+		ClientID:       firstNonEmpty(decoded.ClientID, decoded.AppID),
+		ClientSecret:   contracts.Secret(firstNonEmpty(decoded.ClientSecret, decoded.Password)),
+		SubscriptionID: decoded.SubscriptionID,
+	}
+	if sp.TenantID == "" || sp.ClientID == "" || sp.ClientSecret == "" {
+		return ServicePrincipal{}, ErrInvalidCredential
+	}
+
+	return sp, nil
+}
+
+// servicePrincipal returns the credential of a call: the account's
+// credential, the credential_ref setting, or the AZURE_TENANT_ID,
+// AZURE_CLIENT_ID, AZURE_CLIENT_SECRET and AZURE_SUBSCRIPTION_ID variables.
+func (p *Provider) servicePrincipal(ctx context.Context) (ServicePrincipal, error) {
+	ref := p.cfg.Setting("credential_ref", "")
+	if account, ok := contracts.AccountFromContext(ctx); ok && account.Credential != "" {
+		ref = account.Credential
+	}
+
+	if ref == "" {
+		sp := ServicePrincipal{
+			TenantID:       os.Getenv("AZURE_TENANT_ID"),
+			ClientID:       os.Getenv("AZURE_CLIENT_ID"),
+			ClientSecret:   contracts.Secret(os.Getenv("AZURE_CLIENT_SECRET")),
+			SubscriptionID: os.Getenv("AZURE_SUBSCRIPTION_ID"),
+		}
+		if sp.TenantID == "" || sp.ClientID == "" || sp.ClientSecret == "" {
+			return ServicePrincipal{}, fmt.Errorf("%w: set an account credential or the AZURE_* variables", providerkit.ErrNoCredential)
+		}
+		return sp, nil
+	}
+
+	if p.cfg.Secrets == nil {
+		return ServicePrincipal{}, providerkit.ErrNoSecrets
+	}
+	secret, err := p.cfg.Secrets.Resolve(ctx, ref)
+	if err != nil {
+		return ServicePrincipal{}, fmt.Errorf("failed to resolve credential %q: %w", ref, err)
+	}
+
+	return parseServicePrincipal(secret)
+}
+
+// cachedToken is an access token and its expiry.
+type cachedToken struct {
+	value  string
+	expiry time.Time
+}
+
+// tokenCache caches access tokens by tenant and client.
+type tokenCache struct {
+	mu     sync.Mutex
+	tokens map[string]cachedToken
+}
+
+// token returns an access token for sp, requesting one with the client
+// credentials grant when the cached token is missing or about to expire.
+func (p *Provider) token(ctx context.Context, sp ServicePrincipal) (string, error) {
+	id := sp.TenantID + "/" + sp.ClientID
+
+	p.tokens.mu.Lock()
+	defer p.tokens.mu.Unlock()
+
+	if cached, ok := p.tokens.tokens[id]; ok && time.Now().Add(refreshMargin).Before(cached.expiry) {
+		return cached.value, nil
+	}
+
+	value, lifetime, err := p.requestToken(ctx, sp)
+	if err != nil {
+		return "", err
+	}
+	p.tokens.tokens[id] = cachedToken{value: value, expiry: time.Now().Add(lifetime)}
+
+	return value, nil
+}
+
+// requestToken performs the client credentials grant.
+func (p *Provider) requestToken(ctx context.Context, sp ServicePrincipal) (string, time.Duration, error) {
+	authority := strings.TrimRight(p.cfg.Setting("authority_url", DefaultAuthorityURL), "/")
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {sp.ClientID},
+		"client_secret": {sp.ClientSecret.Reveal()},
+		"scope":         {p.cfg.Setting("scope", DefaultScope)},
+	}
+
+	target := authority + "/" + url.PathEscape(sp.TenantID) + "/oauth2/v2.0/token"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.http.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxTokenResponse))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read token response: %w", err)
+	}
+
+	var decoded struct {
+		AccessToken      string          `json:"access_token"`
+		ExpiresIn        json.RawMessage `json:"expires_in"`
+		Error            string          `json:"error"`
+		ErrorDescription string          `json:"error_description"`
+	}
+	_ = json.Unmarshal(data, &decoded)
+
+	if resp.StatusCode >= http.StatusBadRequest || decoded.AccessToken == "" {
+		status := resp.StatusCode
+		if decoded.Error == "invalid_client" || decoded.Error == "unauthorized_client" || status < http.StatusBadRequest {
+			status = http.StatusUnauthorized
+		}
+		// the first line of an AADSTS description carries the reason; the rest is trace IDs
+		description, _, _ := strings.Cut(decoded.ErrorDescription, "\r\n")
+
+		return "", 0, &providerkit.APIError{Provider: "Microsoft Entra ID", Status: status, Message: strings.Trim(decoded.Error+": "+description, ": ")}
+	}
+
+	// v2 endpoints return expires_in as a number, v1 endpoints as a string
+	seconds, _ := strconv.Atoi(strings.Trim(string(decoded.ExpiresIn), `"`))
+	if seconds <= 0 {
+		seconds = 3600
+	}
+
+	return decoded.AccessToken, time.Duration(seconds) * time.Second, nil
+}
+
+// authorize adds the access token of the call's service principal.
+func (p *Provider) authorize(ctx context.Context, req *http.Request) error {
+	sp, err := p.servicePrincipal(ctx)
+	if err != nil {
+		return fmt.Errorf("azure: %w", err)
+	}
+	token, err := p.token(ctx, sp)
+	if err != nil {
+		return fmt.Errorf("azure: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	return nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
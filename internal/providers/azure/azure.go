@@ -0,0 +1,311 @@
+// Package azure implements the Azure Resource Manager provider. Calls are
+// authorized with tokens obtained through the client credentials grant of a
+// service principal.
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	// Name is the provider name used in settings and accounts.
+	Name = "azure"
+
+	// DefaultARMURL is the Azure Resource Manager endpoint, overridden by the
+	// arm_url setting.
+	DefaultARMURL = "https://management.azure.com"
+
+	// API versions of the resource types the tools use.
+	subscriptionsAPIVersion  = "2022-12-01"
+	resourceGroupsAPIVersion = "2021-04-01"
+	computeAPIVersion        = "2024-07-01"
+	disksAPIVersion          = "2023-04-02"
+	networkAPIVersion        = "2024-05-01"
+)
+
+// Static errors for err113 compliance.
+var (
+	ErrNoSubscription = errors.New("no subscription: pass subscription_id, include it in the credential, or set CLOUD_MCP_AZURE_SUBSCRIPTION_ID")
+	ErrForeignLink    = errors.New("link does not point to the configured Resource Manager endpoint")
+	ErrInvalidRule    = errors.New("invalid security rule")
+)
+
+// Provider is the Azure provider.
+type Provider struct {
+	cfg          contracts.ProviderConfig
+	api          *providerkit.API
+	http         *http.Client
+	pollInterval time.Duration
+	tokens       tokenCache
+}
+
+// New creates an uninitialized Azure provider.
+func New() contracts.Provider {
+	return &Provider{}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return Name
+}
+
+// Initialize configures the API client. Credentials are resolved per call, so
+// each account can use its own service principal.
+func (p *Provider) Initialize(_ context.Context, cfg contracts.ProviderConfig) error {
+	p.http = cfg.HTTPClient
+	if p.http == nil {
+		p.http = http.DefaultClient
+	}
+
+	p.cfg = cfg
+	p.pollInterval = providerkit.PollInterval(cfg)
+	p.tokens = tokenCache{tokens: make(map[string]cachedToken)}
+	p.api = &providerkit.API{
+		Provider:     Name,
+		BaseURL:      strings.TrimRight(cfg.Setting("arm_url", DefaultARMURL), "/"),
+		HTTP:         p.http,
+		Authorize:    p.authorize,
+		ErrorMessage: errorMessage,
+	}
+
+	return nil
+}
+
+// Tools returns the Azure tools.
+func (p *Provider) Tools() []contracts.Tool {
+	return []contracts.Tool{
+		p.listSubscriptionsTool(),
+		p.listResourceGroupsTool(),
+		p.listVMsTool(),
+		p.getVMTool(),
+		p.vmActionTool(),
+		p.listDisksTool(),
+		p.getDiskTool(),
+		p.listNSGsTool(),
+		p.getNSGTool(),
+		p.createNSGRuleTool(),
+		p.deleteNSGRuleTool(),
+		p.getOperationTool(),
+		p.waitOperationTool(),
+	}
+}
+
+// HealthCheck verifies the default account's service principal by reading its
+// subscription, or listing subscriptions when none is configured.
+func (p *Provider) HealthCheck(ctx context.Context) error {
+	ctx = providerkit.DefaultAccountContext(ctx, p.cfg)
+	query := url.Values{"api-version": {subscriptionsAPIVersion}}
+
+	subscription, err := p.subscription(ctx, mcp.CallToolRequest{})
+	if errors.Is(err, ErrNoSubscription) {
+		return p.api.Do(ctx, http.MethodGet, "/subscriptions", query, nil, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	return p.api.Do(ctx, http.MethodGet, "/subscriptions/"+url.PathEscape(subscription), query, nil, nil)
+}
+
+// Shutdown has nothing to release.
+func (p *Provider) Shutdown(context.Context) error {
+	return nil
+}
+
+// errorMessage extracts the code and message of an ARM error response.
+func errorMessage(body []byte) string {
+	var decoded struct {
+		Error *ErrorDetail `json:"error"`
+		ErrorDetail
+	}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return ""
+	}
+	if decoded.Error != nil {
+		return decoded.Error.String()
+	}
+
+	return decoded.ErrorDetail.String()
+}
+
+// ErrorDetail is the error object of ARM responses and failed operations.
+type ErrorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// String formats the error as "code: message".
+func (e ErrorDetail) String() string {
+	return strings.Trim(e.Code+": "+e.Message, ": ")
+}
+
+// subscription returns the subscription of a call: the subscription_id
+// argument, the subscription of the service principal's credential, or the
+// subscription_id setting.
+func (p *Provider) subscription(ctx context.Context, request mcp.CallToolRequest) (string, error) {
+	if subscription := request.GetString("subscription_id", ""); subscription != "" {
+		return subscription, nil
+	}
+
+	sp, err := p.servicePrincipal(ctx)
+	if err != nil {
+		return "", fmt.Errorf("azure: %w", err)
+	}
+	if sp.SubscriptionID != "" {
+		return sp.SubscriptionID, nil
+	}
+	if subscription := p.cfg.Setting("subscription_id", ""); subscription != "" {
+		return subscription, nil
+	}
+
+	return "", ErrNoSubscription
+}
+
+// scope returns the path of the call's subscription, or of its resource group
+// when the resource_group argument is set.
+func (p *Provider) scope(ctx context.Context, request mcp.CallToolRequest) (string, error) {
+	subscription, err := p.subscription(ctx, request)
+	if err != nil {
+		return "", err
+	}
+
+	path := "/subscriptions/" + url.PathEscape(subscription)
+	if group := request.GetString("resource_group", ""); group != "" {
+		path += "/resourceGroups/" + url.PathEscape(group)
+	}
+
+	return path, nil
+}
+
+// resourceArgs reads the resource_group argument and the argument naming a
+// resource.
+func resourceArgs(request mcp.CallToolRequest, nameArg string) (string, string, error) {
+	group, err := request.RequireString("resource_group")
+	if err != nil {
+		return "", "", err
+	}
+	name, err := request.RequireString(nameArg)
+	if err != nil {
+		return "", "", err
+	}
+
+	return group, name, nil
+}
+
+// resourcePath returns the path of a resource of a provider namespace, such as
+// Microsoft.Compute/virtualMachines, in the call's subscription.
+func (p *Provider) resourcePath(ctx context.Context, request mcp.CallToolRequest, resourceType, group, name string) (string, error) {
+	subscription, err := p.subscription(ctx, request)
+	if err != nil {
+		return "", err
+	}
+
+	return "/subscriptions/" + url.PathEscape(subscription) + "/resourceGroups/" + url.PathEscape(group) +
+		"/providers/" + resourceType + "/" + url.PathEscape(name), nil
+}
+
+// relative returns the path and query of an absolute link returned by ARM,
+// such as a nextLink or an operation URL. Links outside the configured
+// endpoint are rejected so the bearer token is never sent elsewhere.
+func (p *Provider) relative(link string) (string, error) {
+	if !strings.HasPrefix(link, p.api.BaseURL+"/") {
+		return "", fmt.Errorf("%w: %s", ErrForeignLink, link)
+	}
+
+	return strings.TrimPrefix(link, p.api.BaseURL), nil
+}
+
+// linkCall returns a GET of a relative link.
+func linkCall(relative string) (providerkit.Call, error) {
+	path, rawQuery, _ := strings.Cut(relative, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return providerkit.Call{}, fmt.Errorf("%w: %w", ErrForeignLink, err)
+	}
+
+	return providerkit.Call{Method: http.MethodGet, Path: path, Query: query}, nil
+}
+
+// listResponse is a page of an ARM collection.
+//
+//nolint:tagliatelle // JSON field names match the ARM API.
+type listResponse[T any] struct {
+	Value    []T    `json:"value"`
+	NextLink string `json:"nextLink"`
+}
+
+// list fetches one page of an ARM collection. ARM chooses its page sizes, so
+// the cursor carries the nextLink and the limit is applied within pages.
+func list[T any](ctx context.Context, p *Provider, request mcp.CallToolRequest, path string, query url.Values) (pagination.Page[T], error) {
+	cursors, req, err := providerkit.ParsePage(ctx, request)
+	if err != nil {
+		return pagination.Page[T]{}, err
+	}
+
+	token, size := req.TokenPage(1, pagination.MaxLimit)
+	call := providerkit.Call{Method: http.MethodGet, Path: path, Query: query}
+	if token != "" {
+		if call, err = linkCall(token); err != nil {
+			return pagination.Page[T]{}, err
+		}
+	}
+
+	var resp listResponse[T]
+	call.Out = &resp
+	if _, err := p.api.Call(ctx, call); err != nil {
+		return pagination.Page[T]{}, err
+	}
+
+	next := ""
+	if resp.NextLink != "" {
+		if next, err = p.relative(resp.NextLink); err != nil {
+			return pagination.Page[T]{}, err
+		}
+	}
+	if resp.Value == nil {
+		resp.Value = []T{}
+	}
+
+	return pagination.FromToken(cursors, req, size, resp.Value, next), nil
+}
+
+// apiVersion returns the query selecting an API version.
+func apiVersion(version string) url.Values {
+	return url.Values{"api-version": {version}}
+}
+
+// withSubscription adds the optional subscription_id argument.
+func withSubscription() mcp.ToolOption {
+	return mcp.WithString("subscription_id", mcp.Description("Subscription ID (default: the account's subscription)"))
+}
+
+// withResourceGroup adds the resource_group argument, required when a single
+// resource is addressed.
+func withResourceGroup(required bool) mcp.ToolOption {
+	if required {
+		return mcp.WithString("resource_group", mcp.Required(), mcp.Description("Resource group name"))
+	}
+
+	return mcp.WithString("resource_group", mcp.Description("Only list resources of this resource group (optional)"))
+}
+
+// Reference is a link to another ARM resource.
+type Reference struct {
+	ID string `json:"id"`
+}
+
+// stringItems is the item schema of string array arguments.
+var stringItems = map[string]any{"type": "string"}
@@ -0,0 +1,425 @@
+package azure_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chadit/CloudMCP/internal/credentials"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/internal/providerkit/providerkittest"
+	"github.com/chadit/CloudMCP/internal/providers/azure"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	tenant       = "tenant-1"
+	clientID     = "client-1"
+	clientSecret = "s3cret"
+	subscription = "sub-1"
+	accessToken  = "eyJ0eXAi.test-token"
+)
+
+// The demo account is the default. The cli and stale accounts use the output
+// of az ad sp create-for-rbac, the latter with an expired password.
+var (
+	demo  = contracts.Account{Provider: azure.Name, Alias: "demo", Credential: "azure-demo", Default: true}
+	cli   = contracts.Account{Provider: azure.Name, Alias: "demo", Credential: "azure-cli"}
+	stale = contracts.Account{Provider: azure.Name, Alias: "demo", Credential: "azure-stale"}
+)
+
+// fakeAzure stubs the Entra ID token endpoint and Resource Manager. VM actions
+// return an Azure-AsyncOperation URL that advances 50% per poll, except
+// restart, which only returns a Location URL; operations on the VM "broken"
+// fail.
+type fakeAzure struct {
+	mu        sync.Mutex
+	url       string
+	exchanges int
+	groups    int
+	ops       map[string]*azure.Operation
+	results   map[string]int
+	queries   map[string]string
+	bodies    map[string]map[string]any
+}
+
+func (f *fakeAzure) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path == "/"+tenant+"/oauth2/v2.0/token" {
+		f.token(w, r)
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+accessToken {
+		writeError(w, http.StatusUnauthorized, "InvalidAuthenticationToken", "The access token is invalid.")
+		return
+	}
+	f.queries[r.Method+" "+r.URL.Path] = r.URL.RawQuery
+	if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+		var body map[string]any
+		_ = json.Unmarshal(data, &body)
+		f.bodies[r.URL.Path] = body
+	}
+
+	sub := "/subscriptions/" + subscription
+	vms := sub + "/resourceGroups/prod/providers/Microsoft.Compute/virtualMachines/"
+	rules := sub + "/resourceGroups/prod/providers/Microsoft.Network/networkSecurityGroups/web-nsg/securityRules/"
+	path := r.URL.Path
+	switch {
+	case r.Method == http.MethodGet && path == sub:
+		providerkittest.WriteJSON(w, http.StatusOK, azure.Subscription{SubscriptionID: subscription, State: "Enabled"})
+	case r.Method == http.MethodGet && path == sub+"/resourcegroups":
+		start, _ := strconv.Atoi(r.URL.Query().Get("$skiptoken"))
+		end := min(start+2, f.groups)
+		groups := []map[string]any{}
+		for i := start; i < end; i++ {
+			groups = append(groups, map[string]any{"name": fmt.Sprintf("rg-%d", i), "location": "eastus"})
+		}
+		resp := map[string]any{"value": groups}
+		if end < f.groups {
+			resp["nextLink"] = f.url + path + "?api-version=2021-04-01&%24skiptoken=" + strconv.Itoa(end)
+		}
+		providerkittest.WriteJSON(w, http.StatusOK, resp)
+	case r.Method == http.MethodGet && path == sub+"/providers/Microsoft.Compute/disks":
+		providerkittest.WriteJSON(w, http.StatusOK, map[string]any{"value": []any{}, "nextLink": "https://attacker.example.com" + path})
+	case r.Method == http.MethodGet && path == vms+"web":
+		providerkittest.WriteJSON(w, http.StatusOK, map[string]any{"name": "web", "properties": map[string]any{
+			"instanceView": map[string]any{"statuses": []map[string]string{
+				{"code": "ProvisioningState/succeeded"}, {"code": "PowerState/running", "displayStatus": "VM running"},
+			}},
+		}})
+	case r.Method == http.MethodPost && path == vms+"web/restart":
+		w.Header().Set("Location", f.url+sub+"/providers/Microsoft.Compute/locations/eastus/operationResults/restart?api-version=2024-07-01")
+		w.WriteHeader(http.StatusAccepted)
+	case r.Method == http.MethodGet && strings.HasSuffix(path, "/operationResults/restart"):
+		f.results[path]++
+		if f.results[path] < 2 {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPost && strings.HasPrefix(path, vms):
+		f.start(w, strings.Split(strings.TrimPrefix(path, vms), "/")[0], http.StatusAccepted, nil)
+	case r.Method == http.MethodPut && strings.HasPrefix(path, rules):
+		f.start(w, strings.TrimPrefix(path, rules), http.StatusCreated, map[string]any{"name": strings.TrimPrefix(path, rules), "properties": map[string]any{"provisioningState": "Updating"}})
+	case r.Method == http.MethodGet && strings.HasPrefix(path, rules):
+		providerkittest.WriteJSON(w, http.StatusOK, map[string]any{"name": strings.TrimPrefix(path, rules), "properties": map[string]any{"provisioningState": "Succeeded"}})
+	case r.Method == http.MethodGet && strings.Contains(path, "/operations/"):
+		f.poll(w, path[strings.LastIndex(path, "/")+1:])
+	default:
+		writeError(w, http.StatusNotFound, "ResourceNotFound", "The Resource '"+path+"' was not found.")
+	}
+}
+
+// token checks the client credentials and issues the access token, with
+// expires_in as a string as some Entra ID endpoints send it.
+func (f *fakeAzure) token(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	if r.PostForm.Get("grant_type") != "client_credentials" || r.PostForm.Get("scope") != azure.DefaultScope {
+		providerkittest.WriteJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	if r.PostForm.Get("client_id") != clientID || r.PostForm.Get("client_secret") != clientSecret {
+		providerkittest.WriteJSON(w, http.StatusUnauthorized, map[string]string{
+			"error":             "invalid_client",
+			"error_description": "AADSTS7000215: Invalid client secret provided.\r\nTrace ID: 0000",
+		})
+		return
+	}
+
+	f.exchanges++
+	providerkittest.WriteJSON(w, http.StatusOK, map[string]any{"access_token": accessToken, "expires_in": "3599", "token_type": "Bearer"})
+}
+
+// start creates a running operation on target and answers with status.
+func (f *fakeAzure) start(w http.ResponseWriter, target string, status int, body any) {
+	name := fmt.Sprintf("op-%d-%s", len(f.ops)+1, target)
+	f.ops[name] = &azure.Operation{Status: "InProgress"}
+	w.Header().Set("Azure-AsyncOperation", f.url+"/subscriptions/"+subscription+"/providers/Microsoft.Compute/locations/eastus/operations/"+name+"?api-version=2024-07-01")
+	w.Header().Set("Location", f.url+"/subscriptions/"+subscription+"/providers/Microsoft.Compute/locations/eastus/operationResults/"+name)
+	if body == nil {
+		w.WriteHeader(status)
+		return
+	}
+	providerkittest.WriteJSON(w, status, body)
+}
+
+// poll advances an operation by one step.
+func (f *fakeAzure) poll(w http.ResponseWriter, name string) {
+	op, ok := f.ops[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "OperationNotFound", "operation not found")
+		return
+	}
+	op.PercentComplete = min(op.PercentComplete+50, 100)
+	if op.PercentComplete == 100 {
+		op.Status = "Succeeded"
+		if strings.HasSuffix(name, "-broken") {
+			op.Status = "Failed"
+			op.Error = &azure.ErrorDetail{Code: "AllocationFailed", Message: "No capacity in eastus"}
+		}
+	}
+	providerkittest.WriteJSON(w, http.StatusOK, op)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	providerkittest.WriteJSON(w, status, map[string]any{"error": map[string]string{"code": code, "message": message}})
+}
+
+// setup starts the stubs and returns the provider and its tools by name.
+func setup(t *testing.T, groups int) (*fakeAzure, contracts.Provider, map[string]contracts.Tool) {
+	t.Helper()
+
+	credential, err := json.Marshal(map[string]string{
+		"tenant_id": tenant, "client_id": clientID, "client_secret": clientSecret, "subscription_id": subscription,
+	})
+	require.NoError(t, err)
+
+	return setupWithSecrets(t, groups, providerkittest.Secrets{
+		"azure-demo": string(credential),
+		// az ad sp create-for-rbac output, without a subscription
+		"azure-cli":   `{"appId":"` + clientID + `","password":"` + clientSecret + `","tenant":"` + tenant + `"}`,
+		"azure-stale": `{"appId":"client-2","password":"expired","tenant":"` + tenant + `"}`,
+	})
+}
+
+// setupWithSecrets starts the stubs and returns a provider that resolves
+// credentials through secrets.
+func setupWithSecrets(t *testing.T, groups int, secrets contracts.SecretResolver) (*fakeAzure, contracts.Provider, map[string]contracts.Tool) {
+	t.Helper()
+
+	api := &fakeAzure{groups: groups, ops: map[string]*azure.Operation{}, results: map[string]int{}, queries: map[string]string{}, bodies: map[string]map[string]any{}}
+	srv := providerkittest.Serve(t, api)
+	api.url = srv.URL
+
+	provider := azure.New()
+	tools := providerkittest.Setup(t, provider, contracts.ProviderConfig{
+		Settings: map[string]string{
+			"arm_url":       srv.URL,
+			"authority_url": srv.URL,
+			"poll_interval": "1ms",
+		},
+		HTTPClient: srv.Client(),
+		Secrets:    secrets,
+		Accounts:   []contracts.Account{demo},
+	})
+
+	return api, provider, tools
+}
+
+// operationResult is the shape of results of tools that start operations.
+type operationResult struct {
+	Operation *azure.Operation `json:"operation"`
+	Resource  json.RawMessage  `json:"resource"`
+}
+
+func TestHealthCheck_ExchangesTokenOnce(t *testing.T) {
+	t.Parallel()
+
+	api, provider, tools := setup(t, 1)
+	require.NoError(t, provider.HealthCheck(t.Context()))
+	text, isError := providerkittest.Call(providerkittest.AccountContext(t, demo), t, tools["azure_resource_groups_list"], map[string]any{})
+	require.False(t, isError, text)
+	require.Equal(t, 1, api.exchanges, "the access token is reused across calls")
+	require.Equal(t, "api-version=2022-12-01", api.queries["GET /subscriptions/"+subscription])
+}
+
+func TestCredential_AzureCLIOutputFromCredentialStore(t *testing.T) {
+	t.Parallel()
+
+	// az ad sp create-for-rbac prints indented JSON with a trailing newline;
+	// `cloud-mcp creds add` stores it as is.
+	output := "{\n" +
+		`  "appId": "` + clientID + `",` + "\n" +
+		`  "displayName": "cloud-mcp",` + "\n" +
+		`  "password": "` + clientSecret + `",` + "\n" +
+		`  "tenant": "` + tenant + `"` + "\n" +
+		"}\n"
+
+	opts := credentials.Options{Path: filepath.Join(t.TempDir(), "credentials.enc"), Passphrase: "correct horse"}
+	store, err := credentials.Open(opts)
+	require.NoError(t, err)
+	secret, err := credentials.ReadSecret(strings.NewReader(output))
+	require.NoError(t, err)
+	require.NoError(t, store.Put("azure-demo", secret))
+
+	api, _, tools := setupWithSecrets(t, 1, credentials.NewResolver(opts))
+	text, isError := providerkittest.Call(providerkittest.AccountContext(t, demo), t, tools["azure_resource_groups_list"], map[string]any{"subscription_id": subscription})
+	require.False(t, isError, text)
+	require.Equal(t, 1, api.exchanges, "the stored service principal authenticates")
+}
+
+func TestVMGet_ReportsPowerState(t *testing.T) {
+	t.Parallel()
+
+	api, _, tools := setup(t, 0)
+
+	text, isError := providerkittest.Call(providerkittest.AccountContext(t, demo), t, tools["azure_vm_get"], map[string]any{"resource_group": "prod", "name": "web"})
+	require.False(t, isError, text)
+	var vm azure.VM
+	require.NoError(t, json.Unmarshal([]byte(text), &vm))
+	require.Equal(t, "running", vm.PowerState)
+	require.Equal(t, "%24expand=instanceView&api-version=2024-07-01",
+		api.queries["GET /subscriptions/"+subscription+"/resourceGroups/prod/providers/Microsoft.Compute/virtualMachines/web"])
+
+	text, isError = providerkittest.Call(providerkittest.AccountContext(t, demo), t, tools["azure_vm_get"], map[string]any{"name": "web"})
+	require.True(t, isError)
+	require.Contains(t, text, "resource_group")
+}
+
+func TestVMAction_PollsAsyncOperation(t *testing.T) {
+	t.Parallel()
+
+	_, _, tools := setup(t, 0)
+	progress := &providerkittest.Progress{}
+	ctx := contracts.WithProgressReporter(providerkittest.AccountContext(t, demo), progress)
+
+	text, isError := providerkittest.Call(ctx, t, tools["azure_vm_action"], map[string]any{"resource_group": "prod", "name": "web", "action": "deallocate"})
+	require.False(t, isError, text)
+	var result operationResult
+	require.NoError(t, json.Unmarshal([]byte(text), &result))
+	require.Equal(t, "Succeeded", result.Operation.Status)
+	require.Contains(t, result.Operation.URL, "/operations/", "Azure-AsyncOperation is preferred over Location")
+	require.Equal(t, []float64{50, 100}, progress.Values())
+
+	text, isError = providerkittest.Call(ctx, t, tools["azure_vm_action"], map[string]any{"resource_group": "prod", "name": "broken", "action": "start"})
+	require.True(t, isError)
+	require.Contains(t, text, providerkit.ErrActionFailed.Error())
+	require.Contains(t, text, "AllocationFailed: No capacity in eastus")
+
+	text, isError = providerkittest.Call(ctx, t, tools["azure_vm_action"], map[string]any{"resource_group": "prod", "name": "web", "action": "stop"})
+	require.True(t, isError)
+	require.Contains(t, text, "action must be one of")
+}
+
+func TestVMAction_PollsLocation(t *testing.T) {
+	t.Parallel()
+
+	_, _, tools := setup(t, 0)
+	ctx := providerkittest.AccountContext(t, demo)
+
+	text, isError := providerkittest.Call(ctx, t, tools["azure_vm_action"], map[string]any{"resource_group": "prod", "name": "web", "action": "restart", "wait": false})
+	require.False(t, isError, text)
+	var started operationResult
+	require.NoError(t, json.Unmarshal([]byte(text), &started))
+	require.Equal(t, "InProgress", started.Operation.Status)
+
+	text, isError = providerkittest.Call(ctx, t, tools["azure_operation_get"], map[string]any{"url": started.Operation.URL})
+	require.False(t, isError, text)
+	require.Contains(t, text, `"status": "InProgress"`, "202 Accepted means the operation is still running")
+
+	text, isError = providerkittest.Call(ctx, t, tools["azure_operation_wait"], map[string]any{"url": started.Operation.URL})
+	require.False(t, isError, text)
+	require.Contains(t, text, `"status": "Succeeded"`)
+
+	text, isError = providerkittest.Call(ctx, t, tools["azure_operation_wait"], map[string]any{"url": "https://attacker.example.com/subscriptions/" + subscription})
+	require.True(t, isError)
+	require.Contains(t, text, azure.ErrForeignLink.Error())
+}
+
+func TestNSGRuleCreate_BuildsRule(t *testing.T) {
+	t.Parallel()
+
+	api, _, tools := setup(t, 0)
+
+	text, isError := providerkittest.Call(providerkittest.AccountContext(t, demo), t, tools["azure_nsg_rule_create"], map[string]any{
+		"resource_group": "prod", "nsg": "web-nsg", "name": "allow-web", "priority": float64(200),
+		"destination_ports": []any{"80", "443"}, "sources": []any{"203.0.113.0/24"},
+	})
+	require.False(t, isError, text)
+	var result operationResult
+	require.NoError(t, json.Unmarshal([]byte(text), &result))
+	var rule azure.SecurityRule
+	require.NoError(t, json.Unmarshal(result.Resource, &rule))
+	require.Equal(t, "Succeeded", rule.Properties.ProvisioningState, "the rule is read again once the operation is done")
+
+	body := api.bodies["/subscriptions/"+subscription+"/resourceGroups/prod/providers/Microsoft.Network/networkSecurityGroups/web-nsg/securityRules/allow-web"]
+	require.Equal(t, map[string]any{
+		"priority": float64(200), "direction": "Inbound", "access": "Allow", "protocol": "Tcp",
+		"sourceAddressPrefix": "203.0.113.0/24", "sourcePortRange": "*",
+		"destinationAddressPrefix": "*", "destinationPortRanges": []any{"80", "443"},
+	}, body["properties"])
+
+	text, isError = providerkittest.Call(providerkittest.AccountContext(t, demo), t, tools["azure_nsg_rule_create"], map[string]any{
+		"resource_group": "prod", "nsg": "web-nsg", "name": "bad", "priority": float64(300),
+		"destination_ports": []any{"22"}, "protocol": "sctp",
+	})
+	require.True(t, isError)
+	require.Contains(t, text, "protocol must be one of")
+
+	text, isError = providerkittest.Call(providerkittest.AccountContext(t, demo), t, tools["azure_nsg_rule_create"], map[string]any{
+		"resource_group": "prod", "nsg": "web-nsg", "name": "broken", "priority": float64(400), "destination_ports": []any{"22"},
+	})
+	require.False(t, isError, "the rule exists, so it is returned rather than an error")
+	var created struct {
+		Resource operationResult `json:"resource"`
+		Error    string          `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(text), &created))
+	require.NoError(t, json.Unmarshal(created.Resource.Resource, &rule))
+	require.Equal(t, "broken", rule.Name)
+	require.Contains(t, created.Error, "AllocationFailed: No capacity in eastus")
+}
+
+func TestLists_FollowNextLink(t *testing.T) {
+	t.Parallel()
+
+	_, _, tools := setup(t, 5)
+	ctx := providerkittest.AccountContext(t, demo)
+
+	var names []string
+	params := map[string]any{"limit": float64(3)}
+	for {
+		text, isError := providerkittest.Call(ctx, t, tools["azure_resource_groups_list"], params)
+		require.False(t, isError, text)
+
+		var page pagination.Page[azure.ResourceGroup]
+		require.NoError(t, json.Unmarshal([]byte(text), &page))
+		for _, group := range page.Items {
+			names = append(names, group.Name)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		params["cursor"] = page.NextCursor
+	}
+	require.Equal(t, []string{"rg-0", "rg-1", "rg-2", "rg-3", "rg-4"}, names)
+
+	text, isError := providerkittest.Call(ctx, t, tools["azure_disks_list"], map[string]any{})
+	require.True(t, isError)
+	require.Contains(t, text, azure.ErrForeignLink.Error(), "the token is never sent outside the endpoint")
+}
+
+func TestCredentials_CLIShapeAndErrors(t *testing.T) {
+	t.Parallel()
+
+	_, _, tools := setup(t, 1)
+
+	text, isError := providerkittest.Call(providerkittest.AccountContext(t, cli), t, tools["azure_resource_groups_list"], map[string]any{"subscription_id": subscription})
+	require.False(t, isError, text)
+
+	text, isError = providerkittest.Call(providerkittest.AccountContext(t, cli), t, tools["azure_resource_groups_list"], map[string]any{})
+	require.True(t, isError)
+	require.Contains(t, text, azure.ErrNoSubscription.Error())
+
+	text, isError = providerkittest.Call(providerkittest.AccountContext(t, stale), t, tools["azure_resource_groups_list"], map[string]any{"subscription_id": subscription})
+	require.True(t, isError)
+	require.Contains(t, text, providerkit.ErrUnauthorized.Error())
+	require.Contains(t, text, "AADSTS7000215: Invalid client secret provided.")
+	require.NotContains(t, text, "Trace ID")
+
+	text, isError = providerkittest.Call(providerkittest.AccountContext(t, demo), t, tools["azure_vm_get"], map[string]any{"resource_group": "prod", "name": "ghost"})
+	require.True(t, isError)
+	require.Contains(t, text, providerkit.ErrNotFound.Error())
+	require.Contains(t, text, "ResourceNotFound: The Resource")
+}
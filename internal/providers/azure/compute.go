@@ -0,0 +1,273 @@
+package azure
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/idempotency"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+)
+
+// Resource types of the compute tools.
+const (
+	vmType   = "Microsoft.Compute/virtualMachines"
+	diskType = "Microsoft.Compute/disks"
+)
+
+// VM is an Azure virtual machine. PowerState is filled from the instance view
+// when the call requests it.
+//
+//nolint:tagliatelle // JSON field names match the ARM API.
+type VM struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Location   string            `json:"location"`
+	Zones      []string          `json:"zones,omitempty"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	PowerState string            `json:"powerState,omitempty"`
+	Properties struct {
+		VMID              string `json:"vmId"`
+		ProvisioningState string `json:"provisioningState"`
+		HardwareProfile   struct {
+			VMSize string `json:"vmSize"`
+		} `json:"hardwareProfile"`
+		StorageProfile struct {
+			OSDisk    DiskRef   `json:"osDisk"`
+			DataDisks []DiskRef `json:"dataDisks,omitempty"`
+		} `json:"storageProfile"`
+		OSProfile *struct {
+			ComputerName  string `json:"computerName"`
+			AdminUsername string `json:"adminUsername"`
+		} `json:"osProfile,omitempty"`
+		NetworkProfile struct {
+			NetworkInterfaces []Reference `json:"networkInterfaces,omitempty"`
+		} `json:"networkProfile"`
+		InstanceView *InstanceView `json:"instanceView,omitempty"`
+	} `json:"properties"`
+}
+
+// DiskRef is a disk of a virtual machine's storage profile.
+//
+//nolint:tagliatelle // JSON field names match the ARM API.
+type DiskRef struct {
+	Name        string     `json:"name"`
+	Lun         *int       `json:"lun,omitempty"`
+	OSType      string     `json:"osType,omitempty"`
+	DiskSizeGB  int        `json:"diskSizeGB,omitempty"`
+	Caching     string     `json:"caching,omitempty"`
+	ManagedDisk *Reference `json:"managedDisk,omitempty"`
+}
+
+// InstanceView is the runtime state of a virtual machine.
+//
+//nolint:tagliatelle // JSON field names match the ARM API.
+type InstanceView struct {
+	ComputerName string `json:"computerName,omitempty"`
+	OSName       string `json:"osName,omitempty"`
+	Statuses     []struct {
+		Code          string `json:"code"`
+		DisplayStatus string `json:"displayStatus"`
+		Time          string `json:"time,omitempty"`
+	} `json:"statuses"`
+}
+
+// setPowerState copies the power state out of the instance view, as in
+// "running" for the status code PowerState/running.
+func (vm *VM) setPowerState() {
+	if vm.Properties.InstanceView == nil {
+		return
+	}
+	for _, status := range vm.Properties.InstanceView.Statuses {
+		if state, ok := strings.CutPrefix(status.Code, "PowerState/"); ok {
+			vm.PowerState = state
+		}
+	}
+}
+
+// Disk is an Azure managed disk.
+//
+//nolint:tagliatelle // JSON field names match the ARM API.
+type Disk struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Location  string            `json:"location"`
+	ManagedBy string            `json:"managedBy,omitempty"`
+	Zones     []string          `json:"zones,omitempty"`
+	Tags      map[string]string `json:"tags,omitempty"`
+	SKU       *struct {
+		Name string `json:"name"`
+	} `json:"sku,omitempty"`
+	Properties struct {
+		DiskSizeGB        int    `json:"diskSizeGB"`
+		DiskState         string `json:"diskState"`
+		OSType            string `json:"osType,omitempty"`
+		ProvisioningState string `json:"provisioningState"`
+		TimeCreated       string `json:"timeCreated,omitempty"`
+	} `json:"properties"`
+}
+
+func (p *Provider) listVMsTool() *providerkit.Tool {
+	tool := mcp.NewTool("azure_vms_list",
+		mcp.WithDescription("Lists the virtual machines of a subscription or resource group"),
+		withResourceGroup(false),
+		withSubscription(),
+		mcp.WithBoolean("power_state", mcp.Description("Include each VM's power state (default true)"), mcp.DefaultBool(true)),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		scope, err := p.scope(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		query := apiVersion(computeAPIVersion)
+		if request.GetBool("power_state", true) {
+			// Subscription-wide listings take statusOnly, resource group
+			// listings $expand; both add the instance view.
+			if request.GetString("resource_group", "") == "" {
+				query.Set("statusOnly", "true")
+			} else {
+				query.Set("$expand", "instanceView")
+			}
+		}
+
+		page, err := list[VM](ctx, p, request, scope+"/providers/"+vmType, query)
+		for i := range page.Items {
+			page.Items[i].setPowerState()
+		}
+
+		return providerkit.Result(page, err)
+	})
+}
+
+func (p *Provider) getVMTool() *providerkit.Tool {
+	tool := mcp.NewTool("azure_vm_get",
+		mcp.WithDescription("Returns a virtual machine with its instance view and power state"),
+		withResourceGroup(true),
+		mcp.WithString("name", mcp.Required(), mcp.Description("VM name")),
+		withSubscription(),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		group, name, err := resourceArgs(request, "name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		path, err := p.resourcePath(ctx, request, vmType, group, name)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		query := apiVersion(computeAPIVersion)
+		query.Set("$expand", "instanceView")
+
+		var vm VM
+		err = p.api.Do(ctx, http.MethodGet, path, query, nil, &vm)
+		vm.setPowerState()
+
+		return providerkit.Result(vm, err)
+	})
+}
+
+// vmActions are the actions of azure_vm_action.
+var vmActions = []string{"start", "deallocate", "restart", "powerOff"}
+
+func (p *Provider) vmActionTool() *providerkit.Tool {
+	tool := mcp.NewTool("azure_vm_action",
+		mcp.WithDescription("Starts, deallocates, restarts or powers off a virtual machine. "+
+			"Deallocating releases the compute resources and stops billing for them; powering off keeps them allocated."),
+		withResourceGroup(true),
+		mcp.WithString("name", mcp.Required(), mcp.Description("VM name")),
+		mcp.WithString("action", mcp.Required(), mcp.Description("Action to perform"), mcp.Enum(vmActions...)),
+		withSubscription(),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		providerkit.WithWaitParam(),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		action, err := request.RequireString("action")
+		if err != nil || !slices.Contains(vmActions, action) {
+			return mcp.NewToolResultError("action must be one of " + strings.Join(vmActions, ", ")), nil
+		}
+		group, name, err := resourceArgs(request, "name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		path, err := p.resourcePath(ctx, request, vmType, group, name)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		return p.start(ctx, request, providerkit.Call{
+			Method: http.MethodPost,
+			Path:   path + "/" + action,
+			Query:  apiVersion(computeAPIVersion),
+		}, nil)
+	})
+}
+
+func (p *Provider) listDisksTool() *providerkit.Tool {
+	tool := mcp.NewTool("azure_disks_list",
+		mcp.WithDescription("Lists the managed disks of a subscription or resource group"),
+		withResourceGroup(false),
+		withSubscription(),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		scope, err := p.scope(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		return providerkit.Result(list[Disk](ctx, p, request, scope+"/providers/"+diskType, apiVersion(disksAPIVersion)))
+	})
+}
+
+func (p *Provider) getDiskTool() *providerkit.Tool {
+	tool := mcp.NewTool("azure_disk_get",
+		mcp.WithDescription("Returns a managed disk"),
+		withResourceGroup(true),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Disk name")),
+		withSubscription(),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		group, name, err := resourceArgs(request, "name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		path, err := p.resourcePath(ctx, request, diskType, group, name)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		var disk Disk
+		err = p.api.Do(ctx, http.MethodGet, path, apiVersion(disksAPIVersion), nil, &disk)
+
+		return providerkit.Result(disk, err)
+	})
+}
@@ -0,0 +1,268 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/idempotency"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+)
+
+// nsgType is the resource type of network security groups.
+const nsgType = "Microsoft.Network/networkSecurityGroups"
+
+// NSG is a network security group.
+//
+//nolint:tagliatelle // JSON field names match the ARM API.
+type NSG struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Location   string            `json:"location"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	Properties struct {
+		ProvisioningState    string         `json:"provisioningState"`
+		SecurityRules        []SecurityRule `json:"securityRules"`
+		DefaultSecurityRules []SecurityRule `json:"defaultSecurityRules,omitempty"`
+		NetworkInterfaces    []Reference    `json:"networkInterfaces,omitempty"`
+		Subnets              []Reference    `json:"subnets,omitempty"`
+	} `json:"properties"`
+}
+
+// SecurityRule is a rule of a network security group. Single and multiple
+// prefixes and port ranges are set in separate fields, as ARM requires.
+//
+//nolint:tagliatelle // JSON field names match the ARM API.
+type SecurityRule struct {
+	ID         string                 `json:"id,omitempty"`
+	Name       string                 `json:"name"`
+	Properties SecurityRuleProperties `json:"properties"`
+}
+
+// SecurityRuleProperties are the settings of a security rule.
+//
+//nolint:tagliatelle // JSON field names match the ARM API.
+type SecurityRuleProperties struct {
+	Description                string   `json:"description,omitempty"`
+	Priority                   int      `json:"priority"`
+	Direction                  string   `json:"direction"`
+	Access                     string   `json:"access"`
+	Protocol                   string   `json:"protocol"`
+	SourceAddressPrefix        string   `json:"sourceAddressPrefix,omitempty"`
+	SourceAddressPrefixes      []string `json:"sourceAddressPrefixes,omitempty"`
+	SourcePortRange            string   `json:"sourcePortRange,omitempty"`
+	SourcePortRanges           []string `json:"sourcePortRanges,omitempty"`
+	DestinationAddressPrefix   string   `json:"destinationAddressPrefix,omitempty"`
+	DestinationAddressPrefixes []string `json:"destinationAddressPrefixes,omitempty"`
+	DestinationPortRange       string   `json:"destinationPortRange,omitempty"`
+	DestinationPortRanges      []string `json:"destinationPortRanges,omitempty"`
+	ProvisioningState          string   `json:"provisioningState,omitempty"`
+}
+
+func (p *Provider) listNSGsTool() *providerkit.Tool {
+	tool := mcp.NewTool("azure_nsgs_list",
+		mcp.WithDescription("Lists the network security groups of a subscription or resource group"),
+		withResourceGroup(false),
+		withSubscription(),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		scope, err := p.scope(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		return providerkit.Result(list[NSG](ctx, p, request, scope+"/providers/"+nsgType, apiVersion(networkAPIVersion)))
+	})
+}
+
+func (p *Provider) getNSGTool() *providerkit.Tool {
+	tool := mcp.NewTool("azure_nsg_get",
+		mcp.WithDescription("Returns a network security group with its rules"),
+		withResourceGroup(true),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Network security group name")),
+		withSubscription(),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		group, name, err := resourceArgs(request, "name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		path, err := p.resourcePath(ctx, request, nsgType, group, name)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		var nsg NSG
+		err = p.api.Do(ctx, http.MethodGet, path, apiVersion(networkAPIVersion), nil, &nsg)
+
+		return providerkit.Result(nsg, err)
+	})
+}
+
+// Allowed values of security rule settings.
+var (
+	ruleDirections = []string{"Inbound", "Outbound"}
+	ruleAccesses   = []string{"Allow", "Deny"}
+	ruleProtocols  = []string{"Tcp", "Udp", "Icmp", "Esp", "Ah", "*"}
+)
+
+// ruleProperties builds the settings of a security rule from the arguments of
+// azure_nsg_rule_create.
+func ruleProperties(request mcp.CallToolRequest) (SecurityRuleProperties, error) {
+	priority, err := request.RequireInt("priority")
+	if err != nil {
+		return SecurityRuleProperties{}, err
+	}
+	ports, err := request.RequireStringSlice("destination_ports")
+	if err != nil {
+		return SecurityRuleProperties{}, err
+	}
+
+	props := SecurityRuleProperties{
+		Description: request.GetString("description", ""),
+		Priority:    priority,
+		Direction:   request.GetString("direction", "Inbound"),
+		Access:      request.GetString("access", "Allow"),
+		Protocol:    request.GetString("protocol", "Tcp"),
+	}
+	for _, setting := range []struct {
+		value   string
+		allowed []string
+		name    string
+	}{
+		{props.Direction, ruleDirections, "direction"},
+		{props.Access, ruleAccesses, "access"},
+		{props.Protocol, ruleProtocols, "protocol"},
+	} {
+		if !slices.Contains(setting.allowed, setting.value) {
+			return SecurityRuleProperties{}, fmt.Errorf("%w: %s must be one of %s", ErrInvalidRule, setting.name, strings.Join(setting.allowed, ", "))
+		}
+	}
+
+	props.DestinationPortRange, props.DestinationPortRanges = single(ports)
+	props.SourcePortRange, props.SourcePortRanges = single(request.GetStringSlice("source_ports", []string{"*"}))
+	props.SourceAddressPrefix, props.SourceAddressPrefixes = single(request.GetStringSlice("sources", []string{"*"}))
+	props.DestinationAddressPrefix, props.DestinationAddressPrefixes = single(request.GetStringSlice("destinations", []string{"*"}))
+
+	return props, nil
+}
+
+// single splits values into the single-value and list fields of a security
+// rule: ARM rejects a list with one element where the single field is meant.
+func single(values []string) (string, []string) {
+	switch len(values) {
+	case 0:
+		return "*", nil
+	case 1:
+		return values[0], nil
+	default:
+		return "", values
+	}
+}
+
+func (p *Provider) createNSGRuleTool() *providerkit.Tool {
+	tool := mcp.NewTool("azure_nsg_rule_create",
+		mcp.WithDescription("Creates or replaces a rule of a network security group. "+
+			"Lower priorities are evaluated first; a rule with the same name is overwritten."),
+		withResourceGroup(true),
+		mcp.WithString("nsg", mcp.Required(), mcp.Description("Network security group name")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Rule name")),
+		mcp.WithNumber("priority", mcp.Required(), mcp.Description("Priority, unique within the group and direction"), mcp.Min(100), mcp.Max(4096)),
+		mcp.WithArray("destination_ports", mcp.Required(), mcp.Items(stringItems),
+			mcp.Description(`Destination ports or ranges, such as ["22"] or ["80", "443", "8000-8100"]; "*" for any`)),
+		mcp.WithString("direction", mcp.Description("Traffic direction (default Inbound)"), mcp.Enum(ruleDirections...)),
+		mcp.WithString("access", mcp.Description("Whether matching traffic is allowed (default Allow)"), mcp.Enum(ruleAccesses...)),
+		mcp.WithString("protocol", mcp.Description("Protocol (default Tcp)"), mcp.Enum(ruleProtocols...)),
+		mcp.WithArray("sources", mcp.Items(stringItems),
+			mcp.Description(`Source CIDRs or service tags, such as ["203.0.113.0/24"] or ["Internet"] (default any)`)),
+		mcp.WithArray("source_ports", mcp.Items(stringItems), mcp.Description("Source ports or ranges (default any)")),
+		mcp.WithArray("destinations", mcp.Items(stringItems), mcp.Description("Destination CIDRs or service tags (default any)")),
+		mcp.WithString("description", mcp.Description("Rule description (optional)")),
+		withSubscription(),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		providerkit.WithWaitParam(),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		group, nsg, err := resourceArgs(request, "nsg")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		name, err := request.RequireString("name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		props, err := ruleProperties(request)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		path, err := p.resourcePath(ctx, request, nsgType, group, nsg)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		return p.start(ctx, request, providerkit.Call{
+			Method: http.MethodPut,
+			Path:   path + "/securityRules/" + url.PathEscape(name),
+			Query:  apiVersion(networkAPIVersion),
+			Body:   SecurityRule{Name: name, Properties: props},
+		}, &SecurityRule{})
+	})
+}
+
+func (p *Provider) deleteNSGRuleTool() *providerkit.Tool {
+	tool := mcp.NewTool("azure_nsg_rule_delete",
+		mcp.WithDescription("Deletes a rule of a network security group"),
+		withResourceGroup(true),
+		mcp.WithString("nsg", mcp.Required(), mcp.Description("Network security group name")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Rule name")),
+		withSubscription(),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		providerkit.WithWaitParam(),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		group, nsg, err := resourceArgs(request, "nsg")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		name, err := request.RequireString("name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		path, err := p.resourcePath(ctx, request, nsgType, group, nsg)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		return p.start(ctx, request, providerkit.Call{
+			Method: http.MethodDelete,
+			Path:   path + "/securityRules/" + url.PathEscape(name),
+			Query:  apiVersion(networkAPIVersion),
+		}, nil)
+	})
+}
@@ -0,0 +1,191 @@
+package azure
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+)
+
+// Operation is an ARM asynchronous operation, tracked through the
+// Azure-AsyncOperation or Location URL of the request that started it.
+//
+//nolint:tagliatelle // JSON field names match the ARM API.
+type Operation struct {
+	URL             string       `json:"url"`
+	Status          string       `json:"status"`
+	PercentComplete float64      `json:"percentComplete,omitempty"`
+	StartTime       string       `json:"startTime,omitempty"`
+	EndTime         string       `json:"endTime,omitempty"`
+	Error           *ErrorDetail `json:"error,omitempty"`
+}
+
+// Operation statuses. Location URLs have no status body, so their 202 and
+// 200/204 responses map to InProgress and Succeeded.
+const (
+	statusInProgress = "InProgress"
+	statusSucceeded  = "Succeeded"
+	statusFailed     = "Failed"
+	statusCanceled   = "Canceled"
+)
+
+// done reports whether the operation has finished.
+func (o Operation) done() bool {
+	return o.Status == statusSucceeded || o.Status == statusFailed || o.Status == statusCanceled
+}
+
+// poll refreshes the operation's status.
+func (p *Provider) poll(ctx context.Context, op Operation) (Operation, error) {
+	relative, err := p.relative(op.URL)
+	if err != nil {
+		return op, err
+	}
+	call, err := linkCall(relative)
+	if err != nil {
+		return op, err
+	}
+
+	var status int
+	var body struct {
+		Status          string       `json:"status"`
+		PercentComplete float64      `json:"percentComplete"`
+		StartTime       string       `json:"startTime"`
+		EndTime         string       `json:"endTime"`
+		Error           *ErrorDetail `json:"error"`
+	}
+	call.Out, call.Status = &body, &status
+	if _, err := p.api.Call(ctx, call); err != nil {
+		return op, err
+	}
+
+	switch {
+	case status == http.StatusAccepted:
+		op.Status = statusInProgress
+	case body.Status == "":
+		op.Status = statusSucceeded
+	default:
+		op.Status = body.Status
+	}
+	op.PercentComplete, op.StartTime, op.EndTime, op.Error = body.PercentComplete, body.StartTime, body.EndTime, body.Error
+
+	return op, nil
+}
+
+// waitOperation polls an operation until it is done, reporting its progress.
+func (p *Provider) waitOperation(ctx context.Context, op Operation) (Operation, error) {
+	_, err := providerkit.Wait(ctx, p.pollInterval, func(ctx context.Context) (providerkit.ActionStatus, error) {
+		var err error
+		if op, err = p.poll(ctx, op); err != nil {
+			return providerkit.ActionStatus{}, err
+		}
+
+		status := providerkit.ActionStatus{
+			Done:    op.done(),
+			Failed:  op.Status == statusFailed || op.Status == statusCanceled,
+			Percent: op.PercentComplete,
+			Message: "operation " + op.Status,
+		}
+		if op.Error != nil && status.Failed {
+			status.Message += ": " + op.Error.String()
+		}
+
+		return status, nil
+	})
+
+	return op, err
+}
+
+// operationResult is returned by tools that start operations: the operation
+// when it runs asynchronously, and the resource once it is done.
+type operationResult struct {
+	Operation *Operation `json:"operation,omitempty"`
+	Resource  any        `json:"resource,omitempty"`
+}
+
+// start sends a mutation and, when ARM runs it asynchronously, waits for it if
+// the call asks to. When resource is not nil, the response is decoded into it
+// and, after a successful wait, the resource at path is read again. ARM has
+// recorded the resource once the mutation is accepted, so a failed wait or
+// read returns it with the error.
+func (p *Provider) start(ctx context.Context, request mcp.CallToolRequest, call providerkit.Call, resource any) (*mcp.CallToolResult, error) {
+	call.Out = resource
+	header, err := p.api.Call(ctx, call)
+	if err != nil {
+		return providerkit.Result(nil, err)
+	}
+
+	link := header.Get("Azure-AsyncOperation")
+	if link == "" {
+		link = header.Get("Location")
+	}
+	if link == "" {
+		return providerkit.Result(operationResult{Resource: resource}, nil)
+	}
+
+	op := Operation{URL: link, Status: statusInProgress}
+	if !request.GetBool(providerkit.WaitParam, true) {
+		return providerkit.Result(operationResult{Operation: &op, Resource: resource}, nil)
+	}
+
+	op, err = p.waitOperation(ctx, op)
+	switch {
+	case resource == nil:
+		return providerkit.Result(operationResult{Operation: &op}, err)
+	case err != nil:
+		return providerkit.CreatedResult(operationResult{Operation: &op, Resource: resource}, err)
+	}
+
+	refresh := providerkit.Call{Method: http.MethodGet, Path: call.Path, Query: call.Query, Out: resource}
+	if _, err := p.api.Call(ctx, refresh); err != nil {
+		return providerkit.CreatedResult(operationResult{Operation: &op, Resource: resource}, err)
+	}
+
+	return providerkit.Result(operationResult{Operation: &op, Resource: resource}, nil)
+}
+
+// withOperation adds the argument naming an operation.
+func withOperation() mcp.ToolOption {
+	return mcp.WithString("url", mcp.Required(),
+		mcp.Description("Operation URL returned by a call with wait=false (its Azure-AsyncOperation or Location URL)"))
+}
+
+func (p *Provider) getOperationTool() *providerkit.Tool {
+	tool := mcp.NewTool("azure_operation_get",
+		mcp.WithDescription("Returns the status of an Azure Resource Manager asynchronous operation"),
+		withOperation(),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		link, err := request.RequireString("url")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return providerkit.Result(p.poll(ctx, Operation{URL: link}))
+	})
+}
+
+func (p *Provider) waitOperationTool() *providerkit.Tool {
+	tool := mcp.NewTool("azure_operation_wait",
+		mcp.WithDescription("Waits for an Azure Resource Manager asynchronous operation, such as one returned by a call with wait=false, reporting progress"),
+		withOperation(),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		link, err := request.RequireString("url")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return providerkit.Result(p.waitOperation(ctx, Operation{URL: link}))
+	})
+}
@@ -0,0 +1,96 @@
+package azure
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+)
+
+// Subscription is an Azure subscription.
+//
+//nolint:tagliatelle // JSON field names match the ARM API.
+type Subscription struct {
+	ID             string            `json:"id"`
+	SubscriptionID string            `json:"subscriptionId"`
+	DisplayName    string            `json:"displayName"`
+	State          string            `json:"state"`
+	TenantID       string            `json:"tenantId,omitempty"`
+	Tags           map[string]string `json:"tags,omitempty"`
+}
+
+// ResourceGroup is an Azure resource group.
+//
+//nolint:tagliatelle // JSON field names match the ARM API.
+type ResourceGroup struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Location   string            `json:"location"`
+	ManagedBy  string            `json:"managedBy,omitempty"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	Properties struct {
+		ProvisioningState string `json:"provisioningState"`
+	} `json:"properties"`
+}
+
+func (p *Provider) listSubscriptionsTool() *providerkit.Tool {
+	tool := mcp.NewTool("azure_subscriptions_list",
+		mcp.WithDescription("Lists the subscriptions the service principal can access"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return providerkit.Result(list[Subscription](ctx, p, request, "/subscriptions", apiVersion(subscriptionsAPIVersion)))
+	})
+}
+
+func (p *Provider) listResourceGroupsTool() *providerkit.Tool {
+	tool := mcp.NewTool("azure_resource_groups_list",
+		mcp.WithDescription("Lists the resource groups of a subscription"),
+		withSubscription(),
+		mcp.WithString("tag", mcp.Description("Only list resource groups with this tag, as name=value or name (optional)")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		subscription, err := p.subscription(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		query := apiVersion(resourceGroupsAPIVersion)
+		if tag := request.GetString("tag", ""); tag != "" {
+			query.Set("$filter", tagFilter(tag))
+		}
+
+		return providerkit.Result(list[ResourceGroup](ctx, p, request, "/subscriptions/"+url.PathEscape(subscription)+"/resourcegroups", query))
+	})
+}
+
+// tagFilter returns the OData filter selecting resources with a tag given as
+// name=value or name.
+func tagFilter(tag string) string {
+	name, value, ok := strings.Cut(tag, "=")
+	filter := "tagName eq '" + escapeOData(name) + "'"
+	if ok {
+		filter += " and tagValue eq '" + escapeOData(value) + "'"
+	}
+
+	return filter
+}
+
+// escapeOData escapes a string literal of an OData filter.
+func escapeOData(value string) string {
+	return strings.ReplaceAll(value, "'", "''")
+}
@@ -0,0 +1,209 @@
+// Package cloudflare implements the Cloudflare API v4 provider for zones, DNS
+// records and cache purges.
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	// Name is the provider name used in settings and accounts.
+	Name = "cloudflare"
+
+	// DefaultAPIURL is the Cloudflare API v4 endpoint, overridden by the
+	// api_url setting.
+	DefaultAPIURL = "https://api.cloudflare.com/client/v4"
+
+	// DefaultTokenRef is the credential used when neither the account nor the
+	// token_ref setting names one.
+	DefaultTokenRef = "env:CLOUDFLARE_API_TOKEN"
+
+	// maxPageSize is the largest per_page the zone and DNS record lists accept.
+	maxPageSize = 50
+)
+
+// Provider is the Cloudflare provider.
+type Provider struct {
+	cfg contracts.ProviderConfig
+	api *providerkit.API
+}
+
+// New creates an uninitialized Cloudflare provider.
+func New() contracts.Provider {
+	return &Provider{}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return Name
+}
+
+// Initialize configures the Cloudflare API client.
+func (p *Provider) Initialize(_ context.Context, cfg contracts.ProviderConfig) error {
+	p.cfg = cfg
+	p.api = providerkit.NewTokenAPI(cfg, providerkit.TokenAPI{
+		Provider:        Name,
+		DefaultURL:      DefaultAPIURL,
+		DefaultTokenRef: DefaultTokenRef,
+		ErrorMessage:    errorMessage,
+	})
+
+	return nil
+}
+
+// Tools returns the Cloudflare tools.
+func (p *Provider) Tools() []contracts.Tool {
+	return []contracts.Tool{
+		p.listZonesTool(),
+		p.getZoneTool(),
+		p.listRecordsTool(),
+		p.createRecordTool(),
+		p.updateRecordTool(),
+		p.deleteRecordTool(),
+		p.purgeCacheTool(),
+	}
+}
+
+// HealthCheck verifies the default account's API token.
+func (p *Provider) HealthCheck(ctx context.Context) error {
+	ctx = providerkit.DefaultAccountContext(ctx, p.cfg)
+
+	return p.do(ctx, http.MethodGet, "/user/tokens/verify", nil, nil, nil)
+}
+
+// Shutdown has nothing to release.
+func (p *Provider) Shutdown(context.Context) error {
+	return nil
+}
+
+// apiErrors is the errors array of a Cloudflare response.
+type apiErrors []struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// errorMessage extracts the codes and messages of a Cloudflare error response.
+func errorMessage(body []byte) string {
+	var decoded struct {
+		Errors apiErrors `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return ""
+	}
+
+	messages := make([]string, len(decoded.Errors))
+	for i, e := range decoded.Errors {
+		messages[i] = strconv.Itoa(e.Code) + ": " + e.Message
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// resultInfo is the pagination metadata of list responses.
+//
+//nolint:tagliatelle // JSON field names match the Cloudflare API.
+type resultInfo struct {
+	Page       int `json:"page"`
+	TotalPages int `json:"total_pages"`
+}
+
+// envelope is the wrapper of every Cloudflare response.
+//
+//nolint:tagliatelle // JSON field names match the Cloudflare API.
+type envelope struct {
+	Result     json.RawMessage `json:"result"`
+	ResultInfo resultInfo      `json:"result_info"`
+}
+
+// call sends a request and decodes the result of the response envelope into
+// out, returning the pagination metadata.
+func (p *Provider) call(ctx context.Context, method, path string, query url.Values, body, out any) (resultInfo, error) {
+	var env envelope
+	if err := p.api.Do(ctx, method, path, query, body, &env); err != nil {
+		return resultInfo{}, err
+	}
+	if out != nil && len(env.Result) > 0 {
+		if err := json.Unmarshal(env.Result, out); err != nil {
+			return resultInfo{}, fmt.Errorf("failed to decode Cloudflare result: %w", err)
+		}
+	}
+
+	return env.ResultInfo, nil
+}
+
+// do sends a request and decodes the result of the response envelope into out.
+func (p *Provider) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	_, err := p.call(ctx, method, path, query, body, out)
+	return err
+}
+
+// list fetches one page of a Cloudflare collection.
+func list[T any](ctx context.Context, p *Provider, request mcp.CallToolRequest, path string, query url.Values) (pagination.Page[T], error) {
+	cursors, req, err := providerkit.ParsePage(ctx, request)
+	if err != nil {
+		return pagination.Page[T]{}, err
+	}
+
+	page, size := req.ProviderPage(5, maxPageSize)
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("page", strconv.Itoa(page))
+	query.Set("per_page", strconv.Itoa(size))
+
+	items := []T{}
+	info, err := p.call(ctx, http.MethodGet, path, query, nil, &items)
+	if err != nil {
+		return pagination.Page[T]{}, err
+	}
+
+	return pagination.FromProviderPage(cursors, req, size, items, page < info.TotalPages), nil
+}
+
+// zoneIDPattern matches Cloudflare zone IDs.
+var zoneIDPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// zoneID returns the ID of a zone given as a zone ID or a domain name.
+func (p *Provider) zoneID(ctx context.Context, zone string) (string, error) {
+	if zoneIDPattern.MatchString(zone) {
+		return zone, nil
+	}
+
+	found, err := p.zoneByName(ctx, zone)
+
+	return found.ID, err
+}
+
+// zoneByName looks up a zone by its domain name.
+func (p *Provider) zoneByName(ctx context.Context, name string) (Zone, error) {
+	var zones []Zone
+	if err := p.do(ctx, http.MethodGet, "/zones", url.Values{"name": {strings.TrimSuffix(name, ".")}}, nil, &zones); err != nil {
+		return Zone{}, err
+	}
+	if len(zones) == 0 {
+		return Zone{}, fmt.Errorf("zone %q: %w", name, providerkit.ErrNotFound)
+	}
+
+	return zones[0], nil
+}
+
+// withZone adds the required zone argument.
+func withZone() mcp.ToolOption {
+	return mcp.WithString("zone", mcp.Required(), mcp.Description("Zone ID or domain name, such as example.com"))
+}
+
+// stringItems is the item schema of string array arguments.
+var stringItems = map[string]any{"type": "string"}
@@ -0,0 +1,270 @@
+package cloudflare_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/internal/providerkit/providerkittest"
+	"github.com/chadit/CloudMCP/internal/providers/cloudflare"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const zoneID = "023e105f4ecef8ad9ca31a8372d0c353"
+
+// ops is the default account, which tool calls run against.
+var ops = contracts.Account{Provider: cloudflare.Name, Alias: "ops", Credential: "cf-ops", Default: true}
+
+// fakeAPI is a stub of the Cloudflare API v4 with the zone example.com.
+type fakeAPI struct {
+	mu       sync.Mutex
+	records  map[string]*cloudflare.Record
+	requests []string
+	bodies   []map[string]any
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+	if r.Header.Get("Authorization") != "Bearer cf-token" {
+		writeError(w, http.StatusForbidden, 10000, "Authentication error")
+		return
+	}
+	var body map[string]any
+	if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+		_ = json.Unmarshal(data, &body)
+		f.bodies = append(f.bodies, body)
+	}
+
+	zone := cloudflare.Zone{ID: zoneID, Name: "example.com", Status: "active"}
+	records := "/zones/" + zoneID + "/dns_records"
+	path := r.URL.Path
+	switch {
+	case r.Method == http.MethodGet && path == "/user/tokens/verify":
+		writeResult(w, http.StatusOK, map[string]string{"status": "active"}, nil)
+	case r.Method == http.MethodGet && path == "/zones":
+		zones := []cloudflare.Zone{}
+		if name := r.URL.Query().Get("name"); name == "" || name == zone.Name {
+			zones = append(zones, zone)
+		}
+		writeResult(w, http.StatusOK, zones, map[string]int{"page": 1, "total_pages": 1})
+	case r.Method == http.MethodGet && path == "/zones/"+zoneID:
+		writeResult(w, http.StatusOK, zone, nil)
+	case r.Method == http.MethodGet && path == records:
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		size, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		all := []cloudflare.Record{}
+		for i := range 7 {
+			all = append(all, cloudflare.Record{ID: fmt.Sprintf("rec-%d", i), Name: fmt.Sprintf("host-%d.example.com", i), Type: "A"})
+		}
+		start := min((page-1)*size, len(all))
+		end := min(start+size, len(all))
+		writeResult(w, http.StatusOK, all[start:end], map[string]int{"page": page, "total_pages": (len(all) + size - 1) / size})
+	case r.Method == http.MethodPost && path == records:
+		if body["name"] == "dup" {
+			writeError(w, http.StatusBadRequest, 81057, "Record already exists.")
+			return
+		}
+		record := &cloudflare.Record{ID: "new", Name: fmt.Sprint(body["name"]), Type: fmt.Sprint(body["type"]), Content: fmt.Sprint(body["content"])}
+		f.records[record.ID] = record
+		writeResult(w, http.StatusOK, record, nil)
+	case strings.HasPrefix(path, records+"/"):
+		record, ok := f.records[strings.TrimPrefix(path, records+"/")]
+		if !ok {
+			writeError(w, http.StatusNotFound, 81044, "Record does not exist.")
+			return
+		}
+		switch r.Method {
+		case http.MethodDelete:
+			delete(f.records, record.ID)
+			writeResult(w, http.StatusOK, map[string]string{"id": record.ID}, nil)
+		case http.MethodPatch:
+			if proxied, ok := body["proxied"].(bool); ok {
+				record.Proxied = proxied
+			}
+			writeResult(w, http.StatusOK, record, nil)
+		default:
+			writeResult(w, http.StatusOK, record, nil)
+		}
+	case r.Method == http.MethodPost && path == "/zones/"+zoneID+"/purge_cache":
+		writeResult(w, http.StatusOK, map[string]string{"id": zoneID}, nil)
+	default:
+		writeError(w, http.StatusNotFound, 7003, "Could not route to "+path)
+	}
+}
+
+func writeResult(w http.ResponseWriter, status int, result, info any) {
+	resp := map[string]any{"success": true, "errors": []any{}, "messages": []any{}, "result": result}
+	if info != nil {
+		resp["result_info"] = info
+	}
+	providerkittest.WriteJSON(w, status, resp)
+}
+
+func writeError(w http.ResponseWriter, status, code int, message string) {
+	providerkittest.WriteJSON(w, status, map[string]any{"success": false, "errors": []map[string]any{{"code": code, "message": message}}, "result": nil})
+}
+
+// setup starts a stub API and returns the provider and its tools by name.
+func setup(t *testing.T) (*fakeAPI, contracts.Provider, map[string]contracts.Tool) {
+	t.Helper()
+
+	api := &fakeAPI{records: map[string]*cloudflare.Record{
+		"www": {ID: "www", Name: "www.example.com", Type: "A", Content: "203.0.113.1"},
+		"txt": {ID: "txt", Name: "example.com", Type: "TXT", Content: "v=spf1 -all"},
+	}}
+	srv := providerkittest.Serve(t, api)
+
+	provider := cloudflare.New()
+	tools := providerkittest.Setup(t, provider, contracts.ProviderConfig{
+		Settings:   map[string]string{"api_url": srv.URL},
+		HTTPClient: srv.Client(),
+		Secrets:    providerkittest.Secrets{"cf-ops": "cf-token"},
+		Accounts:   []contracts.Account{ops},
+	})
+
+	return api, provider, tools
+}
+
+func TestHealthCheck_VerifiesToken(t *testing.T) {
+	t.Parallel()
+
+	api, provider, _ := setup(t)
+	require.NoError(t, provider.HealthCheck(t.Context()))
+	require.Equal(t, []string{"GET /user/tokens/verify"}, api.requests)
+}
+
+func TestRecordsList_ResolvesZoneNameAndPaginates(t *testing.T) {
+	t.Parallel()
+
+	api, _, tools := setup(t)
+	ctx := providerkittest.AccountContext(t, ops)
+
+	var names []string
+	params := map[string]any{"zone": "example.com", "limit": float64(5)}
+	for {
+		text, isError := providerkittest.Call(ctx, t, tools["cloudflare_dns_records_list"], params)
+		require.False(t, isError, text)
+
+		var page pagination.Page[cloudflare.Record]
+		require.NoError(t, json.Unmarshal([]byte(text), &page))
+		for _, record := range page.Items {
+			names = append(names, record.Name)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		params["cursor"] = page.NextCursor
+	}
+	require.Len(t, names, 7)
+	require.Equal(t, "host-6.example.com", names[6])
+	require.Contains(t, api.requests, "GET /zones", "the zone name is looked up")
+
+	text, isError := providerkittest.Call(ctx, t, tools["cloudflare_dns_records_list"], map[string]any{"zone": "missing.example"})
+	require.True(t, isError)
+	require.Contains(t, text, providerkit.ErrNotFound.Error())
+}
+
+func TestRecordCreate_ValidatesAndSendsFields(t *testing.T) {
+	t.Parallel()
+
+	api, _, tools := setup(t)
+	ctx := providerkittest.AccountContext(t, ops)
+
+	text, isError := providerkittest.Call(ctx, t, tools["cloudflare_dns_record_create"], map[string]any{
+		"zone": zoneID, "type": "A", "name": "api", "content": "203.0.113.7", "proxied": true,
+	})
+	require.False(t, isError, text)
+	require.Equal(t, map[string]any{"type": "A", "name": "api", "content": "203.0.113.7", "proxied": true, "ttl": float64(1)}, api.bodies[0])
+
+	text, isError = providerkittest.Call(ctx, t, tools["cloudflare_dns_record_create"], map[string]any{
+		"zone": zoneID, "type": "TXT", "name": "api", "content": "hello", "proxied": true,
+	})
+	require.True(t, isError)
+	require.Contains(t, text, "can be proxied")
+
+	text, isError = providerkittest.Call(ctx, t, tools["cloudflare_dns_record_create"], map[string]any{
+		"zone": zoneID, "type": "A", "name": "api", "content": "203.0.113.7", "ttl": float64(30),
+	})
+	require.True(t, isError)
+	require.Contains(t, text, "ttl must be 1")
+
+	text, isError = providerkittest.Call(ctx, t, tools["cloudflare_dns_record_create"], map[string]any{
+		"zone": zoneID, "type": "A", "name": "dup", "content": "203.0.113.7",
+	})
+	require.True(t, isError)
+	require.Contains(t, text, "81057: Record already exists.")
+	require.Len(t, api.bodies, 2, "invalid records are not sent")
+}
+
+func TestRecordUpdate_ChecksTypeForProxied(t *testing.T) {
+	t.Parallel()
+
+	api, _, tools := setup(t)
+	ctx := providerkittest.AccountContext(t, ops)
+
+	text, isError := providerkittest.Call(ctx, t, tools["cloudflare_dns_record_update"], map[string]any{"zone": zoneID, "record_id": "www", "proxied": true})
+	require.False(t, isError, text)
+	require.Contains(t, text, `"proxied": true`)
+	require.Equal(t, map[string]any{"proxied": true}, api.bodies[0], "only the passed fields are sent")
+
+	text, isError = providerkittest.Call(ctx, t, tools["cloudflare_dns_record_update"], map[string]any{"zone": zoneID, "record_id": "txt", "proxied": true})
+	require.True(t, isError)
+	require.Contains(t, text, "can be proxied")
+}
+
+func TestRecordDelete_RequiresConfirmation(t *testing.T) {
+	t.Parallel()
+
+	api, _, tools := setup(t)
+	ctx := providerkittest.AccountContext(t, ops)
+
+	text, isError := providerkittest.Call(ctx, t, tools["cloudflare_dns_record_delete"], map[string]any{"zone": zoneID, "record_id": "www", "confirm": "www"})
+	require.True(t, isError)
+	require.Contains(t, text, `confirm must be the record name "www.example.com"`)
+	require.NotContains(t, api.requests, "DELETE /zones/"+zoneID+"/dns_records/www")
+
+	text, isError = providerkittest.Call(ctx, t, tools["cloudflare_dns_record_delete"], map[string]any{"zone": zoneID, "record_id": "www", "confirm": "www.example.com"})
+	require.False(t, isError, text)
+	require.Contains(t, api.requests, "DELETE /zones/"+zoneID+"/dns_records/www")
+}
+
+func TestCachePurge_BatchesAndConfirmsEverything(t *testing.T) {
+	t.Parallel()
+
+	api, _, tools := setup(t)
+	ctx := providerkittest.AccountContext(t, ops)
+
+	urls := make([]any, 45)
+	for i := range urls {
+		urls[i] = fmt.Sprintf("https://example.com/asset-%d.js", i)
+	}
+	text, isError := providerkittest.Call(ctx, t, tools["cloudflare_cache_purge"], map[string]any{"zone": "example.com", "urls": urls})
+	require.False(t, isError, text)
+	require.Contains(t, text, `"requests": 2`)
+	require.Len(t, api.bodies[0]["files"], 30)
+	require.Len(t, api.bodies[1]["files"], 15)
+
+	text, isError = providerkittest.Call(ctx, t, tools["cloudflare_cache_purge"], map[string]any{"zone": zoneID, "everything": true, "confirm": "example.org"})
+	require.True(t, isError)
+	require.Contains(t, text, `confirm must be the zone name "example.com"`)
+
+	text, isError = providerkittest.Call(ctx, t, tools["cloudflare_cache_purge"], map[string]any{"zone": zoneID, "everything": true, "confirm": "example.com"})
+	require.False(t, isError, text)
+	require.Equal(t, map[string]any{"purge_everything": true}, api.bodies[2])
+
+	text, isError = providerkittest.Call(ctx, t, tools["cloudflare_cache_purge"], map[string]any{"zone": zoneID, "urls": urls, "tags": []any{"css"}})
+	require.True(t, isError)
+	require.Contains(t, text, "exactly one of urls, tags or everything")
+}
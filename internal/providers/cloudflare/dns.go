@@ -0,0 +1,293 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/idempotency"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+)
+
+// Record is a DNS record of a zone. A TTL of 1 means automatic.
+//
+//nolint:tagliatelle // JSON field names match the Cloudflare API.
+type Record struct {
+	ID         string   `json:"id"`
+	ZoneID     string   `json:"zone_id,omitempty"`
+	ZoneName   string   `json:"zone_name,omitempty"`
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	Content    string   `json:"content"`
+	Proxiable  bool     `json:"proxiable"`
+	Proxied    bool     `json:"proxied"`
+	TTL        int      `json:"ttl"`
+	Priority   *int     `json:"priority,omitempty"`
+	Comment    string   `json:"comment,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	CreatedOn  string   `json:"created_on,omitempty"`
+	ModifiedOn string   `json:"modified_on,omitempty"`
+}
+
+// recordTypes are the record types the tools manage. Types with structured
+// data, such as SRV and CAA, are left to the dashboard.
+var recordTypes = []string{"A", "AAAA", "CNAME", "TXT", "MX", "NS", "PTR"}
+
+// proxyableTypes are the record types Cloudflare can proxy.
+var proxyableTypes = []string{"A", "AAAA", "CNAME"}
+
+// TTL bounds. 1 selects automatic TTL.
+const (
+	autoTTL = 1
+	minTTL  = 60
+	maxTTL  = 86400
+)
+
+// recordPath returns the API path of a record of a zone.
+func recordPath(zoneID, recordID string) string {
+	return "/zones/" + zoneID + "/dns_records/" + url.PathEscape(recordID)
+}
+
+// withRecordFields adds the arguments shared by record create and update.
+func withRecordFields(required bool) []mcp.ToolOption {
+	requiredOption := func(description string) []mcp.PropertyOption {
+		if required {
+			return []mcp.PropertyOption{mcp.Required(), mcp.Description(description)}
+		}
+		return []mcp.PropertyOption{mcp.Description(description + " (optional)")}
+	}
+
+	return []mcp.ToolOption{
+		mcp.WithString("type", append(requiredOption("Record type"), mcp.Enum(recordTypes...))...),
+		mcp.WithString("name", requiredOption("Record name, such as www or www.example.com; @ for the zone apex")...),
+		mcp.WithString("content", requiredOption("Record content, such as an IP address, a target host name or text")...),
+		mcp.WithNumber("ttl", mcp.Description("TTL in seconds, 60 to 86400, or 1 for automatic (default 1 on create)")),
+		mcp.WithBoolean("proxied", mcp.Description("Route traffic through Cloudflare; A, AAAA and CNAME records only (default false on create)")),
+		mcp.WithNumber("priority", mcp.Description("Priority of MX records"), mcp.Min(0), mcp.Max(65535)),
+		mcp.WithString("comment", mcp.Description("Comment shown in the dashboard (optional)")),
+	}
+}
+
+// recordFields returns the record fields present in the arguments, validated.
+func recordFields(request mcp.CallToolRequest) (map[string]any, error) {
+	args := request.GetArguments()
+	fields := map[string]any{}
+	for _, name := range []string{"type", "name", "content", "comment"} {
+		if value := request.GetString(name, ""); value != "" {
+			fields[name] = value
+		}
+	}
+	if recordType, ok := fields["type"].(string); ok && !slices.Contains(recordTypes, recordType) {
+		return nil, fmt.Errorf("%w: type must be one of %v", providerkit.ErrInvalidRequest, recordTypes)
+	}
+	if _, ok := args["ttl"]; ok {
+		ttl := request.GetInt("ttl", 0)
+		if ttl != autoTTL && (ttl < minTTL || ttl > maxTTL) {
+			return nil, fmt.Errorf("%w: ttl must be 1 (automatic) or between %d and %d", providerkit.ErrInvalidRequest, minTTL, maxTTL)
+		}
+		fields["ttl"] = ttl
+	}
+	if _, ok := args["proxied"]; ok {
+		fields["proxied"] = request.GetBool("proxied", false)
+	}
+	if _, ok := args["priority"]; ok {
+		fields["priority"] = request.GetInt("priority", 0)
+	}
+
+	return fields, nil
+}
+
+// checkProxied rejects proxying record types Cloudflare cannot proxy.
+func checkProxied(recordType string, fields map[string]any) error {
+	if proxied, _ := fields["proxied"].(bool); proxied && !slices.Contains(proxyableTypes, recordType) {
+		return fmt.Errorf("%w: only %v records can be proxied", providerkit.ErrInvalidRequest, proxyableTypes)
+	}
+
+	return nil
+}
+
+func (p *Provider) listRecordsTool() *providerkit.Tool {
+	tool := mcp.NewTool("cloudflare_dns_records_list",
+		mcp.WithDescription("Lists the DNS records of a zone"),
+		withZone(),
+		mcp.WithString("type", mcp.Description("Only list records of this type (optional)")),
+		mcp.WithString("name", mcp.Description("Only list records with this full name, such as www.example.com (optional)")),
+		mcp.WithString("content", mcp.Description("Only list records with this content (optional)")),
+		mcp.WithBoolean("proxied", mcp.Description("Only list proxied or unproxied records (optional)")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		zone, err := request.RequireString("zone")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		zoneID, err := p.zoneID(ctx, zone)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		query := url.Values{}
+		for _, filter := range []string{"type", "name", "content"} {
+			if value := request.GetString(filter, ""); value != "" {
+				query.Set(filter, value)
+			}
+		}
+		if _, ok := request.GetArguments()["proxied"]; ok {
+			query.Set("proxied", fmt.Sprint(request.GetBool("proxied", false)))
+		}
+
+		return providerkit.Result(list[Record](ctx, p, request, "/zones/"+zoneID+"/dns_records", query))
+	})
+}
+
+func (p *Provider) createRecordTool() *providerkit.Tool {
+	tool := mcp.NewTool("cloudflare_dns_record_create",
+		append(withRecordFields(true),
+			mcp.WithDescription("Creates a DNS record in a zone"),
+			withZone(),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(false),
+			mcp.WithOpenWorldHintAnnotation(true),
+			idempotency.WithParam(),
+			format.WithParam(),
+		)...,
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		zone, err := request.RequireString("zone")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		fields, err := recordFields(request)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		recordType, _ := fields["type"].(string)
+		if recordType == "" || fields["name"] == nil || fields["content"] == nil {
+			return mcp.NewToolResultError("type, name and content are required"), nil
+		}
+		if err := checkProxied(recordType, fields); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if _, ok := fields["ttl"]; !ok {
+			fields["ttl"] = autoTTL
+		}
+		zoneID, err := p.zoneID(ctx, zone)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		var record Record
+		err = p.do(ctx, http.MethodPost, "/zones/"+zoneID+"/dns_records", nil, fields, &record)
+
+		return providerkit.Result(record, err)
+	})
+}
+
+func (p *Provider) updateRecordTool() *providerkit.Tool {
+	tool := mcp.NewTool("cloudflare_dns_record_update",
+		append(withRecordFields(false),
+			mcp.WithDescription("Updates a DNS record. Only the fields passed are changed."),
+			withZone(),
+			mcp.WithString("record_id", mcp.Required(), mcp.Description("ID of the record")),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(true),
+			idempotency.WithParam(),
+			format.WithParam(),
+		)...,
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		zone, err := request.RequireString("zone")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		recordID, err := request.RequireString("record_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		fields, err := recordFields(request)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if len(fields) == 0 {
+			return mcp.NewToolResultError("pass at least one field to change"), nil
+		}
+		zoneID, err := p.zoneID(ctx, zone)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		// Proxying depends on the type, which the update may leave unchanged
+		if _, ok := fields["proxied"]; ok {
+			recordType, _ := fields["type"].(string)
+			if recordType == "" {
+				var current Record
+				if err := p.do(ctx, http.MethodGet, recordPath(zoneID, recordID), nil, nil, &current); err != nil {
+					return providerkit.Result(nil, err)
+				}
+				recordType = current.Type
+			}
+			if err := checkProxied(recordType, fields); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+
+		var record Record
+		err = p.do(ctx, http.MethodPatch, recordPath(zoneID, recordID), nil, fields, &record)
+
+		return providerkit.Result(record, err)
+	})
+}
+
+func (p *Provider) deleteRecordTool() *providerkit.Tool {
+	tool := mcp.NewTool("cloudflare_dns_record_delete",
+		mcp.WithDescription("Deletes a DNS record"),
+		withZone(),
+		mcp.WithString("record_id", mcp.Required(), mcp.Description("ID of the record")),
+		providerkit.WithConfirmParam("the record's full name, such as www.example.com,"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		zone, err := request.RequireString("zone")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		recordID, err := request.RequireString("record_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		zoneID, err := p.zoneID(ctx, zone)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		var record Record
+		if err := p.do(ctx, http.MethodGet, recordPath(zoneID, recordID), nil, nil, &record); err != nil {
+			return providerkit.Result(nil, err)
+		}
+		if refused := providerkit.Confirm(request, "deleted", "the record name", record.Name); refused != nil {
+			return refused, nil
+		}
+
+		err = p.do(ctx, http.MethodDelete, recordPath(zoneID, recordID), nil, nil, nil)
+
+		return providerkit.Result(map[string]string{"id": record.ID, "name": record.Name, "type": record.Type, "status": "deleted"}, err)
+	})
+}
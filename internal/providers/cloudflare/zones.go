@@ -0,0 +1,169 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/idempotency"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+// Zone is a Cloudflare zone.
+//
+//nolint:tagliatelle // JSON field names match the Cloudflare API.
+type Zone struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Status      string   `json:"status"`
+	Paused      bool     `json:"paused"`
+	Type        string   `json:"type"`
+	NameServers []string `json:"name_servers,omitempty"`
+	Account     struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"account"`
+	Plan struct {
+		Name string `json:"name"`
+	} `json:"plan"`
+	CreatedOn  string `json:"created_on,omitempty"`
+	ModifiedOn string `json:"modified_on,omitempty"`
+}
+
+// maxPurgeItems is the number of URLs or tags one purge request accepts.
+const maxPurgeItems = 30
+
+func (p *Provider) listZonesTool() *providerkit.Tool {
+	tool := mcp.NewTool("cloudflare_zones_list",
+		mcp.WithDescription("Lists the Cloudflare zones the API token can access"),
+		mcp.WithString("name", mcp.Description("Only list the zone with this domain name (optional)")),
+		mcp.WithString("status", mcp.Description("Only list zones in this status (optional)"),
+			mcp.Enum("active", "pending", "initializing", "moved", "deleted", "deactivated")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		query := url.Values{}
+		for _, filter := range []string{"name", "status"} {
+			if value := request.GetString(filter, ""); value != "" {
+				query.Set(filter, value)
+			}
+		}
+
+		return providerkit.Result(list[Zone](ctx, p, request, "/zones", query))
+	})
+}
+
+func (p *Provider) getZoneTool() *providerkit.Tool {
+	tool := mcp.NewTool("cloudflare_zone_get",
+		mcp.WithDescription("Returns a Cloudflare zone with its status and name servers"),
+		withZone(),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		zone, err := request.RequireString("zone")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return providerkit.Result(p.zone(ctx, zone))
+	})
+}
+
+// zone reads a zone given as a zone ID or a domain name.
+func (p *Provider) zone(ctx context.Context, zone string) (Zone, error) {
+	if !zoneIDPattern.MatchString(zone) {
+		return p.zoneByName(ctx, zone)
+	}
+
+	var found Zone
+	err := p.do(ctx, http.MethodGet, "/zones/"+zone, nil, nil, &found)
+
+	return found, err
+}
+
+// purgeResult reports a cache purge.
+type purgeResult struct {
+	Zone     string   `json:"zone"`
+	Purged   string   `json:"purged"`
+	Items    []string `json:"items,omitempty"`
+	Requests int      `json:"requests"`
+}
+
+func (p *Provider) purgeCacheTool() *providerkit.Tool {
+	tool := mcp.NewTool("cloudflare_cache_purge",
+		mcp.WithDescription("Purges cached content of a zone by URL or cache tag, or everything"),
+		withZone(),
+		mcp.WithArray("urls", mcp.Items(stringItems), mcp.Description("Full URLs to purge, such as https://example.com/app.js")),
+		mcp.WithArray("tags", mcp.Items(stringItems), mcp.Description("Cache tags to purge, as set by Cache-Tag headers")),
+		mcp.WithBoolean("everything", mcp.Description("Purge every cached file of the zone (default false)")),
+		mcp.WithString(providerkit.ConfirmParam, mcp.Description("Repeat the zone's domain name to confirm purging everything")),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		zoneArg, err := request.RequireString("zone")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		urls, tags := request.GetStringSlice("urls", nil), request.GetStringSlice("tags", nil)
+		everything := request.GetBool("everything", false)
+
+		var kind, field string
+		var items []string
+		switch {
+		case everything && len(urls) == 0 && len(tags) == 0:
+		case !everything && len(urls) > 0 && len(tags) == 0:
+			kind, field, items = "urls", "files", urls
+		case !everything && len(tags) > 0 && len(urls) == 0:
+			kind, field, items = "tags", "tags", tags
+		default:
+			return mcp.NewToolResultError("pass exactly one of urls, tags or everything"), nil
+		}
+
+		zone, err := p.zone(ctx, zoneArg)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+		path := "/zones/" + zone.ID + "/purge_cache"
+
+		if everything {
+			if refused := providerkit.Confirm(request, "purged", "the zone name", zone.Name); refused != nil {
+				return refused, nil
+			}
+			err := p.do(ctx, http.MethodPost, path, nil, map[string]bool{"purge_everything": true}, nil)
+
+			return providerkit.Result(purgeResult{Zone: zone.Name, Purged: "everything", Requests: 1}, err)
+		}
+
+		// Each request accepts a limited number of items, so larger purges are split
+		result := purgeResult{Zone: zone.Name, Purged: kind, Items: items}
+		progress := contracts.ProgressFromContext(ctx)
+		for start := 0; start < len(items); start += maxPurgeItems {
+			batch := items[start:min(start+maxPurgeItems, len(items))]
+			if err := p.do(ctx, http.MethodPost, path, nil, map[string][]string{field: batch}, nil); err != nil {
+				return providerkit.Result(nil, fmt.Errorf("purged %d of %d %s: %w", start, len(items), kind, err))
+			}
+			result.Requests++
+			progress.Report(float64(start+len(batch)), float64(len(items)), fmt.Sprintf("purged %d of %d", start+len(batch), len(items)))
+		}
+
+		return providerkit.Result(result, nil)
+	})
+}
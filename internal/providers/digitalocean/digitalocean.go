@@ -0,0 +1,207 @@
+// Package digitalocean implements the DigitalOcean API v2 provider.
+package digitalocean
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	// Name is the provider name used in settings and accounts.
+	Name = "digitalocean"
+
+	// DefaultAPIURL is the DigitalOcean API v2 endpoint, overridden by the
+	// api_url setting.
+	DefaultAPIURL = "https://api.digitalocean.com/v2"
+
+	// DefaultTokenRef is the credential used when neither the account nor the
+	// token_ref setting names one.
+	DefaultTokenRef = "env:DIGITALOCEAN_TOKEN"
+
+	// maxPageSize is the largest per_page the API accepts.
+	maxPageSize = 200
+)
+
+// Provider is the DigitalOcean provider.
+type Provider struct {
+	cfg          contracts.ProviderConfig
+	api          *providerkit.API
+	pollInterval time.Duration
+}
+
+// New creates an uninitialized DigitalOcean provider.
+func New() contracts.Provider {
+	return &Provider{}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return Name
+}
+
+// Initialize configures the DigitalOcean API client.
+func (p *Provider) Initialize(_ context.Context, cfg contracts.ProviderConfig) error {
+	p.cfg = cfg
+	p.pollInterval = providerkit.PollInterval(cfg)
+	p.api = providerkit.NewTokenAPI(cfg, providerkit.TokenAPI{
+		Provider:        Name,
+		DefaultURL:      DefaultAPIURL,
+		DefaultTokenRef: DefaultTokenRef,
+		ErrorMessage:    errorMessage,
+	})
+
+	return nil
+}
+
+// Tools returns the DigitalOcean tools.
+func (p *Provider) Tools() []contracts.Tool {
+	return []contracts.Tool{
+		p.listDropletsTool(),
+		p.getDropletTool(),
+		p.createDropletTool(),
+		p.destroyDropletTool(),
+		p.dropletActionTool(),
+		p.listVolumesTool(),
+		p.createVolumeTool(),
+		p.deleteVolumeTool(),
+		p.volumeAttachmentTool("attach"),
+		p.volumeAttachmentTool("detach"),
+		p.listSnapshotsTool(),
+		p.snapshotDropletTool(),
+		p.snapshotVolumeTool(),
+		p.deleteSnapshotTool(),
+	}
+}
+
+// HealthCheck verifies the default account's token by reading the account.
+func (p *Provider) HealthCheck(ctx context.Context) error {
+	ctx = providerkit.DefaultAccountContext(ctx, p.cfg)
+
+	return p.api.Do(ctx, http.MethodGet, "/account", nil, nil, nil)
+}
+
+// Shutdown has nothing to release.
+func (p *Provider) Shutdown(context.Context) error {
+	return nil
+}
+
+// errorMessage extracts the message of a DigitalOcean error response.
+func errorMessage(body []byte) string {
+	var decoded struct {
+		ID      string `json:"id"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return ""
+	}
+
+	return decoded.Message
+}
+
+// Action is a DigitalOcean action, the record of an asynchronous change.
+//
+//nolint:tagliatelle // JSON field names match the DigitalOcean API.
+type Action struct {
+	ID           int    `json:"id"`
+	Type         string `json:"type"`
+	Status       string `json:"status"`
+	ResourceID   int    `json:"resource_id"`
+	ResourceType string `json:"resource_type"`
+	RegionSlug   string `json:"region_slug,omitempty"`
+	StartedAt    string `json:"started_at"`
+	CompletedAt  string `json:"completed_at,omitempty"`
+}
+
+// Action statuses.
+const (
+	actionInProgress = "in-progress"
+	actionErrored    = "errored"
+)
+
+// actionResponse wraps an action.
+type actionResponse struct {
+	Action Action `json:"action"`
+}
+
+// startAction posts an action and, if the call asks to wait, polls it until it
+// finishes.
+func (p *Provider) startAction(ctx context.Context, request mcp.CallToolRequest, path string, body any) (Action, error) {
+	var resp actionResponse
+	if err := p.api.Do(ctx, http.MethodPost, path, nil, body, &resp); err != nil {
+		return Action{}, err
+	}
+	if !request.GetBool(providerkit.WaitParam, true) {
+		return resp.Action, nil
+	}
+
+	return p.waitAction(ctx, resp.Action.ID)
+}
+
+// waitAction polls an action until it finishes, reporting progress.
+func (p *Provider) waitAction(ctx context.Context, id int) (Action, error) {
+	var action Action
+	_, err := providerkit.Wait(ctx, p.pollInterval, func(ctx context.Context) (providerkit.ActionStatus, error) {
+		var resp actionResponse
+		if err := p.api.Do(ctx, http.MethodGet, "/actions/"+strconv.Itoa(id), nil, nil, &resp); err != nil {
+			return providerkit.ActionStatus{}, err
+		}
+		action = resp.Action
+
+		return providerkit.ActionStatus{
+			Done:    action.Status != actionInProgress,
+			Failed:  action.Status == actionErrored,
+			Message: fmt.Sprintf("%s action %d %s", action.Type, action.ID, action.Status),
+		}, nil
+	})
+
+	return action, err
+}
+
+// list fetches one page of a DigitalOcean collection. key is the name of the
+// collection in the response body.
+func list[T any](ctx context.Context, p *Provider, request mcp.CallToolRequest, path, key string, query url.Values) (pagination.Page[T], error) {
+	cursors, req, err := providerkit.ParsePage(ctx, request)
+	if err != nil {
+		return pagination.Page[T]{}, err
+	}
+
+	page, size := req.ProviderPage(1, maxPageSize)
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("page", strconv.Itoa(page))
+	query.Set("per_page", strconv.Itoa(size))
+
+	var resp map[string]json.RawMessage
+	if err := p.api.Do(ctx, http.MethodGet, path, query, nil, &resp); err != nil {
+		return pagination.Page[T]{}, err
+	}
+
+	items := []T{}
+	if raw, ok := resp[key]; ok {
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return pagination.Page[T]{}, fmt.Errorf("failed to decode %s: %w", key, err)
+		}
+	}
+	var links struct {
+		Pages struct {
+			Next string `json:"next"`
+		} `json:"pages"`
+	}
+	if raw, ok := resp["links"]; ok {
+		_ = json.Unmarshal(raw, &links)
+	}
+
+	return pagination.FromProviderPage(cursors, req, size, items, links.Pages.Next != ""), nil
+}
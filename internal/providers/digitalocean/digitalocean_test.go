@@ -0,0 +1,221 @@
+package digitalocean_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/internal/providerkit/providerkittest"
+	"github.com/chadit/CloudMCP/internal/providers/digitalocean"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+// prod is the account tool calls run against.
+var prod = contracts.Account{Provider: digitalocean.Name, Alias: "prod", Credential: "do-prod", Region: "nyc3"}
+
+// fakeAPI is a stub of the DigitalOcean API v2. Actions stay in progress for
+// two polls, then finish; actions on droplet 13, and creating a droplet named
+// broken, fail.
+type fakeAPI struct {
+	mu       sync.Mutex
+	droplets int
+	polls    map[int]int
+	requests []string
+	bodies   []map[string]any
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+	if r.Header.Get("Authorization") != "Bearer do-token" {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "Unable to authenticate you")
+		return
+	}
+	var body map[string]any
+	if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+		_ = json.Unmarshal(data, &body)
+		f.bodies = append(f.bodies, body)
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/droplets":
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		size, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		start := min((page-1)*size, f.droplets)
+		end := min(start+size, f.droplets)
+		droplets := make([]digitalocean.Droplet, 0)
+		for id := start + 1; id <= end; id++ {
+			droplets = append(droplets, digitalocean.Droplet{ID: id, Name: fmt.Sprintf("web-%d", id)})
+		}
+		links := map[string]any{}
+		if end < f.droplets {
+			links["pages"] = map[string]string{"next": "https://api.digitalocean.com/v2/droplets?page=" + strconv.Itoa(page+1)}
+		}
+		providerkittest.WriteJSON(w, http.StatusOK, map[string]any{"droplets": droplets, "links": links, "meta": map[string]int{"total": f.droplets}})
+	case r.Method == http.MethodPost && r.URL.Path == "/droplets" && body["name"] == "broken":
+		providerkittest.WriteJSON(w, http.StatusAccepted, map[string]any{
+			"droplet": digitalocean.Droplet{ID: 13, Name: "broken", Status: "new"},
+			"links":   map[string]any{"actions": []map[string]any{{"id": 13, "rel": "create"}}},
+		})
+	case r.Method == http.MethodPost && r.URL.Path == "/droplets":
+		providerkittest.WriteJSON(w, http.StatusAccepted, map[string]any{
+			"droplet": digitalocean.Droplet{ID: 500, Name: "web", Status: "new"},
+			"links":   map[string]any{"actions": []map[string]any{{"id": 100, "rel": "create"}}},
+		})
+	case r.Method == http.MethodGet && r.URL.Path == "/droplets/500":
+		providerkittest.WriteJSON(w, http.StatusOK, map[string]any{"droplet": digitalocean.Droplet{
+			ID: 500, Name: "web", Status: "active",
+			Networks: digitalocean.Networks{V4: []digitalocean.Address{{IPAddress: "203.0.113.10", Type: "public"}}},
+		}})
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/actions"):
+		id := 200 + len(f.polls)
+		if r.URL.Path == "/droplets/13/actions" {
+			id = 13
+		}
+		providerkittest.WriteJSON(w, http.StatusCreated, map[string]any{"action": digitalocean.Action{ID: id, Type: fmt.Sprint(body["type"]), Status: "in-progress"}})
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/actions/"):
+		id, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/actions/"))
+		f.polls[id]++
+		status := "in-progress"
+		switch {
+		case f.polls[id] > 2 && id == 13:
+			status = "errored"
+		case f.polls[id] > 2:
+			status = "completed"
+		}
+		providerkittest.WriteJSON(w, http.StatusOK, map[string]any{"action": digitalocean.Action{ID: id, Type: "power_off", Status: status}})
+	default:
+		writeError(w, http.StatusNotFound, "not_found", "The resource you were accessing could not be found.")
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, id, message string) {
+	providerkittest.WriteJSON(w, status, map[string]string{"id": id, "message": message})
+}
+
+// setup starts a stub API and returns the provider's tools by name.
+func setup(t *testing.T, droplets int) (*fakeAPI, map[string]contracts.Tool) {
+	t.Helper()
+
+	api := &fakeAPI{droplets: droplets, polls: make(map[int]int)}
+	srv := providerkittest.Serve(t, api)
+
+	provider := digitalocean.New()
+	tools := providerkittest.Setup(t, provider, contracts.ProviderConfig{
+		Settings:   map[string]string{"api_url": srv.URL, "poll_interval": "1ms"},
+		HTTPClient: srv.Client(),
+		Secrets:    providerkittest.Secrets{"do-prod": "do-token"},
+	})
+
+	return api, tools
+}
+
+func TestDropletsList_FollowsLinks(t *testing.T) {
+	t.Parallel()
+
+	_, tools := setup(t, 7)
+	ctx := providerkittest.AccountContext(t, prod)
+
+	var names []string
+	params := map[string]any{"limit": float64(3)}
+	for {
+		text, isError := providerkittest.Call(ctx, t, tools["digitalocean_droplets_list"], params)
+		require.False(t, isError, text)
+
+		var page pagination.Page[digitalocean.Droplet]
+		require.NoError(t, json.Unmarshal([]byte(text), &page))
+		for _, droplet := range page.Items {
+			names = append(names, droplet.Name)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		params["cursor"] = page.NextCursor
+	}
+
+	require.Equal(t, []string{"web-1", "web-2", "web-3", "web-4", "web-5", "web-6", "web-7"}, names)
+}
+
+func TestDropletCreate_WaitsWithProgress(t *testing.T) {
+	t.Parallel()
+
+	api, tools := setup(t, 0)
+	progress := &providerkittest.Progress{}
+	ctx := contracts.WithProgressReporter(providerkittest.AccountContext(t, prod), progress)
+
+	text, isError := providerkittest.Call(ctx, t, tools["digitalocean_droplet_create"], map[string]any{
+		"name": "web", "size": "s-1vcpu-1gb", "image": "ubuntu-24-04-x64",
+	})
+	require.False(t, isError, text)
+	require.Contains(t, text, "203.0.113.10", "the droplet is re-read once active")
+	require.Equal(t, "nyc3", api.bodies[0]["region"], "the account region is the default")
+	require.Equal(t, 3, api.polls[100])
+	require.Len(t, progress.Values(), 3)
+	require.Equal(t, providerkittest.Update{Progress: 100, Total: 100, Message: "power_off action 100 completed"}, progress.Updates()[2])
+}
+
+func TestDropletCreate_FailedWaitKeepsDroplet(t *testing.T) {
+	t.Parallel()
+
+	_, tools := setup(t, 0)
+	ctx := providerkittest.AccountContext(t, prod)
+
+	text, isError := providerkittest.Call(ctx, t, tools["digitalocean_droplet_create"], map[string]any{
+		"name": "broken", "size": "s-1vcpu-1gb", "image": "ubuntu-24-04-x64",
+	})
+	require.False(t, isError, "the droplet exists, so it is returned rather than an error")
+	require.Contains(t, text, `"id": 13`)
+	require.Contains(t, text, providerkit.ErrActionFailed.Error())
+}
+
+func TestDropletAction(t *testing.T) {
+	t.Parallel()
+
+	api, tools := setup(t, 0)
+	ctx := providerkittest.AccountContext(t, prod)
+
+	text, isError := providerkittest.Call(ctx, t, tools["digitalocean_droplet_action"], map[string]any{"droplet_id": float64(7), "type": "power_off"})
+	require.False(t, isError, text)
+	require.Contains(t, text, `"status": "completed"`)
+
+	text, isError = providerkittest.Call(ctx, t, tools["digitalocean_droplet_action"], map[string]any{"droplet_id": float64(7), "type": "reboot", "wait": false})
+	require.False(t, isError, text)
+	require.Contains(t, text, `"status": "in-progress"`, "without wait the action is returned as started")
+
+	text, isError = providerkittest.Call(ctx, t, tools["digitalocean_droplet_action"], map[string]any{"droplet_id": float64(13), "type": "power_off"})
+	require.True(t, isError)
+	require.Contains(t, text, providerkit.ErrActionFailed.Error())
+	require.Equal(t, 3, api.polls[13])
+}
+
+func TestVolumesAndSnapshots(t *testing.T) {
+	t.Parallel()
+
+	api, tools := setup(t, 0)
+	ctx := providerkittest.AccountContext(t, prod)
+
+	text, isError := providerkittest.Call(ctx, t, tools["digitalocean_volume_attach"], map[string]any{"volume_id": "vol-1", "droplet_id": float64(7)})
+	require.False(t, isError, text)
+	require.Contains(t, api.requests, "POST /volumes/vol-1/actions")
+	require.Equal(t, map[string]any{"type": "attach", "droplet_id": float64(7)}, api.bodies[0])
+
+	text, isError = providerkittest.Call(ctx, t, tools["digitalocean_droplet_snapshot"], map[string]any{"droplet_id": float64(7), "name": "nightly"})
+	require.False(t, isError, text)
+	require.Equal(t, map[string]any{"type": "snapshot", "name": "nightly"}, api.bodies[1])
+
+	text, isError = providerkittest.Call(ctx, t, tools["digitalocean_snapshot_delete"], map[string]any{"snapshot_id": "missing"})
+	require.True(t, isError)
+	require.Contains(t, text, providerkit.ErrNotFound.Error())
+	require.Contains(t, text, "The resource you were accessing could not be found.")
+}
@@ -0,0 +1,263 @@
+package digitalocean
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/idempotency"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+)
+
+// Droplet is a DigitalOcean droplet.
+//
+//nolint:tagliatelle // JSON field names match the DigitalOcean API.
+type Droplet struct {
+	ID        int      `json:"id"`
+	Name      string   `json:"name"`
+	Status    string   `json:"status"`
+	Memory    int      `json:"memory"`
+	VCPUs     int      `json:"vcpus"`
+	Disk      int      `json:"disk"`
+	SizeSlug  string   `json:"size_slug"`
+	Region    Region   `json:"region"`
+	Image     Image    `json:"image"`
+	Networks  Networks `json:"networks"`
+	Tags      []string `json:"tags"`
+	VolumeIDs []string `json:"volume_ids"`
+	CreatedAt string   `json:"created_at"`
+}
+
+// Region is the region of a resource.
+type Region struct {
+	Slug string `json:"slug"`
+	Name string `json:"name,omitempty"`
+}
+
+// Image is the image a droplet was created from.
+type Image struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	Slug         string `json:"slug,omitempty"`
+	Distribution string `json:"distribution,omitempty"`
+}
+
+// Networks lists a droplet's addresses.
+type Networks struct {
+	V4 []Address `json:"v4"`
+	V6 []Address `json:"v6"`
+}
+
+// Address is a droplet IP address.
+//
+//nolint:tagliatelle // JSON field names match the DigitalOcean API.
+type Address struct {
+	IPAddress string `json:"ip_address"`
+	Type      string `json:"type"`
+}
+
+// dropletResponse wraps a droplet and the actions started with it.
+type dropletResponse struct {
+	Droplet Droplet `json:"droplet"`
+	Links   struct {
+		Actions []struct {
+			ID int `json:"id"`
+		} `json:"actions"`
+	} `json:"links"`
+}
+
+// withDropletID adds the required droplet_id argument.
+func withDropletID() mcp.ToolOption {
+	return mcp.WithNumber("droplet_id",
+		mcp.Required(),
+		mcp.Description("ID of the droplet"),
+	)
+}
+
+// dropletPath returns the API path of the droplet named by the droplet_id argument.
+func dropletPath(request mcp.CallToolRequest, suffix string) (string, error) {
+	id, err := request.RequireInt("droplet_id")
+	if err != nil {
+		return "", err
+	}
+
+	return "/droplets/" + strconv.Itoa(id) + suffix, nil
+}
+
+// stringItems is the item schema of string array arguments.
+var stringItems = map[string]any{"type": "string"}
+
+func (p *Provider) listDropletsTool() *providerkit.Tool {
+	tool := mcp.NewTool("digitalocean_droplets_list",
+		mcp.WithDescription("Lists DigitalOcean droplets"),
+		mcp.WithString("tag", mcp.Description("Only list droplets with this tag (optional)")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		query := url.Values{}
+		if tag := request.GetString("tag", ""); tag != "" {
+			query.Set("tag_name", tag)
+		}
+
+		return providerkit.Result(list[Droplet](ctx, p, request, "/droplets", "droplets", query))
+	})
+}
+
+func (p *Provider) getDropletTool() *providerkit.Tool {
+	tool := mcp.NewTool("digitalocean_droplet_get",
+		mcp.WithDescription("Returns a DigitalOcean droplet"),
+		withDropletID(),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		path, err := dropletPath(request, "")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		var resp dropletResponse
+		err = p.api.Do(ctx, http.MethodGet, path, nil, nil, &resp)
+
+		return providerkit.Result(resp.Droplet, err)
+	})
+}
+
+// createDropletRequest is the body of a droplet create call.
+//
+//nolint:tagliatelle // JSON field names match the DigitalOcean API.
+type createDropletRequest struct {
+	Name    string   `json:"name"`
+	Region  string   `json:"region"`
+	Size    string   `json:"size"`
+	Image   string   `json:"image"`
+	SSHKeys []string `json:"ssh_keys,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+	VPCUUID string   `json:"vpc_uuid,omitempty"`
+}
+
+func (p *Provider) createDropletTool() *providerkit.Tool {
+	tool := mcp.NewTool("digitalocean_droplet_create",
+		mcp.WithDescription("Creates a DigitalOcean droplet and by default waits until it is active"),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Droplet hostname")),
+		mcp.WithString("region", mcp.Description("Region slug, such as nyc3 (default: the account's region)")),
+		mcp.WithString("size", mcp.Required(), mcp.Description("Size slug, such as s-1vcpu-1gb")),
+		mcp.WithString("image", mcp.Required(), mcp.Description("Image slug or ID, such as ubuntu-24-04-x64")),
+		mcp.WithArray("ssh_keys", mcp.Items(stringItems), mcp.Description("SSH key IDs or fingerprints (optional)")),
+		mcp.WithArray("tags", mcp.Items(stringItems), mcp.Description("Tags to apply (optional)")),
+		mcp.WithString("vpc_uuid", mcp.Description("VPC to place the droplet in (optional)")),
+		providerkit.WithWaitParam(),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		body := createDropletRequest{
+			Name:    request.GetString("name", ""),
+			Region:  providerkit.Region(ctx, request, ""),
+			Size:    request.GetString("size", ""),
+			Image:   request.GetString("image", ""),
+			SSHKeys: request.GetStringSlice("ssh_keys", nil),
+			Tags:    request.GetStringSlice("tags", nil),
+			VPCUUID: request.GetString("vpc_uuid", ""),
+		}
+		if body.Name == "" || body.Size == "" || body.Image == "" {
+			return mcp.NewToolResultError("name, size and image are required"), nil
+		}
+		if body.Region == "" {
+			return mcp.NewToolResultError("region is required when the account has no default region"), nil
+		}
+
+		var resp dropletResponse
+		if err := p.api.Do(ctx, http.MethodPost, "/droplets", nil, body, &resp); err != nil {
+			return providerkit.Result(nil, err)
+		}
+		if !request.GetBool(providerkit.WaitParam, true) || len(resp.Links.Actions) == 0 {
+			return providerkit.Result(resp.Droplet, nil)
+		}
+
+		if _, err := p.waitAction(ctx, resp.Links.Actions[0].ID); err != nil {
+			return providerkit.CreatedResult(resp.Droplet, err)
+		}
+
+		// Re-read the droplet for the addresses assigned while it was created
+		path := "/droplets/" + strconv.Itoa(resp.Droplet.ID)
+		err := p.api.Do(ctx, http.MethodGet, path, nil, nil, &resp)
+
+		return providerkit.CreatedResult(resp.Droplet, err)
+	})
+}
+
+// destroyResult reports a destroyed resource.
+type destroyResult struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+func (p *Provider) destroyDropletTool() *providerkit.Tool {
+	tool := mcp.NewTool("digitalocean_droplet_destroy",
+		mcp.WithDescription("Destroys a DigitalOcean droplet. This cannot be undone."),
+		withDropletID(),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		path, err := dropletPath(request, "")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		err = p.api.Do(ctx, http.MethodDelete, path, nil, nil, nil)
+
+		return providerkit.Result(destroyResult{ID: strconv.Itoa(request.GetInt("droplet_id", 0)), Status: "destroyed"}, err)
+	})
+}
+
+func (p *Provider) dropletActionTool() *providerkit.Tool {
+	tool := mcp.NewTool("digitalocean_droplet_action",
+		mcp.WithDescription("Runs a power action on a DigitalOcean droplet and by default waits for it to finish"),
+		withDropletID(),
+		mcp.WithString("type",
+			mcp.Required(),
+			mcp.Description("Action to run"),
+			mcp.Enum("power_on", "power_off", "shutdown", "reboot", "power_cycle"),
+		),
+		providerkit.WithWaitParam(),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		path, err := dropletPath(request, "/actions")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		actionType, err := request.RequireString("type")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return providerkit.Result(p.startAction(ctx, request, path, map[string]string{"type": actionType}))
+	})
+}
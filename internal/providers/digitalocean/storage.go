@@ -0,0 +1,306 @@
+package digitalocean
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/idempotency"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+)
+
+// Volume is a DigitalOcean block storage volume.
+//
+//nolint:tagliatelle // JSON field names match the DigitalOcean API.
+type Volume struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	SizeGigabytes  int      `json:"size_gigabytes"`
+	Region         Region   `json:"region"`
+	DropletIDs     []int    `json:"droplet_ids"`
+	Description    string   `json:"description,omitempty"`
+	FilesystemType string   `json:"filesystem_type,omitempty"`
+	Tags           []string `json:"tags"`
+	CreatedAt      string   `json:"created_at"`
+}
+
+// Snapshot is a DigitalOcean droplet or volume snapshot.
+//
+//nolint:tagliatelle // JSON field names match the DigitalOcean API.
+type Snapshot struct {
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	ResourceID    string   `json:"resource_id"`
+	ResourceType  string   `json:"resource_type"`
+	Regions       []string `json:"regions"`
+	MinDiskSize   int      `json:"min_disk_size"`
+	SizeGigabytes float64  `json:"size_gigabytes"`
+	Tags          []string `json:"tags"`
+	CreatedAt     string   `json:"created_at"`
+}
+
+// withVolumeID adds the required volume_id argument.
+func withVolumeID() mcp.ToolOption {
+	return mcp.WithString("volume_id",
+		mcp.Required(),
+		mcp.Description("ID of the volume"),
+	)
+}
+
+func (p *Provider) listVolumesTool() *providerkit.Tool {
+	tool := mcp.NewTool("digitalocean_volumes_list",
+		mcp.WithDescription("Lists DigitalOcean block storage volumes"),
+		mcp.WithString("region", mcp.Description("Only list volumes in this region (optional)")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		query := url.Values{}
+		if region := request.GetString("region", ""); region != "" {
+			query.Set("region", region)
+		}
+
+		return providerkit.Result(list[Volume](ctx, p, request, "/volumes", "volumes", query))
+	})
+}
+
+// createVolumeRequest is the body of a volume create call.
+//
+//nolint:tagliatelle // JSON field names match the DigitalOcean API.
+type createVolumeRequest struct {
+	Name           string   `json:"name"`
+	SizeGigabytes  int      `json:"size_gigabytes"`
+	Region         string   `json:"region,omitempty"`
+	Description    string   `json:"description,omitempty"`
+	SnapshotID     string   `json:"snapshot_id,omitempty"`
+	FilesystemType string   `json:"filesystem_type,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+}
+
+func (p *Provider) createVolumeTool() *providerkit.Tool {
+	tool := mcp.NewTool("digitalocean_volume_create",
+		mcp.WithDescription("Creates a DigitalOcean block storage volume, empty or from a volume snapshot"),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Volume name")),
+		mcp.WithNumber("size_gigabytes", mcp.Required(), mcp.Description("Volume size in GiB"), mcp.Min(1)),
+		mcp.WithString("region", mcp.Description("Region slug (default: the account's region; ignored with snapshot_id)")),
+		mcp.WithString("description", mcp.Description("Free-form description (optional)")),
+		mcp.WithString("snapshot_id", mcp.Description("Volume snapshot to restore (optional)")),
+		mcp.WithString("filesystem_type",
+			mcp.Description("Filesystem to format the volume with (optional)"),
+			mcp.Enum("ext4", "xfs"),
+		),
+		mcp.WithArray("tags", mcp.Items(stringItems), mcp.Description("Tags to apply (optional)")),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		body := createVolumeRequest{
+			Name:           request.GetString("name", ""),
+			SizeGigabytes:  request.GetInt("size_gigabytes", 0),
+			Description:    request.GetString("description", ""),
+			SnapshotID:     request.GetString("snapshot_id", ""),
+			FilesystemType: request.GetString("filesystem_type", ""),
+			Tags:           request.GetStringSlice("tags", nil),
+		}
+		if body.Name == "" || body.SizeGigabytes < 1 {
+			return mcp.NewToolResultError("name and a positive size_gigabytes are required"), nil
+		}
+		if body.SnapshotID == "" {
+			body.Region = providerkit.Region(ctx, request, "")
+		}
+
+		var resp struct {
+			Volume Volume `json:"volume"`
+		}
+		err := p.api.Do(ctx, http.MethodPost, "/volumes", nil, body, &resp)
+
+		return providerkit.Result(resp.Volume, err)
+	})
+}
+
+func (p *Provider) deleteVolumeTool() *providerkit.Tool {
+	tool := mcp.NewTool("digitalocean_volume_delete",
+		mcp.WithDescription("Deletes a detached DigitalOcean volume and its data. This cannot be undone."),
+		withVolumeID(),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id, err := request.RequireString("volume_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		err = p.api.Do(ctx, http.MethodDelete, "/volumes/"+url.PathEscape(id), nil, nil, nil)
+
+		return providerkit.Result(destroyResult{ID: id, Status: "deleted"}, err)
+	})
+}
+
+// attachmentRequest is the body of a volume attach or detach action.
+//
+//nolint:tagliatelle // JSON field names match the DigitalOcean API.
+type attachmentRequest struct {
+	Type      string `json:"type"`
+	DropletID int    `json:"droplet_id"`
+}
+
+// volumeAttachmentTool creates the tool attaching or detaching a volume.
+func (p *Provider) volumeAttachmentTool(action string) *providerkit.Tool {
+	description := "Attaches a DigitalOcean volume to a droplet in the same region and by default waits for it to finish"
+	if action == "detach" {
+		description = "Detaches a DigitalOcean volume from a droplet and by default waits for it to finish"
+	}
+
+	tool := mcp.NewTool("digitalocean_volume_"+action,
+		mcp.WithDescription(description),
+		withVolumeID(),
+		withDropletID(),
+		providerkit.WithWaitParam(),
+		mcp.WithDestructiveHintAnnotation(action == "detach"),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id, err := request.RequireString("volume_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		dropletID, err := request.RequireInt("droplet_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		body := attachmentRequest{Type: action, DropletID: dropletID}
+
+		return providerkit.Result(p.startAction(ctx, request, "/volumes/"+url.PathEscape(id)+"/actions", body))
+	})
+}
+
+func (p *Provider) listSnapshotsTool() *providerkit.Tool {
+	tool := mcp.NewTool("digitalocean_snapshots_list",
+		mcp.WithDescription("Lists DigitalOcean droplet and volume snapshots"),
+		mcp.WithString("resource_type",
+			mcp.Description("Only list snapshots of this resource type (optional)"),
+			mcp.Enum("droplet", "volume"),
+		),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		query := url.Values{}
+		if resourceType := request.GetString("resource_type", ""); resourceType != "" {
+			query.Set("resource_type", resourceType)
+		}
+
+		return providerkit.Result(list[Snapshot](ctx, p, request, "/snapshots", "snapshots", query))
+	})
+}
+
+func (p *Provider) snapshotDropletTool() *providerkit.Tool {
+	tool := mcp.NewTool("digitalocean_droplet_snapshot",
+		mcp.WithDescription("Snapshots a DigitalOcean droplet and by default waits for the snapshot to finish. Power the droplet off first for a consistent image."),
+		withDropletID(),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Snapshot name")),
+		providerkit.WithWaitParam(),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		path, err := dropletPath(request, "/actions")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		name, err := request.RequireString("name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return providerkit.Result(p.startAction(ctx, request, path, map[string]string{"type": "snapshot", "name": name}))
+	})
+}
+
+func (p *Provider) snapshotVolumeTool() *providerkit.Tool {
+	tool := mcp.NewTool("digitalocean_volume_snapshot",
+		mcp.WithDescription("Snapshots a DigitalOcean volume"),
+		withVolumeID(),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Snapshot name")),
+		mcp.WithArray("tags", mcp.Items(stringItems), mcp.Description("Tags to apply (optional)")),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id, err := request.RequireString("volume_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		name, err := request.RequireString("name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		body := map[string]any{"name": name}
+		if tags := request.GetStringSlice("tags", nil); len(tags) > 0 {
+			body["tags"] = tags
+		}
+
+		var resp struct {
+			Snapshot Snapshot `json:"snapshot"`
+		}
+		err = p.api.Do(ctx, http.MethodPost, "/volumes/"+url.PathEscape(id)+"/snapshots", nil, body, &resp)
+
+		return providerkit.Result(resp.Snapshot, err)
+	})
+}
+
+func (p *Provider) deleteSnapshotTool() *providerkit.Tool {
+	tool := mcp.NewTool("digitalocean_snapshot_delete",
+		mcp.WithDescription("Deletes a DigitalOcean droplet or volume snapshot. This cannot be undone."),
+		mcp.WithString("snapshot_id", mcp.Required(), mcp.Description("ID of the snapshot")),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id, err := request.RequireString("snapshot_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		err = p.api.Do(ctx, http.MethodDelete, "/snapshots/"+url.PathEscape(id), nil, nil, nil)
+
+		return providerkit.Result(destroyResult{ID: id, Status: "deleted"}, err)
+	})
+}
@@ -0,0 +1,604 @@
+package docker
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/idempotency"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+)
+
+const (
+	// defaultTailLines is how many log lines are returned by default.
+	defaultTailLines = 100
+
+	// maxTailLines bounds the log lines of one call.
+	maxTailLines = 5000
+
+	// maxStopTimeout bounds how long stop and restart let a container exit.
+	maxStopTimeout = 300
+
+	// logHeaderSize is the size of the frame header of multiplexed logs.
+	logHeaderSize = 8
+)
+
+// Static errors for err113 compliance.
+var ErrInvalidSince = errors.New("since must be a duration such as 15m or an RFC 3339 time")
+
+// Container summarizes a container, like docker ps.
+type Container struct {
+	ID      string            `json:"id"`
+	Name    string            `json:"name"`
+	Image   string            `json:"image"`
+	Command string            `json:"command"`
+	State   string            `json:"state"`
+	Status  string            `json:"status"`
+	Created time.Time         `json:"created"`
+	Ports   []string          `json:"ports"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+// ContainerDetail is a container as shown by docker inspect. Environment
+// lists variable names only, since values often hold secrets.
+type ContainerDetail struct {
+	ID            string            `json:"id"`
+	Name          string            `json:"name"`
+	Image         string            `json:"image"`
+	ImageID       string            `json:"image_id"`
+	Command       string            `json:"command"`
+	Created       time.Time         `json:"created"`
+	State         ContainerState    `json:"state"`
+	RestartCount  int               `json:"restart_count"`
+	RestartPolicy string            `json:"restart_policy,omitempty"`
+	Ports         []string          `json:"ports"`
+	Mounts        []string          `json:"mounts,omitempty"`
+	Networks      map[string]string `json:"networks,omitempty"`
+	Environment   []string          `json:"environment,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty"`
+}
+
+// ContainerState is the run state of a container.
+type ContainerState struct {
+	Status     string     `json:"status"`
+	Running    bool       `json:"running"`
+	ExitCode   int        `json:"exit_code"`
+	Error      string     `json:"error,omitempty"`
+	OOMKilled  bool       `json:"oom_killed,omitempty"`
+	Health     string     `json:"health,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// containerSummary is a container as the list endpoint returns it.
+type containerSummary struct {
+	ID      string            `json:"Id"`
+	Names   []string          `json:"Names"`
+	Image   string            `json:"Image"`
+	Command string            `json:"Command"`
+	Created int64             `json:"Created"`
+	State   string            `json:"State"`
+	Status  string            `json:"Status"`
+	Labels  map[string]string `json:"Labels"`
+	Ports   []struct {
+		IP          string `json:"IP"`
+		PrivatePort int    `json:"PrivatePort"`
+		PublicPort  int    `json:"PublicPort"`
+		Type        string `json:"Type"`
+	} `json:"Ports"`
+}
+
+// containerObject is a container as the inspect endpoint returns it.
+type containerObject struct {
+	ID           string    `json:"Id"`
+	Name         string    `json:"Name"`
+	Created      time.Time `json:"Created"`
+	Path         string    `json:"Path"`
+	Args         []string  `json:"Args"`
+	Image        string    `json:"Image"`
+	RestartCount int       `json:"RestartCount"`
+	State        struct {
+		Status     string    `json:"Status"`
+		Running    bool      `json:"Running"`
+		OOMKilled  bool      `json:"OOMKilled"`
+		ExitCode   int       `json:"ExitCode"`
+		Error      string    `json:"Error"`
+		StartedAt  time.Time `json:"StartedAt"`
+		FinishedAt time.Time `json:"FinishedAt"`
+		Health     *struct {
+			Status string `json:"Status"`
+		} `json:"Health"`
+	} `json:"State"`
+	Config struct {
+		Image  string            `json:"Image"`
+		Tty    bool              `json:"Tty"`
+		Env    []string          `json:"Env"`
+		Labels map[string]string `json:"Labels"`
+	} `json:"Config"`
+	HostConfig struct {
+		RestartPolicy struct {
+			Name              string `json:"Name"`
+			MaximumRetryCount int    `json:"MaximumRetryCount"`
+		} `json:"RestartPolicy"`
+	} `json:"HostConfig"`
+	Mounts []struct {
+		Type        string `json:"Type"`
+		Name        string `json:"Name"`
+		Source      string `json:"Source"`
+		Destination string `json:"Destination"`
+		RW          bool   `json:"RW"`
+	} `json:"Mounts"`
+	NetworkSettings struct {
+		Ports    map[string][]portBinding `json:"Ports"`
+		Networks map[string]struct {
+			IPAddress string `json:"IPAddress"`
+		} `json:"Networks"`
+	} `json:"NetworkSettings"`
+}
+
+// portBinding is a host address a container port is published on.
+type portBinding struct {
+	HostIP   string `json:"HostIp"`
+	HostPort string `json:"HostPort"`
+}
+
+func newContainer(object containerSummary) Container {
+	container := Container{
+		ID:      shortID(object.ID),
+		Image:   object.Image,
+		Command: object.Command,
+		State:   object.State,
+		Status:  object.Status,
+		Created: time.Unix(object.Created, 0).UTC(),
+		Ports:   []string{},
+		Labels:  object.Labels,
+	}
+	if len(object.Names) > 0 {
+		container.Name = strings.TrimPrefix(object.Names[0], "/")
+	}
+	for _, port := range object.Ports {
+		container.Ports = append(container.Ports, formatPort(port.IP, port.PublicPort, port.PrivatePort, port.Type))
+	}
+	slices.Sort(container.Ports)
+	container.Ports = slices.Compact(container.Ports)
+
+	return container
+}
+
+func newContainerDetail(object containerObject) ContainerDetail {
+	detail := ContainerDetail{
+		ID:           shortID(object.ID),
+		Name:         strings.TrimPrefix(object.Name, "/"),
+		Image:        object.Config.Image,
+		ImageID:      shortID(object.Image),
+		Command:      strings.TrimSpace(object.Path + " " + strings.Join(object.Args, " ")),
+		Created:      object.Created,
+		RestartCount: object.RestartCount,
+		Ports:        []string{},
+		Labels:       object.Config.Labels,
+		State: ContainerState{
+			Status:    object.State.Status,
+			Running:   object.State.Running,
+			ExitCode:  object.State.ExitCode,
+			Error:     object.State.Error,
+			OOMKilled: object.State.OOMKilled,
+		},
+	}
+	// The API reports times it has not recorded as 0001-01-01.
+	if started := object.State.StartedAt; !started.IsZero() {
+		detail.State.StartedAt = &started
+	}
+	if finished := object.State.FinishedAt; !finished.IsZero() {
+		detail.State.FinishedAt = &finished
+	}
+	if object.State.Health != nil {
+		detail.State.Health = object.State.Health.Status
+	}
+	if policy := object.HostConfig.RestartPolicy; policy.Name != "" && policy.Name != "no" {
+		detail.RestartPolicy = policy.Name
+		if policy.MaximumRetryCount > 0 {
+			detail.RestartPolicy += ":" + strconv.Itoa(policy.MaximumRetryCount)
+		}
+	}
+
+	for port, bindings := range object.NetworkSettings.Ports {
+		number, protocol, _ := strings.Cut(port, "/")
+		private, _ := strconv.Atoi(number)
+		if len(bindings) == 0 {
+			detail.Ports = append(detail.Ports, formatPort("", 0, private, protocol))
+		}
+		for _, binding := range bindings {
+			public, _ := strconv.Atoi(binding.HostPort)
+			detail.Ports = append(detail.Ports, formatPort(binding.HostIP, public, private, protocol))
+		}
+	}
+	slices.Sort(detail.Ports)
+
+	for _, mount := range object.Mounts {
+		text := firstNonEmpty(mount.Name, mount.Source) + ":" + mount.Destination
+		if mount.Type != "" {
+			text = mount.Type + " " + text
+		}
+		if !mount.RW {
+			text += " (ro)"
+		}
+		detail.Mounts = append(detail.Mounts, text)
+	}
+	if len(object.NetworkSettings.Networks) > 0 {
+		detail.Networks = map[string]string{}
+		for name, network := range object.NetworkSettings.Networks {
+			detail.Networks[name] = network.IPAddress
+		}
+	}
+	for _, env := range object.Config.Env {
+		name, _, _ := strings.Cut(env, "=")
+		detail.Environment = append(detail.Environment, name)
+	}
+
+	return detail
+}
+
+// formatPort formats a port like docker ps: 0.0.0.0:8080->80/tcp when
+// published, otherwise 80/tcp.
+func formatPort(ip string, public, private int, protocol string) string {
+	text := strconv.Itoa(private) + "/" + firstNonEmpty(protocol, "tcp")
+	if public == 0 {
+		return text
+	}
+	host := strconv.Itoa(public)
+	if ip != "" {
+		if strings.Contains(ip, ":") {
+			ip = "[" + ip + "]"
+		}
+		host = ip + ":" + host
+	}
+
+	return host + "->" + text
+}
+
+// withContainer adds the container argument.
+func withContainer() mcp.ToolOption {
+	return mcp.WithString("container", mcp.Required(), mcp.Description("Container name or ID"))
+}
+
+// containerPath returns the API path of a container.
+func containerPath(container string) string {
+	return "/containers/" + url.PathEscape(container)
+}
+
+// inspect reads a container.
+func (p *Provider) inspect(ctx context.Context, container string) (containerObject, error) {
+	var object containerObject
+	err := p.api.Do(ctx, http.MethodGet, containerPath(container)+"/json", nil, nil, &object)
+
+	return object, err
+}
+
+func (p *Provider) listContainersTool() *providerkit.Tool {
+	tool := mcp.NewTool("docker_containers_list",
+		mcp.WithDescription("Lists containers with their image, state and published ports, like docker ps"),
+		mcp.WithBoolean("all", mcp.Description("Include stopped containers, like docker ps -a")),
+		mcp.WithString("name", mcp.Description("Only containers whose name contains this (optional)")),
+		mcp.WithString("label", mcp.Description("Only containers with this label, as key or key=value (optional)")),
+		mcp.WithString("status", mcp.Description("Only containers in this state (optional)"),
+			mcp.Enum("created", "restarting", "running", "removing", "paused", "exited", "dead")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		cursors, req, err := providerkit.ParsePage(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		query := filters(map[string]string{
+			"name":   request.GetString("name", ""),
+			"label":  request.GetString("label", ""),
+			"status": request.GetString("status", ""),
+		})
+		if request.GetBool("all", false) {
+			query.Set("all", "true")
+		}
+		var objects []containerSummary
+		if err := p.api.Do(ctx, http.MethodGet, "/containers/json", query, nil, &objects); err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		containers := make([]Container, 0, len(objects))
+		for _, object := range objects {
+			containers = append(containers, newContainer(object))
+		}
+
+		return providerkit.Result(pagination.Slice(cursors, req, containers), nil)
+	})
+}
+
+func (p *Provider) inspectContainerTool() *providerkit.Tool {
+	tool := mcp.NewTool("docker_container_inspect",
+		mcp.WithDescription("Shows a container's state, exit code, health, ports, mounts and networks, like docker inspect"),
+		withContainer(),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		container, err := request.RequireString("container")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		object, err := p.inspect(ctx, container)
+
+		return providerkit.Result(newContainerDetail(object), err)
+	})
+}
+
+// Logs are the last lines a container logged.
+type Logs struct {
+	Container string    `json:"container"`
+	Lines     []LogLine `json:"lines"`
+}
+
+// LogLine is a line of a container's output.
+type LogLine struct {
+	Stream string `json:"stream"`
+	Text   string `json:"text"`
+}
+
+func (p *Provider) containerLogsTool() *providerkit.Tool {
+	tool := mcp.NewTool("docker_container_logs",
+		mcp.WithDescription("Returns the last lines a container wrote to stdout and stderr, like docker logs"),
+		withContainer(),
+		mcp.WithNumber("tail_lines", mcp.Description("Number of lines from the end of the log (default 100)"), mcp.Min(1), mcp.Max(maxTailLines)),
+		mcp.WithString("since", mcp.Description("Only lines newer than a duration such as 15m, or an RFC 3339 time (optional)")),
+		mcp.WithBoolean("timestamps", mcp.Description("Prefix each line with its timestamp")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		container, err := request.RequireString("container")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		query, err := sinceQuery(request.GetString("since", ""), time.Now())
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		query.Set("stdout", "true")
+		query.Set("stderr", "true")
+		query.Set("tail", strconv.Itoa(min(max(request.GetInt("tail_lines", defaultTailLines), 1), maxTailLines)))
+		if request.GetBool("timestamps", false) {
+			query.Set("timestamps", "true")
+		}
+
+		// Without a TTY, the daemon multiplexes stdout and stderr into frames.
+		object, err := p.inspect(ctx, container)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+		var raw []byte
+		if _, err := p.api.Call(ctx, providerkit.Call{
+			Method: http.MethodGet,
+			Path:   containerPath(container) + "/logs",
+			Query:  query,
+			Raw:    &raw,
+		}); err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		logs := Logs{Container: strings.TrimPrefix(object.Name, "/"), Lines: []LogLine{}}
+		if object.Config.Tty {
+			logs.Lines = appendLines(logs.Lines, "stdout", raw)
+		} else {
+			logs.Lines = demux(raw)
+		}
+
+		return providerkit.Result(logs, nil)
+	})
+}
+
+// streams names the streams of multiplexed log frames.
+var streams = map[byte]string{0: "stdin", 1: "stdout", 2: "stderr"}
+
+// demux splits multiplexed logs into lines. Each frame has an 8-byte header
+// holding the stream and the payload size. A truncated last frame is kept.
+func demux(data []byte) []LogLine {
+	lines := []LogLine{}
+	for len(data) >= logHeaderSize {
+		stream := streams[data[0]]
+		size := int(min(binary.BigEndian.Uint32(data[4:logHeaderSize]), uint32(len(data)-logHeaderSize)))
+		lines = appendLines(lines, stream, data[logHeaderSize:logHeaderSize+size])
+		data = data[logHeaderSize+size:]
+	}
+
+	return lines
+}
+
+// appendLines appends the lines of a chunk of output.
+func appendLines(lines []LogLine, stream string, chunk []byte) []LogLine {
+	text := strings.TrimRight(string(chunk), "\n")
+	if text == "" {
+		return lines
+	}
+	for line := range strings.SplitSeq(text, "\n") {
+		lines = append(lines, LogLine{Stream: stream, Text: strings.TrimSuffix(line, "\r")})
+	}
+
+	return lines
+}
+
+// sinceQuery converts a since argument into the since query parameter of a
+// log request, in Unix seconds.
+func sinceQuery(since string, now time.Time) (url.Values, error) {
+	query := url.Values{}
+	if since == "" {
+		return query, nil
+	}
+	if duration, err := time.ParseDuration(since); err == nil && duration > 0 {
+		query.Set("since", strconv.FormatInt(now.Add(-duration).Unix(), 10))
+		return query, nil
+	}
+	if t, err := time.Parse(time.RFC3339, since); err == nil && t.Before(now) {
+		query.Set("since", strconv.FormatInt(t.Unix(), 10))
+		return query, nil
+	}
+
+	return nil, fmt.Errorf("%w, got %q", ErrInvalidSince, since)
+}
+
+func (p *Provider) startContainerTool() *providerkit.Tool {
+	tool := mcp.NewTool("docker_container_start",
+		mcp.WithDescription("Starts a stopped container"),
+		withContainer(),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return p.containerAction(ctx, request, "start")
+	})
+}
+
+func (p *Provider) stopContainerTool() *providerkit.Tool {
+	tool := mcp.NewTool("docker_container_stop",
+		mcp.WithDescription("Stops a running container, killing it if it has not exited after the timeout"),
+		withContainer(),
+		withStopTimeout(),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return p.containerAction(ctx, request, "stop")
+	})
+}
+
+func (p *Provider) restartContainerTool() *providerkit.Tool {
+	tool := mcp.NewTool("docker_container_restart",
+		mcp.WithDescription("Restarts a container, killing it if it has not exited after the timeout"),
+		withContainer(),
+		withStopTimeout(),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(false),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return p.containerAction(ctx, request, "restart")
+	})
+}
+
+// withStopTimeout adds the timeout argument of stop and restart.
+func withStopTimeout() mcp.ToolOption {
+	return mcp.WithNumber("timeout", mcp.Description("Seconds to wait for the container to exit before killing it (default: the container's stop timeout, usually 10)"),
+		mcp.Min(0), mcp.Max(maxStopTimeout))
+}
+
+// containerAction starts, stops or restarts a container and returns its new
+// state. The daemon answers 304 when the container already is in the state
+// asked for, which is not an error.
+func (p *Provider) containerAction(ctx context.Context, request mcp.CallToolRequest, action string) (*mcp.CallToolResult, error) {
+	container, err := request.RequireString("container")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	query := url.Values{}
+	if timeout := request.GetInt("timeout", -1); timeout >= 0 {
+		query.Set("t", strconv.Itoa(min(timeout, maxStopTimeout)))
+	}
+
+	if _, err := p.api.Call(ctx, providerkit.Call{
+		Method: http.MethodPost,
+		Path:   containerPath(container) + "/" + action,
+		Query:  query,
+	}); err != nil {
+		return providerkit.Result(nil, err)
+	}
+	object, err := p.inspect(ctx, container)
+
+	return providerkit.Result(newContainerDetail(object), err)
+}
+
+// removeResult reports a removed container or image.
+type removeResult struct {
+	ID      string   `json:"id,omitempty"`
+	Name    string   `json:"name"`
+	Status  string   `json:"status"`
+	Deleted []string `json:"deleted,omitempty"`
+}
+
+func (p *Provider) removeContainerTool() *providerkit.Tool {
+	tool := mcp.NewTool("docker_container_remove",
+		mcp.WithDescription("Removes a container. Running containers are only removed with force."),
+		withContainer(),
+		providerkit.WithConfirmParam("the container name"),
+		mcp.WithBoolean("force", mcp.Description("Kill and remove the container if it is running")),
+		mcp.WithBoolean("volumes", mcp.Description("Also remove the container's anonymous volumes")),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		container, err := request.RequireString("container")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		object, err := p.inspect(ctx, container)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+		name := strings.TrimPrefix(object.Name, "/")
+		if refused := providerkit.Confirm(request, "removed", "the container name", name, container); refused != nil {
+			return refused, nil
+		}
+
+		query := url.Values{}
+		if request.GetBool("force", false) {
+			query.Set("force", "true")
+		}
+		if request.GetBool("volumes", false) {
+			query.Set("v", "true")
+		}
+		err = p.api.Do(ctx, http.MethodDelete, containerPath(object.ID), query, nil, nil)
+
+		return providerkit.Result(removeResult{ID: shortID(object.ID), Name: name, Status: "removed"}, err)
+	})
+}
+
+// firstNonEmpty returns the first non-empty value.
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
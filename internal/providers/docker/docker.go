@@ -0,0 +1,242 @@
+// Package docker implements the Docker Engine API provider. It talks to the
+// local daemon over its Unix socket, or to a daemon at a configured host, with
+// tools for containers, images and networks.
+package docker
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chadit/CloudMCP/internal/httpclient"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	// Name is the provider name used in settings.
+	Name = "docker"
+
+	// DefaultHost is the daemon address used when neither the host setting
+	// nor DOCKER_HOST is set.
+	DefaultHost = "unix:///var/run/docker.sock"
+
+	// socketBaseURL is the base URL of requests sent over a Unix socket. Its
+	// host is not used to connect.
+	socketBaseURL = "http://docker"
+
+	// shortIDLength is how much of an ID is shown, as by the docker CLI.
+	shortIDLength = 12
+)
+
+// Static errors for err113 compliance.
+var ErrInvalidHost = errors.New("invalid Docker host")
+
+// Provider is the Docker Engine provider.
+type Provider struct {
+	api *providerkit.API
+
+	// pulls is api without a whole-request timeout. Pulls take as long as the
+	// image does and end with the call's context.
+	pulls *providerkit.API
+}
+
+// New creates an uninitialized Docker provider.
+func New() contracts.Provider {
+	return &Provider{}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return Name
+}
+
+// Initialize connects the API client to the daemon. The host setting, like
+// DOCKER_HOST, is a unix://, tcp://, http:// or https:// address; TCP hosts
+// use TLS when cert_path names a directory with ca.pem, cert.pem and key.pem,
+// like DOCKER_CERT_PATH. api_version pins the Engine API version.
+func (p *Provider) Initialize(_ context.Context, cfg contracts.ProviderConfig) error {
+	shared := cfg.HTTPClient
+	if shared == nil {
+		shared = http.DefaultClient
+	}
+
+	host := cfg.Setting("host", os.Getenv("DOCKER_HOST"))
+	if host == "" {
+		host = DefaultHost
+	}
+	baseURL, client, err := newClient(host, cfg.Setting("cert_path", os.Getenv("DOCKER_CERT_PATH")), shared)
+	if err != nil {
+		return err
+	}
+	if version := cfg.Setting("api_version", ""); version != "" {
+		baseURL += "/v" + strings.TrimPrefix(version, "v")
+	}
+
+	p.api = &providerkit.API{
+		Provider:     "Docker",
+		BaseURL:      baseURL,
+		HTTP:         client,
+		ErrorMessage: errorMessage,
+	}
+	untimed := *client
+	untimed.Timeout = 0
+	pulls := *p.api
+	pulls.HTTP = &untimed
+	p.pulls = &pulls
+
+	return nil
+}
+
+// Tools returns the Docker tools.
+func (p *Provider) Tools() []contracts.Tool {
+	return []contracts.Tool{
+		p.listContainersTool(),
+		p.inspectContainerTool(),
+		p.containerLogsTool(),
+		p.startContainerTool(),
+		p.stopContainerTool(),
+		p.restartContainerTool(),
+		p.removeContainerTool(),
+		p.listImagesTool(),
+		p.pullImageTool(),
+		p.removeImageTool(),
+		p.listNetworksTool(),
+		p.inspectNetworkTool(),
+	}
+}
+
+// HealthCheck pings the daemon.
+func (p *Provider) HealthCheck(ctx context.Context) error {
+	return p.api.Do(ctx, http.MethodGet, "/_ping", nil, nil, nil)
+}
+
+// Shutdown has nothing to release.
+func (p *Provider) Shutdown(context.Context) error {
+	return nil
+}
+
+// newClient returns the base URL and HTTP client of a daemon address. The
+// shared client's retries and breakers are kept when it has them.
+func newClient(host, certPath string, shared *http.Client) (string, *http.Client, error) {
+	u, err := url.Parse(host)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w %q: %w", ErrInvalidHost, host, err)
+	}
+
+	base, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		base = &http.Transport{}
+	}
+	transport := base.Clone()
+	baseURL := ""
+
+	switch u.Scheme {
+	case "unix":
+		socket := u.Path
+		if socket == "" {
+			return "", nil, fmt.Errorf("%w %q: no socket path", ErrInvalidHost, host)
+		}
+		transport.Proxy = nil
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socket)
+		}
+		baseURL = socketBaseURL
+	case "tcp", "http", "https":
+		scheme := u.Scheme
+		if scheme == "tcp" {
+			scheme = "http"
+			if certPath != "" {
+				scheme = "https"
+			}
+		}
+		if u.Host == "" {
+			return "", nil, fmt.Errorf("%w %q: no host", ErrInvalidHost, host)
+		}
+		baseURL = scheme + "://" + u.Host + strings.TrimRight(u.Path, "/")
+		if scheme == "http" || certPath == "" {
+			return baseURL, shared, nil
+		}
+		tlsConfig, err := clientTLS(certPath)
+		if err != nil {
+			return "", nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	default:
+		return "", nil, fmt.Errorf("%w %q: use unix://, tcp://, http:// or https://", ErrInvalidHost, host)
+	}
+
+	if resilient, ok := shared.Transport.(*httpclient.Transport); ok {
+		return baseURL, &http.Client{Transport: resilient.WithBase(transport), Timeout: shared.Timeout}, nil
+	}
+
+	return baseURL, &http.Client{Transport: transport, Timeout: shared.Timeout}, nil
+}
+
+// clientTLS loads the CA and client certificate of a TLS-protected daemon
+// from a directory laid out like DOCKER_CERT_PATH.
+func clientTLS(dir string) (*tls.Config, error) {
+	ca, err := os.ReadFile(filepath.Join(dir, "ca.pem"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Docker CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("%w: %s has no PEM certificates", ErrInvalidHost, filepath.Join(dir, "ca.pem"))
+	}
+	pair, err := tls.LoadX509KeyPair(filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load Docker client certificate: %w", err)
+	}
+
+	return &tls.Config{MinVersion: tls.VersionTLS12, RootCAs: pool, Certificates: []tls.Certificate{pair}}, nil
+}
+
+// errorMessage extracts the message of an Engine API error response.
+func errorMessage(body []byte) string {
+	var resp struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return ""
+	}
+
+	return resp.Message
+}
+
+// shortID returns the short form of an ID, without its digest algorithm.
+func shortID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) > shortIDLength {
+		return id[:shortIDLength]
+	}
+
+	return id
+}
+
+// filters encodes Engine API list filters, leaving out empty values.
+func filters(values map[string]string) url.Values {
+	encoded := map[string][]string{}
+	for key, value := range values {
+		if value != "" {
+			encoded[key] = []string{value}
+		}
+	}
+	query := url.Values{}
+	if len(encoded) > 0 {
+		data, _ := json.Marshal(encoded)
+		query.Set("filters", string(data))
+	}
+
+	return query
+}
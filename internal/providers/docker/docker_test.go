@@ -0,0 +1,549 @@
+package docker_test
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chadit/CloudMCP/internal/httpclient"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/internal/providerkit/providerkittest"
+	"github.com/chadit/CloudMCP/internal/providers/docker"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	webID    = "3f4e8a9b1c2d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0f"
+	workerID = "9c8b7a6f5e4d3c2b1a0f9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b3a2f1e0d9c8b"
+	shellID  = "0a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f9"
+)
+
+// fakeContainer is the state of a stub container.
+type fakeContainer struct {
+	id, name, image string
+	running, tty    bool
+	exitCode        int
+	oomKilled       bool
+}
+
+// fakeEngine is a stub of the Docker Engine API with the containers web
+// (running), worker (OOM killed) and shell (running with a TTY).
+type fakeEngine struct {
+	mu         sync.Mutex
+	prefix     string
+	failures   int
+	pullDelay  time.Duration
+	requests   []string
+	queries    map[string]url.Values
+	containers []*fakeContainer
+}
+
+func (f *fakeEngine) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+	f.queries[r.Method+" "+r.URL.Path] = r.URL.Query()
+	if f.failures > 0 {
+		f.failures--
+		writeError(w, http.StatusServiceUnavailable, "daemon is busy")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, f.prefix)
+	switch {
+	case path == "/_ping":
+		_, _ = io.WriteString(w, "OK")
+	case path == "/containers/json":
+		f.listContainers(w, r)
+	case strings.HasPrefix(path, "/containers/"):
+		name, action, _ := strings.Cut(strings.TrimPrefix(path, "/containers/"), "/")
+		container := f.container(name)
+		if container == nil {
+			writeError(w, http.StatusNotFound, "No such container: "+name)
+			return
+		}
+		f.serveContainer(w, r, container, action)
+	case path == "/images/json":
+		providerkittest.WriteJSON(w, http.StatusOK, []map[string]any{
+			{"Id": "sha256:5ef79149e0ec84a7a9f9284c3f91aa3c20608f8391f5445eabe92ef07dbda03c", "RepoTags": []string{"nginx:1.27"},
+				"RepoDigests": []string{"nginx@sha256:28402db6"}, "Created": 1718000000, "Size": 187654321},
+			{"Id": "sha256:aa11bb22cc33dd44ee55ff66aa11bb22cc33dd44ee55ff66aa11bb22cc33dd44", "RepoTags": []string{"<none>:<none>"},
+				"Created": 1717000000, "Size": 1024},
+		})
+	case path == "/images/create" && r.Method == http.MethodPost:
+		if r.URL.Query().Get("fromImage") == "private/app" {
+			_, _ = io.WriteString(w, `{"status":"Pulling from private/app"}`+"\n"+
+				`{"errorDetail":{"message":"pull access denied"},"error":"pull access denied for private/app"}`+"\n")
+			return
+		}
+		_, _ = io.WriteString(w, `{"status":"Pulling from library/nginx","id":"1.27"}`+"\n"+
+			`{"status":"Downloading","progressDetail":{"current":10,"total":100},"id":"a1b2"}`+"\n")
+		if f.pullDelay > 0 {
+			w.(http.Flusher).Flush()
+			time.Sleep(f.pullDelay)
+		}
+		_, _ = io.WriteString(w, `{"status":"Digest: sha256:28402db6"}`+"\n"+
+			`{"status":"Status: Downloaded newer image for nginx:1.27"}`+"\n")
+	case path == "/images/nginx:1.27" && r.Method == http.MethodDelete:
+		providerkittest.WriteJSON(w, http.StatusOK, []map[string]string{{"Untagged": "nginx:1.27"}, {"Deleted": "sha256:5ef79149e0ec"}})
+	case path == "/networks":
+		providerkittest.WriteJSON(w, http.StatusOK, []map[string]any{
+			{"Id": "b1c2d3e4f5a6b7c8d9e0", "Name": "bridge", "Driver": "bridge", "Scope": "local",
+				"IPAM": map[string]any{"Config": []map[string]string{{"Subnet": "172.17.0.0/16"}}}},
+			{"Id": "e5f6a7b8c9d0e1f2a3b4", "Name": "app-net", "Driver": "bridge", "Scope": "local", "Internal": true},
+		})
+	case path == "/networks/app-net":
+		providerkittest.WriteJSON(w, http.StatusOK, map[string]any{
+			"Id": "e5f6a7b8c9d0e1f2a3b4", "Name": "app-net", "Driver": "bridge", "Scope": "local",
+			"Containers": map[string]any{
+				workerID: map[string]string{"Name": "worker", "IPv4Address": "172.18.0.3/16"},
+				webID:    map[string]string{"Name": "web", "IPv4Address": "172.18.0.2/16"},
+			},
+		})
+	default:
+		writeError(w, http.StatusNotFound, "page not found")
+	}
+}
+
+func (f *fakeEngine) container(name string) *fakeContainer {
+	for _, container := range f.containers {
+		if container.name == name || strings.HasPrefix(container.id, name) {
+			return container
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeEngine) listContainers(w http.ResponseWriter, r *http.Request) {
+	list := []map[string]any{}
+	for _, container := range f.containers {
+		if !container.running && r.URL.Query().Get("all") != "true" {
+			continue
+		}
+		state, status := "exited", fmt.Sprintf("Exited (%d) 5 minutes ago", container.exitCode)
+		if container.running {
+			state, status = "running", "Up 2 hours"
+		}
+		list = append(list, map[string]any{
+			"Id": container.id, "Names": []string{"/" + container.name}, "Image": container.image,
+			"Command": "/docker-entrypoint.sh", "Created": 1718000000, "State": state, "Status": status,
+			"Ports": []map[string]any{
+				{"IP": "0.0.0.0", "PrivatePort": 80, "PublicPort": 8080, "Type": "tcp"},
+				{"IP": "0.0.0.0", "PrivatePort": 80, "PublicPort": 8080, "Type": "tcp"},
+				{"PrivatePort": 443, "Type": "tcp"},
+			},
+		})
+	}
+	providerkittest.WriteJSON(w, http.StatusOK, list)
+}
+
+func (f *fakeEngine) serveContainer(w http.ResponseWriter, r *http.Request, container *fakeContainer, action string) {
+	switch {
+	case action == "json":
+		providerkittest.WriteJSON(w, http.StatusOK, inspectJSON(container))
+	case action == "logs":
+		if container.tty {
+			_, _ = io.WriteString(w, "$ ls\r\nbin etc\r\n")
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.docker.multiplexed-stream")
+		_, _ = w.Write(frame(1, "listening on :80\n"))
+		_, _ = w.Write(frame(2, "warn: slow request\nerror: upstream timed out\n"))
+		_, _ = w.Write(frame(1, "GET / 200\n"))
+	case r.Method == http.MethodPost && (action == "start" || action == "stop" || action == "restart"):
+		running := action != "stop"
+		if action != "restart" && container.running == running {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		container.running = running
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodDelete && action == "":
+		if container.running && r.URL.Query().Get("force") != "true" {
+			writeError(w, http.StatusConflict, "You cannot remove a running container "+container.id+". Stop the container before attempting removal or force remove")
+			return
+		}
+		for i, c := range f.containers {
+			if c == container {
+				f.containers = append(f.containers[:i], f.containers[i+1:]...)
+				break
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusNotFound, "page not found")
+	}
+}
+
+func inspectJSON(container *fakeContainer) map[string]any {
+	status := "exited"
+	if container.running {
+		status = "running"
+	}
+
+	return map[string]any{
+		"Id": container.id, "Name": "/" + container.name, "Created": "2026-10-18T08:00:00Z",
+		"Path": "/docker-entrypoint.sh", "Args": []string{"nginx", "-g", "daemon off;"},
+		"Image": "sha256:5ef79149e0ec84a7a9f9284c3f91aa3c20608f8391f5445eabe92ef07dbda03c", "RestartCount": 2,
+		"State": map[string]any{
+			"Status": status, "Running": container.running, "OOMKilled": container.oomKilled, "ExitCode": container.exitCode,
+			"StartedAt": "2026-10-18T08:00:01Z", "FinishedAt": "0001-01-01T00:00:00Z",
+		},
+		"Config": map[string]any{
+			"Image": container.image, "Tty": container.tty,
+			"Env":    []string{"PATH=/usr/bin", "DATABASE_PASSWORD=hunter2"},
+			"Labels": map[string]string{"com.docker.compose.project": "shop"},
+		},
+		"HostConfig": map[string]any{"RestartPolicy": map[string]any{"Name": "on-failure", "MaximumRetryCount": 3}},
+		"Mounts": []map[string]any{
+			{"Type": "volume", "Name": "web-data", "Destination": "/data", "RW": true},
+			{"Type": "bind", "Source": "/etc/nginx", "Destination": "/etc/nginx", "RW": false},
+		},
+		"NetworkSettings": map[string]any{
+			"Ports": map[string]any{
+				"80/tcp":  []map[string]string{{"HostIp": "0.0.0.0", "HostPort": "8080"}, {"HostIp": "::", "HostPort": "8080"}},
+				"443/tcp": nil,
+			},
+			"Networks": map[string]any{"app-net": map[string]string{"IPAddress": "172.18.0.2"}},
+		},
+	}
+}
+
+// frame encodes a frame of a multiplexed log stream.
+func frame(stream byte, payload string) []byte {
+	header := make([]byte, 8)
+	header[0] = stream
+	binary.BigEndian.PutUint32(header[4:], uint32(len(payload)))
+
+	return append(header, payload...)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	providerkittest.WriteJSON(w, status, map[string]string{"message": message})
+}
+
+// serveSocket serves a stub engine on a Unix socket and returns its address.
+func serveSocket(t *testing.T, engine *fakeEngine) string {
+	t.Helper()
+
+	// Socket paths are limited to about 100 bytes, too few for t.TempDir.
+	dir, err := os.MkdirTemp("", "docker")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	socket := filepath.Join(dir, "docker.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	srv := httptest.NewUnstartedServer(engine)
+	_ = srv.Listener.Close()
+	srv.Listener = listener
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	return "unix://" + socket
+}
+
+// setup starts a stub engine and returns it with the provider and its tools
+// by name.
+func setup(t *testing.T, settings map[string]string) (*fakeEngine, contracts.Provider, map[string]contracts.Tool) {
+	t.Helper()
+
+	engine := &fakeEngine{queries: map[string]url.Values{}, containers: []*fakeContainer{
+		{id: webID, name: "web", image: "nginx:1.27", running: true},
+		{id: workerID, name: "worker", image: "shop/worker:2.1", exitCode: 137, oomKilled: true},
+		{id: shellID, name: "shell", image: "alpine:3.20", running: true, tty: true},
+	}}
+	if version := settings["api_version"]; version != "" {
+		engine.prefix = "/v" + version
+	}
+
+	cfg := map[string]string{"host": serveSocket(t, engine)}
+	for key, value := range settings {
+		cfg[key] = value
+	}
+	provider := docker.New()
+	tools := providerkittest.Setup(t, provider, contracts.ProviderConfig{
+		Settings:   cfg,
+		HTTPClient: httpclient.New(httpclient.Options{MaxRetries: 1, BaseDelay: time.Millisecond}),
+	})
+
+	return engine, provider, tools
+}
+
+func TestHealthCheck_PingsOverUnixSocket(t *testing.T) {
+	t.Parallel()
+
+	engine, provider, _ := setup(t, map[string]string{"api_version": "1.43"})
+	engine.failures = 1
+	require.NoError(t, provider.HealthCheck(t.Context()))
+	require.Equal(t, []string{"GET /v1.43/_ping", "GET /v1.43/_ping"}, engine.requests,
+		"the shared client's retries are kept over the socket")
+}
+
+func TestInitialize_RejectsInvalidHosts(t *testing.T) {
+	t.Parallel()
+
+	for _, host := range []string{"ssh://build@host", "unix://", "tcp://"} {
+		err := docker.New().Initialize(t.Context(), contracts.ProviderConfig{Settings: map[string]string{"host": host}})
+		require.ErrorIs(t, err, docker.ErrInvalidHost, host)
+	}
+
+	err := docker.New().Initialize(t.Context(), contracts.ProviderConfig{
+		Settings: map[string]string{"host": "tcp://docker.internal:2376", "cert_path": t.TempDir()},
+	})
+	require.ErrorContains(t, err, "failed to read Docker CA")
+}
+
+func TestContainersList_FiltersAndFormatsPorts(t *testing.T) {
+	t.Parallel()
+
+	engine, _, tools := setup(t, nil)
+	ctx := providerkittest.CallContext(t)
+
+	text, isError := providerkittest.Call(ctx, t, tools["docker_containers_list"], map[string]any{"name": "web"})
+	require.False(t, isError, text)
+	var page pagination.Page[docker.Container]
+	require.NoError(t, json.Unmarshal([]byte(text), &page))
+	require.Len(t, page.Items, 2, "stopped containers are left out by default")
+	web := page.Items[0]
+	require.Equal(t, "3f4e8a9b1c2d", web.ID)
+	require.Equal(t, "web", web.Name)
+	require.Equal(t, []string{"0.0.0.0:8080->80/tcp", "443/tcp"}, web.Ports)
+	require.JSONEq(t, `{"name":["web"]}`, engine.queries["GET /containers/json"].Get("filters"))
+
+	text, isError = providerkittest.Call(ctx, t, tools["docker_containers_list"], map[string]any{"all": true})
+	require.False(t, isError, text)
+	require.NoError(t, json.Unmarshal([]byte(text), &page))
+	require.Len(t, page.Items, 3)
+	require.Equal(t, "exited", page.Items[1].State)
+	require.Equal(t, "true", engine.queries["GET /containers/json"].Get("all"))
+}
+
+func TestContainerInspect_ShowsStateWithoutSecrets(t *testing.T) {
+	t.Parallel()
+
+	_, _, tools := setup(t, nil)
+	ctx := providerkittest.CallContext(t)
+
+	text, isError := providerkittest.Call(ctx, t, tools["docker_container_inspect"], map[string]any{"container": "worker"})
+	require.False(t, isError, text)
+	require.NotContains(t, text, "hunter2", "environment values are left out")
+	var detail docker.ContainerDetail
+	require.NoError(t, json.Unmarshal([]byte(text), &detail))
+	require.Equal(t, "worker", detail.Name)
+	require.Equal(t, "5ef79149e0ec", detail.ImageID)
+	require.Equal(t, "/docker-entrypoint.sh nginx -g daemon off;", detail.Command)
+	require.Equal(t, "exited", detail.State.Status)
+	require.Equal(t, 137, detail.State.ExitCode)
+	require.True(t, detail.State.OOMKilled)
+	require.NotNil(t, detail.State.StartedAt)
+	require.Nil(t, detail.State.FinishedAt, "unrecorded times are left out")
+	require.Equal(t, "on-failure:3", detail.RestartPolicy)
+	require.Equal(t, []string{"0.0.0.0:8080->80/tcp", "443/tcp", "[::]:8080->80/tcp"}, detail.Ports)
+	require.Equal(t, []string{"volume web-data:/data", "bind /etc/nginx:/etc/nginx (ro)"}, detail.Mounts)
+	require.Equal(t, map[string]string{"app-net": "172.18.0.2"}, detail.Networks)
+	require.Equal(t, []string{"PATH", "DATABASE_PASSWORD"}, detail.Environment)
+
+	text, isError = providerkittest.Call(ctx, t, tools["docker_container_inspect"], map[string]any{"container": "missing"})
+	require.True(t, isError)
+	require.Contains(t, text, providerkit.ErrNotFound.Error())
+	require.Contains(t, text, "No such container: missing")
+}
+
+func TestContainerLogs_SplitsStreams(t *testing.T) {
+	t.Parallel()
+
+	engine, _, tools := setup(t, nil)
+	ctx := providerkittest.CallContext(t)
+
+	text, isError := providerkittest.Call(ctx, t, tools["docker_container_logs"], map[string]any{
+		"container": "web", "tail_lines": float64(20), "since": "15m",
+	})
+	require.False(t, isError, text)
+	var logs docker.Logs
+	require.NoError(t, json.Unmarshal([]byte(text), &logs))
+	require.Equal(t, docker.Logs{Container: "web", Lines: []docker.LogLine{
+		{Stream: "stdout", Text: "listening on :80"},
+		{Stream: "stderr", Text: "warn: slow request"},
+		{Stream: "stderr", Text: "error: upstream timed out"},
+		{Stream: "stdout", Text: "GET / 200"},
+	}}, logs)
+	query := engine.queries["GET /containers/web/logs"]
+	require.Equal(t, "20", query.Get("tail"))
+	require.Equal(t, "true", query.Get("stdout"))
+	require.Equal(t, "true", query.Get("stderr"))
+	require.NotEmpty(t, query.Get("since"))
+
+	text, isError = providerkittest.Call(ctx, t, tools["docker_container_logs"], map[string]any{"container": "shell"})
+	require.False(t, isError, text)
+	require.NoError(t, json.Unmarshal([]byte(text), &logs))
+	require.Equal(t, []docker.LogLine{{Stream: "stdout", Text: "$ ls"}, {Stream: "stdout", Text: "bin etc"}}, logs.Lines,
+		"TTY output is not multiplexed")
+
+	text, isError = providerkittest.Call(ctx, t, tools["docker_container_logs"], map[string]any{"container": "web", "since": "last week"})
+	require.True(t, isError)
+	require.Contains(t, text, docker.ErrInvalidSince.Error())
+}
+
+func TestContainerActions_ReportNewState(t *testing.T) {
+	t.Parallel()
+
+	engine, _, tools := setup(t, nil)
+	ctx := providerkittest.CallContext(t)
+
+	text, isError := providerkittest.Call(ctx, t, tools["docker_container_stop"], map[string]any{"container": "web", "timeout": float64(5)})
+	require.False(t, isError, text)
+	var detail docker.ContainerDetail
+	require.NoError(t, json.Unmarshal([]byte(text), &detail))
+	require.False(t, detail.State.Running)
+	require.Equal(t, "5", engine.queries["POST /containers/web/stop"].Get("t"))
+
+	text, isError = providerkittest.Call(ctx, t, tools["docker_container_stop"], map[string]any{"container": "web"})
+	require.False(t, isError, "stopping a stopped container is not an error: %s", text)
+	require.Empty(t, engine.queries["POST /containers/web/stop"].Get("t"), "the container's own timeout is the default")
+
+	text, isError = providerkittest.Call(ctx, t, tools["docker_container_start"], map[string]any{"container": "web"})
+	require.False(t, isError, text)
+	require.NoError(t, json.Unmarshal([]byte(text), &detail))
+	require.True(t, detail.State.Running)
+
+	text, isError = providerkittest.Call(ctx, t, tools["docker_container_restart"], map[string]any{"container": "web"})
+	require.False(t, isError, text)
+	require.Contains(t, engine.requests, "POST /containers/web/restart")
+}
+
+func TestContainerRemove_RequiresConfirmation(t *testing.T) {
+	t.Parallel()
+
+	engine, _, tools := setup(t, nil)
+	ctx := providerkittest.CallContext(t)
+
+	text, isError := providerkittest.Call(ctx, t, tools["docker_container_remove"], map[string]any{"container": "3f4e8a9b1c2d", "confirm": "worker"})
+	require.True(t, isError)
+	require.Contains(t, text, `not removed: confirm must be the container name "web"`)
+	require.NotContains(t, engine.requests, "DELETE /containers/"+webID)
+
+	text, isError = providerkittest.Call(ctx, t, tools["docker_container_remove"], map[string]any{"container": "3f4e8a9b1c2d", "confirm": "web"})
+	require.True(t, isError)
+	require.Contains(t, text, providerkit.ErrConflict.Error())
+	require.Contains(t, text, "Stop the container before attempting removal")
+
+	text, isError = providerkittest.Call(ctx, t, tools["docker_container_remove"], map[string]any{
+		"container": "web", "confirm": "web", "force": true, "volumes": true,
+	})
+	require.False(t, isError, text)
+	require.JSONEq(t, `{"id":"3f4e8a9b1c2d","name":"web","status":"removed"}`, text)
+	require.Equal(t, url.Values{"force": {"true"}, "v": {"true"}}, engine.queries["DELETE /containers/"+webID])
+}
+
+func TestImages_ListPullAndRemove(t *testing.T) {
+	t.Parallel()
+
+	engine, _, tools := setup(t, nil)
+	ctx := providerkittest.CallContext(t)
+
+	text, isError := providerkittest.Call(ctx, t, tools["docker_images_list"], map[string]any{"reference": "nginx", "dangling": true})
+	require.False(t, isError, text)
+	var page pagination.Page[docker.Image]
+	require.NoError(t, json.Unmarshal([]byte(text), &page))
+	require.Len(t, page.Items, 2)
+	require.Equal(t, "5ef79149e0ec", page.Items[0].ID)
+	require.Equal(t, []string{"nginx:1.27"}, page.Items[0].Tags)
+	require.Empty(t, page.Items[1].Tags, "<none> tags are left out")
+	require.JSONEq(t, `{"reference":["nginx"],"dangling":["true"]}`, engine.queries["GET /images/json"].Get("filters"))
+
+	text, isError = providerkittest.Call(ctx, t, tools["docker_image_pull"], map[string]any{"image": "nginx:1.27"})
+	require.False(t, isError, text)
+	require.JSONEq(t, `{"image":"nginx:1.27","digest":"sha256:28402db6","status":"Downloaded newer image for nginx:1.27"}`, text)
+	require.Equal(t, url.Values{"fromImage": {"nginx"}, "tag": {"1.27"}}, engine.queries["POST /images/create"])
+
+	for image, want := range map[string]url.Values{
+		"localhost:5000/shop/api":    {"fromImage": {"localhost:5000/shop/api"}, "tag": {"latest"}},
+		"ghcr.io/shop/api@sha256:ab": {"fromImage": {"ghcr.io/shop/api"}, "tag": {"sha256:ab"}},
+	} {
+		_, isError = providerkittest.Call(ctx, t, tools["docker_image_pull"], map[string]any{"image": image})
+		require.False(t, isError)
+		require.Equal(t, want, engine.queries["POST /images/create"], image)
+	}
+
+	text, isError = providerkittest.Call(ctx, t, tools["docker_image_pull"], map[string]any{"image": "private/app"})
+	require.True(t, isError)
+	require.Contains(t, text, docker.ErrPullFailed.Error())
+	require.Contains(t, text, "pull access denied for private/app")
+
+	text, isError = providerkittest.Call(ctx, t, tools["docker_image_remove"], map[string]any{"image": "nginx:1.27", "confirm": "nginx"})
+	require.True(t, isError)
+	require.Contains(t, text, `not removed: confirm must be the image "nginx:1.27"`)
+
+	text, isError = providerkittest.Call(ctx, t, tools["docker_image_remove"], map[string]any{"image": "nginx:1.27", "confirm": "nginx:1.27"})
+	require.False(t, isError, text)
+	require.JSONEq(t, `{"name":"nginx:1.27","status":"removed","deleted":["untagged nginx:1.27","deleted sha256:5ef79149e0ec"]}`, text)
+}
+
+func TestImagePull_OutlastsRequestTimeout(t *testing.T) {
+	t.Parallel()
+
+	engine := &fakeEngine{queries: map[string]url.Values{}, pullDelay: 300 * time.Millisecond}
+	tools := providerkittest.Setup(t, docker.New(), contracts.ProviderConfig{
+		Settings:   map[string]string{"host": serveSocket(t, engine)},
+		HTTPClient: httpclient.New(httpclient.Options{Timeout: 100 * time.Millisecond}),
+	})
+	pull := tools["docker_image_pull"]
+
+	progress := &providerkittest.Progress{}
+	ctx := contracts.WithProgressReporter(providerkittest.CallContext(t), progress)
+	text, isError := providerkittest.Call(ctx, t, pull, map[string]any{"image": "nginx:1.27"})
+	require.False(t, isError, text)
+	require.Contains(t, text, "Downloaded newer image for nginx:1.27", "the pull is not cut off by the client timeout")
+	require.Equal(t, []string{
+		"1.27: Pulling from library/nginx", "a1b2: Downloading", "Digest: sha256:28402db6", "Status: Downloaded newer image for nginx:1.27",
+	}, progress.Messages())
+
+	ctx, cancel := context.WithTimeout(providerkittest.CallContext(t), 50*time.Millisecond)
+	defer cancel()
+	text, isError = providerkittest.Call(ctx, t, pull, map[string]any{"image": "nginx:1.27"})
+	require.True(t, isError, "the pull ends with the call's context")
+	require.Contains(t, text, context.DeadlineExceeded.Error())
+}
+
+func TestNetworks_ListAndInspect(t *testing.T) {
+	t.Parallel()
+
+	_, _, tools := setup(t, nil)
+	ctx := providerkittest.CallContext(t)
+
+	text, isError := providerkittest.Call(ctx, t, tools["docker_networks_list"], nil)
+	require.False(t, isError, text)
+	var page pagination.Page[docker.Network]
+	require.NoError(t, json.Unmarshal([]byte(text), &page))
+	require.Len(t, page.Items, 2)
+	require.Equal(t, "app-net", page.Items[0].Name)
+	require.True(t, page.Items[0].Internal)
+	require.Equal(t, []string{"172.17.0.0/16"}, page.Items[1].Subnets)
+
+	text, isError = providerkittest.Call(ctx, t, tools["docker_network_inspect"], map[string]any{"network": "app-net"})
+	require.False(t, isError, text)
+	var network docker.Network
+	require.NoError(t, json.Unmarshal([]byte(text), &network))
+	require.Equal(t, []docker.NetworkContainer{
+		{ID: "3f4e8a9b1c2d", Name: "web", IPv4: "172.18.0.2/16"},
+		{ID: "9c8b7a6f5e4d", Name: "worker", IPv4: "172.18.0.3/16"},
+	}, network.Containers)
+}
@@ -0,0 +1,258 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/idempotency"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+// Static errors for err113 compliance.
+var ErrPullFailed = errors.New("image pull failed")
+
+// Image summarizes an image, like docker images.
+type Image struct {
+	ID      string    `json:"id"`
+	Tags    []string  `json:"tags"`
+	Digests []string  `json:"digests,omitempty"`
+	Size    int64     `json:"size_bytes"`
+	Created time.Time `json:"created"`
+}
+
+// imageSummary is an image as the list endpoint returns it.
+type imageSummary struct {
+	ID          string   `json:"Id"`
+	RepoTags    []string `json:"RepoTags"`
+	RepoDigests []string `json:"RepoDigests"`
+	Created     int64    `json:"Created"`
+	Size        int64    `json:"Size"`
+}
+
+func newImage(object imageSummary) Image {
+	image := Image{
+		ID:      shortID(object.ID),
+		Tags:    []string{},
+		Digests: object.RepoDigests,
+		Size:    object.Size,
+		Created: time.Unix(object.Created, 0).UTC(),
+	}
+	// Untagged images are listed with the tag <none>:<none>.
+	for _, tag := range object.RepoTags {
+		if tag != "<none>:<none>" {
+			image.Tags = append(image.Tags, tag)
+		}
+	}
+
+	return image
+}
+
+func (p *Provider) listImagesTool() *providerkit.Tool {
+	tool := mcp.NewTool("docker_images_list",
+		mcp.WithDescription("Lists local images with their tags and size, like docker images"),
+		mcp.WithString("reference", mcp.Description("Only images matching this reference, such as nginx or nginx:1.* (optional)")),
+		mcp.WithBoolean("dangling", mcp.Description("Only untagged images left behind by builds and pulls")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		cursors, req, err := providerkit.ParsePage(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		dangling := ""
+		if request.GetBool("dangling", false) {
+			dangling = "true"
+		}
+		query := filters(map[string]string{"reference": request.GetString("reference", ""), "dangling": dangling})
+		var objects []imageSummary
+		if err := p.api.Do(ctx, http.MethodGet, "/images/json", query, nil, &objects); err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		images := make([]Image, 0, len(objects))
+		for _, object := range objects {
+			images = append(images, newImage(object))
+		}
+
+		return providerkit.Result(pagination.Slice(cursors, req, images), nil)
+	})
+}
+
+// PullResult reports a pulled image.
+type PullResult struct {
+	Image  string `json:"image"`
+	Digest string `json:"digest,omitempty"`
+	Status string `json:"status"`
+}
+
+// pullMessage is a line of the progress stream of a pull.
+type pullMessage struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error"`
+}
+
+func (p *Provider) pullImageTool() *providerkit.Tool {
+	tool := mcp.NewTool("docker_image_pull",
+		mcp.WithDescription("Pulls an image from its registry, like docker pull. Only public images and registries the daemon can reach without credentials are supported."),
+		mcp.WithString("image", mcp.Required(), mcp.Description("Image reference, such as nginx:1.27 or ghcr.io/org/app@sha256:...; the tag defaults to latest")),
+		mcp.WithString("platform", mcp.Description("Platform to pull, such as linux/arm64 (default: the daemon's)")),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		image, err := request.RequireString("image")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		name, tag := splitReference(image)
+		query := url.Values{"fromImage": {name}, "tag": {tag}}
+		if platform := request.GetString("platform", ""); platform != "" {
+			query.Set("platform", platform)
+		}
+
+		var result PullResult
+		_, err = p.pulls.Call(ctx, providerkit.Call{
+			Method: http.MethodPost, Path: "/images/create", Query: query,
+			Stream: func(body io.Reader) (err error) {
+				result, err = pullResult(image, body, contracts.ProgressFromContext(ctx))
+				return err
+			},
+		})
+
+		return providerkit.Result(result, err)
+	})
+}
+
+// pullResult reads the progress stream of a pull as it arrives, reporting
+// each status. The daemon reports errors within the stream after answering
+// 200, so the stream decides the outcome.
+func pullResult(image string, stream io.Reader, progress contracts.ProgressReporter) (PullResult, error) {
+	result := PullResult{Image: image}
+	scanner := bufio.NewScanner(stream)
+	lines := 0
+	for scanner.Scan() {
+		var message pullMessage
+		if err := json.Unmarshal(scanner.Bytes(), &message); err != nil {
+			continue
+		}
+		if message.Error != "" {
+			return PullResult{}, fmt.Errorf("%w: %s", ErrPullFailed, message.Error)
+		}
+		lines++
+		if message.ID != "" {
+			progress.Report(float64(lines), 0, message.ID+": "+message.Status)
+		} else {
+			progress.Report(float64(lines), 0, message.Status)
+		}
+		if digest, ok := strings.CutPrefix(message.Status, "Digest: "); ok {
+			result.Digest = digest
+		}
+		if status, ok := strings.CutPrefix(message.Status, "Status: "); ok {
+			result.Status = status
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return PullResult{}, fmt.Errorf("failed to read pull of %s: %w", image, err)
+	}
+	if result.Status == "" {
+		result.Status = "pulled"
+	}
+
+	return result, nil
+}
+
+// splitReference splits an image reference into the name and the tag or
+// digest the pull API expects. A colon is only a tag separator after the
+// last slash, since registry hosts may have ports.
+func splitReference(reference string) (string, string) {
+	if name, digest, ok := strings.Cut(reference, "@"); ok {
+		return name, digest
+	}
+	if i := strings.LastIndex(reference, ":"); i > strings.LastIndex(reference, "/") {
+		return reference[:i], reference[i+1:]
+	}
+
+	return reference, "latest"
+}
+
+func (p *Provider) removeImageTool() *providerkit.Tool {
+	tool := mcp.NewTool("docker_image_remove",
+		mcp.WithDescription("Removes an image tag, and the image once no tag refers to it, like docker rmi"),
+		mcp.WithString("image", mcp.Required(), mcp.Description("Image reference or ID")),
+		providerkit.WithConfirmParam("the image"),
+		mcp.WithBoolean("force", mcp.Description("Remove the image even if stopped containers use it or it has several tags")),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		image, err := request.RequireString("image")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if refused := providerkit.Confirm(request, "removed", "the image", image); refused != nil {
+			return refused, nil
+		}
+
+		query := url.Values{}
+		if request.GetBool("force", false) {
+			query.Set("force", "true")
+		}
+		var resp []struct {
+			Untagged string `json:"Untagged"`
+			Deleted  string `json:"Deleted"`
+		}
+		if err := p.api.Do(ctx, http.MethodDelete, imagePath(image), query, nil, &resp); err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		result := removeResult{Name: image, Status: "removed", Deleted: []string{}}
+		for _, item := range resp {
+			if item.Untagged != "" {
+				result.Deleted = append(result.Deleted, "untagged "+item.Untagged)
+			}
+			if item.Deleted != "" {
+				result.Deleted = append(result.Deleted, "deleted "+item.Deleted)
+			}
+		}
+
+		return providerkit.Result(result, nil)
+	})
+}
+
+// imagePath returns the API path of an image. References keep their slashes,
+// as the daemon routes on the whole remaining path.
+func imagePath(image string) string {
+	segments := strings.Split(image, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	return "/images/" + strings.Join(segments, "/")
+}
@@ -0,0 +1,153 @@
+package docker
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+)
+
+// Network summarizes a network, like docker network ls.
+type Network struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Driver   string    `json:"driver"`
+	Scope    string    `json:"scope"`
+	Internal bool      `json:"internal,omitempty"`
+	Subnets  []string  `json:"subnets,omitempty"`
+	Created  time.Time `json:"created"`
+
+	// Containers are the attached containers, shown by inspect only.
+	Containers []NetworkContainer `json:"containers,omitempty"`
+}
+
+// NetworkContainer is a container attached to a network.
+type NetworkContainer struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	IPv4 string `json:"ipv4,omitempty"`
+	IPv6 string `json:"ipv6,omitempty"`
+}
+
+// networkObject is a network as the API returns it.
+type networkObject struct {
+	ID       string    `json:"Id"`
+	Name     string    `json:"Name"`
+	Driver   string    `json:"Driver"`
+	Scope    string    `json:"Scope"`
+	Internal bool      `json:"Internal"`
+	Created  time.Time `json:"Created"`
+	IPAM     struct {
+		Config []struct {
+			Subnet  string `json:"Subnet"`
+			Gateway string `json:"Gateway"`
+		} `json:"Config"`
+	} `json:"IPAM"`
+	Containers map[string]struct {
+		Name        string `json:"Name"`
+		IPv4Address string `json:"IPv4Address"`
+		IPv6Address string `json:"IPv6Address"`
+	} `json:"Containers"`
+}
+
+func newNetwork(object networkObject) Network {
+	network := Network{
+		ID:       shortID(object.ID),
+		Name:     object.Name,
+		Driver:   object.Driver,
+		Scope:    object.Scope,
+		Internal: object.Internal,
+		Created:  object.Created,
+	}
+	for _, config := range object.IPAM.Config {
+		if config.Subnet != "" {
+			network.Subnets = append(network.Subnets, config.Subnet)
+		}
+	}
+
+	return network
+}
+
+// newNetworkDetail summarizes a network with its attached containers.
+func newNetworkDetail(object networkObject) Network {
+	network := newNetwork(object)
+	network.Containers = []NetworkContainer{}
+	for id, container := range object.Containers {
+		network.Containers = append(network.Containers, NetworkContainer{
+			ID:   shortID(id),
+			Name: container.Name,
+			IPv4: container.IPv4Address,
+			IPv6: container.IPv6Address,
+		})
+	}
+	slices.SortFunc(network.Containers, func(a, b NetworkContainer) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return network
+}
+
+func (p *Provider) listNetworksTool() *providerkit.Tool {
+	tool := mcp.NewTool("docker_networks_list",
+		mcp.WithDescription("Lists networks with their driver, scope and subnets, like docker network ls"),
+		mcp.WithString("name", mcp.Description("Only networks whose name contains this (optional)")),
+		mcp.WithString("driver", mcp.Description("Only networks with this driver, such as bridge or overlay (optional)")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		cursors, req, err := providerkit.ParsePage(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		query := filters(map[string]string{"name": request.GetString("name", ""), "driver": request.GetString("driver", "")})
+		var objects []networkObject
+		if err := p.api.Do(ctx, http.MethodGet, "/networks", query, nil, &objects); err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		networks := make([]Network, 0, len(objects))
+		for _, object := range objects {
+			networks = append(networks, newNetwork(object))
+		}
+		slices.SortFunc(networks, func(a, b Network) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+
+		return providerkit.Result(pagination.Slice(cursors, req, networks), nil)
+	})
+}
+
+func (p *Provider) inspectNetworkTool() *providerkit.Tool {
+	tool := mcp.NewTool("docker_network_inspect",
+		mcp.WithDescription("Shows a network with the containers attached to it and their addresses"),
+		mcp.WithString("network", mcp.Required(), mcp.Description("Network name or ID")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		network, err := request.RequireString("network")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		var object networkObject
+		err = p.api.Do(ctx, http.MethodGet, "/networks/"+url.PathEscape(network), nil, nil, &object)
+
+		return providerkit.Result(newNetworkDetail(object), err)
+	})
+}
@@ -0,0 +1,233 @@
+package gcp
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/idempotency"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+)
+
+// Disk is a Compute Engine persistent disk.
+//
+//nolint:tagliatelle // JSON field names match the Compute Engine API.
+type Disk struct {
+	ID                string            `json:"id"`
+	Name              string            `json:"name"`
+	Zone              string            `json:"zone"`
+	SizeGB            string            `json:"sizeGb"`
+	Type              string            `json:"type"`
+	Status            string            `json:"status"`
+	SourceImage       string            `json:"sourceImage,omitempty"`
+	SourceSnapshot    string            `json:"sourceSnapshot,omitempty"`
+	Users             []string          `json:"users,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	CreationTimestamp string            `json:"creationTimestamp"`
+}
+
+func (p *Provider) listDisksTool() *providerkit.Tool {
+	tool := mcp.NewTool("gcp_disks_list",
+		append(withFilter(),
+			mcp.WithDescription("Lists the persistent disks of a zone and the instances using them"),
+			withZone(),
+			withProject(),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(true),
+			pagination.WithParams(),
+			format.WithParam(),
+		)...,
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		_, zonePath, err := p.scope(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		return providerkit.Result(list[Disk](ctx, p, request, zonePath+"/disks", filterQuery(request)))
+	})
+}
+
+func (p *Provider) createDiskTool() *providerkit.Tool {
+	tool := mcp.NewTool("gcp_disk_create",
+		mcp.WithDescription("Creates a persistent disk, blank or from an image or snapshot. "+
+			"An idempotency_key is also passed to Compute Engine as the request ID."),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Disk name")),
+		withZone(),
+		withProject(),
+		mcp.WithNumber("size_gb", mcp.Description("Size in GB (required for blank disks)"), mcp.Min(1)),
+		mcp.WithString("type", mcp.Description("Disk type (default pd-balanced)"),
+			mcp.Enum("pd-standard", "pd-balanced", "pd-ssd", "hyperdisk-balanced")),
+		mcp.WithString("source_image", mcp.Description("Image to create the disk from (optional)")),
+		mcp.WithString("source_snapshot", mcp.Description("Snapshot to create the disk from (optional)")),
+		withLabels(),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		providerkit.WithWaitParam(),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, err := request.RequireString("name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		size := request.GetInt("size_gb", 0)
+		image := request.GetString("source_image", "")
+		snapshot := request.GetString("source_snapshot", "")
+		if size <= 0 && image == "" && snapshot == "" {
+			return mcp.NewToolResultError("size_gb is required for a blank disk"), nil
+		}
+		project, zonePath, err := p.scope(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		body := map[string]any{
+			"name": name,
+			"type": "zones/" + lastSegment(zonePath) + "/diskTypes/" + request.GetString("type", "pd-balanced"),
+		}
+		if size > 0 {
+			body["sizeGb"] = size
+		}
+		if image != "" {
+			body["sourceImage"] = image
+		}
+		if snapshot != "" {
+			body["sourceSnapshot"] = snapshot
+		}
+		if labels := labelsFromArgs(request); labels != nil {
+			body["labels"] = labels
+		}
+
+		return p.start(ctx, request, project, providerkit.Call{
+			Method: http.MethodPost,
+			Path:   zonePath + "/disks",
+			Body:   body,
+		}, &Disk{})
+	})
+}
+
+func (p *Provider) deleteDiskTool() *providerkit.Tool {
+	tool := mcp.NewTool("gcp_disk_delete",
+		mcp.WithDescription("Deletes a persistent disk that no instance uses, with its data. This cannot be undone."),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Disk name")),
+		withZone(),
+		withProject(),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		providerkit.WithWaitParam(),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, err := request.RequireString("name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		project, zonePath, err := p.scope(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		return p.start(ctx, request, project, providerkit.Call{
+			Method: http.MethodDelete,
+			Path:   zonePath + "/disks/" + url.PathEscape(name),
+		}, nil)
+	})
+}
+
+func (p *Provider) attachDiskTool() *providerkit.Tool {
+	tool := mcp.NewTool("gcp_disk_attach",
+		mcp.WithDescription("Attaches a persistent disk to an instance in the same zone"),
+		mcp.WithString("instance", mcp.Required(), mcp.Description("Instance name")),
+		mcp.WithString("disk", mcp.Required(), mcp.Description("Disk name")),
+		withZone(),
+		withProject(),
+		mcp.WithString("device_name", mcp.Description("Device name in the guest, under /dev/disk/by-id/google-* (default: the disk name)")),
+		mcp.WithBoolean("read_only", mcp.Description("Attach read-only (default false)")),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		providerkit.WithWaitParam(),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		instance, err := request.RequireString("instance")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		disk, err := request.RequireString("disk")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		project, zonePath, err := p.scope(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		mode := "READ_WRITE"
+		if request.GetBool("read_only", false) {
+			mode = "READ_ONLY"
+		}
+		body := map[string]any{
+			"source":     zonePath[1:] + "/disks/" + disk,
+			"deviceName": request.GetString("device_name", disk),
+			"mode":       mode,
+		}
+
+		return p.start(ctx, request, project, providerkit.Call{
+			Method: http.MethodPost,
+			Path:   zonePath + "/instances/" + url.PathEscape(instance) + "/attachDisk",
+			Body:   body,
+		}, nil)
+	})
+}
+
+func (p *Provider) detachDiskTool() *providerkit.Tool {
+	tool := mcp.NewTool("gcp_disk_detach",
+		mcp.WithDescription("Detaches a disk from an instance. Unmount it first to avoid data loss."),
+		mcp.WithString("instance", mcp.Required(), mcp.Description("Instance name")),
+		mcp.WithString("device_name", mcp.Required(), mcp.Description("Device name of the attached disk, as listed in the instance's disks")),
+		withZone(),
+		withProject(),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		providerkit.WithWaitParam(),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		instance, err := request.RequireString("instance")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		device, err := request.RequireString("device_name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		project, zonePath, err := p.scope(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		return p.start(ctx, request, project, providerkit.Call{
+			Method: http.MethodPost,
+			Path:   zonePath + "/instances/" + url.PathEscape(instance) + "/detachDisk",
+			Query:  url.Values{"deviceName": {device}},
+		}, nil)
+	})
+}
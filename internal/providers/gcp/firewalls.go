@@ -0,0 +1,189 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/idempotency"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+)
+
+// Firewall is a VPC firewall rule.
+//
+//nolint:tagliatelle // JSON field names match the Compute Engine API.
+type Firewall struct {
+	ID                string         `json:"id"`
+	Name              string         `json:"name"`
+	Network           string         `json:"network"`
+	Direction         string         `json:"direction"`
+	Priority          int            `json:"priority"`
+	Allowed           []FirewallPort `json:"allowed,omitempty"`
+	Denied            []FirewallPort `json:"denied,omitempty"`
+	SourceRanges      []string       `json:"sourceRanges,omitempty"`
+	DestinationRanges []string       `json:"destinationRanges,omitempty"`
+	TargetTags        []string       `json:"targetTags,omitempty"`
+	Disabled          bool           `json:"disabled,omitempty"`
+	Description       string         `json:"description,omitempty"`
+	CreationTimestamp string         `json:"creationTimestamp"`
+}
+
+// FirewallPort is a protocol and its ports in a firewall rule.
+//
+//nolint:tagliatelle // JSON field names match the Compute Engine API.
+type FirewallPort struct {
+	Protocol string   `json:"IPProtocol"`
+	Ports    []string `json:"ports,omitempty"`
+}
+
+// parsePorts parses rules such as tcp:22, udp:5000-5100 or icmp, merging
+// ports of the same protocol.
+func parsePorts(rules []string) ([]FirewallPort, error) {
+	ports := []FirewallPort{}
+	index := map[string]int{}
+	for _, rule := range rules {
+		protocol, portRange, hasPorts := strings.Cut(strings.ToLower(strings.TrimSpace(rule)), ":")
+		if protocol == "" || hasPorts && portRange == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidRule, rule)
+		}
+
+		i, ok := index[protocol]
+		if !ok {
+			i = len(ports)
+			index[protocol] = i
+			ports = append(ports, FirewallPort{Protocol: protocol})
+		}
+		if hasPorts {
+			ports[i].Ports = append(ports[i].Ports, portRange)
+		}
+	}
+
+	return ports, nil
+}
+
+func (p *Provider) listFirewallsTool() *providerkit.Tool {
+	tool := mcp.NewTool("gcp_firewalls_list",
+		mcp.WithDescription("Lists the VPC firewall rules of a project"),
+		withProject(),
+		mcp.WithString("filter", mcp.Description(`Compute Engine filter expression, such as direction = "INGRESS" (optional)`)),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		project, err := p.project(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		return providerkit.Result(list[Firewall](ctx, p, request, "/projects/"+project+"/global/firewalls", filterQuery(request)))
+	})
+}
+
+func (p *Provider) createFirewallTool() *providerkit.Tool {
+	tool := mcp.NewTool("gcp_firewall_create",
+		mcp.WithDescription("Creates a VPC firewall rule allowing or denying traffic. "+
+			"Ingress rules apply to instances with the target tags, or to every instance in the network without them."),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Rule name")),
+		mcp.WithArray("rules", mcp.Required(), mcp.Items(stringItems),
+			mcp.Description("Protocols and ports, such as tcp:22, tcp:8000-8080, udp:53 or icmp")),
+		withProject(),
+		mcp.WithString("action", mcp.Description("Allow or deny matching traffic (default allow)"), mcp.Enum("allow", "deny")),
+		mcp.WithString("direction", mcp.Description("Traffic direction (default INGRESS)"), mcp.Enum("INGRESS", "EGRESS")),
+		mcp.WithString("network", mcp.Description("Network (default global/networks/default)")),
+		mcp.WithArray("ranges", mcp.Items(stringItems),
+			mcp.Description("Source ranges of ingress rules or destination ranges of egress rules (default 0.0.0.0/0)")),
+		mcp.WithArray("target_tags", mcp.Items(stringItems), mcp.Description("Network tags of the instances the rule applies to (optional)")),
+		mcp.WithNumber("priority", mcp.Description("Priority, lower wins (default 1000)"), mcp.Min(0), mcp.Max(65535)),
+		mcp.WithString("description", mcp.Description("Rule description (optional)")),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		providerkit.WithWaitParam(),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, err := request.RequireString("name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		rules, err := request.RequireStringSlice("rules")
+		if err != nil || len(rules) == 0 {
+			return mcp.NewToolResultError("rules must list at least one protocol"), nil
+		}
+		ports, err := parsePorts(rules)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		project, err := p.project(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		firewall := Firewall{
+			Name:        name,
+			Network:     request.GetString("network", "global/networks/default"),
+			Direction:   request.GetString("direction", "INGRESS"),
+			Priority:    request.GetInt("priority", 1000),
+			TargetTags:  request.GetStringSlice("target_tags", nil),
+			Description: request.GetString("description", ""),
+		}
+		if request.GetString("action", "allow") == "deny" {
+			firewall.Denied = ports
+		} else {
+			firewall.Allowed = ports
+		}
+		ranges := request.GetStringSlice("ranges", []string{"0.0.0.0/0"})
+		if firewall.Direction == "EGRESS" {
+			firewall.DestinationRanges = ranges
+		} else {
+			firewall.SourceRanges = ranges
+		}
+
+		return p.start(ctx, request, project, providerkit.Call{
+			Method: http.MethodPost,
+			Path:   "/projects/" + project + "/global/firewalls",
+			Body:   firewall,
+		}, &Firewall{})
+	})
+}
+
+func (p *Provider) deleteFirewallTool() *providerkit.Tool {
+	tool := mcp.NewTool("gcp_firewall_delete",
+		mcp.WithDescription("Deletes a VPC firewall rule"),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Rule name")),
+		withProject(),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		providerkit.WithWaitParam(),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, err := request.RequireString("name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		project, err := p.project(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		return p.start(ctx, request, project, providerkit.Call{
+			Method: http.MethodDelete,
+			Path:   "/projects/" + project + "/global/firewalls/" + url.PathEscape(name),
+		}, nil)
+	})
+}
@@ -0,0 +1,401 @@
+// Package gcp implements the Google Cloud Compute Engine provider. Calls are
+// authorized with OAuth tokens obtained by signing JWTs with a service-account
+// key.
+package gcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/gcpauth"
+	"github.com/chadit/CloudMCP/internal/idempotency"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	// Name is the provider name used in settings and accounts.
+	Name = "gcp"
+
+	// DefaultAPIURL is the Compute Engine v1 endpoint, overridden by the
+	// api_url setting.
+	DefaultAPIURL = "https://compute.googleapis.com/compute/v1"
+
+	// KeyFileEnv names the key file used when neither the account nor the
+	// settings name a credential, as for the gcloud tools.
+	KeyFileEnv = "GOOGLE_APPLICATION_CREDENTIALS"
+
+	// maxPageSize is the largest maxResults the API accepts.
+	maxPageSize = 500
+)
+
+// Static errors for err113 compliance.
+var (
+	ErrNoProject   = errors.New("no project: pass project, set CLOUD_MCP_GCP_PROJECT, or use a key with a project_id")
+	ErrNoZone      = errors.New("no zone: pass zone, or set the account's region or CLOUD_MCP_GCP_ZONE")
+	ErrInvalidRule = errors.New("invalid rule: use protocol or protocol:ports, such as tcp:22 or tcp:8000-8080")
+)
+
+// Provider is the Google Cloud provider.
+type Provider struct {
+	cfg          contracts.ProviderConfig
+	api          *providerkit.API
+	http         *http.Client
+	pollInterval time.Duration
+
+	mu      sync.Mutex
+	sources map[string]*gcpauth.TokenSource
+}
+
+// New creates an uninitialized Google Cloud provider.
+func New() contracts.Provider {
+	return &Provider{}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return Name
+}
+
+// Initialize configures the API client. Keys are resolved per call, so each
+// account can use its own service account.
+func (p *Provider) Initialize(_ context.Context, cfg contracts.ProviderConfig) error {
+	p.http = cfg.HTTPClient
+	if p.http == nil {
+		p.http = http.DefaultClient
+	}
+
+	p.cfg = cfg
+	p.pollInterval = providerkit.PollInterval(cfg)
+	p.sources = make(map[string]*gcpauth.TokenSource)
+	p.api = &providerkit.API{
+		Provider:     Name,
+		BaseURL:      strings.TrimRight(cfg.Setting("api_url", DefaultAPIURL), "/"),
+		HTTP:         p.http,
+		Authorize:    p.authorize,
+		ErrorMessage: errorMessage,
+	}
+
+	return nil
+}
+
+// Tools returns the Google Cloud tools.
+func (p *Provider) Tools() []contracts.Tool {
+	return []contracts.Tool{
+		p.listInstancesTool(),
+		p.getInstanceTool(),
+		p.createInstanceTool(),
+		p.deleteInstanceTool(),
+		p.instanceActionTool(),
+		p.listZonesTool(),
+		p.listMachineTypesTool(),
+		p.listDisksTool(),
+		p.createDiskTool(),
+		p.deleteDiskTool(),
+		p.attachDiskTool(),
+		p.detachDiskTool(),
+		p.listFirewallsTool(),
+		p.createFirewallTool(),
+		p.deleteFirewallTool(),
+		p.getOperationTool(),
+		p.waitOperationTool(),
+	}
+}
+
+// HealthCheck verifies the default account's key by reading the project.
+func (p *Provider) HealthCheck(ctx context.Context) error {
+	ctx = providerkit.DefaultAccountContext(ctx, p.cfg)
+	project, err := p.project(ctx, mcp.CallToolRequest{})
+	if err != nil {
+		return err
+	}
+
+	return p.api.Do(ctx, http.MethodGet, "/projects/"+project, url.Values{"fields": {"name"}}, nil, nil)
+}
+
+// Shutdown has nothing to release.
+func (p *Provider) Shutdown(context.Context) error {
+	return nil
+}
+
+// key returns the service-account key for a call: the account's credential,
+// the credential_ref setting, or the key file named by the key_file setting
+// or GOOGLE_APPLICATION_CREDENTIALS.
+func (p *Provider) key(ctx context.Context) (*gcpauth.ServiceAccountKey, error) {
+	ref := p.cfg.Setting("credential_ref", "")
+	if account, ok := contracts.AccountFromContext(ctx); ok && account.Credential != "" {
+		ref = account.Credential
+	}
+
+	var secret contracts.Secret
+	switch {
+	case ref != "":
+		if p.cfg.Secrets == nil {
+			return nil, providerkit.ErrNoSecrets
+		}
+		resolved, err := p.cfg.Secrets.Resolve(ctx, ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve credential %q: %w", ref, err)
+		}
+		secret = resolved
+	default:
+		path := p.cfg.Setting("key_file", os.Getenv(KeyFileEnv))
+		if path == "" {
+			return nil, fmt.Errorf("%w: set an account credential or %s", providerkit.ErrNoCredential, KeyFileEnv)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key file: %w", err)
+		}
+		secret = contracts.Secret(data)
+	}
+
+	return gcpauth.ParseKey(secret)
+}
+
+// tokenSource returns the cached token source of a key, so tokens are reused
+// across calls until they expire.
+func (p *Provider) tokenSource(key *gcpauth.ServiceAccountKey) *gcpauth.TokenSource {
+	id := key.ClientEmail + "/" + key.PrivateKeyID
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	source, ok := p.sources[id]
+	if !ok {
+		source = &gcpauth.TokenSource{Key: key, TokenURL: p.cfg.Setting("token_url", ""), HTTP: p.http}
+		p.sources[id] = source
+	}
+
+	return source
+}
+
+// authorize adds the access token of the call's service account.
+func (p *Provider) authorize(ctx context.Context, req *http.Request) error {
+	key, err := p.key(ctx)
+	if err != nil {
+		return fmt.Errorf("gcp: %w", err)
+	}
+	token, err := p.tokenSource(key).Token(ctx)
+	if err != nil {
+		return fmt.Errorf("gcp: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	return nil
+}
+
+// errorMessage extracts the reason and message of a Google API error response.
+func errorMessage(body []byte) string {
+	var decoded struct {
+		Error struct {
+			Message string `json:"message"`
+			Errors  []struct {
+				Reason string `json:"reason"`
+			} `json:"errors"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &decoded); err != nil || decoded.Error.Message == "" {
+		return ""
+	}
+	if len(decoded.Error.Errors) > 0 && decoded.Error.Errors[0].Reason != "" {
+		return decoded.Error.Errors[0].Reason + ": " + decoded.Error.Message
+	}
+
+	return decoded.Error.Message
+}
+
+// project returns the project of a call: the project argument, the project
+// setting, or the project of the service account's key.
+func (p *Provider) project(ctx context.Context, request mcp.CallToolRequest) (string, error) {
+	if project := request.GetString("project", p.cfg.Setting("project", "")); project != "" {
+		return project, nil
+	}
+
+	key, err := p.key(ctx)
+	if err != nil {
+		return "", fmt.Errorf("gcp: %w", err)
+	}
+	if key.ProjectID == "" {
+		return "", ErrNoProject
+	}
+
+	return key.ProjectID, nil
+}
+
+// zone returns the zone of a call: the zone argument, the account's region,
+// or the zone setting.
+func (p *Provider) zone(ctx context.Context, request mcp.CallToolRequest) string {
+	if zone := request.GetString("zone", ""); zone != "" {
+		return zone
+	}
+	if account, ok := contracts.AccountFromContext(ctx); ok && account.Region != "" {
+		return account.Region
+	}
+
+	return p.cfg.Setting("zone", "")
+}
+
+// scope resolves the project and zone of a zonal call and returns the zone's
+// path.
+func (p *Provider) scope(ctx context.Context, request mcp.CallToolRequest) (string, string, error) {
+	project, err := p.project(ctx, request)
+	if err != nil {
+		return "", "", err
+	}
+	zone := p.zone(ctx, request)
+	if zone == "" {
+		return "", "", ErrNoZone
+	}
+
+	return project, "/projects/" + project + "/zones/" + zone, nil
+}
+
+// withProject adds the optional project argument.
+func withProject() mcp.ToolOption {
+	return mcp.WithString("project", mcp.Description("Project ID (default: the service account's project)"))
+}
+
+// withZone adds the optional zone argument.
+func withZone() mcp.ToolOption {
+	return mcp.WithString("zone", mcp.Description("Zone, such as us-central1-a (default: the account's region)"))
+}
+
+// listResponse is a page of a Compute Engine collection.
+//
+//nolint:tagliatelle // JSON field names match the Compute Engine API.
+type listResponse[T any] struct {
+	Items         []T    `json:"items"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+// list fetches one page of a Compute Engine collection.
+func list[T any](ctx context.Context, p *Provider, request mcp.CallToolRequest, path string, query url.Values) (pagination.Page[T], error) {
+	var resp listResponse[T]
+	page, err := fetchPage(ctx, p, request, path, query, &resp, func() ([]T, string) { return resp.Items, resp.NextPageToken })
+
+	return page, err
+}
+
+// fetchPage fetches one page into resp, from which items extracts the page's
+// items and next page token.
+func fetchPage[T any](ctx context.Context, p *Provider, request mcp.CallToolRequest, path string, query url.Values,
+	resp any, items func() ([]T, string),
+) (pagination.Page[T], error) {
+	cursors, req, err := providerkit.ParsePage(ctx, request)
+	if err != nil {
+		return pagination.Page[T]{}, err
+	}
+
+	token, size := req.TokenPage(1, maxPageSize)
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("maxResults", strconv.Itoa(size))
+	if token != "" {
+		query.Set("pageToken", token)
+	}
+
+	if err := p.api.Do(ctx, http.MethodGet, path, query, nil, resp); err != nil {
+		return pagination.Page[T]{}, err
+	}
+	page, next := items()
+	if page == nil {
+		page = []T{}
+	}
+
+	return pagination.FromToken(cursors, req, size, page, next), nil
+}
+
+// filterQuery returns the query of a list call with the filter argument and
+// label=value label filters.
+func filterQuery(request mcp.CallToolRequest) url.Values {
+	clauses := []string{}
+	if filter := request.GetString("filter", ""); filter != "" {
+		clauses = append(clauses, "("+filter+")")
+	}
+	if label := request.GetString("label", ""); label != "" {
+		if key, value, ok := strings.Cut(label, "="); ok {
+			clauses = append(clauses, fmt.Sprintf("(labels.%s = %q)", key, value))
+		} else {
+			clauses = append(clauses, fmt.Sprintf("(labels.%s:*)", label))
+		}
+	}
+	if len(clauses) == 0 {
+		return url.Values{}
+	}
+
+	return url.Values{"filter": {strings.Join(clauses, " AND ")}}
+}
+
+// withFilter adds the optional filter and label arguments.
+func withFilter() []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithString("filter", mcp.Description(`Compute Engine filter expression, such as status = "RUNNING" (optional)`)),
+		mcp.WithString("label", mcp.Description("Only list resources with this label, as key=value or key (optional)")),
+	}
+}
+
+// withRequestID adds the Compute Engine requestId derived from the call's
+// idempotency key to query, so a retried mutation is also deduplicated by the
+// API. The API expects a UUID, so the key is hashed into one.
+func withRequestID(ctx context.Context, query url.Values) url.Values {
+	key, ok := idempotency.KeyFromContext(ctx)
+	if !ok {
+		return query
+	}
+	if query == nil {
+		query = url.Values{}
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	sum[6] = sum[6]&0x0f | 0x50 // version 5, name-based
+	sum[8] = sum[8]&0x3f | 0x80 // RFC 4122 variant
+	id := hex.EncodeToString(sum[:16])
+
+	query.Set("requestId", id[:8]+"-"+id[8:12]+"-"+id[12:16]+"-"+id[16:20]+"-"+id[20:])
+
+	return query
+}
+
+// labelsFromArgs reads the optional labels object argument.
+func labelsFromArgs(request mcp.CallToolRequest) map[string]string {
+	raw, ok := request.GetArguments()["labels"].(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	labels := make(map[string]string, len(raw))
+	for key, value := range raw {
+		labels[key] = fmt.Sprint(value)
+	}
+
+	return labels
+}
+
+// withLabels adds the optional labels argument.
+func withLabels() mcp.ToolOption {
+	return mcp.WithObject("labels", mcp.Description("Labels to set, as a string map (optional)"))
+}
+
+// lastSegment returns the name at the end of a resource URL.
+func lastSegment(link string) string {
+	return link[strings.LastIndex(link, "/")+1:]
+}
+
+// stringItems is the item schema of string array arguments.
+var stringItems = map[string]any{"type": "string"}
@@ -0,0 +1,415 @@
+package gcp_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chadit/CloudMCP/internal/credentials"
+	"github.com/chadit/CloudMCP/internal/idempotency"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/internal/providerkit/providerkittest"
+	"github.com/chadit/CloudMCP/internal/providers/gcp"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	project     = "demo-project"
+	zone        = "us-central1-a"
+	accessToken = "ya29.test-token"
+)
+
+// demo is the default account, which tool calls run against.
+var demo = contracts.Account{Provider: gcp.Name, Alias: "demo", Credential: "gcp-demo", Region: zone, Default: true}
+
+// fakeGCP stubs the OAuth token endpoint and the Compute Engine API. Each
+// operation advances 50% per poll; operations on the instance "broken" fail.
+type fakeGCP struct {
+	mu        sync.Mutex
+	public    *rsa.PublicKey
+	exchanges int
+	zones     int
+	ops       map[string]*gcp.Operation
+	queries   map[string]string
+	bodies    map[string]map[string]any
+}
+
+func (f *fakeGCP) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path == "/token" {
+		f.token(w, r)
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+accessToken {
+		writeError(w, http.StatusUnauthorized, "authError", "Request had invalid authentication credentials.")
+		return
+	}
+	f.queries[r.Method+" "+r.URL.Path] = r.URL.RawQuery
+	if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+		var body map[string]any
+		_ = json.Unmarshal(data, &body)
+		f.bodies[r.URL.Path] = body
+	}
+
+	base := "/compute/v1/projects/" + project
+	path := strings.TrimPrefix(r.URL.Path, base)
+	switch {
+	case r.Method == http.MethodGet && path == "":
+		providerkittest.WriteJSON(w, http.StatusOK, map[string]string{"name": project})
+	case r.Method == http.MethodGet && path == "/zones":
+		start, _ := strconv.Atoi(r.URL.Query().Get("pageToken"))
+		size, _ := strconv.Atoi(r.URL.Query().Get("maxResults"))
+		end := min(start+size, f.zones)
+		zones := []gcp.Zone{}
+		for i := start; i < end; i++ {
+			zones = append(zones, gcp.Zone{Name: fmt.Sprintf("zone-%d", i), Status: "UP"})
+		}
+		resp := map[string]any{"items": zones}
+		if end < f.zones {
+			resp["nextPageToken"] = strconv.Itoa(end)
+		}
+		providerkittest.WriteJSON(w, http.StatusOK, resp)
+	case r.Method == http.MethodGet && path == "/aggregated/instances":
+		providerkittest.WriteJSON(w, http.StatusOK, map[string]any{"items": map[string]any{
+			"zones/us-east1-b":   map[string]any{"instances": []gcp.Instance{{Name: "east"}}},
+			"zones/asia-east1-a": map[string]any{"warning": map[string]string{"code": "NO_RESULTS_ON_PAGE"}},
+			"zones/" + zone:      map[string]any{"instances": []gcp.Instance{{Name: "central"}}},
+		}})
+	case r.Method == http.MethodGet && path == "/zones/"+zone+"/instances/web":
+		providerkittest.WriteJSON(w, http.StatusOK, gcp.Instance{ID: "123", Name: "web", Status: "RUNNING"})
+	case r.Method == http.MethodPost && path == "/zones/"+zone+"/instances":
+		providerkittest.WriteJSON(w, http.StatusOK, f.start("insert", base+"/zones/"+zone+"/instances/"+fmt.Sprint(f.bodies[r.URL.Path]["name"]), true))
+	case r.Method == http.MethodPost && strings.HasPrefix(path, "/zones/"+zone+"/instances/"):
+		target := strings.Split(path, "/")[4]
+		providerkittest.WriteJSON(w, http.StatusOK, f.start(strings.Split(path, "/")[5], base+"/zones/"+zone+"/instances/"+target, true))
+	case r.Method == http.MethodPost && path == "/global/firewalls":
+		providerkittest.WriteJSON(w, http.StatusOK, f.start("insert", base+"/global/firewalls/allow-ssh", false))
+	case r.Method == http.MethodGet && path == "/global/firewalls/allow-ssh":
+		providerkittest.WriteJSON(w, http.StatusOK, gcp.Firewall{Name: "allow-ssh", Direction: "INGRESS"})
+	case r.Method == http.MethodGet && strings.Contains(path, "/operations/"):
+		f.poll(w, path[strings.LastIndex(path, "/")+1:])
+	default:
+		writeError(w, http.StatusNotFound, "notFound", "The resource 'projects/"+project+path+"' was not found")
+	}
+}
+
+// token checks the JWT assertion and issues the access token.
+func (f *fakeGCP) token(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	parts := strings.Split(r.PostForm.Get("assertion"), ".")
+	if r.PostForm.Get("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" || len(parts) != 3 {
+		providerkittest.WriteJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(f.public, crypto.SHA256, digest[:], signature); err != nil {
+		providerkittest.WriteJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "Invalid JWT Signature."})
+		return
+	}
+
+	f.exchanges++
+	providerkittest.WriteJSON(w, http.StatusOK, map[string]any{"access_token": accessToken, "expires_in": 3599, "token_type": "Bearer"})
+}
+
+// start creates a running operation on target.
+func (f *fakeGCP) start(kind, target string, zonal bool) *gcp.Operation {
+	op := &gcp.Operation{Name: fmt.Sprintf("operation-%d", len(f.ops)+1), OperationType: kind, TargetLink: "https://compute.googleapis.com" + target, Status: "RUNNING"}
+	if zonal {
+		op.Zone = "https://compute.googleapis.com/compute/v1/projects/" + project + "/zones/" + zone
+	}
+	f.ops[op.Name] = op
+	copied := *op
+	return &copied
+}
+
+// poll advances an operation by one step.
+func (f *fakeGCP) poll(w http.ResponseWriter, name string) {
+	op, ok := f.ops[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "notFound", "operation not found")
+		return
+	}
+	op.Progress = min(op.Progress+50, 100)
+	if op.Progress == 100 {
+		op.Status = "DONE"
+		if strings.HasSuffix(op.TargetLink, "/broken") {
+			op.Error = &gcp.OperationError{Errors: []struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			}{{Code: "RESOURCE_NOT_READY", Message: "The instance is being repaired"}}}
+		}
+	}
+	providerkittest.WriteJSON(w, http.StatusOK, op)
+}
+
+func writeError(w http.ResponseWriter, status int, reason, message string) {
+	providerkittest.WriteJSON(w, status, map[string]any{"error": map[string]any{
+		"code": status, "message": message, "errors": []map[string]string{{"reason": reason, "message": message}},
+	}})
+}
+
+// serviceAccountKey returns a service-account JSON key and its public key.
+func serviceAccountKey(t *testing.T) (string, *rsa.PublicKey) {
+	t.Helper()
+
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     project,
+		"private_key_id": "key-1",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"client_email":   "deployer@" + project + ".iam.gserviceaccount.com",
+		"token_uri":      "https://oauth2.googleapis.com/token",
+	})
+	require.NoError(t, err)
+
+	return string(data), &private.PublicKey
+}
+
+// setup starts the stubs and returns the provider and its tools by name.
+func setup(t *testing.T, zones int) (*fakeGCP, contracts.Provider, map[string]contracts.Tool) {
+	t.Helper()
+
+	key, public := serviceAccountKey(t)
+
+	return setupWithSecrets(t, zones, public, providerkittest.Secrets{"gcp-demo": key})
+}
+
+// setupWithSecrets starts the stubs, trusting public for token exchanges, and
+// returns a provider that resolves the demo account's key through secrets.
+func setupWithSecrets(t *testing.T, zones int, public *rsa.PublicKey, secrets contracts.SecretResolver) (*fakeGCP, contracts.Provider, map[string]contracts.Tool) {
+	t.Helper()
+
+	api := &fakeGCP{public: public, zones: zones, ops: map[string]*gcp.Operation{}, queries: map[string]string{}, bodies: map[string]map[string]any{}}
+	srv := providerkittest.Serve(t, api)
+
+	provider := gcp.New()
+	tools := providerkittest.Setup(t, provider, contracts.ProviderConfig{
+		Settings: map[string]string{
+			"api_url":       srv.URL + "/compute/v1",
+			"token_url":     srv.URL + "/token",
+			"poll_interval": "1ms",
+		},
+		HTTPClient: srv.Client(),
+		Secrets:    secrets,
+		Accounts:   []contracts.Account{demo},
+	})
+
+	return api, provider, tools
+}
+
+// operationResult is the shape of results of tools that start operations.
+type operationResult struct {
+	Operation gcp.Operation   `json:"operation"`
+	Resource  json.RawMessage `json:"resource"`
+}
+
+func TestHealthCheck_ExchangesTokenOnce(t *testing.T) {
+	t.Parallel()
+
+	api, provider, tools := setup(t, 3)
+	require.NoError(t, provider.HealthCheck(t.Context()))
+	text, isError := providerkittest.Call(providerkittest.AccountContext(t, demo), t, tools["gcp_zones_list"], map[string]any{})
+	require.False(t, isError, text)
+	require.Equal(t, 1, api.exchanges, "the access token is reused across calls")
+	require.Equal(t, "fields=name", api.queries["GET /compute/v1/projects/"+project])
+}
+
+func TestHealthCheck_KeyFromCredentialStore(t *testing.T) {
+	t.Parallel()
+
+	// The key as downloaded from the console: indented over many lines with a
+	// trailing newline, stored the way `cloud-mcp creds add` stores it.
+	compact, public := serviceAccountKey(t)
+	var downloaded bytes.Buffer
+	require.NoError(t, json.Indent(&downloaded, []byte(compact), "", "  "))
+	downloaded.WriteString("\n")
+
+	opts := credentials.Options{Path: filepath.Join(t.TempDir(), "credentials.enc"), Passphrase: "correct horse"}
+	store, err := credentials.Open(opts)
+	require.NoError(t, err)
+	secret, err := credentials.ReadSecret(&downloaded)
+	require.NoError(t, err)
+	require.NoError(t, store.Put("gcp-demo", secret))
+
+	api, provider, tools := setupWithSecrets(t, 1, public, credentials.NewResolver(opts))
+	require.NoError(t, provider.HealthCheck(t.Context()))
+	text, isError := providerkittest.Call(providerkittest.AccountContext(t, demo), t, tools["gcp_zones_list"], map[string]any{})
+	require.False(t, isError, text)
+	require.Equal(t, 1, api.exchanges, "the stored key signs the token exchange")
+}
+
+func TestInstanceCreate_WaitsForOperation(t *testing.T) {
+	t.Parallel()
+
+	api, _, tools := setup(t, 0)
+	progress := &providerkittest.Progress{}
+	ctx := contracts.WithProgressReporter(idempotency.WithKey(providerkittest.AccountContext(t, demo), "create-web"), progress)
+
+	text, isError := providerkittest.Call(ctx, t, tools["gcp_instance_create"], map[string]any{
+		"name": "web", "machine_type": "e2-small", "tags": []any{"http"}, "labels": map[string]any{"env": "prod"},
+	})
+	require.False(t, isError, text)
+
+	var result operationResult
+	require.NoError(t, json.Unmarshal([]byte(text), &result))
+	require.Equal(t, "DONE", result.Operation.Status)
+	var instance gcp.Instance
+	require.NoError(t, json.Unmarshal(result.Resource, &instance))
+	require.Equal(t, "RUNNING", instance.Status, "the instance is read once the operation is done")
+	require.Equal(t, []float64{50, 100}, progress.Values())
+
+	body := api.bodies["/compute/v1/projects/"+project+"/zones/"+zone+"/instances"]
+	require.Equal(t, "zones/"+zone+"/machineTypes/e2-small", body["machineType"])
+	require.Equal(t, map[string]any{"items": []any{"http"}}, body["tags"])
+	query := api.queries["POST /compute/v1/projects/"+project+"/zones/"+zone+"/instances"]
+	require.Regexp(t, regexp.MustCompile(`^requestId=[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), query)
+}
+
+func TestInstanceCreate_UnreadableInstanceKeepsOperation(t *testing.T) {
+	t.Parallel()
+
+	_, _, tools := setup(t, 0)
+	ctx := providerkittest.AccountContext(t, demo)
+
+	text, isError := providerkittest.Call(ctx, t, tools["gcp_instance_create"], map[string]any{"name": "ghost", "machine_type": "e2-small"})
+	require.False(t, isError, "the instance exists, so its operation is returned rather than an error")
+	var created struct {
+		Resource operationResult `json:"resource"`
+		Error    string          `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(text), &created))
+	require.True(t, strings.HasSuffix(created.Resource.Operation.TargetLink, "/instances/ghost"))
+	require.Contains(t, created.Error, providerkit.ErrNotFound.Error())
+
+	text, isError = providerkittest.Call(ctx, t, tools["gcp_instance_create"], map[string]any{"name": "broken", "machine_type": "e2-small"})
+	require.True(t, isError, "a failed insert creates nothing")
+	require.Contains(t, text, "RESOURCE_NOT_READY")
+}
+
+func TestInstanceAction_WaitLaterAndFailure(t *testing.T) {
+	t.Parallel()
+
+	_, _, tools := setup(t, 0)
+	ctx := providerkittest.AccountContext(t, demo)
+
+	text, isError := providerkittest.Call(ctx, t, tools["gcp_instance_action"], map[string]any{"name": "web", "action": "stop", "wait": false})
+	require.False(t, isError, text)
+	var started operationResult
+	require.NoError(t, json.Unmarshal([]byte(text), &started))
+	require.Equal(t, "RUNNING", started.Operation.Status)
+
+	text, isError = providerkittest.Call(ctx, t, tools["gcp_operation_wait"], map[string]any{"operation": started.Operation.Name, "zone": zone})
+	require.False(t, isError, text)
+	require.Contains(t, text, `"status": "DONE"`)
+
+	text, isError = providerkittest.Call(ctx, t, tools["gcp_instance_action"], map[string]any{"name": "broken", "action": "reset"})
+	require.True(t, isError)
+	require.Contains(t, text, providerkit.ErrActionFailed.Error())
+	require.Contains(t, text, "RESOURCE_NOT_READY: The instance is being repaired")
+
+	text, isError = providerkittest.Call(ctx, t, tools["gcp_instance_action"], map[string]any{"name": "web", "action": "explode"})
+	require.True(t, isError)
+	require.Contains(t, text, "action must be one of")
+}
+
+func TestFirewallCreate_ParsesRules(t *testing.T) {
+	t.Parallel()
+
+	api, _, tools := setup(t, 0)
+
+	text, isError := providerkittest.Call(providerkittest.AccountContext(t, demo), t, tools["gcp_firewall_create"], map[string]any{
+		"name": "allow-ssh", "rules": []any{"tcp:22", "tcp:8000-8080", "icmp"}, "target_tags": []any{"bastion"},
+	})
+	require.False(t, isError, text)
+	require.Contains(t, text, `"name": "allow-ssh"`)
+
+	body := api.bodies["/compute/v1/projects/"+project+"/global/firewalls"]
+	require.Equal(t, []any{
+		map[string]any{"IPProtocol": "tcp", "ports": []any{"22", "8000-8080"}},
+		map[string]any{"IPProtocol": "icmp"},
+	}, body["allowed"])
+	require.Equal(t, []any{"0.0.0.0/0"}, body["sourceRanges"])
+
+	text, isError = providerkittest.Call(providerkittest.AccountContext(t, demo), t, tools["gcp_firewall_create"], map[string]any{"name": "bad", "rules": []any{"tcp:"}})
+	require.True(t, isError)
+	require.Contains(t, text, gcp.ErrInvalidRule.Error())
+}
+
+func TestLists_PaginateAndAggregate(t *testing.T) {
+	t.Parallel()
+
+	_, _, tools := setup(t, 5)
+	ctx := providerkittest.AccountContext(t, demo)
+
+	var names []string
+	params := map[string]any{"limit": float64(2)}
+	for {
+		text, isError := providerkittest.Call(ctx, t, tools["gcp_zones_list"], params)
+		require.False(t, isError, text)
+
+		var page pagination.Page[gcp.Zone]
+		require.NoError(t, json.Unmarshal([]byte(text), &page))
+		for _, z := range page.Items {
+			names = append(names, z.Name)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		params["cursor"] = page.NextCursor
+	}
+	require.Equal(t, []string{"zone-0", "zone-1", "zone-2", "zone-3", "zone-4"}, names)
+
+	text, isError := providerkittest.Call(ctx, t, tools["gcp_instances_list"], map[string]any{"zone": "-"})
+	require.False(t, isError, text)
+	var all pagination.Page[gcp.Instance]
+	require.NoError(t, json.Unmarshal([]byte(text), &all))
+	require.Len(t, all.Items, 2)
+	require.Equal(t, "east", all.Items[1].Name, "zones are flattened in order")
+}
+
+func TestErrors_Mapped(t *testing.T) {
+	t.Parallel()
+
+	_, _, tools := setup(t, 0)
+
+	text, isError := providerkittest.Call(providerkittest.AccountContext(t, demo), t, tools["gcp_instance_get"], map[string]any{"name": "ghost"})
+	require.True(t, isError)
+	require.Contains(t, text, providerkit.ErrNotFound.Error())
+	require.Contains(t, text, "notFound: The resource")
+
+	noZone := contracts.WithAccount(providerkittest.AccountContext(t, demo), contracts.Account{Provider: gcp.Name, Alias: "demo", Credential: "gcp-demo"})
+	text, isError = providerkittest.Call(noZone, t, tools["gcp_instance_get"], map[string]any{"name": "web"})
+	require.True(t, isError)
+	require.Contains(t, text, gcp.ErrNoZone.Error())
+
+	badKey := contracts.WithAccount(providerkittest.AccountContext(t, demo), contracts.Account{Provider: gcp.Name, Alias: "other", Credential: "missing", Region: zone})
+	text, isError = providerkittest.Call(badKey, t, tools["gcp_instance_get"], map[string]any{"name": "web", "project": project})
+	require.True(t, isError)
+	require.Contains(t, text, "missing")
+}
@@ -0,0 +1,355 @@
+package gcp
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/idempotency"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+)
+
+// DefaultImage is the boot image of new instances when none is given.
+const DefaultImage = "projects/debian-cloud/global/images/family/debian-12"
+
+// Instance is a Compute Engine instance.
+//
+//nolint:tagliatelle // JSON field names match the Compute Engine API.
+type Instance struct {
+	ID                string             `json:"id"`
+	Name              string             `json:"name"`
+	Zone              string             `json:"zone"`
+	MachineType       string             `json:"machineType"`
+	Status            string             `json:"status"`
+	CreationTimestamp string             `json:"creationTimestamp"`
+	NetworkInterfaces []NetworkInterface `json:"networkInterfaces,omitempty"`
+	Disks             []AttachedDisk     `json:"disks,omitempty"`
+	Labels            map[string]string  `json:"labels,omitempty"`
+	Tags              *struct {
+		Items []string `json:"items,omitempty"`
+	} `json:"tags,omitempty"`
+}
+
+// NetworkInterface is a network interface of an instance.
+//
+//nolint:tagliatelle // JSON field names match the Compute Engine API.
+type NetworkInterface struct {
+	Network       string `json:"network"`
+	Subnetwork    string `json:"subnetwork,omitempty"`
+	NetworkIP     string `json:"networkIP,omitempty"`
+	AccessConfigs []struct {
+		Name  string `json:"name,omitempty"`
+		NatIP string `json:"natIP,omitempty"`
+	} `json:"accessConfigs,omitempty"`
+}
+
+// AttachedDisk is a disk attached to an instance.
+//
+//nolint:tagliatelle // JSON field names match the Compute Engine API.
+type AttachedDisk struct {
+	Source     string `json:"source"`
+	DeviceName string `json:"deviceName"`
+	Boot       bool   `json:"boot,omitempty"`
+	Mode       string `json:"mode,omitempty"`
+	AutoDelete bool   `json:"autoDelete,omitempty"`
+}
+
+// Zone is a Compute Engine zone.
+//
+//nolint:tagliatelle // JSON field names match the Compute Engine API.
+type Zone struct {
+	Name        string `json:"name"`
+	Region      string `json:"region"`
+	Status      string `json:"status"`
+	Description string `json:"description,omitempty"`
+}
+
+// MachineType is a Compute Engine machine type.
+//
+//nolint:tagliatelle // JSON field names match the Compute Engine API.
+type MachineType struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	GuestCPUs   int    `json:"guestCpus"`
+	MemoryMB    int    `json:"memoryMb"`
+	IsShared    bool   `json:"isSharedCpu,omitempty"`
+}
+
+func (p *Provider) listInstancesTool() *providerkit.Tool {
+	tool := mcp.NewTool("gcp_instances_list",
+		append(withFilter(),
+			mcp.WithDescription("Lists Compute Engine instances in a zone, or in every zone with zone \"-\""),
+			withZone(),
+			withProject(),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(true),
+			pagination.WithParams(),
+			format.WithParam(),
+		)...,
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		project, err := p.project(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+		zone := p.zone(ctx, request)
+		if zone == "" || zone == "-" {
+			return providerkit.Result(p.listAllInstances(ctx, request, project))
+		}
+
+		return providerkit.Result(list[Instance](ctx, p, request, "/projects/"+project+"/zones/"+zone+"/instances", filterQuery(request)))
+	})
+}
+
+// listAllInstances fetches one page of the instances of every zone.
+func (p *Provider) listAllInstances(ctx context.Context, request mcp.CallToolRequest, project string) (pagination.Page[Instance], error) {
+	var resp struct {
+		Items map[string]struct {
+			Instances []Instance `json:"instances"`
+		} `json:"items"`
+		NextPageToken string `json:"nextPageToken"`
+	}
+
+	return fetchPage(ctx, p, request, "/projects/"+project+"/aggregated/instances", filterQuery(request), &resp, func() ([]Instance, string) {
+		instances := []Instance{}
+		for _, scope := range slices.Sorted(maps.Keys(resp.Items)) {
+			instances = append(instances, resp.Items[scope].Instances...)
+		}
+
+		return instances, resp.NextPageToken
+	})
+}
+
+func (p *Provider) getInstanceTool() *providerkit.Tool {
+	tool := mcp.NewTool("gcp_instance_get",
+		mcp.WithDescription("Returns a Compute Engine instance"),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Instance name")),
+		withZone(),
+		withProject(),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, err := request.RequireString("name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		_, zonePath, err := p.scope(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		var instance Instance
+		err = p.api.Do(ctx, http.MethodGet, zonePath+"/instances/"+url.PathEscape(name), nil, nil, &instance)
+
+		return providerkit.Result(instance, err)
+	})
+}
+
+func (p *Provider) createInstanceTool() *providerkit.Tool {
+	tool := mcp.NewTool("gcp_instance_create",
+		mcp.WithDescription("Creates a Compute Engine instance with a new boot disk and, by default, an external IP. "+
+			"An idempotency_key is also passed to Compute Engine as the request ID."),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Instance name")),
+		mcp.WithString("machine_type", mcp.Required(), mcp.Description("Machine type, such as e2-medium")),
+		withZone(),
+		withProject(),
+		mcp.WithString("image", mcp.Description("Boot image or image family (default "+DefaultImage+")")),
+		mcp.WithNumber("disk_size_gb", mcp.Description("Boot disk size in GB (default: the image's size)"), mcp.Min(10)),
+		mcp.WithString("disk_type", mcp.Description("Boot disk type (default pd-balanced)"),
+			mcp.Enum("pd-standard", "pd-balanced", "pd-ssd", "hyperdisk-balanced")),
+		mcp.WithString("network", mcp.Description("Network (default global/networks/default)")),
+		mcp.WithString("subnetwork", mcp.Description("Subnetwork, such as regions/us-central1/subnetworks/apps (optional)")),
+		mcp.WithBoolean("external_ip", mcp.Description("Assign an ephemeral external IP (default true)"), mcp.DefaultBool(true)),
+		mcp.WithArray("tags", mcp.Items(stringItems), mcp.Description("Network tags, used by firewall rules (optional)")),
+		withLabels(),
+		mcp.WithString("startup_script", mcp.Description("Script run at every boot (optional)")),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		providerkit.WithWaitParam(),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, err := request.RequireString("name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		machineType, err := request.RequireString("machine_type")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		project, zonePath, err := p.scope(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+		zone := lastSegment(zonePath)
+
+		initialize := map[string]any{
+			"sourceImage": request.GetString("image", DefaultImage),
+			"diskType":    "zones/" + zone + "/diskTypes/" + request.GetString("disk_type", "pd-balanced"),
+		}
+		if size := request.GetInt("disk_size_gb", 0); size > 0 {
+			initialize["diskSizeGb"] = size
+		}
+
+		nic := map[string]any{"network": request.GetString("network", "global/networks/default")}
+		if subnetwork := request.GetString("subnetwork", ""); subnetwork != "" {
+			nic["subnetwork"] = subnetwork
+		}
+		if request.GetBool("external_ip", true) {
+			nic["accessConfigs"] = []map[string]string{{"name": "External NAT", "type": "ONE_TO_ONE_NAT"}}
+		}
+
+		body := map[string]any{
+			"name":              name,
+			"machineType":       "zones/" + zone + "/machineTypes/" + machineType,
+			"disks":             []map[string]any{{"boot": true, "autoDelete": true, "initializeParams": initialize}},
+			"networkInterfaces": []map[string]any{nic},
+		}
+		if tags := request.GetStringSlice("tags", nil); len(tags) > 0 {
+			body["tags"] = map[string]any{"items": tags}
+		}
+		if labels := labelsFromArgs(request); labels != nil {
+			body["labels"] = labels
+		}
+		if script := request.GetString("startup_script", ""); script != "" {
+			body["metadata"] = map[string]any{"items": []map[string]string{{"key": "startup-script", "value": script}}}
+		}
+
+		return p.start(ctx, request, project, providerkit.Call{
+			Method: http.MethodPost,
+			Path:   zonePath + "/instances",
+			Body:   body,
+		}, &Instance{})
+	})
+}
+
+func (p *Provider) deleteInstanceTool() *providerkit.Tool {
+	tool := mcp.NewTool("gcp_instance_delete",
+		mcp.WithDescription("Deletes a Compute Engine instance and its auto-delete disks. This cannot be undone."),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Instance name")),
+		withZone(),
+		withProject(),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		providerkit.WithWaitParam(),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, err := request.RequireString("name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		project, zonePath, err := p.scope(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		return p.start(ctx, request, project, providerkit.Call{
+			Method: http.MethodDelete,
+			Path:   zonePath + "/instances/" + url.PathEscape(name),
+		}, nil)
+	})
+}
+
+// instanceActions are the actions of gcp_instance_action.
+var instanceActions = []string{"start", "stop", "reset", "suspend", "resume"}
+
+func (p *Provider) instanceActionTool() *providerkit.Tool {
+	tool := mcp.NewTool("gcp_instance_action",
+		mcp.WithDescription("Starts, stops, resets (hard reboots), suspends or resumes a Compute Engine instance"),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Instance name")),
+		mcp.WithString("action", mcp.Required(), mcp.Description("Action to perform"), mcp.Enum(instanceActions...)),
+		withZone(),
+		withProject(),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		providerkit.WithWaitParam(),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, err := request.RequireString("name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		action, err := request.RequireString("action")
+		if err != nil || !slices.Contains(instanceActions, action) {
+			return mcp.NewToolResultError("action must be one of " + strings.Join(instanceActions, ", ")), nil
+		}
+		project, zonePath, err := p.scope(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		return p.start(ctx, request, project, providerkit.Call{
+			Method: http.MethodPost,
+			Path:   zonePath + "/instances/" + url.PathEscape(name) + "/" + action,
+		}, nil)
+	})
+}
+
+func (p *Provider) listZonesTool() *providerkit.Tool {
+	tool := mcp.NewTool("gcp_zones_list",
+		mcp.WithDescription("Lists Compute Engine zones"),
+		withProject(),
+		mcp.WithString("region", mcp.Description("Only list zones of this region, such as europe-west1 (optional)")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		project, err := p.project(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+		query := url.Values{}
+		if region := request.GetString("region", ""); region != "" {
+			query.Set("filter", `name = "`+region+`-*"`)
+		}
+
+		return providerkit.Result(list[Zone](ctx, p, request, "/projects/"+project+"/zones", query))
+	})
+}
+
+func (p *Provider) listMachineTypesTool() *providerkit.Tool {
+	tool := mcp.NewTool("gcp_machine_types_list",
+		mcp.WithDescription("Lists the machine types of a zone with their CPUs and memory"),
+		withZone(),
+		withProject(),
+		mcp.WithString("filter", mcp.Description(`Compute Engine filter expression, such as name = "e2-*" (optional)`)),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		_, zonePath, err := p.scope(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		return providerkit.Result(list[MachineType](ctx, p, request, zonePath+"/machineTypes", filterQuery(request)))
+	})
+}
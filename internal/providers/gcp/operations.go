@@ -0,0 +1,215 @@
+package gcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+)
+
+// Operation is a Compute Engine long-running operation.
+//
+//nolint:tagliatelle // JSON field names match the Compute Engine API.
+type Operation struct {
+	Name             string          `json:"name"`
+	OperationType    string          `json:"operationType"`
+	TargetLink       string          `json:"targetLink"`
+	Status           string          `json:"status"`
+	Progress         int             `json:"progress"`
+	Zone             string          `json:"zone,omitempty"`
+	InsertTime       string          `json:"insertTime,omitempty"`
+	EndTime          string          `json:"endTime,omitempty"`
+	Error            *OperationError `json:"error,omitempty"`
+	HTTPErrorMessage string          `json:"httpErrorMessage,omitempty"`
+}
+
+// OperationError lists the errors of a failed operation.
+type OperationError struct {
+	Errors []struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// operationDone is the status of a finished operation.
+const operationDone = "DONE"
+
+// path returns the API path of an operation in project.
+func (o Operation) path(project string) string {
+	if o.Zone != "" {
+		return "/projects/" + project + "/zones/" + lastSegment(o.Zone) + "/operations/" + o.Name
+	}
+
+	return "/projects/" + project + "/global/operations/" + o.Name
+}
+
+// failure describes why a finished operation failed, or is empty.
+func (o Operation) failure() string {
+	if o.Error == nil || len(o.Error.Errors) == 0 {
+		return ""
+	}
+
+	messages := make([]string, len(o.Error.Errors))
+	for i, e := range o.Error.Errors {
+		messages[i] = e.Code + ": " + e.Message
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// waitOperation polls an operation until it is done, reporting its progress.
+func (p *Provider) waitOperation(ctx context.Context, project string, op Operation) (Operation, error) {
+	_, err := providerkit.Wait(ctx, p.pollInterval, func(ctx context.Context) (providerkit.ActionStatus, error) {
+		if op.Status != operationDone {
+			if err := p.api.Do(ctx, http.MethodGet, op.path(project), nil, nil, &op); err != nil {
+				return providerkit.ActionStatus{}, err
+			}
+		}
+
+		status := providerkit.ActionStatus{
+			Done:    op.Status == operationDone,
+			Percent: float64(op.Progress),
+			Message: fmt.Sprintf("%s %s %s", op.OperationType, lastSegment(op.TargetLink), strings.ToLower(op.Status)),
+		}
+		if failure := op.failure(); failure != "" && status.Done {
+			status.Failed = true
+			status.Message += ": " + failure
+		}
+
+		return status, nil
+	})
+
+	return op, err
+}
+
+// operationResult is returned by tools that start operations: the operation,
+// and for creations the new resource once the operation is done.
+type operationResult struct {
+	Operation Operation `json:"operation"`
+	Resource  any       `json:"resource,omitempty"`
+}
+
+// finish waits for a started operation if the call asks to and builds the tool
+// result. When resource is not nil, the operation's target is read into it
+// after a successful wait. A failed insert creates nothing, but when waiting
+// for it or reading its target fails the resource may exist, so the operation
+// naming it is returned with the error.
+func (p *Provider) finish(ctx context.Context, request mcp.CallToolRequest, project string, op Operation, resource any) (*mcp.CallToolResult, error) {
+	if !request.GetBool(providerkit.WaitParam, true) {
+		return providerkit.Result(operationResult{Operation: op}, nil)
+	}
+
+	op, err := p.waitOperation(ctx, project, op)
+	result := operationResult{Operation: op}
+	switch {
+	case resource == nil || errors.Is(err, providerkit.ErrActionFailed):
+		return providerkit.Result(result, err)
+	case err != nil:
+		return providerkit.CreatedResult(result, err)
+	}
+
+	if err := p.api.Do(ctx, http.MethodGet, linkPath(op.TargetLink), nil, nil, resource); err != nil {
+		return providerkit.CreatedResult(result, err)
+	}
+	result.Resource = resource
+
+	return providerkit.Result(result, nil)
+}
+
+// start sends a mutation and finishes the operation it returns.
+func (p *Provider) start(ctx context.Context, request mcp.CallToolRequest, project string, call providerkit.Call, resource any) (*mcp.CallToolResult, error) {
+	var op Operation
+	call.Query = withRequestID(ctx, call.Query)
+	call.Out = &op
+	if _, err := p.api.Call(ctx, call); err != nil {
+		return providerkit.Result(nil, err)
+	}
+
+	return p.finish(ctx, request, project, op, resource)
+}
+
+// linkPath returns the API path of a resource URL such as an operation's
+// targetLink.
+func linkPath(link string) string {
+	if i := strings.Index(link, "/projects/"); i >= 0 {
+		return link[i:]
+	}
+
+	return link
+}
+
+// operationArgs returns the operation described by the operation and zone
+// arguments.
+func operationArgs(request mcp.CallToolRequest) (Operation, error) {
+	name, err := request.RequireString("operation")
+	if err != nil {
+		return Operation{}, err
+	}
+
+	return Operation{Name: name, Zone: request.GetString("zone", "")}, nil
+}
+
+// withOperation adds the arguments naming an operation.
+func withOperation() []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithString("operation", mcp.Required(), mcp.Description("Operation name")),
+		mcp.WithString("zone", mcp.Description("Zone of a zonal operation; omit for global operations such as firewall changes")),
+		withProject(),
+	}
+}
+
+func (p *Provider) getOperationTool() *providerkit.Tool {
+	tool := mcp.NewTool("gcp_operation_get",
+		append(withOperation(),
+			mcp.WithDescription("Returns the status and progress of a Compute Engine operation"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(true),
+			format.WithParam(),
+		)...,
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		op, err := operationArgs(request)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		project, err := p.project(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		err = p.api.Do(ctx, http.MethodGet, op.path(project), nil, nil, &op)
+
+		return providerkit.Result(op, err)
+	})
+}
+
+func (p *Provider) waitOperationTool() *providerkit.Tool {
+	tool := mcp.NewTool("gcp_operation_wait",
+		append(withOperation(),
+			mcp.WithDescription("Waits for a Compute Engine operation, such as one returned by a call with wait=false, reporting progress"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(true),
+			format.WithParam(),
+		)...,
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		op, err := operationArgs(request)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		project, err := p.project(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		return providerkit.Result(p.waitOperation(ctx, project, op))
+	})
+}
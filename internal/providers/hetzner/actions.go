@@ -0,0 +1,184 @@
+package hetzner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+)
+
+// Action is a Hetzner action, the record of an asynchronous change.
+type Action struct {
+	ID        int              `json:"id"`
+	Command   string           `json:"command"`
+	Status    string           `json:"status"`
+	Progress  float64          `json:"progress"`
+	Started   string           `json:"started"`
+	Finished  string           `json:"finished,omitempty"`
+	Resources []ActionResource `json:"resources"`
+	Error     *ErrorDetail     `json:"error,omitempty"`
+}
+
+// ActionResource is a resource an action changes.
+type ActionResource struct {
+	ID   int    `json:"id"`
+	Type string `json:"type"`
+}
+
+// Action statuses.
+const (
+	actionRunning = "running"
+	actionError   = "error"
+)
+
+// actionIDs returns the IDs of actions, skipping nil entries.
+func actionIDs(actions ...*Action) []int {
+	ids := make([]int, 0, len(actions))
+	for _, action := range actions {
+		if action != nil && action.ID != 0 {
+			ids = append(ids, action.ID)
+		}
+	}
+
+	return ids
+}
+
+// getAction fetches an action.
+func (p *Provider) getAction(ctx context.Context, id int) (Action, error) {
+	var resp struct {
+		Action Action `json:"action"`
+	}
+	err := p.api.Do(ctx, http.MethodGet, "/actions/"+strconv.Itoa(id), nil, nil, &resp)
+
+	return resp.Action, err
+}
+
+// waitActions polls actions until all of them finish, reporting their mean
+// progress. The final state of each action is returned, in order.
+func (p *Provider) waitActions(ctx context.Context, ids []int) ([]Action, error) {
+	actions := make([]Action, len(ids))
+	if len(ids) == 0 {
+		return actions, nil
+	}
+
+	_, err := providerkit.Wait(ctx, p.pollInterval, func(ctx context.Context) (providerkit.ActionStatus, error) {
+		status := providerkit.ActionStatus{Done: true}
+		var progress float64
+		for i, id := range ids {
+			if actions[i].ID == 0 || actions[i].Status == actionRunning {
+				action, err := p.getAction(ctx, id)
+				if err != nil {
+					return providerkit.ActionStatus{}, err
+				}
+				actions[i] = action
+			}
+
+			action := actions[i]
+			progress += action.Progress
+			switch {
+			case action.Status == actionRunning:
+				status.Done = false
+			case action.Status == actionError:
+				status.Failed = true
+				status.Message = fmt.Sprintf("%s action %d failed", action.Command, action.ID)
+				if action.Error != nil {
+					status.Message += ": " + action.Error.Message
+				}
+			}
+		}
+
+		status.Percent = progress / float64(len(ids))
+		if status.Message == "" {
+			status.Message = fmt.Sprintf("actions %s at %.0f%%", joinIDs(ids), status.Percent)
+		}
+
+		return status, nil
+	})
+
+	return actions, err
+}
+
+// actionResult is returned by tools that start actions: the affected
+// resource, if any, and the actions in their final state when waited on.
+type actionResult struct {
+	Resource any      `json:"resource,omitempty"`
+	Actions  []Action `json:"actions"`
+}
+
+// finish waits for the started actions if the call asks to and builds the
+// tool result.
+func (p *Provider) finish(ctx context.Context, request mcp.CallToolRequest, resource any, started ...*Action) (*mcp.CallToolResult, error) {
+	return providerkit.Result(p.settle(ctx, request, resource, started...))
+}
+
+// finishCreate is finish for tools that create resource. Failed actions do
+// not undo the create, so the resource is returned along with the error.
+func (p *Provider) finishCreate(ctx context.Context, request mcp.CallToolRequest, resource any, started ...*Action) (*mcp.CallToolResult, error) {
+	return providerkit.CreatedResult(p.settle(ctx, request, resource, started...))
+}
+
+// settle waits for the started actions if the call asks to and returns them
+// with the affected resource.
+func (p *Provider) settle(ctx context.Context, request mcp.CallToolRequest, resource any, started ...*Action) (actionResult, error) {
+	result := actionResult{Resource: resource, Actions: []Action{}}
+	for _, action := range started {
+		if action != nil {
+			result.Actions = append(result.Actions, *action)
+		}
+	}
+	if !request.GetBool(providerkit.WaitParam, true) {
+		return result, nil
+	}
+
+	actions, err := p.waitActions(ctx, actionIDs(started...))
+	result.Actions = actions
+
+	return result, err
+}
+
+func (p *Provider) getActionTool() *providerkit.Tool {
+	tool := mcp.NewTool("hetzner_action_get",
+		mcp.WithDescription("Returns the status and progress of a Hetzner action"),
+		withID("action_id", "ID of the action"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id, err := request.RequireInt("action_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return providerkit.Result(p.getAction(ctx, id))
+	})
+}
+
+func (p *Provider) waitActionsTool() *providerkit.Tool {
+	tool := mcp.NewTool("hetzner_actions_wait",
+		mcp.WithDescription("Waits for Hetzner actions, such as those returned by a call with wait=false, reporting progress"),
+		mcp.WithArray("action_ids",
+			mcp.Required(),
+			mcp.Items(map[string]any{"type": "number"}),
+			mcp.Description("IDs of the actions to wait for"),
+		),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ids, err := request.RequireIntSlice("action_ids")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return providerkit.Result(p.waitActions(ctx, ids))
+	})
+}
@@ -0,0 +1,134 @@
+package hetzner
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+)
+
+// ServerType is a Hetzner Cloud server type.
+//
+//nolint:tagliatelle // JSON field names match the Hetzner API.
+type ServerType struct {
+	ID           int     `json:"id"`
+	Name         string  `json:"name"`
+	Description  string  `json:"description,omitempty"`
+	Cores        int     `json:"cores"`
+	Memory       float64 `json:"memory"`
+	Disk         int     `json:"disk"`
+	CPUType      string  `json:"cpu_type,omitempty"`
+	Architecture string  `json:"architecture,omitempty"`
+	Prices       []Price `json:"prices,omitempty"`
+}
+
+// Price is the price of a server type in one location.
+//
+//nolint:tagliatelle // JSON field names match the Hetzner API.
+type Price struct {
+	Location     string `json:"location"`
+	PriceHourly  Amount `json:"price_hourly"`
+	PriceMonthly Amount `json:"price_monthly"`
+}
+
+// Amount is a price in euros, as decimal strings.
+type Amount struct {
+	Net   string `json:"net"`
+	Gross string `json:"gross"`
+}
+
+// Location is a Hetzner Cloud location.
+//
+//nolint:tagliatelle // JSON field names match the Hetzner API.
+type Location struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Country     string `json:"country,omitempty"`
+	City        string `json:"city,omitempty"`
+	NetworkZone string `json:"network_zone,omitempty"`
+}
+
+// Image is a Hetzner Cloud image.
+//
+//nolint:tagliatelle // JSON field names match the Hetzner API.
+type Image struct {
+	ID           int               `json:"id"`
+	Name         string            `json:"name"`
+	Description  string            `json:"description"`
+	Type         string            `json:"type"`
+	Status       string            `json:"status,omitempty"`
+	OSFlavor     string            `json:"os_flavor,omitempty"`
+	OSVersion    string            `json:"os_version,omitempty"`
+	Architecture string            `json:"architecture,omitempty"`
+	DiskSize     float64           `json:"disk_size,omitempty"`
+	Deprecated   string            `json:"deprecated,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+}
+
+// withArchitecture adds the optional architecture filter.
+func withArchitecture() mcp.ToolOption {
+	return mcp.WithString("architecture",
+		mcp.Description("Only list entries for this CPU architecture (optional)"),
+		mcp.Enum("x86", "arm"),
+	)
+}
+
+func (p *Provider) listServerTypesTool() *providerkit.Tool {
+	tool := mcp.NewTool("hetzner_server_types_list",
+		mcp.WithDescription("Lists Hetzner Cloud server types with their size and price per location"),
+		mcp.WithString("name", mcp.Description("Only list the server type with this name (optional)")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return providerkit.Result(list[ServerType](ctx, p, request, "/server_types", "server_types", labelQuery(request)))
+	})
+}
+
+func (p *Provider) listLocationsTool() *providerkit.Tool {
+	tool := mcp.NewTool("hetzner_locations_list",
+		mcp.WithDescription("Lists Hetzner Cloud locations"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return providerkit.Result(list[Location](ctx, p, request, "/locations", "locations", nil))
+	})
+}
+
+func (p *Provider) listImagesTool() *providerkit.Tool {
+	tool := mcp.NewTool("hetzner_images_list",
+		mcp.WithDescription("Lists Hetzner Cloud images: system images, snapshots, backups and apps"),
+		mcp.WithString("type",
+			mcp.Description("Only list images of this type (optional)"),
+			mcp.Enum("system", "snapshot", "backup", "app"),
+		),
+		withArchitecture(),
+		withLabelFilters(),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		query := labelQuery(request)
+		for _, param := range []string{"type", "architecture"} {
+			if value := request.GetString(param, ""); value != "" {
+				query.Set(param, value)
+			}
+		}
+
+		return providerkit.Result(list[Image](ctx, p, request, "/images", "images", query))
+	})
+}
@@ -0,0 +1,234 @@
+// Package hetzner implements the Hetzner Cloud API provider.
+package hetzner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	// Name is the provider name used in settings and accounts.
+	Name = "hetzner"
+
+	// DefaultAPIURL is the Hetzner Cloud API endpoint, overridden by the
+	// api_url setting.
+	DefaultAPIURL = "https://api.hetzner.cloud/v1"
+
+	// DefaultTokenRef is the credential used when neither the account nor the
+	// token_ref setting names one.
+	DefaultTokenRef = "env:HCLOUD_TOKEN"
+
+	// maxPageSize is the largest per_page the API accepts.
+	maxPageSize = 50
+)
+
+// Provider is the Hetzner Cloud provider.
+type Provider struct {
+	cfg          contracts.ProviderConfig
+	api          *providerkit.API
+	pollInterval time.Duration
+}
+
+// New creates an uninitialized Hetzner Cloud provider.
+func New() contracts.Provider {
+	return &Provider{}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return Name
+}
+
+// Initialize configures the API client. Each account is a Hetzner project
+// with its own token.
+func (p *Provider) Initialize(_ context.Context, cfg contracts.ProviderConfig) error {
+	p.cfg = cfg
+	p.pollInterval = providerkit.PollInterval(cfg)
+	p.api = providerkit.NewTokenAPI(cfg, providerkit.TokenAPI{
+		Provider:        Name,
+		DefaultURL:      DefaultAPIURL,
+		DefaultTokenRef: DefaultTokenRef,
+		ErrorMessage:    errorMessage,
+	})
+
+	return nil
+}
+
+// Tools returns the Hetzner Cloud tools.
+func (p *Provider) Tools() []contracts.Tool {
+	return []contracts.Tool{
+		p.listServersTool(),
+		p.getServerTool(),
+		p.createServerTool(),
+		p.deleteServerTool(),
+		p.serverActionTool(),
+		p.listServerTypesTool(),
+		p.listLocationsTool(),
+		p.listImagesTool(),
+		p.listSSHKeysTool(),
+		p.createSSHKeyTool(),
+		p.deleteSSHKeyTool(),
+		p.listFirewallsTool(),
+		p.createFirewallTool(),
+		p.firewallResourceTool("apply"),
+		p.firewallResourceTool("remove"),
+		p.deleteFirewallTool(),
+		p.listPrimaryIPsTool(),
+		p.createPrimaryIPTool(),
+		p.primaryIPAssignmentTool("assign"),
+		p.primaryIPAssignmentTool("unassign"),
+		p.deletePrimaryIPTool(),
+		p.getActionTool(),
+		p.waitActionsTool(),
+	}
+}
+
+// HealthCheck verifies the default account's token with a minimal query.
+func (p *Provider) HealthCheck(ctx context.Context) error {
+	ctx = providerkit.DefaultAccountContext(ctx, p.cfg)
+
+	return p.api.Do(ctx, http.MethodGet, "/locations", url.Values{"per_page": {"1"}}, nil, nil)
+}
+
+// Shutdown has nothing to release.
+func (p *Provider) Shutdown(context.Context) error {
+	return nil
+}
+
+// ErrorDetail is the error object of a Hetzner response or failed action.
+type ErrorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// errorMessage extracts the message of a Hetzner error response.
+func errorMessage(body []byte) string {
+	var decoded struct {
+		Error ErrorDetail `json:"error"`
+	}
+	if err := json.Unmarshal(body, &decoded); err != nil || decoded.Error.Message == "" {
+		return ""
+	}
+
+	return decoded.Error.Code + ": " + decoded.Error.Message
+}
+
+// list fetches one page of a Hetzner collection. key is the name of the
+// collection in the response body.
+func list[T any](ctx context.Context, p *Provider, request mcp.CallToolRequest, path, key string, query url.Values) (pagination.Page[T], error) {
+	cursors, req, err := providerkit.ParsePage(ctx, request)
+	if err != nil {
+		return pagination.Page[T]{}, err
+	}
+
+	page, size := req.ProviderPage(1, maxPageSize)
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("page", strconv.Itoa(page))
+	query.Set("per_page", strconv.Itoa(size))
+
+	var resp map[string]json.RawMessage
+	if err := p.api.Do(ctx, http.MethodGet, path, query, nil, &resp); err != nil {
+		return pagination.Page[T]{}, err
+	}
+
+	items := []T{}
+	if raw, ok := resp[key]; ok {
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return pagination.Page[T]{}, fmt.Errorf("failed to decode %s: %w", key, err)
+		}
+	}
+	var meta struct {
+		Pagination struct {
+			NextPage *int `json:"next_page"`
+		} `json:"pagination"`
+	}
+	if raw, ok := resp["meta"]; ok {
+		_ = json.Unmarshal(raw, &meta)
+	}
+
+	return pagination.FromProviderPage(cursors, req, size, items, meta.Pagination.NextPage != nil), nil
+}
+
+// labelQuery returns the query filtering a list by the label_selector and
+// name arguments.
+func labelQuery(request mcp.CallToolRequest) url.Values {
+	query := url.Values{}
+	if selector := request.GetString("label_selector", ""); selector != "" {
+		query.Set("label_selector", selector)
+	}
+	if name := request.GetString("name", ""); name != "" {
+		query.Set("name", name)
+	}
+
+	return query
+}
+
+// withLabelFilters adds the optional label_selector and name list filters.
+func withLabelFilters() mcp.ToolOption {
+	return func(tool *mcp.Tool) {
+		mcp.WithString("label_selector", mcp.Description("Label selector such as env=prod,role!=db (optional)"))(tool)
+		mcp.WithString("name", mcp.Description("Only list the resource with this name (optional)"))(tool)
+	}
+}
+
+// idPath returns path/{id} for the required numeric argument param.
+func idPath(request mcp.CallToolRequest, param, path, suffix string) (int, string, error) {
+	id, err := request.RequireInt(param)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return id, path + "/" + strconv.Itoa(id) + suffix, nil
+}
+
+// withID adds a required numeric resource ID argument.
+func withID(param, description string) mcp.ToolOption {
+	return mcp.WithNumber(param, mcp.Required(), mcp.Description(description))
+}
+
+// stringItems is the item schema of string array arguments.
+var stringItems = map[string]any{"type": "string"}
+
+// labelsFromArgs reads the optional labels object argument.
+func labelsFromArgs(request mcp.CallToolRequest) map[string]string {
+	raw, ok := request.GetArguments()["labels"].(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	labels := make(map[string]string, len(raw))
+	for key, value := range raw {
+		labels[key] = fmt.Sprint(value)
+	}
+
+	return labels
+}
+
+// withLabels adds the optional labels argument.
+func withLabels() mcp.ToolOption {
+	return mcp.WithObject("labels", mcp.Description("Labels to set, as a string map (optional)"))
+}
+
+// joinIDs formats action IDs for messages.
+func joinIDs(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+
+	return strings.Join(parts, ", ")
+}
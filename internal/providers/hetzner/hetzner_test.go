@@ -0,0 +1,254 @@
+package hetzner_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/internal/providerkit/providerkittest"
+	"github.com/chadit/CloudMCP/internal/providers/hetzner"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+// builds is the account tool calls run against, a Hetzner project.
+var builds = contracts.Account{Provider: hetzner.Name, Alias: "builds", Credential: "hcloud-builds", Region: "fsn1"}
+
+// fakeAPI is a stub of the Hetzner Cloud API. Each action advances 50% per
+// poll; action 13, which creates a server named locked, fails.
+type fakeAPI struct {
+	mu        sync.Mutex
+	locations int
+	progress  map[int]float64
+	nextID    int
+	bodies    map[string]map[string]any
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer hcloud-token" {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "unable to authenticate")
+		return
+	}
+	if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+		var body map[string]any
+		_ = json.Unmarshal(data, &body)
+		f.bodies[r.URL.Path] = body
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/locations":
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		size, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		start := min((page-1)*size, f.locations)
+		end := min(start+size, f.locations)
+		locations := make([]hetzner.Location, 0)
+		for id := start + 1; id <= end; id++ {
+			locations = append(locations, hetzner.Location{ID: id, Name: fmt.Sprintf("loc%d", id)})
+		}
+		var next *int
+		if end < f.locations {
+			next = new(int)
+			*next = page + 1
+		}
+		providerkittest.WriteJSON(w, http.StatusOK, map[string]any{"locations": locations, "meta": map[string]any{"pagination": map[string]any{"page": page, "next_page": next}}})
+	case r.Method == http.MethodPost && r.URL.Path == "/servers" && f.bodies[r.URL.Path]["name"] == "locked":
+		f.progress[13] = 0
+		providerkittest.WriteJSON(w, http.StatusCreated, map[string]any{
+			"server": hetzner.Server{ID: 43, Name: "locked", Status: "initializing"},
+			"action": hetzner.Action{ID: 13, Command: "create_server", Status: "running"},
+		})
+	case r.Method == http.MethodPost && r.URL.Path == "/servers":
+		providerkittest.WriteJSON(w, http.StatusCreated, map[string]any{
+			"server":        hetzner.Server{ID: 42, Name: "runner", Status: "initializing"},
+			"action":        f.start("create_server"),
+			"next_actions":  []hetzner.Action{f.start("start_server")},
+			"root_password": "should-not-leak",
+		})
+	case r.Method == http.MethodGet && r.URL.Path == "/servers/42":
+		providerkittest.WriteJSON(w, http.StatusOK, map[string]any{"server": hetzner.Server{ID: 42, Name: "runner", Status: "running"}})
+	case r.Method == http.MethodPost && r.URL.Path == "/servers/13/actions/reboot":
+		f.progress[13] = 0
+		providerkittest.WriteJSON(w, http.StatusCreated, map[string]any{"action": hetzner.Action{ID: 13, Command: "reboot_server", Status: "running"}})
+	case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/servers/") && strings.Contains(r.URL.Path, "/actions/"):
+		providerkittest.WriteJSON(w, http.StatusCreated, map[string]any{"action": f.start("poweroff_server")})
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/actions/apply_to_resources"):
+		providerkittest.WriteJSON(w, http.StatusCreated, map[string]any{"actions": []hetzner.Action{f.start("apply_firewall")}})
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/actions/"):
+		id, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/actions/"))
+		providerkittest.WriteJSON(w, http.StatusOK, map[string]any{"action": f.poll(id)})
+	default:
+		writeError(w, http.StatusNotFound, "not_found", "server with ID '7' not found")
+	}
+}
+
+// start creates a running action.
+func (f *fakeAPI) start(command string) hetzner.Action {
+	f.nextID++
+	f.progress[f.nextID] = 0
+	return hetzner.Action{ID: f.nextID, Command: command, Status: "running"}
+}
+
+// poll advances an action by one step.
+func (f *fakeAPI) poll(id int) hetzner.Action {
+	f.progress[id] = min(f.progress[id]+50, 100)
+	action := hetzner.Action{ID: id, Command: "action", Status: "running", Progress: f.progress[id]}
+	switch {
+	case f.progress[id] == 100 && id == 13:
+		action.Status = "error"
+		action.Error = &hetzner.ErrorDetail{Code: "action_failed", Message: "server is locked"}
+	case f.progress[id] == 100:
+		action.Status = "success"
+	}
+	return action
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	providerkittest.WriteJSON(w, status, map[string]any{"error": map[string]string{"code": code, "message": message}})
+}
+
+// setup starts a stub API and returns the provider's tools by name.
+func setup(t *testing.T, locations int) (*fakeAPI, map[string]contracts.Tool) {
+	t.Helper()
+
+	api := &fakeAPI{locations: locations, progress: make(map[int]float64), bodies: make(map[string]map[string]any)}
+	srv := providerkittest.Serve(t, api)
+
+	provider := hetzner.New()
+	tools := providerkittest.Setup(t, provider, contracts.ProviderConfig{
+		Settings:   map[string]string{"api_url": srv.URL, "poll_interval": "1ms"},
+		HTTPClient: srv.Client(),
+		Secrets:    providerkittest.Secrets{"hcloud-builds": "hcloud-token"},
+	})
+
+	return api, tools
+}
+
+// actionsResult is the shape of results of tools that start actions.
+type actionsResult struct {
+	Resource json.RawMessage  `json:"resource"`
+	Actions  []hetzner.Action `json:"actions"`
+}
+
+func TestServerCreate_WaitsForAllActions(t *testing.T) {
+	t.Parallel()
+
+	api, tools := setup(t, 0)
+	progress := &providerkittest.Progress{}
+	ctx := contracts.WithProgressReporter(providerkittest.AccountContext(t, builds), progress)
+
+	text, isError := providerkittest.Call(ctx, t, tools["hetzner_server_create"], map[string]any{
+		"name": "runner", "server_type": "cx22", "image": "ubuntu-24.04", "ssh_keys": []any{"ci"},
+	})
+	require.False(t, isError, text)
+	require.NotContains(t, text, "should-not-leak")
+	require.Equal(t, "fsn1", api.bodies["/servers"]["location"], "the account region is the default location")
+
+	var result actionsResult
+	require.NoError(t, json.Unmarshal([]byte(text), &result))
+	var server hetzner.Server
+	require.NoError(t, json.Unmarshal(result.Resource, &server))
+	require.Equal(t, "running", server.Status, "the server is re-read after its actions")
+	require.Len(t, result.Actions, 2)
+	for _, action := range result.Actions {
+		require.Equal(t, "success", action.Status)
+	}
+	require.Equal(t, []float64{50, 100}, progress.Values(), "progress is the mean over both actions")
+}
+
+func TestServerCreate_FailedActionKeepsServer(t *testing.T) {
+	t.Parallel()
+
+	_, tools := setup(t, 0)
+	ctx := providerkittest.AccountContext(t, builds)
+
+	text, isError := providerkittest.Call(ctx, t, tools["hetzner_server_create"], map[string]any{
+		"name": "locked", "server_type": "cx22", "image": "ubuntu-24.04",
+	})
+	require.False(t, isError, "the server exists, so it is returned rather than an error")
+
+	var created struct {
+		Resource actionsResult `json:"resource"`
+		Error    string        `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(text), &created))
+	var server hetzner.Server
+	require.NoError(t, json.Unmarshal(created.Resource.Resource, &server))
+	require.Equal(t, 43, server.ID)
+	require.Contains(t, created.Error, "server is locked")
+}
+
+func TestServerAction_WaitLaterAndFailures(t *testing.T) {
+	t.Parallel()
+
+	_, tools := setup(t, 0)
+	ctx := providerkittest.AccountContext(t, builds)
+
+	text, isError := providerkittest.Call(ctx, t, tools["hetzner_server_action"], map[string]any{"server_id": float64(7), "action": "poweroff", "wait": false})
+	require.False(t, isError, text)
+	var started actionsResult
+	require.NoError(t, json.Unmarshal([]byte(text), &started))
+	require.Equal(t, "running", started.Actions[0].Status)
+
+	text, isError = providerkittest.Call(ctx, t, tools["hetzner_actions_wait"], map[string]any{"action_ids": []any{float64(started.Actions[0].ID)}})
+	require.False(t, isError, text)
+	require.Contains(t, text, `"status": "success"`)
+
+	text, isError = providerkittest.Call(ctx, t, tools["hetzner_server_action"], map[string]any{"server_id": float64(13), "action": "reboot"})
+	require.True(t, isError)
+	require.Contains(t, text, providerkit.ErrActionFailed.Error())
+	require.Contains(t, text, "server is locked")
+}
+
+func TestLocationsList_Paginates(t *testing.T) {
+	t.Parallel()
+
+	_, tools := setup(t, 5)
+	ctx := providerkittest.AccountContext(t, builds)
+
+	var names []string
+	params := map[string]any{"limit": float64(2)}
+	for {
+		text, isError := providerkittest.Call(ctx, t, tools["hetzner_locations_list"], params)
+		require.False(t, isError, text)
+
+		var page pagination.Page[hetzner.Location]
+		require.NoError(t, json.Unmarshal([]byte(text), &page))
+		for _, location := range page.Items {
+			names = append(names, location.Name)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		params["cursor"] = page.NextCursor
+	}
+
+	require.Equal(t, []string{"loc1", "loc2", "loc3", "loc4", "loc5"}, names)
+}
+
+func TestFirewallApply_AndErrors(t *testing.T) {
+	t.Parallel()
+
+	api, tools := setup(t, 0)
+	ctx := providerkittest.AccountContext(t, builds)
+
+	text, isError := providerkittest.Call(ctx, t, tools["hetzner_firewall_apply"], map[string]any{"firewall_id": float64(3), "server_id": float64(42)})
+	require.False(t, isError, text)
+	require.Equal(t, []any{map[string]any{"type": "server", "server": map[string]any{"id": float64(42)}}},
+		api.bodies["/firewalls/3/actions/apply_to_resources"]["apply_to"])
+
+	text, isError = providerkittest.Call(ctx, t, tools["hetzner_server_get"], map[string]any{"server_id": float64(7)})
+	require.True(t, isError)
+	require.Contains(t, text, providerkit.ErrNotFound.Error())
+	require.Contains(t, text, "not_found: server with ID '7' not found")
+}
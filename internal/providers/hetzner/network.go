@@ -0,0 +1,424 @@
+package hetzner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/idempotency"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+)
+
+// SSHKey is a Hetzner Cloud SSH key.
+//
+//nolint:tagliatelle // JSON field names match the Hetzner API.
+type SSHKey struct {
+	ID          int               `json:"id"`
+	Name        string            `json:"name"`
+	Fingerprint string            `json:"fingerprint"`
+	PublicKey   string            `json:"public_key"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Created     string            `json:"created"`
+}
+
+// Firewall is a Hetzner Cloud firewall.
+//
+//nolint:tagliatelle // JSON field names match the Hetzner API.
+type Firewall struct {
+	ID        int               `json:"id"`
+	Name      string            `json:"name"`
+	Rules     []FirewallRule    `json:"rules"`
+	AppliedTo []FirewallTarget  `json:"applied_to"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Created   string            `json:"created"`
+}
+
+// FirewallRule is an inbound or outbound firewall rule.
+//
+//nolint:tagliatelle // JSON field names match the Hetzner API.
+type FirewallRule struct {
+	Direction      string   `json:"direction"`
+	Protocol       string   `json:"protocol"`
+	Port           string   `json:"port,omitempty"`
+	SourceIPs      []string `json:"source_ips,omitempty"`
+	DestinationIPs []string `json:"destination_ips,omitempty"`
+	Description    string   `json:"description,omitempty"`
+}
+
+// FirewallTarget is a resource a firewall applies to.
+//
+//nolint:tagliatelle // JSON field names match the Hetzner API.
+type FirewallTarget struct {
+	Type   string `json:"type"`
+	Server *struct {
+		ID int `json:"id"`
+	} `json:"server,omitempty"`
+	LabelSelector *struct {
+		Selector string `json:"selector"`
+	} `json:"label_selector,omitempty"`
+}
+
+// PrimaryIP is a Hetzner Cloud primary IP.
+//
+//nolint:tagliatelle // JSON field names match the Hetzner API.
+type PrimaryIP struct {
+	ID           int               `json:"id"`
+	Name         string            `json:"name"`
+	IP           string            `json:"ip"`
+	Type         string            `json:"type"`
+	AssigneeID   *int              `json:"assignee_id"`
+	AssigneeType string            `json:"assignee_type"`
+	AutoDelete   bool              `json:"auto_delete"`
+	Blocked      bool              `json:"blocked"`
+	Datacenter   Datacenter        `json:"datacenter"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	Created      string            `json:"created"`
+}
+
+// deleted reports a deleted resource.
+type deleted struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+}
+
+// deleteByID deletes the resource at path/{id} for tools whose delete call
+// returns no action.
+func (p *Provider) deleteByID(ctx context.Context, request mcp.CallToolRequest, param, path string) (*mcp.CallToolResult, error) {
+	id, target, err := idPath(request, param, path, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	err = p.api.Do(ctx, http.MethodDelete, target, nil, nil, nil)
+
+	return providerkit.Result(deleted{ID: id, Status: "deleted"}, err)
+}
+
+// deleteTool creates a tool deleting a resource without an action.
+func (p *Provider) deleteTool(name, description, param, path string) *providerkit.Tool {
+	tool := mcp.NewTool(name,
+		mcp.WithDescription(description),
+		withID(param, "ID of the resource to delete"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return p.deleteByID(ctx, request, param, path)
+	})
+}
+
+func (p *Provider) listSSHKeysTool() *providerkit.Tool {
+	tool := mcp.NewTool("hetzner_ssh_keys_list",
+		mcp.WithDescription("Lists the SSH keys of the Hetzner Cloud project"),
+		withLabelFilters(),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return providerkit.Result(list[SSHKey](ctx, p, request, "/ssh_keys", "ssh_keys", labelQuery(request)))
+	})
+}
+
+func (p *Provider) createSSHKeyTool() *providerkit.Tool {
+	tool := mcp.NewTool("hetzner_ssh_key_create",
+		mcp.WithDescription("Adds an SSH public key to the Hetzner Cloud project"),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Key name, unique in the project")),
+		mcp.WithString("public_key", mcp.Required(), mcp.Description("OpenSSH public key")),
+		withLabels(),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, err := request.RequireString("name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		publicKey, err := request.RequireString("public_key")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		body := map[string]any{"name": name, "public_key": publicKey}
+		if labels := labelsFromArgs(request); labels != nil {
+			body["labels"] = labels
+		}
+
+		var resp struct {
+			SSHKey SSHKey `json:"ssh_key"`
+		}
+		err = p.api.Do(ctx, http.MethodPost, "/ssh_keys", nil, body, &resp)
+
+		return providerkit.Result(resp.SSHKey, err)
+	})
+}
+
+func (p *Provider) deleteSSHKeyTool() *providerkit.Tool {
+	return p.deleteTool("hetzner_ssh_key_delete", "Removes an SSH key from the Hetzner Cloud project", "ssh_key_id", "/ssh_keys")
+}
+
+func (p *Provider) listFirewallsTool() *providerkit.Tool {
+	tool := mcp.NewTool("hetzner_firewalls_list",
+		mcp.WithDescription("Lists Hetzner Cloud firewalls with their rules and the resources they apply to"),
+		withLabelFilters(),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return providerkit.Result(list[Firewall](ctx, p, request, "/firewalls", "firewalls", labelQuery(request)))
+	})
+}
+
+// firewallRuleSchema is the item schema of the rules argument.
+var firewallRuleSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"direction":       map[string]any{"type": "string", "enum": []string{"in", "out"}},
+		"protocol":        map[string]any{"type": "string", "enum": []string{"tcp", "udp", "icmp", "esp", "gre"}},
+		"port":            map[string]any{"type": "string", "description": "Port or range such as 80 or 8000-8080, for tcp and udp"},
+		"source_ips":      map[string]any{"type": "array", "items": stringItems},
+		"destination_ips": map[string]any{"type": "array", "items": stringItems},
+		"description":     map[string]any{"type": "string"},
+	},
+	"required": []string{"direction", "protocol"},
+}
+
+func (p *Provider) createFirewallTool() *providerkit.Tool {
+	tool := mcp.NewTool("hetzner_firewall_create",
+		mcp.WithDescription("Creates a Hetzner Cloud firewall"),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Firewall name, unique in the project")),
+		mcp.WithArray("rules", mcp.Items(firewallRuleSchema), mcp.Description("Rules; traffic not allowed by an inbound rule is dropped (optional)")),
+		withLabels(),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, err := request.RequireString("name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		body := map[string]any{"name": name}
+		if raw, ok := request.GetArguments()["rules"]; ok {
+			var rules []FirewallRule
+			if err := remarshal(raw, &rules); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			body["rules"] = rules
+		}
+		if labels := labelsFromArgs(request); labels != nil {
+			body["labels"] = labels
+		}
+
+		var resp struct {
+			Firewall Firewall  `json:"firewall"`
+			Actions  []*Action `json:"actions"`
+		}
+		if err := p.api.Do(ctx, http.MethodPost, "/firewalls", nil, body, &resp); err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		return p.finishCreate(ctx, request, resp.Firewall, resp.Actions...)
+	})
+}
+
+// remarshal converts a decoded JSON argument into a typed value.
+func remarshal(value, target any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("invalid argument: %w", err)
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("invalid argument: %w", err)
+	}
+
+	return nil
+}
+
+// firewallResourceTool creates the tool applying a firewall to a server or
+// removing it.
+func (p *Provider) firewallResourceTool(action string) *providerkit.Tool {
+	endpoint, field, description := "apply_to_resources", "apply_to", "Applies a Hetzner Cloud firewall to a server"
+	if action == "remove" {
+		endpoint, field, description = "remove_from_resources", "remove_from", "Removes a Hetzner Cloud firewall from a server"
+	}
+
+	tool := mcp.NewTool("hetzner_firewall_"+action,
+		mcp.WithDescription(description+" and by default waits for the change to finish"),
+		withID("firewall_id", "ID of the firewall"),
+		withID("server_id", "ID of the server"),
+		providerkit.WithWaitParam(),
+		mcp.WithDestructiveHintAnnotation(action == "remove"),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		_, path, err := idPath(request, "firewall_id", "/firewalls", "/actions/"+endpoint)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		serverID, err := request.RequireInt("server_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		body := map[string]any{field: []map[string]any{{"type": "server", "server": map[string]int{"id": serverID}}}}
+
+		var resp struct {
+			Actions []*Action `json:"actions"`
+		}
+		if err := p.api.Do(ctx, http.MethodPost, path, nil, body, &resp); err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		return p.finish(ctx, request, nil, resp.Actions...)
+	})
+}
+
+func (p *Provider) deleteFirewallTool() *providerkit.Tool {
+	return p.deleteTool("hetzner_firewall_delete", "Deletes a Hetzner Cloud firewall that is not applied to any resource", "firewall_id", "/firewalls")
+}
+
+func (p *Provider) listPrimaryIPsTool() *providerkit.Tool {
+	tool := mcp.NewTool("hetzner_primary_ips_list",
+		mcp.WithDescription("Lists Hetzner Cloud primary IPs and the servers they are assigned to"),
+		withLabelFilters(),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return providerkit.Result(list[PrimaryIP](ctx, p, request, "/primary_ips", "primary_ips", labelQuery(request)))
+	})
+}
+
+func (p *Provider) createPrimaryIPTool() *providerkit.Tool {
+	tool := mcp.NewTool("hetzner_primary_ip_create",
+		mcp.WithDescription("Creates a Hetzner Cloud primary IP, assigned to a powered-off server or unassigned in a datacenter"),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Primary IP name")),
+		mcp.WithString("type", mcp.Required(), mcp.Description("Address family"), mcp.Enum("ipv4", "ipv6")),
+		mcp.WithNumber("assignee_id", mcp.Description("Server to assign the IP to (optional)")),
+		mcp.WithString("datacenter", mcp.Description("Datacenter such as fsn1-dc14, required without assignee_id")),
+		mcp.WithBoolean("auto_delete", mcp.Description("Delete the IP with the server it is assigned to (default false)")),
+		withLabels(),
+		providerkit.WithWaitParam(),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, err := request.RequireString("name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		ipType, err := request.RequireString("type")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		body := map[string]any{
+			"name":          name,
+			"type":          ipType,
+			"assignee_type": "server",
+			"auto_delete":   request.GetBool("auto_delete", false),
+		}
+		switch assignee, datacenter := request.GetInt("assignee_id", 0), request.GetString("datacenter", ""); {
+		case assignee != 0:
+			body["assignee_id"] = assignee
+		case datacenter != "":
+			body["datacenter"] = datacenter
+		default:
+			return mcp.NewToolResultError("assignee_id or datacenter is required"), nil
+		}
+		if labels := labelsFromArgs(request); labels != nil {
+			body["labels"] = labels
+		}
+
+		var resp struct {
+			PrimaryIP PrimaryIP `json:"primary_ip"`
+			Action    *Action   `json:"action"`
+		}
+		if err := p.api.Do(ctx, http.MethodPost, "/primary_ips", nil, body, &resp); err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		return p.finishCreate(ctx, request, resp.PrimaryIP, resp.Action)
+	})
+}
+
+// primaryIPAssignmentTool creates the tool assigning a primary IP to a server
+// or unassigning it.
+func (p *Provider) primaryIPAssignmentTool(action string) *providerkit.Tool {
+	options := []mcp.ToolOption{
+		mcp.WithDescription("Unassigns a Hetzner Cloud primary IP from its powered-off server and by default waits for the change"),
+		withID("primary_ip_id", "ID of the primary IP"),
+	}
+	if action == "assign" {
+		options = []mcp.ToolOption{
+			mcp.WithDescription("Assigns a Hetzner Cloud primary IP to a powered-off server and by default waits for the change"),
+			withID("primary_ip_id", "ID of the primary IP"),
+			withID("server_id", "ID of the server"),
+		}
+	}
+	options = append(options,
+		providerkit.WithWaitParam(),
+		mcp.WithDestructiveHintAnnotation(action == "unassign"),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+	tool := mcp.NewTool("hetzner_primary_ip_"+action, options...)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		_, path, err := idPath(request, "primary_ip_id", "/primary_ips", "/actions/"+action)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		var body any
+		if action == "assign" {
+			serverID, err := request.RequireInt("server_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			body = map[string]any{"assignee_id": serverID, "assignee_type": "server"}
+		}
+
+		return p.postAction(ctx, request, path, body)
+	})
+}
+
+func (p *Provider) deletePrimaryIPTool() *providerkit.Tool {
+	return p.deleteTool("hetzner_primary_ip_delete", "Releases an unassigned Hetzner Cloud primary IP. This cannot be undone.", "primary_ip_id", "/primary_ips")
+}
@@ -0,0 +1,262 @@
+package hetzner
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/idempotency"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+)
+
+// Server is a Hetzner Cloud server.
+//
+//nolint:tagliatelle // JSON field names match the Hetzner API.
+type Server struct {
+	ID              int               `json:"id"`
+	Name            string            `json:"name"`
+	Status          string            `json:"status"`
+	Created         string            `json:"created"`
+	PublicNet       PublicNet         `json:"public_net"`
+	ServerType      ServerType        `json:"server_type"`
+	Datacenter      Datacenter        `json:"datacenter"`
+	Image           *Image            `json:"image"`
+	PrimaryDiskSize int               `json:"primary_disk_size"`
+	Labels          map[string]string `json:"labels"`
+}
+
+// PublicNet holds a server's public addresses.
+type PublicNet struct {
+	IPv4 *struct {
+		IP string `json:"ip"`
+	} `json:"ipv4"`
+	IPv6 *struct {
+		IP string `json:"ip"`
+	} `json:"ipv6"`
+	Firewalls []struct {
+		ID     int    `json:"id"`
+		Status string `json:"status"`
+	} `json:"firewalls"`
+}
+
+// Datacenter is the datacenter a server runs in.
+type Datacenter struct {
+	Name     string   `json:"name"`
+	Location Location `json:"location"`
+}
+
+// serverWrite is the response of a server create or delete call.
+//
+//nolint:tagliatelle // JSON field names match the Hetzner API.
+type serverWrite struct {
+	Server      *Server   `json:"server"`
+	Action      *Action   `json:"action"`
+	NextActions []*Action `json:"next_actions"`
+}
+
+func (p *Provider) listServersTool() *providerkit.Tool {
+	tool := mcp.NewTool("hetzner_servers_list",
+		mcp.WithDescription("Lists Hetzner Cloud servers"),
+		withLabelFilters(),
+		mcp.WithString("status",
+			mcp.Description("Only list servers in this status (optional)"),
+			mcp.Enum("running", "initializing", "starting", "stopping", "off", "deleting", "migrating", "rebuilding"),
+		),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		query := labelQuery(request)
+		if status := request.GetString("status", ""); status != "" {
+			query.Set("status", status)
+		}
+
+		return providerkit.Result(list[Server](ctx, p, request, "/servers", "servers", query))
+	})
+}
+
+func (p *Provider) getServerTool() *providerkit.Tool {
+	tool := mcp.NewTool("hetzner_server_get",
+		mcp.WithDescription("Returns a Hetzner Cloud server"),
+		withID("server_id", "ID of the server"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		_, path, err := idPath(request, "server_id", "/servers", "")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		var resp struct {
+			Server Server `json:"server"`
+		}
+		err = p.api.Do(ctx, http.MethodGet, path, nil, nil, &resp)
+
+		return providerkit.Result(resp.Server, err)
+	})
+}
+
+// createServerRequest is the body of a server create call.
+//
+//nolint:tagliatelle // JSON field names match the Hetzner API.
+type createServerRequest struct {
+	Name       string            `json:"name"`
+	ServerType string            `json:"server_type"`
+	Image      string            `json:"image"`
+	Location   string            `json:"location,omitempty"`
+	SSHKeys    []string          `json:"ssh_keys,omitempty"`
+	Firewalls  []firewallRef     `json:"firewalls,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	UserData   string            `json:"user_data,omitempty"`
+}
+
+// firewallRef refers to a firewall by ID.
+type firewallRef struct {
+	Firewall int `json:"firewall"`
+}
+
+func (p *Provider) createServerTool() *providerkit.Tool {
+	tool := mcp.NewTool("hetzner_server_create",
+		mcp.WithDescription("Creates a Hetzner Cloud server and by default waits until it is running. A root password generated for servers without ssh_keys is not returned, so pass ssh_keys."),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Server name, unique in the project")),
+		mcp.WithString("server_type", mcp.Required(), mcp.Description("Server type, such as cx22")),
+		mcp.WithString("image", mcp.Required(), mcp.Description("Image name or ID, such as ubuntu-24.04")),
+		mcp.WithString("location", mcp.Description("Location, such as fsn1 (default: the account's region)")),
+		mcp.WithArray("ssh_keys", mcp.Items(stringItems), mcp.Description("SSH key names or IDs for root")),
+		mcp.WithArray("firewalls", mcp.Items(map[string]any{"type": "number"}), mcp.Description("Firewall IDs to apply (optional)")),
+		mcp.WithString("user_data", mcp.Description("Cloud-init user data (optional)")),
+		withLabels(),
+		providerkit.WithWaitParam(),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		body := createServerRequest{
+			Name:       request.GetString("name", ""),
+			ServerType: request.GetString("server_type", ""),
+			Image:      request.GetString("image", ""),
+			Location:   providerkit.Region(ctx, request, ""),
+			SSHKeys:    request.GetStringSlice("ssh_keys", nil),
+			Labels:     labelsFromArgs(request),
+			UserData:   request.GetString("user_data", ""),
+		}
+		if body.Name == "" || body.ServerType == "" || body.Image == "" {
+			return mcp.NewToolResultError("name, server_type and image are required"), nil
+		}
+		if location := request.GetString("location", ""); location != "" {
+			body.Location = location
+		}
+		for _, id := range request.GetIntSlice("firewalls", nil) {
+			body.Firewalls = append(body.Firewalls, firewallRef{Firewall: id})
+		}
+
+		var resp serverWrite
+		if err := p.api.Do(ctx, http.MethodPost, "/servers", nil, body, &resp); err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		started := append([]*Action{resp.Action}, resp.NextActions...)
+		if !request.GetBool(providerkit.WaitParam, true) || resp.Server == nil {
+			return p.finishCreate(ctx, request, resp.Server, started...)
+		}
+
+		actions, err := p.waitActions(ctx, actionIDs(started...))
+		if err == nil {
+			// Re-read the server for its state once the actions finished
+			var current struct {
+				Server Server `json:"server"`
+			}
+			if err = p.api.Do(ctx, http.MethodGet, "/servers/"+strconv.Itoa(resp.Server.ID), nil, nil, &current); err == nil {
+				resp.Server = &current.Server
+			}
+		}
+
+		return providerkit.CreatedResult(actionResult{Resource: resp.Server, Actions: actions}, err)
+	})
+}
+
+func (p *Provider) deleteServerTool() *providerkit.Tool {
+	tool := mcp.NewTool("hetzner_server_delete",
+		mcp.WithDescription("Deletes a Hetzner Cloud server and its disk. This cannot be undone."),
+		withID("server_id", "ID of the server"),
+		providerkit.WithWaitParam(),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		_, path, err := idPath(request, "server_id", "/servers", "")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		var resp serverWrite
+		if err := p.api.Do(ctx, http.MethodDelete, path, nil, nil, &resp); err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		return p.finish(ctx, request, nil, resp.Action)
+	})
+}
+
+func (p *Provider) serverActionTool() *providerkit.Tool {
+	tool := mcp.NewTool("hetzner_server_action",
+		mcp.WithDescription("Runs a power action on a Hetzner Cloud server and by default waits for it to finish"),
+		withID("server_id", "ID of the server"),
+		mcp.WithString("action",
+			mcp.Required(),
+			mcp.Description("poweron, poweroff (hard), shutdown (ACPI), reboot (ACPI) or reset (hard)"),
+			mcp.Enum("poweron", "poweroff", "shutdown", "reboot", "reset"),
+		),
+		providerkit.WithWaitParam(),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		action, err := request.RequireString("action")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		_, path, err := idPath(request, "server_id", "/servers", "/actions/"+url.PathEscape(action))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return p.postAction(ctx, request, path, nil)
+	})
+}
+
+// postAction posts to an action endpoint returning a single action and
+// finishes the call.
+func (p *Provider) postAction(ctx context.Context, request mcp.CallToolRequest, path string, body any) (*mcp.CallToolResult, error) {
+	var resp struct {
+		Action *Action `json:"action"`
+	}
+	if err := p.api.Do(ctx, http.MethodPost, path, nil, body, &resp); err != nil {
+		return providerkit.Result(nil, err)
+	}
+
+	return p.finish(ctx, request, nil, resp.Action)
+}
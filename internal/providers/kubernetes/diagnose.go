@@ -0,0 +1,518 @@
+package kubernetes
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+)
+
+const (
+	// diagnoseLogLines is how many log lines of a failing container a
+	// diagnosis shows.
+	diagnoseLogLines = 20
+
+	// maxDiagnoseLogs bounds the containers whose logs one diagnosis reads.
+	maxDiagnoseLogs = 3
+
+	// diagnoseEventWindow is how old a warning event may be and still count.
+	diagnoseEventWindow = time.Hour
+
+	// restartThreshold is the restart count at which restarts are a cause of
+	// their own.
+	restartThreshold = 3
+
+	// maxEvidence bounds the evidence lines of one cause.
+	maxEvidence = 5
+)
+
+// Severities of causes.
+const (
+	severityCritical = "critical"
+	severityWarning  = "warning"
+)
+
+// Static errors for err113 compliance.
+var (
+	ErrAmbiguousTarget     = errors.New("give either deployment or pod, not both")
+	ErrUnsupportedSelector = errors.New("unsupported label selector")
+)
+
+// Diagnosis is the result of a health check of a namespace or workload.
+type Diagnosis struct {
+	Target    string  `json:"target"`
+	Namespace string  `json:"namespace"`
+	Healthy   bool    `json:"healthy"`
+	Summary   string  `json:"summary"`
+	Pods      int     `json:"pods"`
+	Unhealthy int     `json:"unhealthy_pods"`
+	Causes    []Cause `json:"causes"`
+}
+
+// Cause is a likely cause of trouble, with the objects it affects and the
+// evidence for it. Causes are ranked from most to least likely.
+type Cause struct {
+	Rank     int      `json:"rank"`
+	Severity string   `json:"severity"`
+	Reason   string   `json:"reason"`
+	Summary  string   `json:"summary"`
+	Hint     string   `json:"hint,omitempty"`
+	Objects  []string `json:"objects"`
+	Evidence []string `json:"evidence,omitempty"`
+	Logs     *Logs    `json:"logs,omitempty"`
+}
+
+// symptom describes a known failure reason. Higher scores rank first.
+type symptom struct {
+	severity string
+	score    int
+	summary  string
+	hint     string
+}
+
+// symptoms are the failure reasons a diagnosis recognizes, keyed by the
+// container, pod or event reason they are reported as.
+var symptoms = map[string]symptom{
+	"OOMKilled": {severityCritical, 100, "Containers are killed for exceeding their memory limit",
+		"Raise the container's memory limit, or find what uses the memory in its logs"},
+	"CrashLoopBackOff": {severityCritical, 90, "Containers crash repeatedly after starting",
+		"Read the logs of the previous run for the error the container exits with"},
+	"ImagePullBackOff": {severityCritical, 85, "The container image cannot be pulled",
+		"Check the image name and tag, and the pull secret for private registries"},
+	"CreateContainerConfigError": {severityCritical, 80, "A container refers to a missing secret or config map",
+		"Create the secret or config map, or fix the container's reference to it"},
+	"CreateContainerError": {severityCritical, 75, "The container runtime cannot create the container",
+		"Check the container's command, volume mounts and security context"},
+	"RunContainerError": {severityCritical, 75, "The container runtime cannot start the container",
+		"Check the container's command and entrypoint"},
+	"Unschedulable": {severityCritical, 70, "Pods cannot be scheduled onto any node",
+		"Check the pods' resource requests, node selectors, affinities and tolerations against the nodes"},
+	"ProgressDeadlineExceeded": {severityCritical, 65, "The deployment's rollout has stalled",
+		"Fix the new pods' problems, or roll back to the previous version"},
+	"FailedMount": {severityWarning, 60, "Volumes cannot be mounted",
+		"Check that the volume claims, secrets and config maps the pods mount exist"},
+	"Error": {severityWarning, 55, "Containers exit with an error",
+		"Read the container's logs for the error it exits with"},
+	"Evicted": {severityWarning, 50, "Pods were evicted from their node",
+		"The node ran short of memory or disk; check the pods' requests and the node's pressure"},
+	"ProbeFailing": {severityWarning, 45, "Liveness or readiness probes fail",
+		"Check the probes' path, port and delays against how long the app takes to start"},
+	"FailedCreate": {severityWarning, 40, "Pods cannot be created",
+		"Check resource quotas, limit ranges and admission webhooks"},
+	"Restarting": {severityWarning, 30, "Containers have restarted several times",
+		"Read the logs of the previous run for why the container exited"},
+	"NotReady": {severityWarning, 20, "Pods are running but not ready",
+		"Check the readiness probe and the services the app depends on"},
+}
+
+// logReasons are the causes of containers that have run, whose logs may
+// explain them.
+var logReasons = []string{"OOMKilled", "CrashLoopBackOff", "Error", "Restarting"}
+
+func (p *Provider) diagnoseTool() *providerkit.Tool {
+	tool := mcp.NewTool("k8s_diagnose",
+		mcp.WithDescription("Finds out why a namespace or workload is unhealthy. Checks pod conditions, restarts, "+
+			"CrashLoopBackOff, ImagePullBackOff and OOMKilled containers, recent warning events and the logs of "+
+			"failing containers, and returns the likely causes, most likely first."),
+		withNamespace(),
+		mcp.WithString("deployment", mcp.Description("Only diagnose this deployment (optional)")),
+		mcp.WithString("pod", mcp.Description("Only diagnose this pod (optional)")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		deployment, pod := request.GetString("deployment", ""), request.GetString("pod", "")
+		if deployment != "" && pod != "" {
+			return mcp.NewToolResultError(ErrAmbiguousTarget.Error()), nil
+		}
+		c, err := p.cluster(ctx)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		return providerkit.Result(c.diagnose(ctx, c.namespaceOf(request), deployment, pod))
+	})
+}
+
+// diagnose checks the pods of a deployment, a single pod, or every pod of
+// the namespace when neither is given.
+func (c *cluster) diagnose(ctx context.Context, namespace, deployment, pod string) (Diagnosis, error) {
+	d := &diagnoser{
+		namespace: namespace,
+		causes:    map[string]*Cause{},
+		unhealthy: map[string]bool{},
+		logs:      map[string]logCandidate{},
+	}
+	var found []podObject
+
+	switch {
+	case pod != "":
+		d.target = "pod/" + pod
+		var object podObject
+		if err := c.api.Do(ctx, http.MethodGet, pods.object(namespace, pod), nil, nil, &object); err != nil {
+			return Diagnosis{}, err
+		}
+		found = []podObject{object}
+		d.related = func(object string) bool { return object == "Pod/"+pod }
+	case deployment != "":
+		d.target = "deployment/" + deployment
+		var object deploymentObject
+		if err := c.api.Do(ctx, http.MethodGet, deployments.object(namespace, deployment), nil, nil, &object); err != nil {
+			return Diagnosis{}, err
+		}
+		d.deployment(object)
+
+		objects, related, err := c.deploymentPods(ctx, namespace, object)
+		if err != nil {
+			return Diagnosis{}, err
+		}
+		found = objects
+		d.related = func(object string) bool { return related[object] }
+	default:
+		d.target = "namespace/" + namespace
+		objects, err := all[podObject](ctx, c, pods.path(namespace), nil)
+		if err != nil {
+			return Diagnosis{}, err
+		}
+		found = objects
+		d.related = func(string) bool { return true }
+	}
+
+	for _, object := range found {
+		d.pod(object)
+	}
+
+	warnings, err := c.events(ctx, namespace, eventFilter{WarningsOnly: true})
+	if err != nil {
+		return Diagnosis{}, err
+	}
+	d.events(warnings, time.Now().Add(-diagnoseEventWindow))
+
+	diagnosis := d.result(len(found))
+	c.attachLogs(ctx, namespace, diagnosis.Causes, d.logs)
+
+	return diagnosis, nil
+}
+
+// deploymentPods returns the pods of a deployment and the objects whose
+// events concern it: the deployment, its replica sets and their pods. The
+// selector may match other workloads' objects too, so only those the
+// deployment owns through its replica sets count.
+func (c *cluster) deploymentPods(ctx context.Context, namespace string, deployment deploymentObject) ([]podObject, map[string]bool, error) {
+	selector, err := labelSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, nil, err
+	}
+	query := url.Values{"labelSelector": {selector}}
+
+	owner := "Deployment/" + deployment.Metadata.Name
+	related := map[string]bool{owner: true}
+	sets, err := all[replicaSetObject](ctx, c, replicaSets.path(namespace), query)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, set := range sets {
+		if set.Metadata.ownedBy(owner) {
+			related["ReplicaSet/"+set.Metadata.Name] = true
+		}
+	}
+
+	objects, err := all[podObject](ctx, c, pods.path(namespace), query)
+	if err != nil {
+		return nil, nil, err
+	}
+	owned := make([]podObject, 0, len(objects))
+	for _, object := range objects {
+		if slices.ContainsFunc(object.Metadata.OwnerReferences, func(ref ownerReference) bool {
+			return ref.Kind == "ReplicaSet" && related["ReplicaSet/"+ref.Name]
+		}) {
+			owned = append(owned, object)
+			related["Pod/"+object.Metadata.Name] = true
+		}
+	}
+
+	return owned, related, nil
+}
+
+// attachLogs adds the log tail of the first failing container of each cause,
+// for the highest ranked causes. Logs that cannot be read are left out; they
+// are evidence, not the diagnosis.
+func (c *cluster) attachLogs(ctx context.Context, namespace string, causes []Cause, candidates map[string]logCandidate) {
+	read := 0
+	for i := range causes {
+		candidate, ok := candidates[causes[i].Reason]
+		if !ok || read == maxDiagnoseLogs {
+			continue
+		}
+		read++
+		logs, err := c.logs(ctx, namespace, candidate.pod, logOptions{
+			Container: candidate.container,
+			TailLines: diagnoseLogLines,
+			Previous:  candidate.previous,
+		})
+		if err == nil {
+			causes[i].Logs = &logs
+		}
+	}
+}
+
+// logCandidate is a failing container whose logs may explain a cause.
+type logCandidate struct {
+	pod       string
+	container string
+	previous  bool
+}
+
+// diagnoser collects the causes found in a target's pods and events.
+type diagnoser struct {
+	namespace string
+	target    string
+	related   func(object string) bool
+
+	causes    map[string]*Cause
+	unhealthy map[string]bool
+	logs      map[string]logCandidate
+}
+
+// add records that reason affects object, with a line of evidence.
+func (d *diagnoser) add(reason, object, evidence string) {
+	cause, ok := d.causes[reason]
+	if !ok {
+		known, ok := symptoms[reason]
+		if !ok {
+			known = symptom{severity: severityWarning, summary: "Warning events with reason " + reason}
+		}
+		cause = &Cause{Severity: known.severity, Reason: reason, Summary: known.summary, Hint: known.hint, Objects: []string{}}
+		d.causes[reason] = cause
+	}
+	if !slices.Contains(cause.Objects, object) {
+		cause.Objects = append(cause.Objects, object)
+	}
+	if line := object + ": " + evidence; evidence != "" && len(cause.Evidence) < maxEvidence && !slices.Contains(cause.Evidence, line) {
+		cause.Evidence = append(cause.Evidence, line)
+	}
+
+	if strings.HasPrefix(object, "Pod/") {
+		d.unhealthy[object] = true
+	}
+}
+
+// addContainer records a container's cause and, for containers that have
+// run, remembers the first one as the container whose logs explain it.
+func (d *diagnoser) addContainer(reason string, pod podObject, status containerStatusObject, evidence string) {
+	d.add(reason, "Pod/"+pod.Metadata.Name, evidence)
+	if _, ok := d.logs[reason]; ok || !slices.Contains(logReasons, reason) {
+		return
+	}
+	// A container waiting to restart has no current run to read.
+	d.logs[reason] = logCandidate{
+		pod:       pod.Metadata.Name,
+		container: status.Name,
+		previous:  status.State.Terminated == nil && status.RestartCount > 0,
+	}
+}
+
+// deployment checks a deployment's rollout.
+func (d *diagnoser) deployment(object deploymentObject) {
+	for _, condition := range object.Status.Conditions {
+		if condition.Type == "Progressing" && condition.Reason == "ProgressDeadlineExceeded" {
+			d.add("ProgressDeadlineExceeded", "Deployment/"+object.Metadata.Name, condition.Message)
+		}
+		if condition.Type == "ReplicaFailure" && condition.Status == "True" {
+			d.add("FailedCreate", "Deployment/"+object.Metadata.Name, condition.Message)
+		}
+	}
+}
+
+// pod checks a pod's conditions and the state, last termination and
+// restarts of its containers.
+func (d *diagnoser) pod(object podObject) {
+	name := "Pod/" + object.Metadata.Name
+	if object.Status.Reason == "Evicted" {
+		d.add("Evicted", name, object.Status.Message)
+		return
+	}
+	for _, condition := range object.Status.Conditions {
+		if condition.Type == "PodScheduled" && condition.Status == "False" {
+			d.add("Unschedulable", name, condition.Message)
+		}
+	}
+
+	failing := false
+	for _, statuses := range [][]containerStatusObject{object.Status.InitContainerStatuses, object.Status.ContainerStatuses} {
+		for _, status := range statuses {
+			if d.container(object, status) {
+				failing = true
+			}
+		}
+	}
+	if failing || object.Status.Phase != "Running" {
+		return
+	}
+	for _, condition := range object.Status.Conditions {
+		if condition.Type == "Ready" && condition.Status == "False" {
+			d.add("NotReady", name, firstNonEmpty(condition.Message, condition.Reason))
+		}
+	}
+}
+
+// container checks a container and reports whether it is failing. A
+// container's exit code is evidence of the reason it is waiting, or else a
+// cause of its own.
+func (d *diagnoser) container(pod podObject, status containerStatusObject) bool {
+	prefix := "container " + status.Name + " "
+	reason := ""
+
+	if waiting := status.State.Waiting; waiting != nil {
+		waitingReason := waiting.Reason
+		if waitingReason == "ErrImagePull" || waitingReason == "InvalidImageName" {
+			waitingReason = "ImagePullBackOff"
+		}
+		if _, ok := symptoms[waitingReason]; ok {
+			reason = waitingReason
+			d.addContainer(reason, pod, status, prefix+"is waiting: "+firstNonEmpty(waiting.Message, waiting.Reason))
+		}
+	}
+
+	oomKilled := false
+	for _, terminated := range []*terminatedObject{status.State.Terminated, status.LastState.Terminated} {
+		switch {
+		case terminated == nil:
+		case terminated.Reason == "OOMKilled":
+			if !oomKilled {
+				d.addContainer("OOMKilled", pod, status, fmt.Sprintf("%swas OOM killed with exit code %d", prefix, terminated.ExitCode))
+				oomKilled = true
+			}
+		case terminated.ExitCode != 0:
+			evidence := fmt.Sprintf("%sexited with code %d (%s)",
+				prefix, terminated.ExitCode, firstNonEmpty(terminated.Message, terminated.Reason, "Error"))
+			if reason == "" {
+				reason = "Error"
+			}
+			d.addContainer(reason, pod, status, evidence)
+		}
+	}
+
+	if reason == "" && !oomKilled && status.RestartCount >= restartThreshold {
+		reason = "Restarting"
+		d.addContainer(reason, pod, status, fmt.Sprintf("%srestarted %d times", prefix, status.RestartCount))
+	}
+
+	return reason != "" || oomKilled
+}
+
+// events adds the target's warning events seen since a time. Events about a
+// cause already found add evidence to it.
+func (d *diagnoser) events(events []Event, since time.Time) {
+	for _, event := range events {
+		if event.LastSeen.Before(since) || !d.related(event.Object) {
+			continue
+		}
+		evidence := event.Message
+		if event.Count > 1 {
+			evidence += fmt.Sprintf(" (x%d)", event.Count)
+		}
+		d.add(eventReason(event), event.Object, evidence)
+	}
+}
+
+// eventReason maps an event to the cause it is evidence of.
+func eventReason(event Event) string {
+	switch event.Reason {
+	case "BackOff":
+		if strings.Contains(event.Message, "pulling image") {
+			return "ImagePullBackOff"
+		}
+		return "CrashLoopBackOff"
+	case "Failed":
+		if strings.Contains(strings.ToLower(event.Message), "pull") {
+			return "ImagePullBackOff"
+		}
+		return "Error"
+	case "FailedScheduling":
+		return "Unschedulable"
+	case "Unhealthy":
+		return "ProbeFailing"
+	case "FailedAttachVolume":
+		return "FailedMount"
+	case "OOMKilling":
+		return "OOMKilled"
+	}
+
+	return event.Reason
+}
+
+// result ranks the causes found: by how likely the reason is to be the
+// problem, then by how many objects it affects.
+func (d *diagnoser) result(checked int) Diagnosis {
+	causes := make([]Cause, 0, len(d.causes))
+	for _, cause := range d.causes {
+		causes = append(causes, *cause)
+	}
+	slices.SortFunc(causes, func(a, b Cause) int {
+		return cmp.Or(
+			cmp.Compare(symptoms[b.Reason].score, symptoms[a.Reason].score),
+			cmp.Compare(len(b.Objects), len(a.Objects)),
+			cmp.Compare(a.Reason, b.Reason),
+		)
+	})
+	for i := range causes {
+		causes[i].Rank = i + 1
+	}
+
+	diagnosis := Diagnosis{
+		Target:    d.target,
+		Namespace: d.namespace,
+		Healthy:   len(causes) == 0,
+		Pods:      checked,
+		Unhealthy: len(d.unhealthy),
+		Causes:    causes,
+	}
+	if diagnosis.Healthy {
+		diagnosis.Summary = fmt.Sprintf("No problems found; %d pods checked", checked)
+	} else {
+		diagnosis.Summary = fmt.Sprintf("%d of %d pods unhealthy; most likely cause: %s (%s)",
+			diagnosis.Unhealthy, checked, causes[0].Summary, causes[0].Reason)
+	}
+
+	return diagnosis
+}
+
+// labelSelector formats a workload's selector as a label selector query:
+// its match labels, then its match expressions.
+func labelSelector(selector labelSelectorObject) (string, error) {
+	requirements := make([]string, 0, len(selector.MatchLabels)+len(selector.MatchExpressions))
+	for _, key := range slices.Sorted(maps.Keys(selector.MatchLabels)) {
+		requirements = append(requirements, key+"="+selector.MatchLabels[key])
+	}
+	for _, expression := range selector.MatchExpressions {
+		values := "(" + strings.Join(expression.Values, ",") + ")"
+		switch expression.Operator {
+		case "In":
+			requirements = append(requirements, expression.Key+" in "+values)
+		case "NotIn":
+			requirements = append(requirements, expression.Key+" notin "+values)
+		case "Exists":
+			requirements = append(requirements, expression.Key)
+		case "DoesNotExist":
+			requirements = append(requirements, "!"+expression.Key)
+		default:
+			return "", fmt.Errorf("%w: operator %q of key %s", ErrUnsupportedSelector, expression.Operator, expression.Key)
+		}
+	}
+
+	return strings.Join(requirements, ","), nil
+}
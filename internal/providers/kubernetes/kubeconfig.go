@@ -0,0 +1,450 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/chadit/CloudMCP/internal/httpclient"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	// inClusterContext names the context of the pod's service account when
+	// the server runs inside a cluster without a kubeconfig.
+	inClusterContext = "in-cluster"
+
+	// serviceAccountDir holds the credentials of a pod's service account.
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+	// execTokenLifetime is how long an exec plugin token without an expiry is
+	// reused.
+	execTokenLifetime = 5 * time.Minute
+
+	// execTokenMargin renews exec plugin tokens this long before they expire.
+	execTokenMargin = time.Minute
+)
+
+// Static errors for err113 compliance.
+var (
+	ErrNoKubeconfig    = errors.New("no kubeconfig found, set the kubeconfig setting or KUBECONFIG")
+	ErrUnknownContext  = errors.New("unknown kubeconfig context")
+	ErrInvalidContext  = errors.New("invalid kubeconfig context")
+	ErrUnsupportedAuth = errors.New("unsupported kubeconfig authentication")
+)
+
+// kubeconfig is the layout of a kubeconfig file.
+//
+//nolint:tagliatelle // YAML field names match the kubeconfig format.
+type kubeconfig struct {
+	CurrentContext string `yaml:"current-context"`
+	Clusters       []struct {
+		Name    string      `yaml:"name"`
+		Cluster clusterInfo `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Users []struct {
+		Name string   `yaml:"name"`
+		User userInfo `yaml:"user"`
+	} `yaml:"users"`
+	Contexts []struct {
+		Name    string      `yaml:"name"`
+		Context contextInfo `yaml:"context"`
+	} `yaml:"contexts"`
+}
+
+// clusterInfo is a cluster entry of a kubeconfig.
+//
+//nolint:tagliatelle // YAML field names match the kubeconfig format.
+type clusterInfo struct {
+	Server                   string `yaml:"server"`
+	CertificateAuthority     string `yaml:"certificate-authority"`
+	CertificateAuthorityData string `yaml:"certificate-authority-data"`
+	InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify"`
+	TLSServerName            string `yaml:"tls-server-name"`
+}
+
+// userInfo is a user entry of a kubeconfig.
+//
+//nolint:tagliatelle // YAML field names match the kubeconfig format.
+type userInfo struct {
+	Token                 string      `yaml:"token"`
+	TokenFile             string      `yaml:"tokenFile"`
+	ClientCertificate     string      `yaml:"client-certificate"`
+	ClientCertificateData string      `yaml:"client-certificate-data"`
+	ClientKey             string      `yaml:"client-key"`
+	ClientKeyData         string      `yaml:"client-key-data"`
+	Username              string      `yaml:"username"`
+	Password              string      `yaml:"password"`
+	Exec                  *execConfig `yaml:"exec"`
+	AuthProvider          *struct {
+		Name string `yaml:"name"`
+	} `yaml:"auth-provider"`
+}
+
+// execConfig is an exec credential plugin, such as aws eks get-token.
+//
+//nolint:tagliatelle // YAML field names match the kubeconfig format.
+type execConfig struct {
+	APIVersion string   `yaml:"apiVersion"`
+	Command    string   `yaml:"command"`
+	Args       []string `yaml:"args"`
+	Env        []struct {
+		Name  string `yaml:"name"`
+		Value string `yaml:"value"`
+	} `yaml:"env"`
+}
+
+// contextInfo is a context entry of a kubeconfig.
+type contextInfo struct {
+	Cluster   string `yaml:"cluster"`
+	User      string `yaml:"user"`
+	Namespace string `yaml:"namespace"`
+}
+
+// contextConfig is a context with its cluster and user resolved.
+type contextConfig struct {
+	Name        string
+	ClusterName string
+	UserName    string
+	Namespace   string
+	Cluster     clusterInfo
+	User        userInfo
+}
+
+// config is the merged kubeconfig: the contexts in file order and the
+// current context.
+type config struct {
+	Contexts []contextConfig
+	Current  string
+}
+
+// kubeconfigPaths returns the kubeconfig files to read: the kubeconfig
+// setting or KUBECONFIG, both path lists, otherwise ~/.kube/config.
+func kubeconfigPaths(setting string) []string {
+	list := setting
+	if list == "" {
+		list = os.Getenv("KUBECONFIG")
+	}
+	if list != "" {
+		var paths []string
+		for _, path := range filepath.SplitList(list) {
+			if path != "" {
+				paths = append(paths, path)
+			}
+		}
+		return paths
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+
+	return []string{filepath.Join(home, ".kube", "config")}
+}
+
+// loadConfig reads and merges kubeconfig files the way kubectl does: missing
+// files are skipped, the first file defining a name wins, and the first
+// current-context set is used. Relative paths in a file are relative to it.
+func loadConfig(paths []string) (*config, error) {
+	clusters := map[string]clusterInfo{}
+	users := map[string]userInfo{}
+	contexts := map[string]contextInfo{}
+	var order []string
+	merged := &config{}
+	found := false
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read kubeconfig: %w", err)
+		}
+		found = true
+
+		var file kubeconfig
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("invalid kubeconfig %s: %w", path, err)
+		}
+
+		dir := filepath.Dir(path)
+		for _, entry := range file.Clusters {
+			if _, ok := clusters[entry.Name]; !ok {
+				entry.Cluster.CertificateAuthority = relativeTo(dir, entry.Cluster.CertificateAuthority)
+				clusters[entry.Name] = entry.Cluster
+			}
+		}
+		for _, entry := range file.Users {
+			if _, ok := users[entry.Name]; !ok {
+				user := entry.User
+				user.TokenFile = relativeTo(dir, user.TokenFile)
+				user.ClientCertificate = relativeTo(dir, user.ClientCertificate)
+				user.ClientKey = relativeTo(dir, user.ClientKey)
+				if user.Exec != nil && strings.ContainsRune(user.Exec.Command, filepath.Separator) {
+					user.Exec.Command = relativeTo(dir, user.Exec.Command)
+				}
+				users[entry.Name] = user
+			}
+		}
+		for _, entry := range file.Contexts {
+			if _, ok := contexts[entry.Name]; !ok {
+				contexts[entry.Name] = entry.Context
+				order = append(order, entry.Name)
+			}
+		}
+		if merged.Current == "" {
+			merged.Current = file.CurrentContext
+		}
+	}
+	if !found {
+		return nil, ErrNoKubeconfig
+	}
+
+	for _, name := range order {
+		info := contexts[name]
+		merged.Contexts = append(merged.Contexts, contextConfig{
+			Name:        name,
+			ClusterName: info.Cluster,
+			UserName:    info.User,
+			Namespace:   info.Namespace,
+			Cluster:     clusters[info.Cluster],
+			User:        users[info.User],
+		})
+	}
+
+	return merged, nil
+}
+
+// inClusterConfig returns the configuration of the pod's service account, or
+// false when not running in a cluster.
+func inClusterConfig() (*config, bool) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, false
+	}
+
+	namespace, _ := os.ReadFile(filepath.Join(serviceAccountDir, "namespace"))
+
+	return &config{
+		Current: inClusterContext,
+		Contexts: []contextConfig{{
+			Name:      inClusterContext,
+			Namespace: strings.TrimSpace(string(namespace)),
+			Cluster: clusterInfo{
+				Server:               "https://" + net.JoinHostPort(host, port),
+				CertificateAuthority: filepath.Join(serviceAccountDir, "ca.crt"),
+			},
+			User: userInfo{TokenFile: filepath.Join(serviceAccountDir, "token")},
+		}},
+	}, true
+}
+
+// relativeTo resolves a relative path against dir.
+func relativeTo(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(dir, path)
+}
+
+// client returns the HTTP client of a context's cluster. HTTPS clusters get
+// their own TLS settings; the shared client's retries and breakers are kept
+// when it has them.
+func (c contextConfig) client(shared *http.Client) (*http.Client, error) {
+	if c.Cluster.Server == "" {
+		return nil, fmt.Errorf("%w %s: cluster %q has no server", ErrInvalidContext, c.Name, c.ClusterName)
+	}
+	if !strings.HasPrefix(c.Cluster.Server, "https://") {
+		return shared, nil
+	}
+
+	tlsConfig, err := c.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	base, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		base = &http.Transport{}
+	}
+	transport := base.Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	if resilient, ok := shared.Transport.(*httpclient.Transport); ok {
+		return &http.Client{Transport: resilient.WithBase(transport), Timeout: shared.Timeout}, nil
+	}
+
+	return &http.Client{Transport: transport, Timeout: shared.Timeout}, nil
+}
+
+// tlsConfig returns the TLS settings of a context: the cluster's CA and the
+// user's client certificate.
+func (c contextConfig) tlsConfig() (*tls.Config, error) {
+	//nolint:gosec // InsecureSkipVerify is only set when the kubeconfig asks for it.
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.Cluster.TLSServerName,
+		InsecureSkipVerify: c.Cluster.InsecureSkipTLSVerify,
+	}
+
+	ca, err := fileOrData(c.Cluster.CertificateAuthority, c.Cluster.CertificateAuthorityData)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: certificate authority: %w", ErrInvalidContext, c.Name, err)
+	}
+	if len(ca) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("%w %s: the certificate authority has no PEM certificates", ErrInvalidContext, c.Name)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	cert, err := fileOrData(c.User.ClientCertificate, c.User.ClientCertificateData)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: client certificate: %w", ErrInvalidContext, c.Name, err)
+	}
+	key, err := fileOrData(c.User.ClientKey, c.User.ClientKeyData)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: client key: %w", ErrInvalidContext, c.Name, err)
+	}
+	if len(cert) > 0 || len(key) > 0 {
+		pair, err := tls.X509KeyPair(cert, key)
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: client certificate: %w", ErrInvalidContext, c.Name, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{pair}
+	}
+
+	return tlsConfig, nil
+}
+
+// fileOrData returns base64 data from a kubeconfig, or else the contents of
+// the file it names.
+func fileOrData(path, data string) ([]byte, error) {
+	if data != "" {
+		return base64.StdEncoding.DecodeString(data)
+	}
+	if path == "" {
+		return nil, nil
+	}
+
+	return os.ReadFile(path)
+}
+
+// authenticator adds a context's user credentials to requests. Tokens and
+// passwords are registered with secrets, so they are redacted from tool
+// output like resolved credentials.
+type authenticator struct {
+	context string
+	user    userInfo
+	secrets contracts.SecretResolver
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// authorize adds the user's token or basic credentials. Token files are read
+// on every request since projected service account tokens rotate.
+func (a *authenticator) authorize(ctx context.Context, req *http.Request) error {
+	switch {
+	case a.user.Token != "":
+		contracts.RegisterSecret(a.secrets, contracts.Secret(a.user.Token))
+		req.Header.Set("Authorization", "Bearer "+a.user.Token)
+	case a.user.TokenFile != "":
+		token, err := os.ReadFile(a.user.TokenFile)
+		if err != nil {
+			return fmt.Errorf("%w %s: failed to read token file: %w", ErrInvalidContext, a.context, err)
+		}
+		bearer := strings.TrimSpace(string(token))
+		contracts.RegisterSecret(a.secrets, contracts.Secret(bearer))
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	case a.user.Exec != nil:
+		token, err := a.execToken(ctx)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	case a.user.Username != "":
+		contracts.RegisterSecret(a.secrets, contracts.Secret(a.user.Password))
+		req.SetBasicAuth(a.user.Username, a.user.Password)
+	case a.user.AuthProvider != nil:
+		return fmt.Errorf("%w %s: auth-provider %s, use an exec credential plugin instead",
+			ErrUnsupportedAuth, a.context, a.user.AuthProvider.Name)
+	}
+
+	return nil
+}
+
+// execCredential is the output of an exec credential plugin.
+type execCredential struct {
+	Status struct {
+		Token               string    `json:"token"`
+		ExpirationTimestamp time.Time `json:"expirationTimestamp"`
+	} `json:"status"`
+}
+
+// execToken runs the user's exec credential plugin, reusing its token until
+// shortly before it expires.
+func (a *authenticator) execToken(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token != "" && time.Now().Before(a.expires) {
+		return a.token, nil
+	}
+
+	plugin := a.user.Exec
+	info, err := json.Marshal(map[string]any{
+		"apiVersion": plugin.APIVersion,
+		"kind":       "ExecCredential",
+		"spec":       map[string]any{"interactive": false},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode exec credential request: %w", err)
+	}
+
+	//nolint:gosec // The kubeconfig names the credential plugin to run, as for kubectl.
+	cmd := exec.CommandContext(ctx, plugin.Command, plugin.Args...)
+	cmd.Env = append(os.Environ(), "KUBERNETES_EXEC_INFO="+string(info))
+	for _, env := range plugin.Env {
+		cmd.Env = append(cmd.Env, env.Name+"="+env.Value)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%w %s: credential plugin %s failed: %w: %s",
+			ErrUnsupportedAuth, a.context, plugin.Command, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var credential execCredential
+	if err := json.Unmarshal(stdout.Bytes(), &credential); err != nil || credential.Status.Token == "" {
+		return "", fmt.Errorf("%w %s: credential plugin %s returned no token", ErrUnsupportedAuth, a.context, plugin.Command)
+	}
+
+	a.token = credential.Status.Token
+	contracts.RegisterSecret(a.secrets, contracts.Secret(a.token))
+	a.expires = time.Now().Add(execTokenLifetime)
+	if expiry := credential.Status.ExpirationTimestamp; !expiry.IsZero() {
+		a.expires = expiry.Add(-execTokenMargin)
+	}
+
+	return a.token, nil
+}
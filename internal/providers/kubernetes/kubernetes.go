@@ -0,0 +1,367 @@
+// Package kubernetes implements the Kubernetes provider. It reads kubeconfig
+// files like kubectl and exposes each context as an account, with tools for
+// namespaces, pods, deployments, services, events and pod logs.
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	// Name is the provider name used in settings and accounts.
+	Name = "kubernetes"
+
+	// DefaultNamespace is used when neither the call nor the context names
+	// a namespace.
+	DefaultNamespace = "default"
+
+	// ContextSetting names a kubeconfig context, as the provider setting
+	// choosing the default context and as the account setting choosing an
+	// account's context.
+	ContextSetting = "context"
+
+	// maxPageSize is the largest limit list calls send.
+	maxPageSize = 500
+)
+
+// Provider is the Kubernetes provider.
+type Provider struct {
+	cfg          contracts.ProviderConfig
+	http         *http.Client
+	kubeconfig   *config
+	current      string
+	pollInterval time.Duration
+
+	mu       sync.Mutex
+	clusters map[string]*cluster
+}
+
+// New creates an uninitialized Kubernetes provider.
+func New() contracts.Provider {
+	return &Provider{}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return Name
+}
+
+// Initialize reads the kubeconfig files. Without one, the pod's service
+// account is used when the server runs inside a cluster. The context setting
+// overrides the kubeconfig's current context.
+func (p *Provider) Initialize(_ context.Context, cfg contracts.ProviderConfig) error {
+	p.cfg = cfg
+	p.http = cfg.HTTPClient
+	if p.http == nil {
+		p.http = http.DefaultClient
+	}
+	p.pollInterval = providerkit.PollInterval(cfg)
+	p.clusters = map[string]*cluster{}
+
+	kubeconfig, err := loadConfig(kubeconfigPaths(cfg.Setting("kubeconfig", "")))
+	if err != nil {
+		inCluster, ok := inClusterConfig()
+		if !ok {
+			return err
+		}
+		kubeconfig = inCluster
+	}
+	p.kubeconfig = kubeconfig
+
+	p.current = cfg.Setting(ContextSetting, kubeconfig.Current)
+	if _, ok := p.contextConfig(p.current); !ok {
+		if p.current != "" {
+			return fmt.Errorf("%w %q", ErrUnknownContext, p.current)
+		}
+		if len(kubeconfig.Contexts) == 0 {
+			return fmt.Errorf("%w: the kubeconfig has no contexts", ErrNoKubeconfig)
+		}
+		p.current = kubeconfig.Contexts[0].Name
+	}
+
+	return nil
+}
+
+// Tools returns the Kubernetes tools.
+func (p *Provider) Tools() []contracts.Tool {
+	return []contracts.Tool{
+		p.listContextsTool(),
+		p.listNamespacesTool(),
+		p.listPodsTool(),
+		p.getPodTool(),
+		p.podLogsTool(),
+		p.listDeploymentsTool(),
+		p.getDeploymentTool(),
+		p.restartDeploymentTool(),
+		p.scaleDeploymentTool(),
+		p.listServicesTool(),
+		p.listEventsTool(),
+		p.diagnoseTool(),
+	}
+}
+
+// DiscoveredAccounts exposes each kubeconfig context as an account, the
+// current context being the default.
+func (p *Provider) DiscoveredAccounts() []contracts.Account {
+	accounts := make([]contracts.Account, 0, len(p.kubeconfig.Contexts))
+	for _, kubeContext := range p.kubeconfig.Contexts {
+		accounts = append(accounts, contracts.Account{
+			Provider: Name,
+			Alias:    kubeContext.Name,
+			Default:  kubeContext.Name == p.current,
+		})
+	}
+
+	return accounts
+}
+
+// HealthCheck reads the API server version of the default context.
+func (p *Provider) HealthCheck(ctx context.Context) error {
+	ctx = providerkit.DefaultAccountContext(ctx, p.cfg)
+	c, err := p.cluster(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.api.Do(ctx, http.MethodGet, "/version", nil, nil, nil)
+}
+
+// Shutdown has nothing to release.
+func (p *Provider) Shutdown(context.Context) error {
+	return nil
+}
+
+// contextConfig returns the kubeconfig context with the given name.
+func (p *Provider) contextConfig(name string) (contextConfig, bool) {
+	for _, kubeContext := range p.kubeconfig.Contexts {
+		if kubeContext.Name == name {
+			return kubeContext, true
+		}
+	}
+
+	return contextConfig{}, false
+}
+
+// contextName returns the kubeconfig context of a call. An account names its
+// context with the context setting, so an account in the accounts file can
+// use another alias; otherwise the alias is the context, as for the accounts
+// discovered from the kubeconfig. Without an account the current context is used.
+func (p *Provider) contextName(ctx context.Context) string {
+	if account, ok := contracts.AccountFromContext(ctx); ok {
+		return account.Setting(ContextSetting, account.Alias)
+	}
+
+	return p.current
+}
+
+// cluster is the API client of a kubeconfig context.
+type cluster struct {
+	context   string
+	namespace string
+	api       *providerkit.API
+}
+
+// cluster returns the client of the call's context: the context named by its
+// account, otherwise the current context. Clients are created on first use.
+func (p *Provider) cluster(ctx context.Context) (*cluster, error) {
+	name := p.contextName(ctx)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if c, ok := p.clusters[name]; ok {
+		return c, nil
+	}
+
+	kubeContext, ok := p.contextConfig(name)
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownContext, name)
+	}
+	client, err := kubeContext.client(p.http)
+	if err != nil {
+		return nil, err
+	}
+
+	auth := &authenticator{context: name, user: kubeContext.User, secrets: p.cfg.Secrets}
+	c := &cluster{
+		context:   name,
+		namespace: kubeContext.Namespace,
+		api: &providerkit.API{
+			Provider:     "Kubernetes",
+			BaseURL:      kubeContext.Cluster.Server,
+			HTTP:         client,
+			Authorize:    auth.authorize,
+			ErrorMessage: errorMessage,
+		},
+	}
+	p.clusters[name] = c
+
+	return c, nil
+}
+
+// namespaceOf returns the namespace of a call: the namespace argument,
+// otherwise the context's namespace, otherwise default.
+func (c *cluster) namespaceOf(request mcp.CallToolRequest) string {
+	if namespace := request.GetString("namespace", ""); namespace != "" {
+		return namespace
+	}
+	if c.namespace != "" {
+		return c.namespace
+	}
+
+	return DefaultNamespace
+}
+
+// errorMessage extracts the message of a Kubernetes Status response.
+func errorMessage(body []byte) string {
+	var status struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &status); err != nil {
+		return ""
+	}
+
+	return status.Message
+}
+
+// resource addresses a collection of namespaced or cluster-wide objects.
+type resource struct {
+	// group is the API path of the resource's group version, such as /api/v1
+	// or /apis/apps/v1.
+	group string
+
+	// plural is the resource name, such as pods.
+	plural string
+}
+
+// Resources the tools work with.
+var (
+	namespaces  = resource{group: "/api/v1", plural: "namespaces"}
+	pods        = resource{group: "/api/v1", plural: "pods"}
+	services    = resource{group: "/api/v1", plural: "services"}
+	events      = resource{group: "/api/v1", plural: "events"}
+	deployments = resource{group: "/apis/apps/v1", plural: "deployments"}
+	replicaSets = resource{group: "/apis/apps/v1", plural: "replicasets"}
+)
+
+// path returns the path of the resource's collection in namespace, or across
+// namespaces when namespace is empty.
+func (r resource) path(namespace string) string {
+	if namespace == "" {
+		return r.group + "/" + r.plural
+	}
+
+	return r.group + "/namespaces/" + url.PathEscape(namespace) + "/" + r.plural
+}
+
+// object returns the path of a named object in namespace.
+func (r resource) object(namespace, name string) string {
+	return r.path(namespace) + "/" + url.PathEscape(name)
+}
+
+// objectList is a Kubernetes list response.
+type objectList[T any] struct {
+	Metadata struct {
+		Continue string `json:"continue"`
+	} `json:"metadata"`
+	Items []T `json:"items"`
+}
+
+// list fetches one page of a collection, converting each object for output.
+// Kubernetes continue tokens are passed through as page tokens.
+func list[T, O any](ctx context.Context, c *cluster, request mcp.CallToolRequest, path string, query url.Values, convert func(T) O) (pagination.Page[O], error) {
+	cursors, req, err := providerkit.ParsePage(ctx, request)
+	if err != nil {
+		return pagination.Page[O]{}, err
+	}
+
+	token, size := req.TokenPage(1, maxPageSize)
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("limit", strconv.Itoa(size))
+	if token != "" {
+		query.Set("continue", token)
+	}
+
+	var objects objectList[T]
+	if err := c.api.Do(ctx, http.MethodGet, path, query, nil, &objects); err != nil {
+		return pagination.Page[O]{}, err
+	}
+
+	items := make([]O, 0, len(objects.Items))
+	for _, object := range objects.Items {
+		items = append(items, convert(object))
+	}
+
+	return pagination.FromToken(cursors, req, size, items, objects.Metadata.Continue), nil
+}
+
+// all fetches every object of a collection matching query.
+func all[T any](ctx context.Context, c *cluster, path string, query url.Values) ([]T, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("limit", strconv.Itoa(maxPageSize))
+
+	items := []T{}
+	for {
+		var objects objectList[T]
+		if err := c.api.Do(ctx, http.MethodGet, path, query, nil, &objects); err != nil {
+			return nil, err
+		}
+		items = append(items, objects.Items...)
+		if objects.Metadata.Continue == "" {
+			return items, nil
+		}
+		query.Set("continue", objects.Metadata.Continue)
+	}
+}
+
+// withNamespace adds the namespace argument of namespaced tools.
+func withNamespace() mcp.ToolOption {
+	return mcp.WithString("namespace", mcp.Description("Namespace (default: the context's namespace, else default)"))
+}
+
+// withListScope adds the namespace, all_namespaces and selector arguments of
+// list tools.
+func withListScope() []mcp.ToolOption {
+	return []mcp.ToolOption{
+		withNamespace(),
+		mcp.WithBoolean("all_namespaces", mcp.Description("List across all namespaces instead")),
+		mcp.WithString("label_selector", mcp.Description("Label selector, such as app=web,tier!=cache (optional)")),
+		mcp.WithString("field_selector", mcp.Description("Field selector, such as status.phase=Running (optional)")),
+	}
+}
+
+// listScope returns the namespace and selector query of a list call. The
+// namespace is empty for all namespaces.
+func (c *cluster) listScope(request mcp.CallToolRequest) (string, url.Values) {
+	namespace := c.namespaceOf(request)
+	if request.GetBool("all_namespaces", false) {
+		namespace = ""
+	}
+
+	query := url.Values{}
+	if selector := request.GetString("label_selector", ""); selector != "" {
+		query.Set("labelSelector", selector)
+	}
+	if selector := request.GetString("field_selector", ""); selector != "" {
+		query.Set("fieldSelector", selector)
+	}
+
+	return namespace, query
+}
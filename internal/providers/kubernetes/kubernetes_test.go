@@ -0,0 +1,847 @@
+package kubernetes_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chadit/CloudMCP/internal/credentials"
+	"github.com/chadit/CloudMCP/internal/httpclient"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/internal/providerkit/providerkittest"
+	"github.com/chadit/CloudMCP/internal/providers/kubernetes"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+// The accounts of the kubeconfig's kind and staging contexts.
+var (
+	kindContext    = contracts.Account{Provider: kubernetes.Name, Alias: "kind"}
+	stagingContext = contracts.Account{Provider: kubernetes.Name, Alias: "staging"}
+)
+
+// fakeCluster is a stub of the Kubernetes API server. The namespace apps runs
+// the deployment web with a healthy pod and a crash-looping one. After a
+// patch, each read of the deployment rolls one more replica out.
+type fakeCluster struct {
+	mu       sync.Mutex
+	token    string
+	requests []string
+	queries  map[string]string
+	patches  []string
+	failures int
+
+	replicas   int
+	generation int
+	updated    int
+}
+
+func newFakeCluster(token string) *fakeCluster {
+	return &fakeCluster{token: token, queries: map[string]string{}, replicas: 2, generation: 1, updated: 2}
+}
+
+func (f *fakeCluster) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+	f.queries[r.URL.Path] = r.URL.RawQuery
+	if f.failures > 0 {
+		f.failures--
+		writeStatus(w, http.StatusServiceUnavailable, "the server is currently unable to handle the request")
+		return
+	}
+	if got := r.Header.Get("Authorization"); got != f.token {
+		writeStatus(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	const deployment = "/apis/apps/v1/namespaces/apps/deployments/web"
+	path := r.URL.Path
+	switch {
+	case path == "/version":
+		providerkittest.WriteJSON(w, http.StatusOK, map[string]string{"gitVersion": "v1.30.2"})
+	case path == "/api/v1/namespaces":
+		providerkittest.WriteJSON(w, http.StatusOK, list("", namespace("default"), namespace("apps")))
+	case path == "/api/v1/namespaces/apps/pods":
+		providerkittest.WriteJSON(w, http.StatusOK, f.podPage(r))
+	case path == "/api/v1/namespaces/default/pods":
+		providerkittest.WriteJSON(w, http.StatusOK, list("", pod("staging-1", "default", nil)))
+	case path == "/api/v1/namespaces/apps/pods/web-1":
+		providerkittest.WriteJSON(w, http.StatusOK, pod("web-1", "apps", nil))
+	case path == "/api/v1/namespaces/apps/pods/web-2":
+		providerkittest.WriteJSON(w, http.StatusOK, crashingPod())
+	case path == "/api/v1/namespaces/apps/pods/web-2/log":
+		_, _ = io.WriteString(w, "starting\nout of memory\n")
+	case path == "/api/v1/namespaces/apps/services":
+		providerkittest.WriteJSON(w, http.StatusOK, list("", map[string]any{
+			"metadata": map[string]any{"name": "web", "namespace": "apps"},
+			"spec": map[string]any{
+				"type": "NodePort", "clusterIP": "10.96.0.10", "selector": map[string]string{"app": "web"},
+				"ports": []map[string]any{
+					{"port": 80, "nodePort": 30080, "protocol": "TCP", "targetPort": 8080},
+					{"port": 443, "targetPort": 443},
+				},
+			},
+		}))
+	case path == "/api/v1/namespaces/apps/events":
+		providerkittest.WriteJSON(w, http.StatusOK, list("",
+			event("Normal", "Pulled", "2026-10-18T10:00:00Z"),
+			event("Warning", "BackOff", "2026-10-18T10:05:00Z"),
+			event("Warning", "Unhealthy", "2026-10-18T10:02:00Z"),
+		))
+	case strings.Contains(path, "/namespaces/broken/"):
+		serveBroken(w, r)
+	case path == deployment && r.Method == http.MethodGet:
+		if f.updated < f.replicas {
+			f.updated++
+		}
+		providerkittest.WriteJSON(w, http.StatusOK, f.deployment())
+	case (path == deployment || path == deployment+"/scale") && r.Method == http.MethodPatch:
+		data, _ := io.ReadAll(r.Body)
+		f.patches = append(f.patches, r.Header.Get("Content-Type")+" "+string(data))
+		var patch struct {
+			Spec struct {
+				Replicas *int `json:"replicas"`
+			} `json:"spec"`
+		}
+		_ = json.Unmarshal(data, &patch)
+		if patch.Spec.Replicas != nil {
+			f.replicas = *patch.Spec.Replicas
+		}
+		f.generation++
+		f.updated = 0
+		providerkittest.WriteJSON(w, http.StatusOK, f.deployment())
+	default:
+		writeStatus(w, http.StatusNotFound, strings.TrimPrefix(path, "/")+" not found")
+	}
+}
+
+// podPage pages through three pods, one pod per continue token.
+func (f *fakeCluster) podPage(r *http.Request) map[string]any {
+	all := []map[string]any{
+		pod("web-1", "apps", nil),
+		crashingPod(),
+		pod("worker-1", "apps", nil),
+	}
+	start, _ := strconv.Atoi(r.URL.Query().Get("continue"))
+	size, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	end := min(start+size, len(all))
+	next := ""
+	if end < len(all) {
+		next = strconv.Itoa(end)
+	}
+
+	return list(next, all[start:end]...)
+}
+
+func (f *fakeCluster) deployment() map[string]any {
+	return map[string]any{
+		"metadata": map[string]any{"name": "web", "namespace": "apps", "generation": f.generation},
+		"spec": map[string]any{
+			"replicas": f.replicas,
+			"selector": map[string]any{"matchLabels": map[string]string{"app": "web"}},
+			"template": map[string]any{"spec": map[string]any{"containers": []map[string]string{{"name": "web", "image": "nginx:1.27"}}}},
+		},
+		"status": map[string]any{
+			"observedGeneration": f.generation, "replicas": f.replicas,
+			"updatedReplicas": f.updated, "readyReplicas": f.updated, "availableReplicas": f.updated,
+		},
+	}
+}
+
+func list(next string, items ...map[string]any) map[string]any {
+	if items == nil {
+		items = []map[string]any{}
+	}
+
+	return map[string]any{"metadata": map[string]string{"continue": next}, "items": items}
+}
+
+func namespace(name string) map[string]any {
+	return map[string]any{"metadata": map[string]any{"name": name}, "status": map[string]string{"phase": "Active"}}
+}
+
+func pod(name, ns string, status map[string]any) map[string]any {
+	if status == nil {
+		status = map[string]any{
+			"phase": "Running",
+			"containerStatuses": []map[string]any{
+				{"name": "istio-proxy", "ready": true, "state": map[string]any{"running": map[string]any{}}},
+				{"name": "web", "ready": true, "state": map[string]any{"running": map[string]any{}}},
+			},
+		}
+	}
+
+	return map[string]any{
+		"metadata": map[string]any{"name": name, "namespace": ns, "labels": map[string]string{"app": "web"}},
+		"spec":     map[string]any{"containers": []map[string]string{{"name": "istio-proxy"}, {"name": "web"}}},
+		"status":   status,
+	}
+}
+
+// crashingPod is the pod web-2, whose container web is in CrashLoopBackOff
+// after being OOM killed.
+func crashingPod() map[string]any {
+	crashing := pod("web-2", "apps", map[string]any{
+		"phase":      "Running",
+		"conditions": []map[string]string{{"type": "Ready", "status": "False", "reason": "ContainersNotReady"}},
+		"containerStatuses": []map[string]any{
+			{"name": "istio-proxy", "ready": true, "state": map[string]any{"running": map[string]any{}}},
+			{
+				"name": "web", "restartCount": 7,
+				"state":     map[string]any{"waiting": map[string]string{"reason": "CrashLoopBackOff", "message": "back-off 5m0s"}},
+				"lastState": map[string]any{"terminated": map[string]any{"reason": "OOMKilled", "exitCode": 137, "finishedAt": "2026-10-18T10:04:00Z"}},
+			},
+		},
+	})
+	metadata, _ := crashing["metadata"].(map[string]any)
+	metadata["annotations"] = map[string]string{"kubectl.kubernetes.io/default-container": "web"}
+
+	return crashing
+}
+
+func event(kind, reason, last string) map[string]any {
+	return map[string]any{
+		"metadata":       map[string]any{"name": "web-2." + reason, "namespace": "apps"},
+		"involvedObject": map[string]string{"kind": "Pod", "name": "web-2"},
+		"type":           kind, "reason": reason, "message": reason + " message", "count": 1,
+		"lastTimestamp": last,
+	}
+}
+
+// serveBroken serves the namespace broken, where the deployment api is
+// crash looping and OOM killed, the pod cron-1 cannot pull its image, batch-1
+// cannot be scheduled and the readiness probe of ok-1 fails. The deployment
+// api-canary shares api's labels and runs the healthy pod api-canary-5c6d-x.
+func serveBroken(w http.ResponseWriter, r *http.Request) {
+	const ns = "broken"
+	running := map[string]any{"running": map[string]any{}}
+	crashLooping := func(name string, lastState map[string]any) map[string]any {
+		return owned(labeled(pod(name, ns, map[string]any{
+			"phase": "Running",
+			"containerStatuses": []map[string]any{{
+				"name": "api", "restartCount": 6, "lastState": lastState,
+				"state": map[string]any{"waiting": map[string]string{"reason": "CrashLoopBackOff", "message": "back-off 5m0s restarting failed container"}},
+			}},
+		}), "api"), "ReplicaSet", "api-7d9f")
+	}
+	all := []map[string]any{
+		crashLooping("api-7d9f-a", map[string]any{"terminated": map[string]any{"reason": "OOMKilled", "exitCode": 137}}),
+		crashLooping("api-7d9f-b", map[string]any{"terminated": map[string]any{"reason": "Error", "exitCode": 1}}),
+		labeled(pod("cron-1", ns, map[string]any{
+			"phase": "Pending",
+			"containerStatuses": []map[string]any{{"name": "cron", "state": map[string]any{
+				"waiting": map[string]string{"reason": "ImagePullBackOff", "message": `Back-off pulling image "registry.example.com/cron:v9"`},
+			}}},
+		}), "cron"),
+		labeled(pod("batch-1", ns, map[string]any{
+			"phase":      "Pending",
+			"conditions": []map[string]string{{"type": "PodScheduled", "status": "False", "reason": "Unschedulable", "message": "0/3 nodes are available: 3 Insufficient memory."}},
+		}), "batch"),
+		labeled(pod("ok-1", ns, map[string]any{
+			"phase":             "Running",
+			"conditions":        []map[string]string{{"type": "Ready", "status": "True"}},
+			"containerStatuses": []map[string]any{{"name": "ok", "ready": true, "state": running}},
+		}), "ok"),
+		owned(labeled(pod("api-canary-5c6d-x", ns, map[string]any{
+			"phase":             "Running",
+			"conditions":        []map[string]string{{"type": "Ready", "status": "True"}},
+			"containerStatuses": []map[string]any{{"name": "api", "ready": true, "state": running}},
+		}), "api"), "ReplicaSet", "api-canary-5c6d"),
+	}
+
+	switch path := r.URL.Path; path {
+	case "/api/v1/namespaces/broken/pods":
+		selected := []map[string]any{}
+		for _, item := range all {
+			metadata, _ := item["metadata"].(map[string]any)
+			labels, _ := metadata["labels"].(map[string]string)
+			if selector := r.URL.Query().Get("labelSelector"); selector == "" || selector == "app="+labels["app"] {
+				selected = append(selected, item)
+			}
+		}
+		providerkittest.WriteJSON(w, http.StatusOK, list("", selected...))
+	case "/apis/apps/v1/namespaces/broken/replicasets":
+		providerkittest.WriteJSON(w, http.StatusOK, list("",
+			owned(map[string]any{"metadata": map[string]any{"name": "api-7d9f", "namespace": ns}}, "Deployment", "api"),
+			owned(map[string]any{"metadata": map[string]any{"name": "api-canary-5c6d", "namespace": ns}}, "Deployment", "api-canary"),
+		))
+	case "/apis/apps/v1/namespaces/broken/deployments/jobs", "/apis/apps/v1/namespaces/broken/deployments/legacy":
+		operator := "In"
+		if strings.HasSuffix(path, "/legacy") {
+			operator = "Gt"
+		}
+		providerkittest.WriteJSON(w, http.StatusOK, map[string]any{
+			"metadata": map[string]any{"name": "jobs", "namespace": ns},
+			"spec": map[string]any{"selector": map[string]any{
+				"matchLabels": map[string]string{"tier": "batch"},
+				"matchExpressions": []map[string]any{
+					{"key": "app", "operator": operator, "values": []string{"batch", "cron"}},
+					{"key": "paused", "operator": "DoesNotExist"},
+				},
+			}},
+		})
+	case "/api/v1/namespaces/broken/pods/ok-1":
+		providerkittest.WriteJSON(w, http.StatusOK, all[4])
+	case "/api/v1/namespaces/broken/pods/api-7d9f-a/log":
+		_, _ = io.WriteString(w, "loading cache\nfatal error: runtime: out of memory\n")
+	case "/apis/apps/v1/namespaces/broken/deployments/api":
+		providerkittest.WriteJSON(w, http.StatusOK, map[string]any{
+			"metadata": map[string]any{"name": "api", "namespace": ns},
+			"spec":     map[string]any{"replicas": 2, "selector": map[string]any{"matchLabels": map[string]string{"app": "api"}}},
+			"status": map[string]any{"replicas": 2, "conditions": []map[string]string{{
+				"type": "Progressing", "status": "False", "reason": "ProgressDeadlineExceeded",
+				"message": `ReplicaSet "api-7d9f" has timed out progressing.`,
+			}}},
+		})
+	case "/api/v1/namespaces/broken/events":
+		ago := func(d time.Duration) string { return time.Now().Add(-d).UTC().Format(time.RFC3339) }
+		found := []map[string]any{
+			brokenEvent("Warning", "BackOff", "api-7d9f-a", "Back-off restarting failed container api in pod api-7d9f-a", 12, ago(2*time.Minute)),
+			brokenEvent("Warning", "FailedScheduling", "batch-1", "0/3 nodes are available: 3 Insufficient memory.", 1, ago(time.Minute)),
+			brokenEvent("Warning", "Unhealthy", "ok-1", "Readiness probe failed: HTTP probe failed with statuscode: 503", 4, ago(5*time.Minute)),
+			brokenEvent("Warning", "FailedMount", "old-1", "MountVolume.SetUp failed", 1, ago(3*time.Hour)),
+			replicaSetEvent("api-7d9f", "exceeded quota: compute", ago(3*time.Minute)),
+			replicaSetEvent("api-canary-5c6d", "admission webhook denied the request", ago(3*time.Minute)),
+			brokenEvent("Normal", "Pulled", "ok-1", "Container image already present", 1, ago(time.Minute)),
+		}
+		if strings.Contains(r.URL.Query().Get("fieldSelector"), "type=Warning") {
+			found = slices.DeleteFunc(found, func(event map[string]any) bool { return event["type"] != "Warning" })
+		}
+		providerkittest.WriteJSON(w, http.StatusOK, list("", found...))
+	default:
+		writeStatus(w, http.StatusNotFound, strings.TrimPrefix(path, "/")+" not found")
+	}
+}
+
+// labeled sets the app label of a pod.
+func labeled(object map[string]any, app string) map[string]any {
+	metadata, _ := object["metadata"].(map[string]any)
+	metadata["labels"] = map[string]string{"app": app}
+	spec, _ := object["spec"].(map[string]any)
+	spec["containers"] = []map[string]string{{"name": app}}
+
+	return object
+}
+
+// owned sets the owner of an object.
+func owned(object map[string]any, kind, name string) map[string]any {
+	metadata, _ := object["metadata"].(map[string]any)
+	metadata["ownerReferences"] = []map[string]string{{"kind": kind, "name": name}}
+
+	return object
+}
+
+// replicaSetEvent is a FailedCreate warning of a replica set of namespace broken.
+func replicaSetEvent(name, message, last string) map[string]any {
+	event := brokenEvent("Warning", "FailedCreate", name, message, 1, last)
+	event["involvedObject"] = map[string]string{"kind": "ReplicaSet", "name": name}
+
+	return event
+}
+
+func brokenEvent(kind, reason, pod, message string, count int, last string) map[string]any {
+	return map[string]any{
+		"metadata":       map[string]any{"name": pod + "." + reason, "namespace": "broken"},
+		"involvedObject": map[string]string{"kind": "Pod", "name": pod},
+		"type":           kind, "reason": reason, "message": message, "count": count,
+		"lastTimestamp": last,
+	}
+}
+
+func writeStatus(w http.ResponseWriter, status int, message string) {
+	providerkittest.WriteJSON(w, status, map[string]any{"kind": "Status", "status": "Failure", "message": message, "code": status})
+}
+
+// writeKubeconfig writes a kubeconfig with the contexts kind, using a token
+// and the namespace apps, and staging, using a token file next to it.
+func writeKubeconfig(t *testing.T, kind, staging string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "staging-token"), []byte("staging-token\n"), 0o600))
+	kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+current-context: kind
+clusters:
+- name: kind
+  cluster:
+    server: %s
+- name: staging
+  cluster:
+    server: %s
+users:
+- name: kind-admin
+  user:
+    token: kind-token
+- name: staging-admin
+  user:
+    tokenFile: staging-token
+contexts:
+- name: kind
+  context:
+    cluster: kind
+    user: kind-admin
+    namespace: apps
+- name: staging
+  context:
+    cluster: staging
+    user: staging-admin
+`, kind, staging)
+	path := filepath.Join(dir, "config")
+	require.NoError(t, os.WriteFile(path, []byte(kubeconfig), 0o600))
+
+	return path
+}
+
+// setup starts stub API servers for the contexts kind and staging and returns
+// the kind cluster, the provider and its tools by name.
+func setup(t *testing.T) (*fakeCluster, *fakeCluster, contracts.Provider, map[string]contracts.Tool) {
+	t.Helper()
+
+	kind, staging := newFakeCluster("Bearer kind-token"), newFakeCluster("Bearer staging-token")
+	kindServer, stagingServer := providerkittest.Serve(t, kind), providerkittest.Serve(t, staging)
+
+	provider := kubernetes.New()
+	tools := providerkittest.Setup(t, provider, contracts.ProviderConfig{
+		Settings: map[string]string{
+			"kubeconfig":                    writeKubeconfig(t, kindServer.URL, stagingServer.URL),
+			providerkit.PollIntervalSetting: "1ms",
+		},
+		HTTPClient: kindServer.Client(),
+	})
+
+	return kind, staging, provider, tools
+}
+
+func TestDiscoveredAccounts_ContextsAsAccounts(t *testing.T) {
+	t.Parallel()
+
+	kind, _, provider, tools := setup(t)
+	discoverer, ok := provider.(contracts.AccountDiscoverer)
+	require.True(t, ok)
+	require.Equal(t, []contracts.Account{
+		{Provider: kubernetes.Name, Alias: "kind", Default: true},
+		{Provider: kubernetes.Name, Alias: "staging"},
+	}, discoverer.DiscoveredAccounts())
+
+	require.NoError(t, provider.HealthCheck(t.Context()))
+	require.Equal(t, []string{"GET /version"}, kind.requests)
+
+	text, isError := providerkittest.Call(providerkittest.AccountContext(t, stagingContext), t, tools["k8s_contexts_list"], nil)
+	require.False(t, isError, text)
+	var contexts pagination.Page[kubernetes.Context]
+	require.NoError(t, json.Unmarshal([]byte(text), &contexts))
+	require.Len(t, contexts.Items, 2)
+	require.Equal(t, kubernetes.Context{Name: "kind", Cluster: "kind", Server: contexts.Items[0].Server, User: "kind-admin", Namespace: "apps"}, contexts.Items[0])
+	require.True(t, contexts.Items[1].Active)
+	require.Empty(t, contexts.NextCursor)
+
+	text, isError = providerkittest.Call(providerkittest.AccountContext(t, stagingContext), t, tools["k8s_contexts_list"], map[string]any{"limit": float64(1)})
+	require.False(t, isError, text)
+	contexts = pagination.Page[kubernetes.Context]{}
+	require.NoError(t, json.Unmarshal([]byte(text), &contexts))
+	require.Equal(t, "kind", contexts.Items[0].Name)
+	require.NotEmpty(t, contexts.NextCursor)
+
+	text, isError = providerkittest.Call(providerkittest.AccountContext(t, stagingContext), t, tools["k8s_contexts_list"], map[string]any{"cursor": contexts.NextCursor})
+	require.False(t, isError, text)
+	contexts = pagination.Page[kubernetes.Context]{}
+	require.NoError(t, json.Unmarshal([]byte(text), &contexts))
+	require.Len(t, contexts.Items, 1)
+	require.True(t, contexts.Items[0].Active)
+	require.Empty(t, contexts.NextCursor)
+}
+
+func TestInitialize_SelectsContext(t *testing.T) {
+	t.Parallel()
+
+	path := writeKubeconfig(t, "http://127.0.0.1:1", "http://127.0.0.1:2")
+
+	provider := kubernetes.New()
+	require.NoError(t, provider.Initialize(t.Context(), contracts.ProviderConfig{
+		Settings: map[string]string{"kubeconfig": path, "context": "staging"},
+	}))
+	discoverer, ok := provider.(contracts.AccountDiscoverer)
+	require.True(t, ok)
+	require.True(t, discoverer.DiscoveredAccounts()[1].Default)
+
+	err := kubernetes.New().Initialize(t.Context(), contracts.ProviderConfig{
+		Settings: map[string]string{"kubeconfig": path, "context": "prod"},
+	})
+	require.ErrorIs(t, err, kubernetes.ErrUnknownContext)
+}
+
+func TestPodsList_PaginatesWithContinueTokens(t *testing.T) {
+	t.Parallel()
+
+	kind, _, _, tools := setup(t)
+	ctx := providerkittest.AccountContext(t, kindContext)
+
+	var found []kubernetes.Pod
+	params := map[string]any{"label_selector": "app=web", "limit": float64(2)}
+	for {
+		text, isError := providerkittest.Call(ctx, t, tools["k8s_pods_list"], params)
+		require.False(t, isError, text)
+
+		var page pagination.Page[kubernetes.Pod]
+		require.NoError(t, json.Unmarshal([]byte(text), &page))
+		found = append(found, page.Items...)
+		if page.NextCursor == "" {
+			break
+		}
+		params["cursor"] = page.NextCursor
+	}
+	require.Len(t, found, 3)
+	require.Equal(t, "continue=2&labelSelector=app%3Dweb&limit=2", kind.queries["/api/v1/namespaces/apps/pods"],
+		"the context's namespace is used and the continue token is passed back")
+
+	require.Equal(t, "Running", found[0].Status)
+	require.Equal(t, "2/2", found[0].Ready)
+	require.Equal(t, "CrashLoopBackOff", found[1].Status)
+	require.Equal(t, "1/2", found[1].Ready)
+	require.Equal(t, 7, found[1].Restarts)
+}
+
+func TestPodGet_ShowsContainerStates(t *testing.T) {
+	t.Parallel()
+
+	_, _, _, tools := setup(t)
+	ctx := providerkittest.AccountContext(t, kindContext)
+
+	text, isError := providerkittest.Call(ctx, t, tools["k8s_pod_get"], map[string]any{"name": "web-2"})
+	require.False(t, isError, text)
+	var pod kubernetes.Pod
+	require.NoError(t, json.Unmarshal([]byte(text), &pod))
+	require.Len(t, pod.Containers, 2)
+	web := pod.Containers[1]
+	require.Equal(t, "waiting", web.State)
+	require.Equal(t, "CrashLoopBackOff", web.Reason)
+	require.NotNil(t, web.LastTermination)
+	require.Equal(t, "OOMKilled", web.LastTermination.Reason)
+	require.Equal(t, 137, web.LastTermination.ExitCode)
+	require.Equal(t, []kubernetes.Condition{{Type: "Ready", Status: "False", Reason: "ContainersNotReady"}}, pod.Conditions)
+
+	text, isError = providerkittest.Call(ctx, t, tools["k8s_pod_get"], map[string]any{"name": "web-9"})
+	require.True(t, isError)
+	require.Contains(t, text, providerkit.ErrNotFound.Error())
+	require.Contains(t, text, "web-9 not found", "the Status message is reported")
+}
+
+func TestPodLogs_TailsDefaultContainer(t *testing.T) {
+	t.Parallel()
+
+	kind, _, _, tools := setup(t)
+	ctx := providerkittest.AccountContext(t, kindContext)
+
+	text, isError := providerkittest.Call(ctx, t, tools["k8s_pod_logs"], map[string]any{
+		"pod": "web-2", "tail_lines": float64(20), "since": "15m", "previous": true,
+	})
+	require.False(t, isError, text)
+	var logs kubernetes.Logs
+	require.NoError(t, json.Unmarshal([]byte(text), &logs))
+	require.Equal(t, kubernetes.Logs{
+		Pod: "web-2", Namespace: "apps", Container: "web", Previous: true,
+		Lines: []string{"starting", "out of memory"},
+	}, logs, "the default-container annotation wins over the first container")
+	require.Equal(t, "container=web&limitBytes=1048576&previous=true&sinceSeconds=900&tailLines=20",
+		kind.queries["/api/v1/namespaces/apps/pods/web-2/log"])
+
+	text, isError = providerkittest.Call(ctx, t, tools["k8s_pod_logs"], map[string]any{"pod": "web-2", "since": "yesterday"})
+	require.True(t, isError)
+	require.Contains(t, text, kubernetes.ErrInvalidSince.Error())
+}
+
+func TestEventsList_NewestFirstWithFieldSelector(t *testing.T) {
+	t.Parallel()
+
+	kind, _, _, tools := setup(t)
+
+	text, isError := providerkittest.Call(providerkittest.AccountContext(t, kindContext), t, tools["k8s_events_list"], map[string]any{
+		"object": "web-2", "kind": "Pod", "warnings_only": true,
+	})
+	require.False(t, isError, text)
+	var page pagination.Page[kubernetes.Event]
+	require.NoError(t, json.Unmarshal([]byte(text), &page))
+	var reasons []string
+	for _, event := range page.Items {
+		reasons = append(reasons, event.Reason)
+	}
+	require.Equal(t, []string{"BackOff", "Unhealthy", "Pulled"}, reasons)
+	require.Equal(t, "Pod/web-2", page.Items[0].Object)
+	require.Equal(t, "fieldSelector=involvedObject.name%3Dweb-2%2CinvolvedObject.kind%3DPod%2Ctype%3DWarning&limit=500",
+		kind.queries["/api/v1/namespaces/apps/events"])
+}
+
+func TestServicesList_FormatsPorts(t *testing.T) {
+	t.Parallel()
+
+	_, _, _, tools := setup(t)
+
+	text, isError := providerkittest.Call(providerkittest.AccountContext(t, kindContext), t, tools["k8s_services_list"], nil)
+	require.False(t, isError, text)
+	var page pagination.Page[kubernetes.Service]
+	require.NoError(t, json.Unmarshal([]byte(text), &page))
+	require.Len(t, page.Items, 1)
+	require.Equal(t, []string{"80:30080/TCP -> 8080", "443/TCP"}, page.Items[0].Ports)
+	require.Equal(t, "10.96.0.10", page.Items[0].ClusterIP)
+}
+
+func TestDeploymentRestart_ConfirmsAndWaitsForRollout(t *testing.T) {
+	t.Parallel()
+
+	kind, _, _, tools := setup(t)
+	progress := &providerkittest.Progress{}
+	ctx := contracts.WithProgressReporter(providerkittest.AccountContext(t, kindContext), progress)
+
+	text, isError := providerkittest.Call(ctx, t, tools["k8s_deployment_restart"], map[string]any{"name": "web", "confirm": "api"})
+	require.True(t, isError)
+	require.Contains(t, text, `not restarted: confirm must be the deployment name "web"`)
+	require.Empty(t, kind.patches)
+
+	text, isError = providerkittest.Call(ctx, t, tools["k8s_deployment_restart"], map[string]any{"name": "web", "confirm": "web"})
+	require.False(t, isError, text)
+	var deployment kubernetes.Deployment
+	require.NoError(t, json.Unmarshal([]byte(text), &deployment))
+	require.Equal(t, 2, deployment.UpToDate)
+	require.Equal(t, []float64{50, 100}, progress.Values())
+
+	require.Len(t, kind.patches, 1)
+	contentType, body, _ := strings.Cut(kind.patches[0], " ")
+	require.Equal(t, "application/merge-patch+json", contentType)
+	var patch struct {
+		Spec struct {
+			Template struct {
+				Metadata struct {
+					Annotations map[string]string `json:"annotations"`
+				} `json:"metadata"`
+			} `json:"template"`
+		} `json:"spec"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(body), &patch))
+	restartedAt, err := time.Parse(time.RFC3339, patch.Spec.Template.Metadata.Annotations["kubectl.kubernetes.io/restartedAt"])
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now(), restartedAt, time.Minute)
+}
+
+func TestDeploymentScale_PatchesScaleSubresource(t *testing.T) {
+	t.Parallel()
+
+	kind, _, _, tools := setup(t)
+	ctx := providerkittest.AccountContext(t, kindContext)
+
+	text, isError := providerkittest.Call(ctx, t, tools["k8s_deployment_scale"], map[string]any{"name": "web", "replicas": float64(3)})
+	require.True(t, isError)
+	require.Contains(t, text, "not scaled: confirm must be the deployment name")
+
+	text, isError = providerkittest.Call(ctx, t, tools["k8s_deployment_scale"], map[string]any{
+		"name": "web", "replicas": float64(3), "confirm": "web", "wait": false,
+	})
+	require.False(t, isError, text)
+	var deployment kubernetes.Deployment
+	require.NoError(t, json.Unmarshal([]byte(text), &deployment))
+	require.Equal(t, 3, deployment.Replicas)
+	require.Equal(t, []string{`application/merge-patch+json {"spec":{"replicas":3}}`}, kind.patches)
+	require.Contains(t, kind.requests, "PATCH /apis/apps/v1/namespaces/apps/deployments/web/scale")
+}
+
+func TestAccounts_SwitchContexts(t *testing.T) {
+	t.Parallel()
+
+	kind, staging, _, tools := setup(t)
+
+	text, isError := providerkittest.Call(providerkittest.AccountContext(t, stagingContext), t, tools["k8s_pods_list"], nil)
+	require.False(t, isError, text)
+	var page pagination.Page[kubernetes.Pod]
+	require.NoError(t, json.Unmarshal([]byte(text), &page))
+	require.Len(t, page.Items, 1)
+	require.Equal(t, "staging-1", page.Items[0].Name)
+	require.Equal(t, []string{"GET /api/v1/namespaces/default/pods"}, staging.requests,
+		"the token file is used and a context without a namespace lists default")
+	require.Empty(t, kind.requests)
+
+	text, isError = providerkittest.Call(providerkittest.AccountContext(t, contracts.Account{Provider: kubernetes.Name, Alias: "prod"}), t, tools["k8s_pods_list"], nil)
+	require.True(t, isError)
+	require.Contains(t, text, kubernetes.ErrUnknownContext.Error())
+
+	named := contracts.Account{Provider: kubernetes.Name, Alias: "local", Settings: map[string]string{kubernetes.ContextSetting: "kind"}}
+	text, isError = providerkittest.Call(providerkittest.AccountContext(t, named), t, tools["k8s_pods_list"], nil)
+	require.False(t, isError, text)
+	require.Equal(t, []string{"GET /api/v1/namespaces/apps/pods"}, kind.requests,
+		"an account's context setting picks the context instead of its alias")
+}
+
+func TestAccounts_RegisterKubeconfigTokens(t *testing.T) {
+	t.Parallel()
+
+	kind, staging := newFakeCluster("Bearer kind-token"), newFakeCluster("Bearer staging-token")
+	kindServer, stagingServer := providerkittest.Serve(t, kind), providerkittest.Serve(t, staging)
+	resolver := credentials.NewResolver(credentials.Options{Path: filepath.Join(t.TempDir(), "credentials.enc")})
+
+	tools := providerkittest.Setup(t, kubernetes.New(), contracts.ProviderConfig{
+		Settings:   map[string]string{"kubeconfig": writeKubeconfig(t, kindServer.URL, stagingServer.URL)},
+		HTTPClient: kindServer.Client(),
+		Secrets:    resolver,
+	})
+	for _, account := range []contracts.Account{kindContext, stagingContext} {
+		providerkittest.CallOK(providerkittest.AccountContext(t, account), t, tools["k8s_pods_list"], nil)
+	}
+
+	require.Equal(t, "kind [REDACTED], staging [REDACTED]",
+		resolver.Redactor().Redact("kind kind-token, staging staging-token"),
+		"the token and the token file contents are redacted")
+}
+
+func TestCluster_TLSWithCertificateAuthority(t *testing.T) {
+	t.Parallel()
+
+	api := newFakeCluster("")
+	api.failures = 1
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, _ := r.BasicAuth()
+		if user == "admin" && password == "secret" {
+			r.Header.Del("Authorization")
+		}
+		api.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	kubeconfig := fmt.Sprintf(`current-context: secure
+clusters:
+- name: secure
+  cluster:
+    server: %s
+    certificate-authority-data: %s
+users:
+- name: admin
+  user:
+    username: admin
+    password: secret
+contexts:
+- name: secure
+  context: {cluster: secure, user: admin}
+`, srv.URL, base64.StdEncoding.EncodeToString(ca))
+	path := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(path, []byte(kubeconfig), 0o600))
+
+	provider := kubernetes.New()
+	require.NoError(t, provider.Initialize(t.Context(), contracts.ProviderConfig{
+		Settings:   map[string]string{"kubeconfig": path},
+		HTTPClient: httpclient.New(httpclient.Options{MaxRetries: 1, BaseDelay: time.Millisecond}),
+	}))
+	require.NoError(t, provider.HealthCheck(t.Context()))
+	require.Equal(t, []string{"GET /version", "GET /version"}, api.requests,
+		"the shared client's retries are kept with the cluster's TLS settings")
+}
+
+func TestDiagnose_RanksLikelyCauses(t *testing.T) {
+	t.Parallel()
+
+	kind, _, _, tools := setup(t)
+
+	text, isError := providerkittest.Call(providerkittest.AccountContext(t, kindContext), t, tools["k8s_diagnose"], map[string]any{"namespace": "broken"})
+	require.False(t, isError, text)
+	var diagnosis kubernetes.Diagnosis
+	require.NoError(t, json.Unmarshal([]byte(text), &diagnosis))
+	require.Equal(t, "namespace/broken", diagnosis.Target)
+	require.False(t, diagnosis.Healthy)
+	require.Equal(t, 6, diagnosis.Pods)
+	require.Equal(t, 5, diagnosis.Unhealthy)
+	require.True(t, strings.HasPrefix(diagnosis.Summary, "5 of 6 pods unhealthy; most likely cause: Containers are killed for exceeding their memory limit"), diagnosis.Summary)
+
+	var reasons []string
+	for i, cause := range diagnosis.Causes {
+		require.Equal(t, i+1, cause.Rank)
+		reasons = append(reasons, cause.Reason)
+	}
+	require.Equal(t, []string{"OOMKilled", "CrashLoopBackOff", "ImagePullBackOff", "Unschedulable", "ProbeFailing", "FailedCreate"}, reasons,
+		"old and normal events are left out")
+
+	oom := diagnosis.Causes[0]
+	require.Equal(t, "critical", oom.Severity)
+	require.Equal(t, []string{"Pod/api-7d9f-a"}, oom.Objects)
+	require.NotNil(t, oom.Logs)
+	require.Equal(t, []string{"loading cache", "fatal error: runtime: out of memory"}, oom.Logs.Lines)
+	require.True(t, oom.Logs.Previous, "a crash-looping container's last run is read")
+	require.Equal(t, "container=api&limitBytes=1048576&previous=true&tailLines=20",
+		kind.queries["/api/v1/namespaces/broken/pods/api-7d9f-a/log"])
+
+	crashLoop := diagnosis.Causes[1]
+	require.Equal(t, []string{"Pod/api-7d9f-a", "Pod/api-7d9f-b"}, crashLoop.Objects)
+	require.Contains(t, crashLoop.Evidence, "Pod/api-7d9f-b: container api exited with code 1 (Error)")
+	require.Contains(t, crashLoop.Evidence, "Pod/api-7d9f-a: Back-off restarting failed container api in pod api-7d9f-a (x12)")
+
+	require.Nil(t, diagnosis.Causes[2].Logs, "a container that never ran has no logs")
+	require.Equal(t, []string{`Pod/cron-1: container cron is waiting: Back-off pulling image "registry.example.com/cron:v9"`}, diagnosis.Causes[2].Evidence)
+	require.Equal(t, []string{"Pod/batch-1: 0/3 nodes are available: 3 Insufficient memory."}, diagnosis.Causes[3].Evidence,
+		"the pod condition and its event are the same evidence")
+	require.Equal(t, "warning", diagnosis.Causes[4].Severity)
+}
+
+func TestDiagnose_Workloads(t *testing.T) {
+	t.Parallel()
+
+	kind, _, _, tools := setup(t)
+	ctx := providerkittest.AccountContext(t, kindContext)
+
+	text, isError := providerkittest.Call(ctx, t, tools["k8s_diagnose"], map[string]any{"namespace": "broken", "deployment": "api"})
+	require.False(t, isError, text)
+	var diagnosis kubernetes.Diagnosis
+	require.NoError(t, json.Unmarshal([]byte(text), &diagnosis))
+	require.Equal(t, "deployment/api", diagnosis.Target)
+	require.Equal(t, 2, diagnosis.Pods)
+	require.Equal(t, "labelSelector=app%3Dapi&limit=500", kind.queries["/api/v1/namespaces/broken/pods"])
+	require.Equal(t, "labelSelector=app%3Dapi&limit=500", kind.queries["/apis/apps/v1/namespaces/broken/replicasets"])
+	var reasons []string
+	for _, cause := range diagnosis.Causes {
+		reasons = append(reasons, cause.Reason)
+	}
+	require.Equal(t, []string{"OOMKilled", "CrashLoopBackOff", "ProgressDeadlineExceeded", "FailedCreate"}, reasons,
+		"only the deployment's pods and events count")
+	require.Equal(t, []string{"ReplicaSet/api-7d9f"}, diagnosis.Causes[3].Objects,
+		"api-canary matches the selector and the name prefix but is not owned by api")
+
+	text, isError = providerkittest.Call(ctx, t, tools["k8s_diagnose"], map[string]any{"namespace": "broken", "deployment": "jobs"})
+	require.False(t, isError, text)
+	require.Equal(t, "labelSelector="+url.QueryEscape("tier=batch,app in (batch,cron),!paused")+"&limit=500",
+		kind.queries["/api/v1/namespaces/broken/pods"], "match expressions are part of the selector")
+
+	text, isError = providerkittest.Call(ctx, t, tools["k8s_diagnose"], map[string]any{"namespace": "broken", "deployment": "legacy"})
+	require.True(t, isError)
+	require.Contains(t, text, kubernetes.ErrUnsupportedSelector.Error())
+
+	text, isError = providerkittest.Call(ctx, t, tools["k8s_diagnose"], map[string]any{"namespace": "broken", "pod": "ok-1"})
+	require.False(t, isError, text)
+	require.NoError(t, json.Unmarshal([]byte(text), &diagnosis))
+	require.Len(t, diagnosis.Causes, 1)
+	require.Equal(t, "ProbeFailing", diagnosis.Causes[0].Reason)
+	require.Equal(t, []string{"Pod/ok-1: Readiness probe failed: HTTP probe failed with statuscode: 503 (x4)"}, diagnosis.Causes[0].Evidence)
+
+	text, isError = providerkittest.Call(ctx, t, tools["k8s_diagnose"], map[string]any{"pod": "web-1"})
+	require.False(t, isError, text)
+	require.NoError(t, json.Unmarshal([]byte(text), &diagnosis))
+	require.True(t, diagnosis.Healthy)
+	require.Empty(t, diagnosis.Causes)
+	require.Equal(t, "No problems found; 1 pods checked", diagnosis.Summary)
+
+	text, isError = providerkittest.Call(ctx, t, tools["k8s_diagnose"], map[string]any{"deployment": "web", "pod": "web-1"})
+	require.True(t, isError)
+	require.Contains(t, text, kubernetes.ErrAmbiguousTarget.Error())
+}
@@ -0,0 +1,501 @@
+package kubernetes
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// objectMeta is the metadata of a Kubernetes object.
+type objectMeta struct {
+	Name              string            `json:"name"`
+	Namespace         string            `json:"namespace,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+	CreationTimestamp time.Time         `json:"creationTimestamp"`
+	Generation        int64             `json:"generation,omitempty"`
+	OwnerReferences   []ownerReference  `json:"ownerReferences,omitempty"`
+}
+
+// ownerReference names the object that manages an object, such as the
+// replica set of a pod.
+type ownerReference struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// ownedBy reports whether an object is managed by the object kind/name.
+func (m objectMeta) ownedBy(object string) bool {
+	return slices.ContainsFunc(m.OwnerReferences, func(owner ownerReference) bool {
+		return owner.Kind+"/"+owner.Name == object
+	})
+}
+
+// podObject is a pod as the API returns it.
+type podObject struct {
+	Metadata objectMeta `json:"metadata"`
+	Spec     struct {
+		NodeName       string            `json:"nodeName,omitempty"`
+		Containers     []containerObject `json:"containers"`
+		InitContainers []containerObject `json:"initContainers,omitempty"`
+	} `json:"spec"`
+	Status struct {
+		Phase                 string                  `json:"phase"`
+		Reason                string                  `json:"reason,omitempty"`
+		Message               string                  `json:"message,omitempty"`
+		PodIP                 string                  `json:"podIP,omitempty"`
+		Conditions            []conditionObject       `json:"conditions,omitempty"`
+		ContainerStatuses     []containerStatusObject `json:"containerStatuses,omitempty"`
+		InitContainerStatuses []containerStatusObject `json:"initContainerStatuses,omitempty"`
+	} `json:"status"`
+}
+
+// containerObject is a container of a pod spec.
+type containerObject struct {
+	Name  string `json:"name"`
+	Image string `json:"image"`
+}
+
+// conditionObject is a status condition of a pod or deployment.
+type conditionObject struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// containerStatusObject is the status of a container of a pod.
+type containerStatusObject struct {
+	Name         string               `json:"name"`
+	Image        string               `json:"image"`
+	Ready        bool                 `json:"ready"`
+	RestartCount int                  `json:"restartCount"`
+	State        containerStateObject `json:"state"`
+	LastState    containerStateObject `json:"lastState"`
+}
+
+// containerStateObject is the state of a container. One field is set.
+type containerStateObject struct {
+	Waiting *struct {
+		Reason  string `json:"reason,omitempty"`
+		Message string `json:"message,omitempty"`
+	} `json:"waiting,omitempty"`
+	Running *struct {
+		StartedAt time.Time `json:"startedAt"`
+	} `json:"running,omitempty"`
+	Terminated *terminatedObject `json:"terminated,omitempty"`
+}
+
+// terminatedObject is the state of a container that has exited.
+type terminatedObject struct {
+	ExitCode   int       `json:"exitCode"`
+	Reason     string    `json:"reason,omitempty"`
+	Message    string    `json:"message,omitempty"`
+	FinishedAt time.Time `json:"finishedAt"`
+}
+
+// deploymentObject is a deployment as the API returns it.
+type deploymentObject struct {
+	Metadata objectMeta `json:"metadata"`
+	Spec     struct {
+		Replicas *int                `json:"replicas,omitempty"`
+		Paused   bool                `json:"paused,omitempty"`
+		Selector labelSelectorObject `json:"selector"`
+		Template struct {
+			Spec struct {
+				Containers []containerObject `json:"containers"`
+			} `json:"spec"`
+		} `json:"template"`
+	} `json:"spec"`
+	Status struct {
+		ObservedGeneration  int64             `json:"observedGeneration,omitempty"`
+		Replicas            int               `json:"replicas,omitempty"`
+		UpdatedReplicas     int               `json:"updatedReplicas,omitempty"`
+		ReadyReplicas       int               `json:"readyReplicas,omitempty"`
+		AvailableReplicas   int               `json:"availableReplicas,omitempty"`
+		UnavailableReplicas int               `json:"unavailableReplicas,omitempty"`
+		Conditions          []conditionObject `json:"conditions,omitempty"`
+	} `json:"status"`
+}
+
+// labelSelectorObject is the label selector of a workload.
+type labelSelectorObject struct {
+	MatchLabels      map[string]string `json:"matchLabels,omitempty"`
+	MatchExpressions []struct {
+		Key      string   `json:"key"`
+		Operator string   `json:"operator"`
+		Values   []string `json:"values,omitempty"`
+	} `json:"matchExpressions,omitempty"`
+}
+
+// replicaSetObject is a replica set as the API returns it. Only its metadata
+// is read, to find the replica sets of a deployment.
+type replicaSetObject struct {
+	Metadata objectMeta `json:"metadata"`
+}
+
+// serviceObject is a service as the API returns it.
+type serviceObject struct {
+	Metadata objectMeta `json:"metadata"`
+	Spec     struct {
+		Type        string            `json:"type"`
+		ClusterIP   string            `json:"clusterIP,omitempty"`
+		ExternalIPs []string          `json:"externalIPs,omitempty"`
+		Selector    map[string]string `json:"selector,omitempty"`
+		Ports       []struct {
+			Name       string `json:"name,omitempty"`
+			Protocol   string `json:"protocol,omitempty"`
+			Port       int    `json:"port"`
+			TargetPort any    `json:"targetPort,omitempty"`
+			NodePort   int    `json:"nodePort,omitempty"`
+		} `json:"ports,omitempty"`
+	} `json:"spec"`
+	Status struct {
+		LoadBalancer struct {
+			Ingress []struct {
+				IP       string `json:"ip,omitempty"`
+				Hostname string `json:"hostname,omitempty"`
+			} `json:"ingress,omitempty"`
+		} `json:"loadBalancer"`
+	} `json:"status"`
+}
+
+// eventObject is a core/v1 event as the API returns it.
+type eventObject struct {
+	Metadata       objectMeta `json:"metadata"`
+	InvolvedObject struct {
+		Kind      string `json:"kind"`
+		Name      string `json:"name"`
+		Namespace string `json:"namespace,omitempty"`
+	} `json:"involvedObject"`
+	Reason         string    `json:"reason"`
+	Message        string    `json:"message"`
+	Type           string    `json:"type"`
+	Count          int       `json:"count,omitempty"`
+	FirstTimestamp time.Time `json:"firstTimestamp"`
+	LastTimestamp  time.Time `json:"lastTimestamp"`
+	EventTime      time.Time `json:"eventTime"`
+	Source         struct {
+		Component string `json:"component,omitempty"`
+	} `json:"source"`
+}
+
+// namespaceObject is a namespace as the API returns it.
+type namespaceObject struct {
+	Metadata objectMeta `json:"metadata"`
+	Status   struct {
+		Phase string `json:"phase"`
+	} `json:"status"`
+}
+
+// Namespace is a namespace of a cluster.
+type Namespace struct {
+	Name    string            `json:"name"`
+	Status  string            `json:"status"`
+	Created time.Time         `json:"created"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+func newNamespace(object namespaceObject) Namespace {
+	return Namespace{
+		Name:    object.Metadata.Name,
+		Status:  object.Status.Phase,
+		Created: object.Metadata.CreationTimestamp,
+		Labels:  object.Metadata.Labels,
+	}
+}
+
+// Pod summarizes a pod the way kubectl get pods does. Containers and
+// conditions are only filled in for a single pod.
+type Pod struct {
+	Name       string            `json:"name"`
+	Namespace  string            `json:"namespace"`
+	Status     string            `json:"status"`
+	Ready      string            `json:"ready"`
+	Restarts   int               `json:"restarts"`
+	Node       string            `json:"node,omitempty"`
+	IP         string            `json:"ip,omitempty"`
+	Created    time.Time         `json:"created"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	Message    string            `json:"message,omitempty"`
+	Containers []Container       `json:"containers,omitempty"`
+	Conditions []Condition       `json:"conditions,omitempty"`
+}
+
+// Container is the status of a container of a pod.
+type Container struct {
+	Name            string       `json:"name"`
+	Image           string       `json:"image"`
+	Init            bool         `json:"init,omitempty"`
+	Ready           bool         `json:"ready"`
+	Restarts        int          `json:"restarts"`
+	State           string       `json:"state"`
+	Reason          string       `json:"reason,omitempty"`
+	Message         string       `json:"message,omitempty"`
+	ExitCode        *int         `json:"exit_code,omitempty"`
+	LastTermination *Termination `json:"last_termination,omitempty"`
+}
+
+// Termination describes how a container last exited.
+type Termination struct {
+	Reason     string    `json:"reason,omitempty"`
+	ExitCode   int       `json:"exit_code"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// Condition is a status condition of a pod or deployment.
+type Condition struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+func newPod(object podObject) Pod {
+	ready, restarts := 0, 0
+	for _, status := range object.Status.ContainerStatuses {
+		if status.Ready {
+			ready++
+		}
+		restarts += status.RestartCount
+	}
+
+	return Pod{
+		Name:      object.Metadata.Name,
+		Namespace: object.Metadata.Namespace,
+		Status:    podStatus(object),
+		Ready:     fmt.Sprintf("%d/%d", ready, len(object.Spec.Containers)),
+		Restarts:  restarts,
+		Node:      object.Spec.NodeName,
+		IP:        object.Status.PodIP,
+		Created:   object.Metadata.CreationTimestamp,
+		Labels:    object.Metadata.Labels,
+		Message:   object.Status.Message,
+	}
+}
+
+// newPodDetail summarizes a pod with its containers and conditions.
+func newPodDetail(object podObject) Pod {
+	pod := newPod(object)
+	for _, status := range object.Status.InitContainerStatuses {
+		container := newContainer(status)
+		container.Init = true
+		pod.Containers = append(pod.Containers, container)
+	}
+	for _, status := range object.Status.ContainerStatuses {
+		pod.Containers = append(pod.Containers, newContainer(status))
+	}
+	pod.Conditions = newConditions(object.Status.Conditions)
+
+	return pod
+}
+
+func newContainer(status containerStatusObject) Container {
+	container := Container{Name: status.Name, Image: status.Image, Ready: status.Ready, Restarts: status.RestartCount}
+	switch state := status.State; {
+	case state.Running != nil:
+		container.State = "running"
+	case state.Waiting != nil:
+		container.State = "waiting"
+		container.Reason, container.Message = state.Waiting.Reason, state.Waiting.Message
+	case state.Terminated != nil:
+		container.State = "terminated"
+		container.Reason, container.Message = state.Terminated.Reason, state.Terminated.Message
+		container.ExitCode = &state.Terminated.ExitCode
+	default:
+		container.State = "unknown"
+	}
+	if last := status.LastState.Terminated; last != nil {
+		container.LastTermination = &Termination{Reason: last.Reason, ExitCode: last.ExitCode, FinishedAt: last.FinishedAt}
+	}
+
+	return container
+}
+
+func newConditions(objects []conditionObject) []Condition {
+	conditions := make([]Condition, 0, len(objects))
+	for _, object := range objects {
+		conditions = append(conditions, Condition(object))
+	}
+
+	return conditions
+}
+
+// podStatus returns the status kubectl shows for a pod: the reason a
+// container is waiting or terminated, such as CrashLoopBackOff, otherwise the
+// pod's reason or phase.
+func podStatus(object podObject) string {
+	for _, status := range object.Status.InitContainerStatuses {
+		if status.State.Waiting != nil && status.State.Waiting.Reason != "" && status.State.Waiting.Reason != "PodInitializing" {
+			return "Init:" + status.State.Waiting.Reason
+		}
+		if terminated := status.State.Terminated; terminated != nil && terminated.ExitCode != 0 {
+			return "Init:" + firstNonEmpty(terminated.Reason, "Error")
+		}
+	}
+
+	status := firstNonEmpty(object.Status.Reason, object.Status.Phase)
+	for _, container := range object.Status.ContainerStatuses {
+		switch state := container.State; {
+		case state.Waiting != nil && state.Waiting.Reason != "":
+			return state.Waiting.Reason
+		case state.Terminated != nil && state.Terminated.Reason != "":
+			status = state.Terminated.Reason
+		}
+	}
+
+	return status
+}
+
+// Deployment summarizes a deployment.
+type Deployment struct {
+	Name       string            `json:"name"`
+	Namespace  string            `json:"namespace"`
+	Replicas   int               `json:"replicas"`
+	Ready      int               `json:"ready"`
+	UpToDate   int               `json:"up_to_date"`
+	Available  int               `json:"available"`
+	Paused     bool              `json:"paused,omitempty"`
+	Images     []string          `json:"images"`
+	Selector   map[string]string `json:"selector,omitempty"`
+	Created    time.Time         `json:"created"`
+	Conditions []Condition       `json:"conditions,omitempty"`
+}
+
+func newDeployment(object deploymentObject) Deployment {
+	deployment := Deployment{
+		Name:      object.Metadata.Name,
+		Namespace: object.Metadata.Namespace,
+		Replicas:  desiredReplicas(object),
+		Ready:     object.Status.ReadyReplicas,
+		UpToDate:  object.Status.UpdatedReplicas,
+		Available: object.Status.AvailableReplicas,
+		Paused:    object.Spec.Paused,
+		Images:    []string{},
+		Selector:  object.Spec.Selector.MatchLabels,
+		Created:   object.Metadata.CreationTimestamp,
+	}
+	for _, container := range object.Spec.Template.Spec.Containers {
+		deployment.Images = append(deployment.Images, container.Image)
+	}
+
+	return deployment
+}
+
+// newDeploymentDetail summarizes a deployment with its conditions.
+func newDeploymentDetail(object deploymentObject) Deployment {
+	deployment := newDeployment(object)
+	deployment.Conditions = newConditions(object.Status.Conditions)
+
+	return deployment
+}
+
+// desiredReplicas returns the replica count of a deployment's spec, which
+// defaults to 1.
+func desiredReplicas(object deploymentObject) int {
+	if object.Spec.Replicas == nil {
+		return 1
+	}
+
+	return *object.Spec.Replicas
+}
+
+// Service summarizes a service.
+type Service struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Type      string            `json:"type"`
+	ClusterIP string            `json:"cluster_ip,omitempty"`
+	External  []string          `json:"external,omitempty"`
+	Ports     []string          `json:"ports"`
+	Selector  map[string]string `json:"selector,omitempty"`
+	Created   time.Time         `json:"created"`
+}
+
+func newService(object serviceObject) Service {
+	service := Service{
+		Name:      object.Metadata.Name,
+		Namespace: object.Metadata.Namespace,
+		Type:      object.Spec.Type,
+		ClusterIP: object.Spec.ClusterIP,
+		External:  slices.Clone(object.Spec.ExternalIPs),
+		Ports:     []string{},
+		Selector:  object.Spec.Selector,
+		Created:   object.Metadata.CreationTimestamp,
+	}
+	for _, ingress := range object.Status.LoadBalancer.Ingress {
+		service.External = append(service.External, firstNonEmpty(ingress.IP, ingress.Hostname))
+	}
+	for _, port := range object.Spec.Ports {
+		text := strconv.Itoa(port.Port)
+		if port.NodePort != 0 {
+			text += ":" + strconv.Itoa(port.NodePort)
+		}
+		text += "/" + firstNonEmpty(port.Protocol, "TCP")
+		if port.TargetPort != nil && fmt.Sprint(port.TargetPort) != strconv.Itoa(port.Port) {
+			text += " -> " + fmt.Sprint(port.TargetPort)
+		}
+		service.Ports = append(service.Ports, text)
+	}
+
+	return service
+}
+
+// Event is an event of a cluster.
+type Event struct {
+	Namespace string    `json:"namespace,omitempty"`
+	Type      string    `json:"type"`
+	Reason    string    `json:"reason"`
+	Object    string    `json:"object"`
+	Message   string    `json:"message"`
+	Count     int       `json:"count"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+	Source    string    `json:"source,omitempty"`
+}
+
+func newEvent(object eventObject) Event {
+	event := Event{
+		Namespace: object.Metadata.Namespace,
+		Type:      object.Type,
+		Reason:    object.Reason,
+		Object:    object.InvolvedObject.Kind + "/" + object.InvolvedObject.Name,
+		Message:   object.Message,
+		Count:     max(object.Count, 1),
+		FirstSeen: object.FirstTimestamp,
+		LastSeen:  object.LastTimestamp,
+		Source:    object.Source.Component,
+	}
+	// Events recorded through events.k8s.io only carry an event time.
+	if event.LastSeen.IsZero() {
+		event.LastSeen = firstTime(object.EventTime, object.Metadata.CreationTimestamp)
+	}
+	if event.FirstSeen.IsZero() {
+		event.FirstSeen = event.LastSeen
+	}
+
+	return event
+}
+
+// firstNonEmpty returns the first non-empty value.
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// firstTime returns the first non-zero time.
+func firstTime(times ...time.Time) time.Time {
+	for _, t := range times {
+		if !t.IsZero() {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
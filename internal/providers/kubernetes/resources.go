@@ -0,0 +1,186 @@
+package kubernetes
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+)
+
+// Context is a kubeconfig context. Contexts are the provider's accounts.
+type Context struct {
+	Name      string `json:"name"`
+	Cluster   string `json:"cluster"`
+	Server    string `json:"server"`
+	User      string `json:"user"`
+	Namespace string `json:"namespace,omitempty"`
+	Active    bool   `json:"active"`
+}
+
+func (p *Provider) listContextsTool() *providerkit.Tool {
+	tool := mcp.NewTool("k8s_contexts_list",
+		mcp.WithDescription("Lists the kubeconfig contexts with their cluster, user and namespace. Each context is an account; switch with switch_account."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		cursors, req, err := providerkit.ParsePage(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		active := p.contextName(ctx)
+
+		contexts := make([]Context, 0, len(p.kubeconfig.Contexts))
+		for _, kubeContext := range p.kubeconfig.Contexts {
+			contexts = append(contexts, Context{
+				Name:      kubeContext.Name,
+				Cluster:   kubeContext.ClusterName,
+				Server:    kubeContext.Cluster.Server,
+				User:      kubeContext.UserName,
+				Namespace: kubeContext.Namespace,
+				Active:    kubeContext.Name == active,
+			})
+		}
+
+		return providerkit.Result(pagination.Slice(cursors, req, contexts), nil)
+	})
+}
+
+func (p *Provider) listNamespacesTool() *providerkit.Tool {
+	tool := mcp.NewTool("k8s_namespaces_list",
+		mcp.WithDescription("Lists the namespaces of the cluster"),
+		mcp.WithString("label_selector", mcp.Description("Label selector, such as team=payments (optional)")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		c, err := p.cluster(ctx)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+		query := url.Values{}
+		if selector := request.GetString("label_selector", ""); selector != "" {
+			query.Set("labelSelector", selector)
+		}
+
+		return providerkit.Result(list(ctx, c, request, namespaces.path(""), query, newNamespace))
+	})
+}
+
+func (p *Provider) listServicesTool() *providerkit.Tool {
+	tool := mcp.NewTool("k8s_services_list",
+		append(withListScope(),
+			mcp.WithDescription("Lists services with their type, cluster IP, external addresses and ports"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(true),
+			pagination.WithParams(),
+			format.WithParam(),
+		)...,
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		c, err := p.cluster(ctx)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+		namespace, query := c.listScope(request)
+
+		return providerkit.Result(list(ctx, c, request, services.path(namespace), query, newService))
+	})
+}
+
+func (p *Provider) listEventsTool() *providerkit.Tool {
+	tool := mcp.NewTool("k8s_events_list",
+		mcp.WithDescription("Lists events, newest first. Filter by the object they are about to see why a pod or deployment is failing."),
+		withNamespace(),
+		mcp.WithBoolean("all_namespaces", mcp.Description("List across all namespaces instead")),
+		mcp.WithString("object", mcp.Description("Only events about the object with this name (optional)")),
+		mcp.WithString("kind", mcp.Description("Only events about objects of this kind, such as Pod (optional)")),
+		mcp.WithBoolean("warnings_only", mcp.Description("Only Warning events")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		cursors, req, err := providerkit.ParsePage(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+		c, err := p.cluster(ctx)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		namespace := c.namespaceOf(request)
+		if request.GetBool("all_namespaces", false) {
+			namespace = ""
+		}
+		found, err := c.events(ctx, namespace, eventFilter{
+			Object:       request.GetString("object", ""),
+			Kind:         request.GetString("kind", ""),
+			WarningsOnly: request.GetBool("warnings_only", false),
+		})
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		return providerkit.Result(pagination.Slice(cursors, req, found), nil)
+	})
+}
+
+// eventFilter selects events by the object they are about and their type.
+type eventFilter struct {
+	Object       string
+	Kind         string
+	WarningsOnly bool
+}
+
+// events returns the events of a namespace, or of all namespaces when
+// namespace is empty, newest first. Events are sorted here since the API
+// returns them in no useful order.
+func (c *cluster) events(ctx context.Context, namespace string, filter eventFilter) ([]Event, error) {
+	query := url.Values{}
+	var selectors []string
+	if filter.Object != "" {
+		selectors = append(selectors, "involvedObject.name="+filter.Object)
+	}
+	if filter.Kind != "" {
+		selectors = append(selectors, "involvedObject.kind="+filter.Kind)
+	}
+	if filter.WarningsOnly {
+		selectors = append(selectors, "type=Warning")
+	}
+	if len(selectors) > 0 {
+		query.Set("fieldSelector", strings.Join(selectors, ","))
+	}
+
+	objects, err := all[eventObject](ctx, c, events.path(namespace), query)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make([]Event, 0, len(objects))
+	for _, object := range objects {
+		found = append(found, newEvent(object))
+	}
+	slices.SortStableFunc(found, func(a, b Event) int {
+		return b.LastSeen.Compare(a.LastSeen)
+	})
+
+	return found, nil
+}
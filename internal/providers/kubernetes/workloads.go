@@ -0,0 +1,407 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/idempotency"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+)
+
+const (
+	// defaultTailLines is how many log lines are returned by default.
+	defaultTailLines = 100
+
+	// maxTailLines bounds the log lines of one call.
+	maxTailLines = 5000
+
+	// maxLogBytes bounds the log bytes of one call.
+	maxLogBytes = 1 << 20
+
+	// restartedAtAnnotation is the pod template annotation kubectl rollout
+	// restart sets to roll a deployment's pods.
+	restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+	// defaultContainerAnnotation names the container kubectl logs defaults to.
+	defaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+
+	// mergePatch is the content type of JSON merge patches.
+	mergePatch = "application/merge-patch+json"
+)
+
+// Static errors for err113 compliance.
+var ErrInvalidSince = errors.New("since must be a duration such as 15m or an RFC 3339 time")
+
+func (p *Provider) listPodsTool() *providerkit.Tool {
+	tool := mcp.NewTool("k8s_pods_list",
+		append(withListScope(),
+			mcp.WithDescription("Lists pods with their status, readiness and restart count, like kubectl get pods"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(true),
+			pagination.WithParams(),
+			format.WithParam(),
+		)...,
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		c, err := p.cluster(ctx)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+		namespace, query := c.listScope(request)
+
+		return providerkit.Result(list(ctx, c, request, pods.path(namespace), query, newPod))
+	})
+}
+
+func (p *Provider) getPodTool() *providerkit.Tool {
+	tool := mcp.NewTool("k8s_pod_get",
+		mcp.WithDescription("Shows a pod with the state, restarts and last termination of each container and the pod's conditions"),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Pod name")),
+		withNamespace(),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, err := request.RequireString("name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		c, err := p.cluster(ctx)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		var pod podObject
+		err = c.api.Do(ctx, http.MethodGet, pods.object(c.namespaceOf(request), name), nil, nil, &pod)
+
+		return providerkit.Result(newPodDetail(pod), err)
+	})
+}
+
+// Logs are the last lines a container logged.
+type Logs struct {
+	Pod       string   `json:"pod"`
+	Namespace string   `json:"namespace"`
+	Container string   `json:"container"`
+	Previous  bool     `json:"previous,omitempty"`
+	Lines     []string `json:"lines"`
+}
+
+// logOptions selects the log lines to read.
+type logOptions struct {
+	Container string
+	TailLines int
+	Since     string
+	Previous  bool
+}
+
+func (p *Provider) podLogsTool() *providerkit.Tool {
+	tool := mcp.NewTool("k8s_pod_logs",
+		mcp.WithDescription("Returns the last log lines of a pod's container. Use previous to read the logs of the container's last run, such as before a crash."),
+		mcp.WithString("pod", mcp.Required(), mcp.Description("Pod name")),
+		withNamespace(),
+		mcp.WithString("container", mcp.Description("Container name (default: the pod's default container, else its first)")),
+		mcp.WithNumber("tail_lines", mcp.Description("Number of lines from the end of the log (default 100)"), mcp.Min(1), mcp.Max(maxTailLines)),
+		mcp.WithString("since", mcp.Description("Only lines newer than a duration such as 15m, or an RFC 3339 time (optional)")),
+		mcp.WithBoolean("previous", mcp.Description("Read the logs of the previous, terminated run of the container")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, err := request.RequireString("pod")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		options := logOptions{
+			Container: request.GetString("container", ""),
+			TailLines: min(max(request.GetInt("tail_lines", defaultTailLines), 1), maxTailLines),
+			Since:     request.GetString("since", ""),
+			Previous:  request.GetBool("previous", false),
+		}
+		if _, err := sinceQuery(options.Since, time.Now()); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		c, err := p.cluster(ctx)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		return providerkit.Result(c.logs(ctx, c.namespaceOf(request), name, options))
+	})
+}
+
+// logs reads the log lines of a pod's container. Without a container name,
+// the pod's default container is read.
+func (c *cluster) logs(ctx context.Context, namespace, name string, options logOptions) (Logs, error) {
+	if options.Container == "" {
+		var pod podObject
+		if err := c.api.Do(ctx, http.MethodGet, pods.object(namespace, name), nil, nil, &pod); err != nil {
+			return Logs{}, err
+		}
+		options.Container = defaultContainer(pod)
+	}
+
+	query, err := sinceQuery(options.Since, time.Now())
+	if err != nil {
+		return Logs{}, err
+	}
+	query.Set("container", options.Container)
+	query.Set("tailLines", strconv.Itoa(options.TailLines))
+	query.Set("limitBytes", strconv.Itoa(maxLogBytes))
+	if options.Previous {
+		query.Set("previous", "true")
+	}
+
+	var raw []byte
+	_, err = c.api.Call(ctx, providerkit.Call{
+		Method: http.MethodGet,
+		Path:   pods.object(namespace, name) + "/log",
+		Query:  query,
+		Raw:    &raw,
+	})
+	if err != nil {
+		return Logs{}, err
+	}
+
+	lines := []string{}
+	if text := strings.TrimRight(string(raw), "\n"); text != "" {
+		lines = strings.Split(text, "\n")
+	}
+
+	return Logs{Pod: name, Namespace: namespace, Container: options.Container, Previous: options.Previous, Lines: lines}, nil
+}
+
+// defaultContainer returns the container kubectl logs reads by default.
+func defaultContainer(pod podObject) string {
+	if name := pod.Metadata.Annotations[defaultContainerAnnotation]; name != "" {
+		return name
+	}
+	if len(pod.Spec.Containers) > 0 {
+		return pod.Spec.Containers[0].Name
+	}
+
+	return ""
+}
+
+// sinceQuery converts a since argument into the sinceSeconds or sinceTime
+// query parameter of a log request.
+func sinceQuery(since string, now time.Time) (url.Values, error) {
+	query := url.Values{}
+	if since == "" {
+		return query, nil
+	}
+	if duration, err := time.ParseDuration(since); err == nil && duration > 0 {
+		query.Set("sinceSeconds", strconv.Itoa(max(int(duration.Seconds()), 1)))
+		return query, nil
+	}
+	if t, err := time.Parse(time.RFC3339, since); err == nil && t.Before(now) {
+		query.Set("sinceTime", t.UTC().Format(time.RFC3339))
+		return query, nil
+	}
+
+	return nil, fmt.Errorf("%w, got %q", ErrInvalidSince, since)
+}
+
+func (p *Provider) listDeploymentsTool() *providerkit.Tool {
+	tool := mcp.NewTool("k8s_deployments_list",
+		append(withListScope(),
+			mcp.WithDescription("Lists deployments with their desired, ready, up-to-date and available replicas"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(true),
+			pagination.WithParams(),
+			format.WithParam(),
+		)...,
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		c, err := p.cluster(ctx)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+		namespace, query := c.listScope(request)
+
+		return providerkit.Result(list(ctx, c, request, deployments.path(namespace), query, newDeployment))
+	})
+}
+
+func (p *Provider) getDeploymentTool() *providerkit.Tool {
+	tool := mcp.NewTool("k8s_deployment_get",
+		mcp.WithDescription("Shows a deployment with its replica counts and conditions"),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Deployment name")),
+		withNamespace(),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, err := request.RequireString("name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		c, err := p.cluster(ctx)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		var deployment deploymentObject
+		err = c.api.Do(ctx, http.MethodGet, deployments.object(c.namespaceOf(request), name), nil, nil, &deployment)
+
+		return providerkit.Result(newDeploymentDetail(deployment), err)
+	})
+}
+
+func (p *Provider) restartDeploymentTool() *providerkit.Tool {
+	tool := mcp.NewTool("k8s_deployment_restart",
+		mcp.WithDescription("Restarts a deployment's pods with a rolling update, like kubectl rollout restart"),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Deployment name")),
+		withNamespace(),
+		providerkit.WithConfirmParam("the deployment name"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		providerkit.WithWaitParam(),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, err := request.RequireString("name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if refused := providerkit.Confirm(request, "restarted", "the deployment name", name); refused != nil {
+			return refused, nil
+		}
+		c, err := p.cluster(ctx)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		patch := map[string]any{"spec": map[string]any{"template": map[string]any{"metadata": map[string]any{
+			"annotations": map[string]string{restartedAtAnnotation: time.Now().UTC().Format(time.RFC3339)},
+		}}}}
+
+		return providerkit.Result(p.patchDeployment(ctx, request, c, deployments.object(c.namespaceOf(request), name), patch))
+	})
+}
+
+func (p *Provider) scaleDeploymentTool() *providerkit.Tool {
+	tool := mcp.NewTool("k8s_deployment_scale",
+		mcp.WithDescription("Sets the number of replicas of a deployment"),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Deployment name")),
+		withNamespace(),
+		mcp.WithNumber("replicas", mcp.Required(), mcp.Description("Desired number of replicas; 0 stops all pods"), mcp.Min(0)),
+		providerkit.WithConfirmParam("the deployment name"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		providerkit.WithWaitParam(),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, err := request.RequireString("name")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		replicas, err := request.RequireInt("replicas")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if replicas < 0 {
+			return mcp.NewToolResultError("replicas must not be negative"), nil
+		}
+		if refused := providerkit.Confirm(request, "scaled", "the deployment name", name); refused != nil {
+			return refused, nil
+		}
+		c, err := p.cluster(ctx)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		patch := map[string]any{"spec": map[string]any{"replicas": replicas}}
+
+		return providerkit.Result(p.patchDeployment(ctx, request, c, deployments.object(c.namespaceOf(request), name)+"/scale", patch))
+	})
+}
+
+// patchDeployment applies a merge patch to a deployment or its scale
+// subresource and, unless wait is false, waits for the rollout to finish.
+func (p *Provider) patchDeployment(ctx context.Context, request mcp.CallToolRequest, c *cluster, path string, patch any) (Deployment, error) {
+	_, err := c.api.Call(ctx, providerkit.Call{
+		Method: http.MethodPatch,
+		Path:   path,
+		Header: http.Header{"Content-Type": {mergePatch}},
+		Body:   patch,
+	})
+	if err != nil {
+		return Deployment{}, err
+	}
+
+	deploymentPath := strings.TrimSuffix(path, "/scale")
+	var deployment deploymentObject
+	if !request.GetBool(providerkit.WaitParam, true) {
+		err := c.api.Do(ctx, http.MethodGet, deploymentPath, nil, nil, &deployment)
+		return newDeploymentDetail(deployment), err
+	}
+
+	_, err = providerkit.Wait(ctx, p.pollInterval, func(ctx context.Context) (providerkit.ActionStatus, error) {
+		if err := c.api.Do(ctx, http.MethodGet, deploymentPath, nil, nil, &deployment); err != nil {
+			return providerkit.ActionStatus{}, err
+		}
+		return rolloutStatus(deployment), nil
+	})
+
+	return newDeploymentDetail(deployment), err
+}
+
+// rolloutStatus reports the progress of a deployment's rollout the way
+// kubectl rollout status does.
+func rolloutStatus(deployment deploymentObject) providerkit.ActionStatus {
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == "Progressing" && condition.Reason == "ProgressDeadlineExceeded" {
+			return providerkit.ActionStatus{Done: true, Failed: true, Message: condition.Message}
+		}
+	}
+
+	desired := desiredReplicas(deployment)
+	status := deployment.Status
+	if status.ObservedGeneration < deployment.Metadata.Generation {
+		return providerkit.ActionStatus{Message: "waiting for the rollout to start"}
+	}
+
+	percent := 100.0
+	if desired > 0 {
+		percent = float64(min(status.UpdatedReplicas, desired)) / float64(desired) * 100
+	}
+	switch {
+	case status.UpdatedReplicas < desired:
+		return providerkit.ActionStatus{Percent: percent,
+			Message: fmt.Sprintf("%d of %d replicas updated", status.UpdatedReplicas, desired)}
+	case status.Replicas > status.UpdatedReplicas:
+		return providerkit.ActionStatus{Percent: percent,
+			Message: fmt.Sprintf("%d old replicas pending termination", status.Replicas-status.UpdatedReplicas)}
+	case status.AvailableReplicas < status.UpdatedReplicas:
+		return providerkit.ActionStatus{Percent: percent,
+			Message: fmt.Sprintf("%d of %d updated replicas available", status.AvailableReplicas, status.UpdatedReplicas)}
+	}
+
+	return providerkit.ActionStatus{Done: true, Percent: 100, Message: "rollout complete"}
+}
@@ -0,0 +1,113 @@
+// Package linode implements the Linode API v4 provider.
+package linode
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	// Name is the provider name used in settings and accounts.
+	Name = "linode"
+
+	// DefaultAPIURL is the Linode API v4 endpoint, overridden by the api_url setting.
+	DefaultAPIURL = "https://api.linode.com/v4"
+
+	// DefaultTokenRef is the credential used when neither the account nor the
+	// token_ref setting names one.
+	DefaultTokenRef = "env:LINODE_TOKEN"
+
+	// minPageSize and maxPageSize bound the page_size the API accepts.
+	minPageSize = 25
+	maxPageSize = 500
+)
+
+// Provider is the Linode provider.
+type Provider struct {
+	cfg contracts.ProviderConfig
+	api *providerkit.API
+}
+
+// New creates an uninitialized Linode provider.
+func New() contracts.Provider {
+	return &Provider{}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return Name
+}
+
+// Initialize configures the Linode API client.
+func (p *Provider) Initialize(_ context.Context, cfg contracts.ProviderConfig) error {
+	p.cfg = cfg
+	p.api = providerkit.NewTokenAPI(cfg, providerkit.TokenAPI{
+		Provider:        Name,
+		DefaultURL:      DefaultAPIURL,
+		DefaultTokenRef: DefaultTokenRef,
+		ErrorMessage:    errorMessage,
+	})
+
+	return nil
+}
+
+// Tools returns the Linode tools.
+func (p *Provider) Tools() []contracts.Tool {
+	return []contracts.Tool{
+		p.listInstancesTool(),
+		p.getInstanceTool(),
+		p.createInstanceTool(),
+		p.deleteInstanceTool(),
+		p.instanceActionTool("reboot", "Reboots a Linode instance", true),
+		p.instanceActionTool("boot", "Boots a stopped Linode instance", false),
+		p.instanceActionTool("shutdown", "Shuts down a running Linode instance", true),
+		p.resizeInstanceTool(),
+		p.listRegionsTool(),
+		p.listTypesTool(),
+		p.listImagesTool(),
+	}
+}
+
+// HealthCheck verifies the default account's token by reading its profile.
+func (p *Provider) HealthCheck(ctx context.Context) error {
+	ctx = providerkit.DefaultAccountContext(ctx, p.cfg)
+
+	return p.api.Do(ctx, http.MethodGet, "/profile", nil, nil, nil)
+}
+
+// Shutdown has nothing to release.
+func (p *Provider) Shutdown(context.Context) error {
+	return nil
+}
+
+// apiErrors is the body of a Linode error response.
+type apiErrors struct {
+	Errors []struct {
+		Field  string `json:"field"`
+		Reason string `json:"reason"`
+	} `json:"errors"`
+}
+
+// errorMessage joins the reasons of a Linode error response.
+func errorMessage(body []byte) string {
+	var decoded apiErrors
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return ""
+	}
+
+	reasons := make([]string, 0, len(decoded.Errors))
+	for _, e := range decoded.Errors {
+		if e.Field != "" {
+			reasons = append(reasons, e.Field+": "+e.Reason)
+			continue
+		}
+		reasons = append(reasons, e.Reason)
+	}
+
+	return strings.Join(reasons, "; ")
+}
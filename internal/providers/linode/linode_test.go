@@ -0,0 +1,234 @@
+package linode_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/internal/providerkit/providerkittest"
+	"github.com/chadit/CloudMCP/internal/providers/linode"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+// The prod account is the default; staging uses its own token.
+var (
+	prod    = contracts.Account{Provider: linode.Name, Alias: "prod", Credential: "linode-prod", Region: "us-east", Default: true}
+	staging = contracts.Account{Provider: linode.Name, Alias: "staging", Credential: "linode-staging", Region: "us-east"}
+)
+
+// fakeAPI is an in-memory stand-in for the Linode API v4.
+type fakeAPI struct {
+	mu        sync.Mutex
+	instances []linode.Instance
+	requests  []string
+	created   map[string]any
+	filters   []string
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+	if r.Header.Get("Authorization") != "Bearer prod-token" && r.Header.Get("Authorization") != "Bearer staging-token" {
+		writeError(w, http.StatusUnauthorized, "", "Invalid Token")
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/profile":
+		providerkittest.WriteJSON(w, http.StatusOK, map[string]any{"username": "tester"})
+	case r.Method == http.MethodGet && r.URL.Path == "/linode/instances":
+		f.filters = append(f.filters, r.Header.Get("X-Filter"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		size, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+		if size < 25 || size > 500 {
+			writeError(w, http.StatusBadRequest, "page_size", "Must be 25-500")
+			return
+		}
+		start := min((page-1)*size, len(f.instances))
+		end := min(start+size, len(f.instances))
+		pages := max((len(f.instances)+size-1)/size, 1)
+		providerkittest.WriteJSON(w, http.StatusOK, map[string]any{"data": f.instances[start:end], "page": page, "pages": pages, "results": len(f.instances)})
+	case r.Method == http.MethodPost && r.URL.Path == "/linode/instances":
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &f.created)
+		if f.created["type"] == "g6-bogus" {
+			writeError(w, http.StatusBadRequest, "type", "A valid plan type by that ID was not found")
+			return
+		}
+		providerkittest.WriteJSON(w, http.StatusOK, linode.Instance{ID: 900, Label: "web", Status: "provisioning", Region: "us-east", Type: "g6-nanode-1"})
+	case r.URL.Path == "/linode/instances/7/reboot", r.URL.Path == "/linode/instances/7/resize":
+		providerkittest.WriteJSON(w, http.StatusOK, map[string]any{})
+	case strings.HasPrefix(r.URL.Path, "/linode/instances/"):
+		writeError(w, http.StatusNotFound, "", "Not found")
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, field, reason string) {
+	providerkittest.WriteJSON(w, status, map[string]any{"errors": []map[string]string{{"field": field, "reason": reason}}})
+}
+
+// setup starts a fake API and returns an initialized provider's tools by name.
+func setup(t *testing.T, instances int) (*fakeAPI, contracts.Provider, map[string]contracts.Tool) {
+	t.Helper()
+
+	api := &fakeAPI{}
+	for i := 1; i <= instances; i++ {
+		api.instances = append(api.instances, linode.Instance{ID: i, Label: fmt.Sprintf("node-%d", i), Region: "us-east"})
+	}
+	srv := providerkittest.Serve(t, api)
+
+	provider := linode.New()
+	tools := providerkittest.Setup(t, provider, contracts.ProviderConfig{
+		Settings:   map[string]string{"api_url": srv.URL},
+		HTTPClient: srv.Client(),
+		Secrets:    providerkittest.Secrets{"linode-prod": "prod-token", "linode-staging": "staging-token", "root-pass": "s3cret-root-pass"},
+		Accounts:   []contracts.Account{prod},
+	})
+
+	return api, provider, tools
+}
+
+func TestProvider_ToolsAndHealth(t *testing.T) {
+	t.Parallel()
+
+	api, provider, tools := setup(t, 0)
+	for _, name := range []string{
+		"linode_instances_list", "linode_instance_get", "linode_instance_create", "linode_instance_delete",
+		"linode_instance_reboot", "linode_instance_boot", "linode_instance_shutdown", "linode_instance_resize",
+		"linode_regions_list", "linode_types_list", "linode_images_list",
+	} {
+		require.Contains(t, tools, name)
+	}
+
+	definer, ok := tools["linode_instance_delete"].(interface{ Definition() mcp.Tool })
+	require.True(t, ok)
+	require.True(t, *definer.Definition().Annotations.DestructiveHint)
+
+	require.NoError(t, provider.HealthCheck(t.Context()), "health uses the default account")
+	require.Equal(t, []string{"GET /profile"}, api.requests)
+}
+
+func TestInstancesList_PaginatesAcrossProviderPages(t *testing.T) {
+	t.Parallel()
+
+	api, _, tools := setup(t, 60)
+	ctx := providerkittest.AccountContext(t, prod)
+	list := tools["linode_instances_list"]
+
+	var ids []int
+	params := map[string]any{"limit": float64(20), "region": "us-east"}
+	for range 10 {
+		var page pagination.Page[linode.Instance]
+		require.NoError(t, json.Unmarshal([]byte(providerkittest.CallOK(ctx, t, list, params)), &page))
+		require.LessOrEqual(t, len(page.Items), 20)
+		for _, instance := range page.Items {
+			ids = append(ids, instance.ID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		params["cursor"] = page.NextCursor
+	}
+
+	require.Len(t, ids, 60)
+	for i, id := range ids {
+		require.Equal(t, i+1, id, "no instance is skipped or repeated")
+	}
+	require.Equal(t, `{"region":"us-east"}`, api.filters[0])
+
+	// A cursor is bound to the account it was issued for
+	other := providerkittest.AccountContext(t, staging)
+	result, err := list.Execute(other, params)
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+}
+
+func TestInstanceActions(t *testing.T) {
+	t.Parallel()
+
+	api, _, tools := setup(t, 0)
+	ctx := providerkittest.AccountContext(t, prod)
+
+	text := providerkittest.CallOK(ctx, t, tools["linode_instance_create"], map[string]any{
+		"type": "g6-nanode-1", "image": "linode/debian12", "label": "web", "root_pass_ref": "root-pass",
+	})
+	require.Contains(t, text, `"id": 900`)
+	require.Equal(t, "us-east", api.created["region"], "the account region is the default")
+	require.Equal(t, "s3cret-root-pass", api.created["root_pass"])
+	require.NotContains(t, text, "s3cret-root-pass")
+
+	providerkittest.CallOK(ctx, t, tools["linode_instance_create"], map[string]any{"type": "g6-nanode-1", "image": "linode/debian12"})
+	require.Len(t, api.created["root_pass"], 52, "a random root password is generated")
+
+	text = providerkittest.CallOK(ctx, t, tools["linode_instance_reboot"], map[string]any{"instance_id": float64(7)})
+	require.Contains(t, text, `"action": "reboot"`)
+
+	providerkittest.CallOK(ctx, t, tools["linode_instance_resize"], map[string]any{"instance_id": float64(7), "type": "g6-standard-2"})
+	require.Contains(t, api.requests, "POST /linode/instances/7/resize")
+}
+
+func TestErrors_MappedFromAPIResponses(t *testing.T) {
+	t.Parallel()
+
+	_, _, tools := setup(t, 0)
+	ctx := providerkittest.AccountContext(t, prod)
+
+	tests := []struct {
+		name   string
+		tool   string
+		ctx    context.Context
+		params map[string]any
+		want   error
+		reason string
+	}{
+		{"not found", "linode_instance_get", ctx, map[string]any{"instance_id": float64(42)}, providerkit.ErrNotFound, "Not found"},
+		{"validation", "linode_instance_create", ctx, map[string]any{"type": "g6-bogus"}, providerkit.ErrInvalidRequest, "type: A valid plan type"},
+		{"bad token", "linode_instance_get", providerkittest.AccountContext(t, contracts.Account{Provider: linode.Name, Alias: "other", Credential: "linode-prod-typo"}), map[string]any{"instance_id": float64(1)}, providerkit.ErrNoCredential, ""},
+		{"missing id", "linode_instance_boot", ctx, map[string]any{}, nil, "instance_id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := tools[tt.tool].Execute(tt.ctx, tt.params)
+			require.NoError(t, err)
+			require.True(t, result.IsError)
+			text := result.Content[0].(mcp.TextContent).Text
+			if tt.want != nil {
+				require.Contains(t, text, tt.want.Error())
+			}
+			require.Contains(t, text, tt.reason)
+		})
+	}
+}
+
+func TestAPIError_Unwrap(t *testing.T) {
+	t.Parallel()
+
+	for status, want := range map[int]error{
+		http.StatusUnauthorized:        providerkit.ErrUnauthorized,
+		http.StatusForbidden:           providerkit.ErrForbidden,
+		http.StatusNotFound:            providerkit.ErrNotFound,
+		http.StatusTooManyRequests:     providerkit.ErrRateLimited,
+		http.StatusUnprocessableEntity: providerkit.ErrInvalidRequest,
+		http.StatusBadGateway:          providerkit.ErrUpstream,
+	} {
+		err := &providerkit.APIError{Provider: "linode", Status: status}
+		require.ErrorIs(t, err, want, "status %d", status)
+	}
+}
@@ -0,0 +1,416 @@
+package linode
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/idempotency"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+)
+
+// Instance is a Linode instance.
+type Instance struct {
+	ID      int      `json:"id"`
+	Label   string   `json:"label"`
+	Status  string   `json:"status"`
+	Region  string   `json:"region"`
+	Type    string   `json:"type"`
+	Image   string   `json:"image"`
+	IPv4    []string `json:"ipv4"`
+	IPv6    string   `json:"ipv6"`
+	Tags    []string `json:"tags"`
+	Created string   `json:"created"`
+	Updated string   `json:"updated"`
+}
+
+// Region is a Linode region.
+type Region struct {
+	ID           string   `json:"id"`
+	Label        string   `json:"label"`
+	Country      string   `json:"country"`
+	Status       string   `json:"status"`
+	Capabilities []string `json:"capabilities"`
+}
+
+// Type is a Linode instance type.
+//
+//nolint:tagliatelle // JSON field names match the Linode API.
+type Type struct {
+	ID       string  `json:"id"`
+	Label    string  `json:"label"`
+	Class    string  `json:"class"`
+	VCPUs    int     `json:"vcpus"`
+	Memory   int     `json:"memory"`
+	Disk     int     `json:"disk"`
+	Transfer int     `json:"transfer"`
+	Price    Pricing `json:"price"`
+}
+
+// Pricing is the price of an instance type in US dollars.
+type Pricing struct {
+	Hourly  float64 `json:"hourly"`
+	Monthly float64 `json:"monthly"`
+}
+
+// Image is a Linode image.
+//
+//nolint:tagliatelle // JSON field names match the Linode API.
+type Image struct {
+	ID         string `json:"id"`
+	Label      string `json:"label"`
+	Vendor     string `json:"vendor"`
+	Type       string `json:"type"`
+	Status     string `json:"status"`
+	Size       int    `json:"size"`
+	IsPublic   bool   `json:"is_public"`
+	Deprecated bool   `json:"deprecated"`
+}
+
+// actionResult reports an accepted instance action.
+//
+//nolint:tagliatelle // JSON field names maintain API compatibility with snake_case.
+type actionResult struct {
+	InstanceID int    `json:"instance_id"`
+	Action     string `json:"action"`
+	Status     string `json:"status"`
+	Type       string `json:"type,omitempty"`
+}
+
+// listResponse is a page of a Linode collection.
+type listResponse[T any] struct {
+	Data  []T `json:"data"`
+	Page  int `json:"page"`
+	Pages int `json:"pages"`
+}
+
+// list fetches one page of a Linode collection. filter becomes the X-Filter
+// header when not empty.
+func list[T any](ctx context.Context, p *Provider, request mcp.CallToolRequest, path string, filter map[string]any) (pagination.Page[T], error) {
+	cursors, req, err := providerkit.ParsePage(ctx, request)
+	if err != nil {
+		return pagination.Page[T]{}, err
+	}
+
+	page, size := req.ProviderPage(minPageSize, maxPageSize)
+	query := url.Values{
+		"page":      {strconv.Itoa(page)},
+		"page_size": {strconv.Itoa(size)},
+	}
+	header := http.Header{}
+	if len(filter) > 0 {
+		data, err := json.Marshal(filter)
+		if err != nil {
+			return pagination.Page[T]{}, fmt.Errorf("failed to encode filter: %w", err)
+		}
+		header.Set("X-Filter", string(data))
+	}
+
+	var resp listResponse[T]
+	if _, err := p.api.Call(ctx, providerkit.Call{Method: http.MethodGet, Path: path, Query: query, Header: header, Out: &resp}); err != nil {
+		return pagination.Page[T]{}, err
+	}
+	if resp.Data == nil {
+		resp.Data = []T{}
+	}
+
+	return pagination.FromProviderPage(cursors, req, size, resp.Data, resp.Page < resp.Pages), nil
+}
+
+// instancePath returns the API path of the instance named by the instance_id argument.
+func instancePath(request mcp.CallToolRequest, suffix string) (int, string, error) {
+	id, err := request.RequireInt("instance_id")
+	if err != nil {
+		return 0, "", err
+	}
+
+	return id, "/linode/instances/" + strconv.Itoa(id) + suffix, nil
+}
+
+// withInstanceID adds the required instance_id argument.
+func withInstanceID() mcp.ToolOption {
+	return mcp.WithNumber("instance_id",
+		mcp.Required(),
+		mcp.Description("ID of the Linode instance"),
+	)
+}
+
+// stringItems is the item schema of string array arguments.
+var stringItems = map[string]any{"type": "string"}
+
+func (p *Provider) listInstancesTool() *providerkit.Tool {
+	tool := mcp.NewTool("linode_instances_list",
+		mcp.WithDescription("Lists Linode instances"),
+		mcp.WithString("region", mcp.Description("Only list instances in this region (optional)")),
+		mcp.WithString("tag", mcp.Description("Only list instances with this tag (optional)")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filter := map[string]any{}
+		if region := request.GetString("region", ""); region != "" {
+			filter["region"] = region
+		}
+		if tag := request.GetString("tag", ""); tag != "" {
+			filter["tags"] = tag
+		}
+
+		return providerkit.Result(list[Instance](ctx, p, request, "/linode/instances", filter))
+	})
+}
+
+func (p *Provider) getInstanceTool() *providerkit.Tool {
+	tool := mcp.NewTool("linode_instance_get",
+		mcp.WithDescription("Returns a Linode instance"),
+		withInstanceID(),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		_, path, err := instancePath(request, "")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		var instance Instance
+		err = p.api.Do(ctx, http.MethodGet, path, nil, nil, &instance)
+
+		return providerkit.Result(instance, err)
+	})
+}
+
+// createRequest is the body of an instance create call.
+//
+//nolint:tagliatelle // JSON field names match the Linode API.
+type createRequest struct {
+	Region         string   `json:"region"`
+	Type           string   `json:"type"`
+	Image          string   `json:"image,omitempty"`
+	Label          string   `json:"label,omitempty"`
+	RootPass       string   `json:"root_pass,omitempty"`
+	AuthorizedKeys []string `json:"authorized_keys,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+}
+
+func (p *Provider) createInstanceTool() *providerkit.Tool {
+	tool := mcp.NewTool("linode_instance_create",
+		mcp.WithDescription("Creates a Linode instance. Without root_pass_ref a random root password is set and never returned, so pass authorized_keys for SSH access."),
+		mcp.WithString("region", mcp.Description("Region to create the instance in (default: the account's region)")),
+		mcp.WithString("type", mcp.Required(), mcp.Description("Instance type, such as g6-nanode-1")),
+		mcp.WithString("image", mcp.Description("Image to deploy, such as linode/debian12 (optional)")),
+		mcp.WithString("label", mcp.Description("Instance label (optional)")),
+		mcp.WithString("root_pass_ref", mcp.Description("Credential reference holding the root password (optional)")),
+		mcp.WithArray("authorized_keys", mcp.Items(stringItems), mcp.Description("SSH public keys for root (optional)")),
+		mcp.WithArray("tags", mcp.Items(stringItems), mcp.Description("Tags to apply (optional)")),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		instanceType, err := request.RequireString("type")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		region := providerkit.Region(ctx, request, "")
+		if region == "" {
+			return mcp.NewToolResultError("region is required when the account has no default region"), nil
+		}
+
+		body := createRequest{
+			Region:         region,
+			Type:           instanceType,
+			Image:          request.GetString("image", ""),
+			Label:          request.GetString("label", ""),
+			AuthorizedKeys: request.GetStringSlice("authorized_keys", nil),
+			Tags:           request.GetStringSlice("tags", nil),
+		}
+		if body.Image != "" {
+			if body.RootPass, err = p.rootPassword(ctx, request.GetString("root_pass_ref", "")); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+
+		var instance Instance
+		err = p.api.Do(ctx, http.MethodPost, "/linode/instances", nil, body, &instance)
+
+		return providerkit.Result(instance, err)
+	})
+}
+
+// rootPassword resolves ref, or generates a random password if ref is empty.
+func (p *Provider) rootPassword(ctx context.Context, ref string) (string, error) {
+	if ref == "" {
+		return rand.Text() + rand.Text(), nil
+	}
+	if p.cfg.Secrets == nil {
+		return "", providerkit.ErrNoSecrets
+	}
+
+	secret, err := p.cfg.Secrets.Resolve(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve root password: %w", err)
+	}
+
+	return secret.Reveal(), nil
+}
+
+func (p *Provider) deleteInstanceTool() *providerkit.Tool {
+	tool := mcp.NewTool("linode_instance_delete",
+		mcp.WithDescription("Deletes a Linode instance and all of its disks. This cannot be undone."),
+		withInstanceID(),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id, path, err := instancePath(request, "")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		err = p.api.Do(ctx, http.MethodDelete, path, nil, nil, nil)
+
+		return providerkit.Result(actionResult{InstanceID: id, Action: "delete", Status: "deleted"}, err)
+	})
+}
+
+// instanceActionTool creates a tool posting a power action for an instance.
+func (p *Provider) instanceActionTool(action, description string, destructive bool) *providerkit.Tool {
+	tool := mcp.NewTool("linode_instance_"+action,
+		mcp.WithDescription(description),
+		withInstanceID(),
+		mcp.WithDestructiveHintAnnotation(destructive),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id, path, err := instancePath(request, "/"+action)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		err = p.api.Do(ctx, http.MethodPost, path, nil, struct{}{}, nil)
+
+		return providerkit.Result(actionResult{InstanceID: id, Action: action, Status: "requested"}, err)
+	})
+}
+
+// resizeRequest is the body of an instance resize call.
+//
+//nolint:tagliatelle // JSON field names match the Linode API.
+type resizeRequest struct {
+	Type                string `json:"type"`
+	AllowAutoDiskResize bool   `json:"allow_auto_disk_resize"`
+}
+
+func (p *Provider) resizeInstanceTool() *providerkit.Tool {
+	tool := mcp.NewTool("linode_instance_resize",
+		mcp.WithDescription("Resizes a Linode instance to another type. The instance is shut down and migrated, which takes several minutes."),
+		withInstanceID(),
+		mcp.WithString("type", mcp.Required(), mcp.Description("New instance type")),
+		mcp.WithBoolean("allow_auto_disk_resize",
+			mcp.Description("Grow the primary disk to use the new type's storage (default true)"),
+			mcp.DefaultBool(true),
+		),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id, path, err := instancePath(request, "/resize")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		instanceType, err := request.RequireString("type")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		body := resizeRequest{Type: instanceType, AllowAutoDiskResize: request.GetBool("allow_auto_disk_resize", true)}
+		err = p.api.Do(ctx, http.MethodPost, path, nil, body, nil)
+
+		return providerkit.Result(actionResult{InstanceID: id, Action: "resize", Status: "requested", Type: instanceType}, err)
+	})
+}
+
+func (p *Provider) listRegionsTool() *providerkit.Tool {
+	tool := mcp.NewTool("linode_regions_list",
+		mcp.WithDescription("Lists Linode regions"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return providerkit.Result(list[Region](ctx, p, request, "/regions", nil))
+	})
+}
+
+func (p *Provider) listTypesTool() *providerkit.Tool {
+	tool := mcp.NewTool("linode_types_list",
+		mcp.WithDescription("Lists Linode instance types with their size and price"),
+		mcp.WithString("class",
+			mcp.Description("Only list types of this class (optional)"),
+			mcp.Enum("nanode", "standard", "dedicated", "highmem", "premium", "gpu"),
+		),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var filter map[string]any
+		if class := request.GetString("class", ""); class != "" {
+			filter = map[string]any{"class": class}
+		}
+
+		return providerkit.Result(list[Type](ctx, p, request, "/linode/types", filter))
+	})
+}
+
+func (p *Provider) listImagesTool() *providerkit.Tool {
+	tool := mcp.NewTool("linode_images_list",
+		mcp.WithDescription("Lists Linode images available to the account, public and private"),
+		mcp.WithBoolean("public", mcp.Description("Only list public (true) or private (false) images (optional)")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var filter map[string]any
+		if public, ok := request.GetArguments()["public"].(bool); ok {
+			filter = map[string]any{"is_public": public}
+		}
+
+		return providerkit.Result(list[Image](ctx, p, request, "/images", filter))
+	})
+}
@@ -0,0 +1,287 @@
+// Package openapi implements a generic provider that turns the operations of
+// an OpenAPI 3 document into tools, so REST APIs without a dedicated provider,
+// such as internal platform APIs, can be brought in with configuration alone.
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	// Name is the provider name. Further APIs are enabled as named instances
+	// such as openapi-billing, each configured by its own settings.
+	Name = "openapi"
+
+	// DefaultAuthHeader is the header an API key is sent in with auth=header.
+	DefaultAuthHeader = "X-API-Key"
+
+	// DefaultAuthQuery is the query parameter an API key is sent in with
+	// auth=query.
+	DefaultAuthQuery = "api_key"
+)
+
+// Authentication schemes of the auth setting.
+const (
+	authNone   = "none"
+	authBearer = "bearer"
+	authHeader = "header"
+	authQuery  = "query"
+	authBasic  = "basic"
+)
+
+// Static errors for err113 compliance.
+var (
+	ErrNoBaseURL   = errors.New("the OpenAPI document has no absolute server URL, set the base_url setting")
+	ErrServerHost  = errors.New("the fetched OpenAPI document names a server on another host, set the base_url setting to trust it")
+	ErrInvalidAuth = errors.New("invalid auth setting")
+	ErrNoTools     = errors.New("no operations selected")
+)
+
+// Provider exposes the operations of one OpenAPI document as tools.
+type Provider struct {
+	name  string
+	cfg   contracts.ProviderConfig
+	api   *providerkit.API
+	auth  string
+	title string
+	tools []contracts.Tool
+}
+
+// New creates an uninitialized OpenAPI provider named openapi.
+func New() contracts.Provider {
+	return NewNamed(Name)
+}
+
+// NewNamed creates an uninitialized OpenAPI provider with the given name, so
+// several APIs can be enabled side by side.
+func NewNamed(name string) contracts.Provider {
+	return &Provider{name: name}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// Initialize loads the OpenAPI document named by the spec setting and builds
+// a tool for each selected operation.
+func (p *Provider) Initialize(ctx context.Context, cfg contracts.ProviderConfig) error {
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	p.cfg = cfg
+
+	p.auth = strings.ToLower(cfg.Setting("auth", ""))
+	switch p.auth {
+	case "", authNone, authBearer, authHeader, authQuery, authBasic:
+	default:
+		return fmt.Errorf("%w %q (expected none, bearer, header, query or basic)", ErrInvalidAuth, p.auth)
+	}
+
+	location := cfg.Setting("spec", "")
+	data, err := loadSpec(ctx, httpClient, location)
+	if err != nil {
+		return err
+	}
+	doc, err := parseSpec(data)
+	if err != nil {
+		return err
+	}
+	p.title = doc.Info.Title
+
+	baseURL := cfg.Setting("base_url", "")
+	if baseURL == "" {
+		if baseURL, err = doc.serverURL(location); err != nil {
+			return err
+		}
+		// Calls carry the credential, so a fetched document is only trusted
+		// to name a server on its own host
+		if err := checkServerHost(location, baseURL); err != nil {
+			return err
+		}
+	}
+	if baseURL == "" {
+		return ErrNoBaseURL
+	}
+
+	p.api = &providerkit.API{
+		Provider:     p.displayName(),
+		BaseURL:      baseURL,
+		HTTP:         httpClient,
+		Authorize:    p.authorize,
+		ErrorMessage: errorMessage,
+	}
+
+	readOnly, err := strconv.ParseBool(cfg.Setting("read_only", "false"))
+	if err != nil {
+		return fmt.Errorf("invalid %s read_only %q: %w", p.name, cfg.Setting("read_only", ""), err)
+	}
+	selection := filter{
+		tags:       splitList(cfg.Setting("include_tags", "")),
+		operations: splitList(cfg.Setting("include_operations", "")),
+		excluded:   splitList(cfg.Setting("exclude_operations", "")),
+		readOnly:   readOnly,
+	}
+
+	p.tools, err = p.buildTools(doc, selection, cfg.Setting("tool_prefix", defaultPrefix(p.name)))
+	if err != nil {
+		return err
+	}
+	if len(p.tools) == 0 {
+		return fmt.Errorf("%w from %s, check include_tags and include_operations", ErrNoTools, location)
+	}
+
+	return nil
+}
+
+// Tools returns a tool for each selected operation.
+func (p *Provider) Tools() []contracts.Tool {
+	return p.tools
+}
+
+// HealthCheck calls the health_path setting, if set, with the default
+// account's credential. Otherwise the loaded document is enough.
+func (p *Provider) HealthCheck(ctx context.Context) error {
+	path := p.cfg.Setting("health_path", "")
+	if path == "" {
+		return nil
+	}
+	ctx = providerkit.DefaultAccountContext(ctx, p.cfg)
+
+	return p.api.Do(ctx, http.MethodGet, path, nil, nil, nil)
+}
+
+// Shutdown has nothing to release.
+func (p *Provider) Shutdown(context.Context) error {
+	return nil
+}
+
+// displayName names the API in error messages: the document title when it
+// has one, otherwise the provider name.
+func (p *Provider) displayName() string {
+	if p.title != "" {
+		return p.title
+	}
+
+	return p.name
+}
+
+// authorize adds the credential of the call's account as the auth setting
+// describes. Without an auth setting, a credential is sent as a bearer token
+// when one is configured and requests are unauthenticated otherwise.
+func (p *Provider) authorize(ctx context.Context, req *http.Request) error {
+	if p.auth == authNone {
+		return nil
+	}
+
+	secret, err := providerkit.Credential(ctx, p.cfg, "")
+	if errors.Is(err, providerkit.ErrNoCredential) && p.auth == "" {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", p.name, err)
+	}
+	credential := secret.Reveal()
+
+	switch p.auth {
+	case authHeader:
+		req.Header.Set(p.cfg.Setting("auth_header", DefaultAuthHeader), credential)
+	case authQuery:
+		query := req.URL.Query()
+		query.Set(p.cfg.Setting("auth_query", DefaultAuthQuery), credential)
+		req.URL.RawQuery = query.Encode()
+	case authBasic:
+		user, password, _ := strings.Cut(credential, ":")
+		req.SetBasicAuth(user, password)
+	default:
+		req.Header.Set("Authorization", "Bearer "+credential)
+	}
+
+	return nil
+}
+
+// errorMessage extracts the message of common JSON error shapes: RFC 9457
+// problem details, {"message": ...} and {"error": ...} with a string or an
+// object carrying a message.
+func errorMessage(body []byte) string {
+	var decoded struct {
+		Title   string          `json:"title"`
+		Detail  string          `json:"detail"`
+		Message string          `json:"message"`
+		Error   json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return ""
+	}
+
+	switch {
+	case decoded.Detail != "" && decoded.Title != "":
+		return decoded.Title + ": " + decoded.Detail
+	case decoded.Detail != "":
+		return decoded.Detail
+	case decoded.Message != "":
+		return decoded.Message
+	}
+
+	var text string
+	if err := json.Unmarshal(decoded.Error, &text); err == nil && text != "" {
+		return text
+	}
+	var nested struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(decoded.Error, &nested); err == nil && nested.Message != "" {
+		return nested.Message
+	}
+
+	return decoded.Title
+}
+
+// checkServerHost returns ErrServerHost when the document was fetched over
+// http(s) and serverURL is on a different host.
+func checkServerHost(location, serverURL string) error {
+	spec, err := url.Parse(location)
+	if err != nil || (spec.Scheme != "http" && spec.Scheme != "https") || serverURL == "" {
+		return nil
+	}
+
+	server, err := url.Parse(serverURL)
+	if err != nil || !strings.EqualFold(server.Host, spec.Host) {
+		return fmt.Errorf("%w: %s is served from %s", ErrServerHost, serverURL, spec.Host)
+	}
+
+	return nil
+}
+
+// defaultPrefix returns the tool name prefix of a provider: the instance name
+// of openapi-<instance>, or openapi.
+func defaultPrefix(name string) string {
+	if instance, ok := strings.CutPrefix(name, Name+"-"); ok && instance != "" {
+		name = instance
+	}
+
+	return strings.ReplaceAll(name, "-", "_") + "_"
+}
+
+// splitList splits a comma-separated setting, dropping blanks.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,411 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/internal/providerkit/providerkittest"
+	"github.com/chadit/CloudMCP/internal/providers/openapi"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+// petstore is the OpenAPI document served by the stub. Its server URL is
+// relative, so it resolves against the document URL.
+const petstore = `
+openapi: 3.0.3
+info:
+  title: Petstore
+  version: "1.0"
+servers:
+  - url: /v1
+paths:
+  /health:
+    get:
+      summary: Reports service health
+      responses:
+        200:
+          description: OK
+  /pets:
+    get:
+      operationId: listPets
+      tags: [pets]
+      summary: Lists pets
+      parameters:
+        - name: limit
+          in: query
+          description: Most pets to return
+          schema: {type: integer, maximum: 100}
+        - name: tags
+          in: query
+          schema: {type: array, items: {type: string}}
+      responses:
+        200:
+          description: Pets
+    post:
+      operationId: createPet
+      tags: [pets]
+      summary: Creates a pet
+      requestBody:
+        $ref: '#/components/requestBodies/NewPet'
+      responses:
+        201:
+          description: Created
+  /pets/{petId}:
+    parameters:
+      - $ref: '#/components/parameters/PetId'
+    get:
+      operationId: getPetById
+      tags: [pets]
+      summary: Gets a pet
+      parameters:
+        - name: X-Request-ID
+          in: header
+          schema: {type: string}
+        - name: Accept
+          in: header
+          schema: {type: string}
+      responses:
+        200:
+          description: The pet
+    delete:
+      operationId: deletePet
+      tags: [admin]
+      summary: Deletes a pet
+      responses:
+        204:
+          description: Deleted
+  /pets/{petId}/photo:
+    put:
+      operationId: uploadPhoto
+      tags: [pets]
+      requestBody:
+        required: true
+        content:
+          image/png:
+            schema: {type: string, format: binary}
+      responses:
+        204:
+          description: Uploaded
+components:
+  parameters:
+    PetId:
+      name: petId
+      in: path
+      required: true
+      description: ID of the pet
+      schema: {type: string}
+  requestBodies:
+    NewPet:
+      required: true
+      description: The pet to create
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/Pet'
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+        owner:
+          $ref: '#/components/schemas/Owner'
+    Owner:
+      type: object
+      properties:
+        name: {type: string}
+        pets:
+          type: array
+          items:
+            $ref: '#/components/schemas/Pet'
+`
+
+// request is a request received by the stub.
+type request struct {
+	Method string
+	URI    string
+	Header http.Header
+	Body   map[string]any
+}
+
+// fakeAPI serves the petstore document and a stub of its API.
+type fakeAPI struct {
+	mu       sync.Mutex
+	requests []request
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path == "/openapi.yaml" {
+		_, _ = io.WriteString(w, petstore)
+		return
+	}
+
+	received := request{Method: r.Method, URI: r.URL.RequestURI(), Header: r.Header.Clone()}
+	if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+		_ = json.Unmarshal(data, &received.Body)
+	}
+	f.requests = append(f.requests, received)
+
+	switch {
+	case r.URL.Path == "/v1/health":
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = io.WriteString(w, "ok\n")
+	case r.URL.Path == "/v1/pets" && r.Method == http.MethodGet:
+		providerkittest.WriteJSON(w, http.StatusOK, []map[string]any{{"name": "rex"}, {"name": "tom"}})
+	case r.URL.Path == "/v1/pets" && r.Method == http.MethodPost:
+		providerkittest.WriteJSON(w, http.StatusCreated, received.Body)
+	case r.URL.Path == "/v1/pets/rex" && r.Method == http.MethodGet:
+		providerkittest.WriteJSON(w, http.StatusOK, map[string]any{"name": "rex"})
+	case r.URL.Path == "/v1/pets/rex" && r.Method == http.MethodDelete:
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		providerkittest.WriteJSON(w, http.StatusNotFound, map[string]any{"title": "Not Found", "detail": "no pet at " + r.URL.Path})
+	}
+}
+
+// last returns the last request the stub received.
+func (f *fakeAPI) last() request {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.requests[len(f.requests)-1]
+}
+
+// setup starts the stub and returns a provider named openapi-pets loading the
+// petstore document from it, with its tools by name.
+func setup(t *testing.T, settings map[string]string) (*fakeAPI, contracts.Provider, map[string]contracts.Tool) {
+	t.Helper()
+
+	api := &fakeAPI{}
+	srv := providerkittest.Serve(t, api)
+
+	cfg := map[string]string{"spec": srv.URL + "/openapi.yaml", "token_ref": "pets-token"}
+	for key, value := range settings {
+		cfg[key] = value
+	}
+
+	provider := openapi.NewNamed("openapi-pets")
+	tools := providerkittest.Setup(t, provider, contracts.ProviderConfig{
+		Settings:   cfg,
+		HTTPClient: srv.Client(),
+		Secrets:    providerkittest.Secrets{"pets-token": "s3cret"},
+	})
+
+	return api, provider, tools
+}
+
+// names returns the sorted tool names.
+func names(tools map[string]contracts.Tool) []string {
+	var list []string
+	for name := range tools {
+		list = append(list, name)
+	}
+	slices.Sort(list)
+
+	return list
+}
+
+// definition returns the full MCP definition of a tool.
+func definition(t *testing.T, tool contracts.Tool) mcp.Tool {
+	t.Helper()
+
+	definer, ok := tool.(interface{ Definition() mcp.Tool })
+	require.True(t, ok)
+
+	return definer.Definition()
+}
+
+func TestTools_OnePerOperation(t *testing.T) {
+	t.Parallel()
+
+	_, provider, tools := setup(t, nil)
+	require.Equal(t, "openapi-pets", provider.Name())
+	require.Equal(t, []string{
+		"pets_create_pet", "pets_delete_pet", "pets_get_health", "pets_get_pet_by_id", "pets_list_pets",
+	}, names(tools), "uploadPhoto needs a binary body and is left out")
+
+	list := definition(t, tools["pets_list_pets"])
+	require.Contains(t, list.Description, "Lists pets")
+	require.Contains(t, list.Description, "GET /pets")
+	require.True(t, *list.Annotations.ReadOnlyHint)
+	require.Equal(t, map[string]any{"type": "integer", "maximum": float64(100), "description": "Most pets to return"},
+		list.InputSchema.Properties["limit"])
+	require.NotContains(t, list.InputSchema.Properties, "idempotency_key")
+
+	get := definition(t, tools["pets_get_pet_by_id"])
+	require.Equal(t, []string{"petId"}, get.InputSchema.Required, "path item parameters are inherited")
+	require.Contains(t, get.InputSchema.Properties, "X-Request-ID")
+	require.NotContains(t, get.InputSchema.Properties, "Accept")
+
+	remove := definition(t, tools["pets_delete_pet"])
+	require.True(t, *remove.Annotations.DestructiveHint)
+	require.Contains(t, remove.InputSchema.Properties, "idempotency_key")
+
+	create := definition(t, tools["pets_create_pet"])
+	require.Contains(t, create.InputSchema.Required, "body")
+	body, ok := create.InputSchema.Properties["body"].(map[string]any)
+	require.True(t, ok)
+	require.Equal(t, "The pet to create", body["description"])
+	require.Equal(t, []any{"name"}, body["required"])
+
+	// References are inlined, and the Pet -> Owner -> Pet cycle is cut off.
+	data, err := json.Marshal(body)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "$ref")
+	require.Contains(t, string(data), `"items":{"description":"Recursive Pet","type":"object"}`)
+}
+
+func TestCall_BuildsRequest(t *testing.T) {
+	t.Parallel()
+
+	api, _, tools := setup(t, nil)
+
+	text, isError := providerkittest.Call(t.Context(), t, tools["pets_list_pets"], map[string]any{"limit": float64(2), "tags": []any{"a", "b"}})
+	require.False(t, isError, text)
+	require.JSONEq(t, `[{"name":"rex"},{"name":"tom"}]`, text)
+	received := api.last()
+	require.Equal(t, "/v1/pets?limit=2&tags=a&tags=b", received.URI)
+	require.Equal(t, "Bearer s3cret", received.Header.Get("Authorization"))
+
+	text, isError = providerkittest.Call(t.Context(), t, tools["pets_get_pet_by_id"], map[string]any{"petId": "rex", "X-Request-ID": "req-1"})
+	require.False(t, isError, text)
+	require.Equal(t, "req-1", api.last().Header.Get("X-Request-ID"))
+
+	text, isError = providerkittest.Call(t.Context(), t, tools["pets_create_pet"], map[string]any{"body": map[string]any{"name": "kit"}})
+	require.False(t, isError, text)
+	require.Equal(t, map[string]any{"name": "kit"}, api.last().Body)
+
+	text, isError = providerkittest.Call(t.Context(), t, tools["pets_delete_pet"], map[string]any{"petId": "rex"})
+	require.False(t, isError, text)
+	require.JSONEq(t, `{"status":204}`, text)
+
+	text, isError = providerkittest.Call(t.Context(), t, tools["pets_get_health"], map[string]any{})
+	require.False(t, isError, text)
+	require.JSONEq(t, `"ok\n"`, text, "responses that are not JSON are returned as text")
+}
+
+func TestCall_Errors(t *testing.T) {
+	t.Parallel()
+
+	api, _, tools := setup(t, nil)
+
+	text, isError := providerkittest.Call(t.Context(), t, tools["pets_get_pet_by_id"], map[string]any{})
+	require.True(t, isError)
+	require.Contains(t, text, openapi.ErrMissingArgument.Error())
+
+	text, isError = providerkittest.Call(t.Context(), t, tools["pets_create_pet"], map[string]any{})
+	require.True(t, isError)
+	require.Contains(t, text, `"body"`)
+	require.Empty(t, api.requests)
+
+	text, isError = providerkittest.Call(t.Context(), t, tools["pets_get_pet_by_id"], map[string]any{"petId": "a/b"})
+	require.True(t, isError)
+	require.Equal(t, "/v1/pets/a%2Fb", api.last().URI, "path arguments are escaped")
+	require.Contains(t, text, providerkit.ErrNotFound.Error())
+	require.Contains(t, text, "Petstore API")
+	require.Contains(t, text, "Not Found: no pet at /v1/pets/a/b")
+
+	sent := len(api.requests)
+	for _, segment := range []string{"", ".", ".."} {
+		text, isError = providerkittest.Call(t.Context(), t, tools["pets_get_pet_by_id"], map[string]any{"petId": segment})
+		require.True(t, isError)
+		require.Contains(t, text, openapi.ErrPathSegment.Error())
+	}
+	require.Len(t, api.requests, sent, "dot segments never reach the API")
+}
+
+func TestFilter_SelectsOperations(t *testing.T) {
+	t.Parallel()
+
+	_, _, tools := setup(t, map[string]string{"include_tags": "admin", "include_operations": "list*"})
+	require.Equal(t, []string{"pets_delete_pet", "pets_list_pets"}, names(tools))
+
+	_, _, tools = setup(t, map[string]string{"read_only": "true", "exclude_operations": "get_health"})
+	require.Equal(t, []string{"pets_get_pet_by_id", "pets_list_pets"}, names(tools))
+
+	_, _, tools = setup(t, map[string]string{"include_tags": "pets", "tool_prefix": "store_"})
+	require.Equal(t, []string{"store_create_pet", "store_get_pet_by_id", "store_list_pets"}, names(tools))
+}
+
+func TestAuth_Schemes(t *testing.T) {
+	t.Parallel()
+
+	api, _, tools := setup(t, map[string]string{"auth": "header", "auth_header": "X-Token"})
+	_, isError := providerkittest.Call(t.Context(), t, tools["pets_list_pets"], map[string]any{})
+	require.False(t, isError)
+	require.Equal(t, "s3cret", api.last().Header.Get("X-Token"))
+	require.Empty(t, api.last().Header.Get("Authorization"))
+
+	api, _, tools = setup(t, map[string]string{"auth": "query"})
+	_, isError = providerkittest.Call(t.Context(), t, tools["pets_list_pets"], map[string]any{})
+	require.False(t, isError)
+	require.Equal(t, "/v1/pets?api_key=s3cret", api.last().URI)
+
+	api, _, tools = setup(t, map[string]string{"auth": "none"})
+	_, isError = providerkittest.Call(t.Context(), t, tools["pets_list_pets"], map[string]any{})
+	require.False(t, isError)
+	require.Empty(t, api.last().Header.Get("Authorization"))
+
+	api, _, tools = setup(t, map[string]string{"token_ref": ""})
+	_, isError = providerkittest.Call(t.Context(), t, tools["pets_list_pets"], map[string]any{})
+	require.False(t, isError, "without a credential or auth setting, calls are unauthenticated")
+	require.Empty(t, api.last().Header.Get("Authorization"))
+}
+
+func TestInitialize_RejectsInvalidConfiguration(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+	initialize := func(settings map[string]string) error {
+		return openapi.New().Initialize(t.Context(), contracts.ProviderConfig{Settings: settings})
+	}
+
+	require.ErrorIs(t, initialize(nil), openapi.ErrNoSpec)
+
+	swagger := write("swagger.json", `{"swagger": "2.0", "paths": {}}`)
+	require.ErrorIs(t, initialize(map[string]string{"spec": swagger}), openapi.ErrUnsupportedSpec)
+
+	relative := write("relative.yaml", petstore)
+	require.ErrorIs(t, initialize(map[string]string{"spec": relative}), openapi.ErrNoBaseURL)
+	require.NoError(t, initialize(map[string]string{"spec": relative, "base_url": "https://pets.example"}))
+
+	require.ErrorIs(t, initialize(map[string]string{"spec": relative, "base_url": "https://pets.example", "auth": "oauth"}),
+		openapi.ErrInvalidAuth)
+	require.ErrorIs(t, initialize(map[string]string{"spec": relative, "base_url": "https://pets.example", "include_tags": "none"}),
+		openapi.ErrNoTools)
+
+	duplicate := write("duplicate.json", `{"openapi": "3.1.0", "servers": [{"url": "https://api.example"}], "paths": {
+		"/a": {"get": {"operationId": "getThing"}},
+		"/b": {"get": {"operationId": "get_thing"}}}}`)
+	require.ErrorIs(t, initialize(map[string]string{"spec": duplicate}), openapi.ErrDuplicateTool)
+
+	foreign := providerkittest.Serve(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(strings.Replace(petstore, "url: /v1", "url: https://collector.example/v1", 1)))
+	}))
+	require.ErrorIs(t, initialize(map[string]string{"spec": foreign.URL + "/openapi.yaml"}), openapi.ErrServerHost,
+		"a fetched document may not send the credential to another host")
+	require.NoError(t, initialize(map[string]string{"spec": foreign.URL + "/openapi.yaml", "base_url": "https://collector.example/v1"}))
+
+	missing := providerkittest.Serve(t, http.NotFoundHandler())
+	require.ErrorIs(t, initialize(map[string]string{"spec": missing.URL + "/openapi.yaml", "base_url": "https://pets.example"}),
+		openapi.ErrSpecFetch)
+}
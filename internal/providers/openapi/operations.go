@@ -0,0 +1,530 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/idempotency"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	// bodyArg is the argument carrying the JSON request body.
+	bodyArg = "body"
+
+	// maxNameLength is the longest tool or argument name clients accept.
+	maxNameLength = 64
+
+	// maxDescriptionLength bounds the operation description copied into a
+	// tool description.
+	maxDescriptionLength = 1000
+)
+
+// Static errors for err113 compliance.
+var (
+	ErrDuplicateTool   = errors.New("operations map to the same tool name")
+	ErrMissingArgument = errors.New("missing required argument")
+	ErrPathSegment     = errors.New("path argument cannot be empty, . or ..")
+)
+
+// methods are the HTTP methods of a path item, in the order tools are listed.
+var methods = []string{"get", "head", "options", "post", "put", "patch", "delete"}
+
+// reservedArgs are argument names the server or the tools themselves use.
+var reservedArgs = []string{bodyArg, contracts.AccountParam, format.Param, idempotency.Param}
+
+// skippedHeaders are header parameters set by the provider itself.
+var skippedHeaders = []string{"accept", "content-type", "authorization"}
+
+// filter selects the operations that become tools.
+type filter struct {
+	// tags and operations include operations with any of the tags or any of
+	// the operation IDs. With neither, every operation is included.
+	tags       []string
+	operations []string
+
+	// excluded drops operations by operation ID.
+	excluded []string
+
+	// readOnly drops operations that are not GET, HEAD or OPTIONS.
+	readOnly bool
+}
+
+// selects reports whether the operation with the given method and ID is
+// selected. Operation IDs may be glob patterns such as list*.
+func (f filter) selects(method, id string, tags []string) bool {
+	if f.readOnly && !readOnlyMethod(method) {
+		return false
+	}
+	if matchAny(f.excluded, id) {
+		return false
+	}
+	if len(f.tags) == 0 && len(f.operations) == 0 {
+		return true
+	}
+	if matchAny(f.operations, id) {
+		return true
+	}
+
+	return slices.ContainsFunc(tags, func(tag string) bool { return slices.Contains(f.tags, tag) })
+}
+
+// matchAny reports whether id matches any of the patterns.
+func matchAny(patterns []string, id string) bool {
+	return slices.ContainsFunc(patterns, func(pattern string) bool {
+		matched, err := path.Match(pattern, id)
+		return pattern == id || (err == nil && matched)
+	})
+}
+
+// readOnlyMethod reports whether method does not change anything.
+func readOnlyMethod(method string) bool {
+	return method == "get" || method == "head" || method == "options"
+}
+
+// binding maps a tool argument to a parameter of the operation.
+type binding struct {
+	arg      string
+	name     string
+	in       string
+	required bool
+}
+
+// endpoint is what a tool needs to call its operation.
+type endpoint struct {
+	method       string
+	path         string
+	params       []binding
+	body         bool
+	bodyRequired bool
+}
+
+// buildTools creates a tool for each selected operation, in path order.
+func (p *Provider) buildTools(doc *document, selection filter, prefix string) ([]contracts.Tool, error) {
+	paths := make([]string, 0, len(doc.Paths))
+	for route := range doc.Paths {
+		paths = append(paths, route)
+	}
+	slices.Sort(paths)
+
+	tools := []contracts.Tool{}
+	names := map[string]string{}
+	for _, route := range paths {
+		item := doc.Paths[route]
+		var shared []parameter
+		if raw, ok := item["parameters"]; ok {
+			if err := json.Unmarshal(raw, &shared); err != nil {
+				return nil, fmt.Errorf("invalid parameters of %s: %w", route, err)
+			}
+		}
+
+		for _, method := range methods {
+			raw, ok := item[method]
+			if !ok {
+				continue
+			}
+			var op operation
+			if err := json.Unmarshal(raw, &op); err != nil {
+				return nil, fmt.Errorf("invalid operation %s %s: %w", strings.ToUpper(method), route, err)
+			}
+
+			id := op.OperationID
+			if id == "" {
+				id = snakeCase(method + " " + route)
+			}
+			if !selection.selects(method, id, op.Tags) {
+				continue
+			}
+
+			name := truncate(prefix+snakeCase(id), maxNameLength)
+			if other, ok := names[name]; ok {
+				return nil, fmt.Errorf("%w %s: %s and %s", ErrDuplicateTool, name, other, id)
+			}
+			names[name] = id
+
+			tool, err := p.operationTool(doc, name, method, route, shared, op)
+			if err != nil {
+				return nil, fmt.Errorf("operation %s: %w", id, err)
+			}
+			if tool != nil {
+				tools = append(tools, tool)
+			}
+		}
+	}
+
+	return tools, nil
+}
+
+// operationTool builds the tool of an operation. Operations whose request body
+// is required but not JSON are skipped, since tools cannot send it.
+func (p *Provider) operationTool(doc *document, name, method, route string, shared []parameter, op operation) (*providerkit.Tool, error) {
+	params, err := operationParameters(doc, shared, op.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	options := []mcp.ToolOption{mcp.WithDescription(describe(method, route, op))}
+	switch method {
+	case "get", "head", "options":
+		options = append(options, mcp.WithReadOnlyHintAnnotation(true))
+	case "put", "delete":
+		options = append(options, mcp.WithDestructiveHintAnnotation(true), mcp.WithIdempotentHintAnnotation(true))
+	default:
+		options = append(options, mcp.WithDestructiveHintAnnotation(false), mcp.WithIdempotentHintAnnotation(false))
+	}
+	options = append(options, mcp.WithOpenWorldHintAnnotation(true))
+	if !readOnlyMethod(method) {
+		options = append(options, idempotency.WithParam())
+	}
+	options = append(options, format.WithParam())
+	tool := mcp.NewTool(name, options...)
+
+	target := endpoint{method: strings.ToUpper(method), path: route}
+	used := slices.Clone(reservedArgs)
+	for _, param := range params {
+		arg := argName(param, used)
+		used = append(used, arg)
+
+		schema, err := parameterSchema(doc, param)
+		if err != nil {
+			return nil, err
+		}
+		tool.InputSchema.Properties[arg] = schema
+		if param.Required {
+			tool.InputSchema.Required = append(tool.InputSchema.Required, arg)
+		}
+		target.params = append(target.params, binding{arg: arg, name: param.Name, in: param.In, required: param.Required})
+	}
+
+	if op.RequestBody != nil {
+		body := *op.RequestBody
+		if body.Ref != "" {
+			if err := doc.resolve(body.Ref, &body); err != nil {
+				return nil, err
+			}
+		}
+
+		media, ok := jsonMediaType(body.Content)
+		switch {
+		case ok:
+			schema, err := bodySchema(doc, media, body.Description)
+			if err != nil {
+				return nil, err
+			}
+			tool.InputSchema.Properties[bodyArg] = schema
+			if body.Required {
+				tool.InputSchema.Required = append(tool.InputSchema.Required, bodyArg)
+			}
+			target.body = true
+			target.bodyRequired = body.Required
+		case body.Required:
+			return nil, nil
+		}
+	}
+
+	return providerkit.NewTool(tool, p.handler(target)), nil
+}
+
+// operationParameters merges the parameters of a path item and an operation,
+// resolving references. Operation parameters override path item parameters
+// with the same name and location. Cookies and headers the provider sets are
+// left out.
+func operationParameters(doc *document, shared, own []parameter) ([]parameter, error) {
+	var params []parameter
+	for _, list := range [][]parameter{shared, own} {
+		for _, param := range list {
+			if param.Ref != "" {
+				if err := doc.resolve(param.Ref, &param); err != nil {
+					return nil, err
+				}
+			}
+			if param.In == "cookie" || (param.In == "header" && slices.Contains(skippedHeaders, strings.ToLower(param.Name))) {
+				continue
+			}
+			if param.In == "path" {
+				param.Required = true
+			}
+
+			index := slices.IndexFunc(params, func(p parameter) bool { return p.Name == param.Name && p.In == param.In })
+			if index >= 0 {
+				params[index] = param
+			} else {
+				params = append(params, param)
+			}
+		}
+	}
+
+	return params, nil
+}
+
+// argName returns the argument name of a parameter: its name with characters
+// clients reject replaced, prefixed with its location when the name is taken.
+func argName(param parameter, used []string) string {
+	var b strings.Builder
+	for _, r := range param.Name {
+		if r < 128 && (isAlphanumeric(byte(r)) || r == '_' || r == '-' || r == '.') {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+
+	name := truncate(b.String(), maxNameLength)
+	if slices.Contains(used, name) {
+		name = truncate(param.In+"_"+name, maxNameLength)
+	}
+
+	return name
+}
+
+// parameterSchema returns the input schema of a parameter, with its
+// description. Parameters described by content rather than a schema are
+// taken as strings.
+func parameterSchema(doc *document, param parameter) (map[string]any, error) {
+	schema := map[string]any{"type": "string"}
+	if param.Schema != nil {
+		inlined, err := doc.inline(param.Schema, nil)
+		if err != nil {
+			return nil, err
+		}
+		schema, _ = inlined.(map[string]any)
+	}
+	if _, ok := schema["description"]; !ok && param.Description != "" {
+		schema["description"] = param.Description
+	}
+
+	return schema, nil
+}
+
+// bodySchema returns the input schema of a JSON request body.
+func bodySchema(doc *document, media mediaType, description string) (map[string]any, error) {
+	schema := map[string]any{}
+	if media.Schema != nil {
+		inlined, err := doc.inline(media.Schema, nil)
+		if err != nil {
+			return nil, err
+		}
+		schema, _ = inlined.(map[string]any)
+	}
+	if description == "" {
+		description = "JSON request body"
+	}
+	if _, ok := schema["description"]; !ok {
+		schema["description"] = description
+	}
+
+	return schema, nil
+}
+
+// jsonMediaType returns the JSON entry of a request body's content:
+// application/json, or else a JSON-based type such as
+// application/merge-patch+json.
+func jsonMediaType(content map[string]mediaType) (mediaType, bool) {
+	if media, ok := content["application/json"]; ok {
+		return media, true
+	}
+
+	types := make([]string, 0, len(content))
+	for contentType := range content {
+		types = append(types, contentType)
+	}
+	slices.Sort(types)
+	for _, contentType := range types {
+		if strings.HasSuffix(strings.Split(contentType, ";")[0], "+json") {
+			return content[contentType], true
+		}
+	}
+
+	return mediaType{}, false
+}
+
+// describe returns the tool description of an operation: its summary and
+// description, followed by the method and path.
+func describe(method, route string, op operation) string {
+	var parts []string
+	if summary := strings.TrimSpace(op.Summary); summary != "" {
+		parts = append(parts, summary)
+	}
+	if description := strings.TrimSpace(op.Description); description != "" && description != strings.TrimSpace(op.Summary) {
+		parts = append(parts, shorten(description, maxDescriptionLength))
+	}
+	if op.Deprecated {
+		parts = append(parts, "Deprecated.")
+	}
+	parts = append(parts, strings.ToUpper(method)+" "+route)
+
+	return strings.Join(parts, "\n\n")
+}
+
+// handler returns the handler calling an endpoint with the tool arguments.
+func (p *Provider) handler(target endpoint) providerkit.Handler {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		call, err := target.call(request.GetArguments())
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		var status int
+		var raw []byte
+		call.Status = &status
+		call.Raw = &raw
+		if _, err := p.api.Call(ctx, call); err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		return providerkit.Result(response(status, raw), nil)
+	}
+}
+
+// call builds the request of an endpoint from tool arguments.
+func (e endpoint) call(args map[string]any) (providerkit.Call, error) {
+	call := providerkit.Call{Method: e.method, Query: url.Values{}, Header: http.Header{}}
+	route := e.path
+	for _, param := range e.params {
+		values := argValues(args[param.arg])
+		if len(values) == 0 {
+			if param.required {
+				return providerkit.Call{}, fmt.Errorf("%w %q", ErrMissingArgument, param.arg)
+			}
+			continue
+		}
+
+		switch param.in {
+		case "path":
+			// PathEscape leaves dots alone, and the URL would be resolved
+			// to another endpoint than the operation's
+			segment := strings.Join(values, ",")
+			if segment == "" || segment == "." || segment == ".." {
+				return providerkit.Call{}, fmt.Errorf("%w: %q", ErrPathSegment, param.arg)
+			}
+			route = strings.ReplaceAll(route, "{"+param.name+"}", url.PathEscape(segment))
+		case "query":
+			call.Query[param.name] = values
+		case "header":
+			call.Header.Set(param.name, strings.Join(values, ","))
+		}
+	}
+	call.Path = route
+
+	if body, ok := args[bodyArg]; ok && e.body && body != nil {
+		call.Body = body
+	} else if e.bodyRequired {
+		return providerkit.Call{}, fmt.Errorf("%w %q", ErrMissingArgument, bodyArg)
+	}
+
+	return call, nil
+}
+
+// argValues formats an argument as parameter values. Arrays become one value
+// per item, and objects are sent as JSON.
+func argValues(value any) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{v}
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}
+	case bool:
+		return []string{strconv.FormatBool(v)}
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			values = append(values, argValues(item)...)
+		}
+		return values
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return []string{fmt.Sprint(v)}
+		}
+		return []string{string(data)}
+	}
+}
+
+// response returns the result of a call: the decoded JSON body, the body as
+// text when it is not JSON, or the status when it is empty.
+func response(status int, body []byte) any {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return map[string]any{"status": status}
+	}
+
+	var decoded any
+	if err := json.Unmarshal(trimmed, &decoded); err == nil {
+		return decoded
+	}
+
+	return string(body)
+}
+
+// snakeCase converts an operation ID such as listPetsByOwner, or a method and
+// path, into a lower-case name with underscores: list_pets_by_owner.
+func snakeCase(value string) string {
+	var b strings.Builder
+	separate := false
+	for i := range len(value) {
+		c := value[i]
+		if !isAlphanumeric(c) {
+			separate = true
+			continue
+		}
+		if isUpper(c) && i > 0 {
+			prev := value[i-1]
+			nextLower := i+1 < len(value) && isLower(value[i+1])
+			if isLower(prev) || isDigit(prev) || (isUpper(prev) && nextLower) {
+				separate = true
+			}
+		}
+		if separate && b.Len() > 0 {
+			b.WriteByte('_')
+		}
+		separate = false
+		if isUpper(c) {
+			c += 'a' - 'A'
+		}
+		b.WriteByte(c)
+	}
+
+	return b.String()
+}
+
+func isUpper(c byte) bool { return c >= 'A' && c <= 'Z' }
+
+func isLower(c byte) bool { return c >= 'a' && c <= 'z' }
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isAlphanumeric(c byte) bool { return isUpper(c) || isLower(c) || isDigit(c) }
+
+// truncate shortens a name to at most n bytes. Names are ASCII.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+
+	return strings.TrimRight(s[:n], "_")
+}
+
+// shorten cuts text to at most n runes, marking the cut with an ellipsis.
+func shorten(text string, n int) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+
+	return strings.TrimSpace(string(runes[:n])) + "..."
+}
@@ -0,0 +1,327 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxSpecBytes bounds the size of an OpenAPI document.
+const maxSpecBytes = 16 << 20
+
+// Static errors for err113 compliance.
+var (
+	ErrNoSpec          = errors.New("no OpenAPI document configured, set the spec setting")
+	ErrUnsupportedSpec = errors.New("unsupported API description, OpenAPI 3 is required")
+	ErrInvalidRef      = errors.New("invalid $ref")
+	ErrSpecFetch       = errors.New("failed to fetch OpenAPI document")
+)
+
+// document is the part of an OpenAPI 3 document tools are built from. Schemas
+// are kept as decoded JSON so they can be passed to clients as they are.
+type document struct {
+	OpenAPI string              `json:"openapi"`
+	Swagger string              `json:"swagger"`
+	Info    info                `json:"info"`
+	Servers []server            `json:"servers"`
+	Paths   map[string]pathItem `json:"paths"`
+
+	// raw is the whole document, for resolving $ref pointers.
+	raw any
+}
+
+// info describes the API.
+type info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// server is an entry of the servers list.
+type server struct {
+	URL       string `json:"url"`
+	Variables map[string]struct {
+		Default string `json:"default"`
+	} `json:"variables"`
+}
+
+// pathItem holds the operations of a path by lower-case method, plus the
+// parameters shared by all of them.
+type pathItem map[string]json.RawMessage
+
+// operation is an operation object.
+//
+//nolint:tagliatelle // JSON field names match the OpenAPI specification.
+type operation struct {
+	OperationID string       `json:"operationId"`
+	Summary     string       `json:"summary"`
+	Description string       `json:"description"`
+	Tags        []string     `json:"tags"`
+	Deprecated  bool         `json:"deprecated"`
+	Parameters  []parameter  `json:"parameters"`
+	RequestBody *requestBody `json:"requestBody"`
+}
+
+// parameter is a parameter object or a reference to one.
+type parameter struct {
+	Ref         string         `json:"$ref"`
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description"`
+	Required    bool           `json:"required"`
+	Schema      map[string]any `json:"schema"`
+}
+
+// requestBody is a request body object or a reference to one.
+type requestBody struct {
+	Ref         string               `json:"$ref"`
+	Description string               `json:"description"`
+	Required    bool                 `json:"required"`
+	Content     map[string]mediaType `json:"content"`
+}
+
+// mediaType is an entry of a request body's content map.
+type mediaType struct {
+	Schema map[string]any `json:"schema"`
+}
+
+// loadSpec reads an OpenAPI document from a file or an http(s) URL.
+func loadSpec(ctx context.Context, client *http.Client, location string) ([]byte, error) {
+	if location == "" {
+		return nil, ErrNoSpec
+	}
+	if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+		data, err := os.ReadFile(location)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read OpenAPI document: %w", err)
+		}
+		return data, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OpenAPI document request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json, application/yaml;q=0.9, */*;q=0.1")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch OpenAPI document: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w %s: HTTP %d", ErrSpecFetch, location, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSpecBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OpenAPI document: %w", err)
+	}
+
+	return data, nil
+}
+
+// parseSpec decodes a JSON or YAML OpenAPI 3 document.
+func parseSpec(data []byte) (*document, error) {
+	var raw any
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(trimmed, &raw); err != nil {
+			return nil, fmt.Errorf("failed to decode OpenAPI document: %w", err)
+		}
+	} else {
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("failed to decode OpenAPI document: %w", err)
+		}
+		raw = fromYAML(raw)
+	}
+
+	var doc document
+	if err := remarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode OpenAPI document: %w", err)
+	}
+	switch {
+	case doc.Swagger != "":
+		return nil, fmt.Errorf("%w: found Swagger %s, convert it to OpenAPI 3 first", ErrUnsupportedSpec, doc.Swagger)
+	case !strings.HasPrefix(doc.OpenAPI, "3."):
+		return nil, fmt.Errorf("%w: found openapi %q", ErrUnsupportedSpec, doc.OpenAPI)
+	}
+	doc.raw = raw
+
+	return &doc, nil
+}
+
+// fromYAML converts decoded YAML to the types decoded JSON has. Mappings with
+// non-string keys, such as unquoted response codes, get string keys.
+func fromYAML(node any) any {
+	switch value := node.(type) {
+	case map[string]any:
+		for key, item := range value {
+			value[key] = fromYAML(item)
+		}
+		return value
+	case map[any]any:
+		out := make(map[string]any, len(value))
+		for key, item := range value {
+			out[fmt.Sprint(key)] = fromYAML(item)
+		}
+		return out
+	case []any:
+		for i, item := range value {
+			value[i] = fromYAML(item)
+		}
+		return value
+	case int:
+		return float64(value)
+	default:
+		return value
+	}
+}
+
+// remarshal converts decoded JSON into a typed value.
+func remarshal(in, out any) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, out)
+}
+
+// serverURL returns the URL of the first server with its variables set to
+// their defaults. A relative URL is resolved against the document location.
+func (d *document) serverURL(location string) (string, error) {
+	if len(d.Servers) == 0 {
+		return "", nil
+	}
+
+	target := d.Servers[0].URL
+	for name, variable := range d.Servers[0].Variables {
+		target = strings.ReplaceAll(target, "{"+name+"}", variable.Default)
+	}
+
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return "", fmt.Errorf("invalid server URL %q: %w", target, err)
+	}
+	if parsed.IsAbs() {
+		return target, nil
+	}
+
+	base, err := url.Parse(location)
+	if err != nil || !base.IsAbs() {
+		return "", nil
+	}
+
+	return base.ResolveReference(parsed).String(), nil
+}
+
+// lookup resolves a local $ref such as #/components/schemas/Pet.
+func (d *document) lookup(ref string) (any, error) {
+	pointer, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return nil, fmt.Errorf("%w %q: only references within the document are supported", ErrInvalidRef, ref)
+	}
+
+	node := d.raw
+	for _, token := range strings.Split(pointer, "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		object, ok := node.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%w %q", ErrInvalidRef, ref)
+		}
+		if node, ok = object[token]; !ok {
+			return nil, fmt.Errorf("%w %q", ErrInvalidRef, ref)
+		}
+	}
+
+	return node, nil
+}
+
+// resolve decodes the object a $ref points to into out.
+func (d *document) resolve(ref string, out any) error {
+	node, err := d.lookup(ref)
+	if err != nil {
+		return err
+	}
+	if err := remarshal(node, out); err != nil {
+		return fmt.Errorf("%w %q: %w", ErrInvalidRef, ref, err)
+	}
+
+	return nil
+}
+
+// maxSchemaDepth bounds how deeply references are inlined.
+const maxSchemaDepth = 32
+
+// inline returns a copy of schema with its $refs replaced by the schemas they
+// point to, since tool input schemas must be self-contained. A recursive
+// reference is cut off as a plain object.
+func (d *document) inline(schema any, seen map[string]bool) (any, error) {
+	switch node := schema.(type) {
+	case map[string]any:
+		if ref, ok := node["$ref"].(string); ok {
+			if seen[ref] || len(seen) >= maxSchemaDepth {
+				return map[string]any{"type": "object", "description": "Recursive " + refName(ref)}, nil
+			}
+			target, err := d.lookup(ref)
+			if err != nil {
+				return nil, err
+			}
+
+			nested := make(map[string]bool, len(seen)+1)
+			for key := range seen {
+				nested[key] = true
+			}
+			nested[ref] = true
+
+			resolved, err := d.inline(target, nested)
+			if err != nil {
+				return nil, err
+			}
+			// OpenAPI 3.1 allows keywords next to $ref; they take precedence.
+			if object, ok := resolved.(map[string]any); ok && len(node) > 1 {
+				for key, value := range node {
+					if key != "$ref" {
+						object[key] = value
+					}
+				}
+			}
+			return resolved, nil
+		}
+
+		out := make(map[string]any, len(node))
+		for key, value := range node {
+			inlined, err := d.inline(value, seen)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = inlined
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(node))
+		for i, value := range node {
+			inlined, err := d.inline(value, seen)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = inlined
+		}
+		return out, nil
+	default:
+		return schema, nil
+	}
+}
+
+// refName returns the last segment of a $ref.
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
@@ -0,0 +1,85 @@
+// Package providers lists the cloud providers built into CloudMCP and creates
+// the ones enabled by configuration.
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/chadit/CloudMCP/internal/providers/aws"
+	"github.com/chadit/CloudMCP/internal/providers/azure"
+	"github.com/chadit/CloudMCP/internal/providers/cloudflare"
+	"github.com/chadit/CloudMCP/internal/providers/digitalocean"
+	"github.com/chadit/CloudMCP/internal/providers/docker"
+	"github.com/chadit/CloudMCP/internal/providers/gcp"
+	"github.com/chadit/CloudMCP/internal/providers/hetzner"
+	"github.com/chadit/CloudMCP/internal/providers/kubernetes"
+	"github.com/chadit/CloudMCP/internal/providers/linode"
+	"github.com/chadit/CloudMCP/internal/providers/openapi"
+	"github.com/chadit/CloudMCP/internal/providers/s3"
+	"github.com/chadit/CloudMCP/internal/providers/scaleway"
+	"github.com/chadit/CloudMCP/internal/providers/vultr"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+// ErrUnknownProvider is returned for provider names with no implementation.
+var ErrUnknownProvider = errors.New("unknown provider")
+
+// factories creates each built-in provider by name.
+var factories = map[string]func() contracts.Provider{
+	aws.Name:          aws.New,
+	azure.Name:        azure.New,
+	cloudflare.Name:   cloudflare.New,
+	digitalocean.Name: digitalocean.New,
+	docker.Name:       docker.New,
+	gcp.Name:          gcp.New,
+	hetzner.Name:      hetzner.New,
+	kubernetes.Name:   kubernetes.New,
+	linode.Name:       linode.New,
+	openapi.Name:      openapi.New,
+	s3.Name:           s3.New,
+	scaleway.Name:     scaleway.New,
+	vultr.Name:        vultr.New,
+}
+
+// Names returns the names of the built-in providers, sorted.
+func Names() []string {
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// New creates the named providers in order. Names of the form
+// openapi-<instance> create further OpenAPI providers, one per API.
+func New(names []string) ([]contracts.Provider, error) {
+	created := make([]contracts.Provider, 0, len(names))
+	for _, name := range names {
+		factory, ok := factories[name]
+		switch {
+		case ok:
+			created = append(created, factory())
+		case strings.HasPrefix(name, openapi.Name+"-") && len(name) > len(openapi.Name)+1:
+			created = append(created, openapi.NewNamed(name))
+		default:
+			return nil, fmt.Errorf("%w: %q (available: %s)", ErrUnknownProvider, name, available())
+		}
+	}
+
+	return created, nil
+}
+
+// available formats the built-in provider names for error messages.
+func available() string {
+	names := Names()
+	if len(names) == 0 {
+		return "none"
+	}
+
+	return strings.Join(names, ", ")
+}
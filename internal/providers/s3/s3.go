@@ -0,0 +1,224 @@
+// Package s3 implements the S3 provider: bucket and object tools for AWS S3
+// and S3-compatible services such as Linode Object Storage, DigitalOcean
+// Spaces and MinIO, signed natively with SigV4.
+package s3
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/awsauth"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	// Name is the provider name used in settings and accounts.
+	Name = "s3"
+
+	// DefaultEndpoint is the AWS S3 endpoint template. {region} is replaced
+	// with the region of the call.
+	DefaultEndpoint = "https://s3.{region}.amazonaws.com"
+
+	// maxErrorBytes bounds how much of an error response is read.
+	maxErrorBytes = 64 << 10
+
+	// maxListBytes bounds how much of a listing response is read.
+	maxListBytes = 32 << 20
+)
+
+// Provider is the S3 provider.
+type Provider struct {
+	cfg       contracts.ProviderConfig
+	http      *http.Client
+	auth      awsauth.Resolver
+	endpoint  string
+	pathStyle bool
+}
+
+// New creates an uninitialized S3 provider.
+func New() contracts.Provider {
+	return &Provider{}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return Name
+}
+
+// Initialize reads the endpoint settings. Buckets are addressed path-style
+// when a custom endpoint is set, as MinIO expects, and virtual-hosted style
+// on AWS; the path_style setting overrides either.
+func (p *Provider) Initialize(_ context.Context, cfg contracts.ProviderConfig) error {
+	p.cfg = cfg
+	p.auth = awsauth.Resolver{Config: cfg}
+	p.http = cfg.HTTPClient
+	if p.http == nil {
+		p.http = http.DefaultClient
+	}
+
+	p.endpoint = strings.TrimRight(cfg.Setting("endpoint", DefaultEndpoint), "/")
+	if _, err := url.Parse(strings.ReplaceAll(p.endpoint, "{region}", awsauth.DefaultRegion)); err != nil {
+		return fmt.Errorf("invalid s3 endpoint %q: %w", p.endpoint, err)
+	}
+
+	pathStyle := cfg.Setting("path_style", strconv.FormatBool(p.endpoint != DefaultEndpoint))
+	var err error
+	if p.pathStyle, err = strconv.ParseBool(pathStyle); err != nil {
+		return fmt.Errorf("invalid s3 path_style %q: %w", pathStyle, err)
+	}
+
+	return nil
+}
+
+// Tools returns the S3 tools.
+func (p *Provider) Tools() []contracts.Tool {
+	return []contracts.Tool{
+		p.listBucketsTool(),
+		p.listObjectsTool(),
+		p.searchObjectsTool(),
+		p.headObjectTool(),
+		p.getObjectTool(),
+		p.presignTool(),
+		p.deleteObjectTool(),
+	}
+}
+
+// HealthCheck verifies the default account's credentials by listing buckets.
+func (p *Provider) HealthCheck(ctx context.Context) error {
+	ctx = providerkit.DefaultAccountContext(ctx, p.cfg)
+
+	return p.call(ctx, p.region(ctx, mcp.CallToolRequest{}), object{}, http.MethodGet, nil, nil)
+}
+
+// Shutdown has nothing to release.
+func (p *Provider) Shutdown(context.Context) error {
+	return nil
+}
+
+// region returns the region for a call.
+func (p *Provider) region(ctx context.Context, request mcp.CallToolRequest) string {
+	return p.auth.Region(ctx, request)
+}
+
+// object addresses a bucket, an object, or with neither the service itself.
+type object struct {
+	Bucket string
+	Key    string
+}
+
+// url returns the URL of o in region.
+func (p *Provider) url(region string, o object) *url.URL {
+	u, _ := url.Parse(strings.ReplaceAll(p.endpoint, "{region}", region))
+
+	segments := []string{}
+	switch {
+	case o.Bucket == "":
+	case p.pathStyle:
+		segments = append(segments, o.Bucket)
+	default:
+		u.Host = o.Bucket + "." + u.Host
+	}
+	if o.Key != "" {
+		segments = append(segments, o.Key)
+	}
+
+	u.Path = "/" + strings.Join(segments, "/")
+	u.RawPath = awsauth.EscapePath(u.Path)
+
+	return u
+}
+
+// send performs a signed request and returns the response of a successful
+// call. The caller closes the body.
+func (p *Provider) send(ctx context.Context, region string, o object, method string, query url.Values, header http.Header) (*http.Response, error) {
+	creds, err := p.auth.Credentials(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("s3: %w", err)
+	}
+
+	target := p.url(region, o)
+	target.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 request: %w", err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	signer := awsauth.Signer{Credentials: creds, Region: region, Service: "s3"}
+	if err := signer.Sign(req); err != nil {
+		return nil, fmt.Errorf("failed to sign S3 request: %w", err)
+	}
+
+	resp, err := p.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("S3 %s %s failed: %w", method, target.Path, err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBytes))
+
+		return nil, s3Error(resp, body)
+	}
+
+	return resp, nil
+}
+
+// call performs a signed request and decodes the XML response into out.
+func (p *Provider) call(ctx context.Context, region string, o object, method string, query url.Values, out any) error {
+	resp, err := p.send(ctx, region, o, method, query, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxListBytes))
+	if err != nil {
+		return fmt.Errorf("failed to read S3 response: %w", err)
+	}
+	if out != nil {
+		if err := xml.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("failed to decode S3 response: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// s3Error converts an S3 error response into a *providerkit.APIError. HEAD
+// responses carry no body, so the status alone is mapped. A bucket in another
+// region is reported with the region to retry in.
+func s3Error(resp *http.Response, body []byte) error {
+	status := resp.StatusCode
+	var decoded struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	if err := xml.Unmarshal(body, &decoded); err != nil || decoded.Code == "" {
+		return &providerkit.APIError{Provider: Name, Status: status, Message: http.StatusText(status)}
+	}
+
+	switch decoded.Code {
+	case "InvalidAccessKeyId", "SignatureDoesNotMatch", "ExpiredToken", "InvalidToken":
+		status = http.StatusUnauthorized
+	case "SlowDown":
+		status = http.StatusTooManyRequests
+	}
+
+	message := decoded.Code + ": " + decoded.Message
+	if region := resp.Header.Get("X-Amz-Bucket-Region"); region != "" && decoded.Code != "NoSuchKey" {
+		message += " (the bucket is in region " + region + ")"
+	}
+
+	return &providerkit.APIError{Provider: Name, Status: status, Message: message}
+}
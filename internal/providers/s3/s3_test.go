@@ -0,0 +1,459 @@
+package s3_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chadit/CloudMCP/internal/awsauth"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/internal/providerkit/providerkittest"
+	"github.com/chadit/CloudMCP/internal/providers/s3"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	accessKeyID     = "AKIDEXAMPLE"
+	secretAccessKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+	region          = "us-east-1"
+)
+
+var credentials = awsauth.Credentials{AccessKeyID: accessKeyID, SecretAccessKey: secretAccessKey}
+
+// stored is an object held by the fake.
+type stored struct {
+	data        []byte
+	contentType string
+	meta        map[string]string
+}
+
+// minio is the account tool calls run against.
+var minio = contracts.Account{Provider: s3.Name, Alias: "minio", Credential: "minio", Region: region}
+
+// fakeS3 is an in-process, path-style S3-compatible server. It checks the
+// SigV4 signature of every request, from headers or a presigned query.
+type fakeS3 struct {
+	mu      sync.Mutex
+	buckets map[string]map[string]stored
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := verify(r); err != nil {
+		writeError(w, http.StatusForbidden, "SignatureDoesNotMatch", err.Error())
+		return
+	}
+
+	bucketName, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucketName == "" {
+		f.listBuckets(w)
+		return
+	}
+	bucket, ok := f.buckets[bucketName]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist")
+		return
+	}
+	if key == "" {
+		f.listObjects(w, r.URL.Query(), bucket)
+		return
+	}
+
+	obj, ok := bucket[key]
+	switch {
+	case r.Method == http.MethodDelete:
+		delete(bucket, key)
+		w.WriteHeader(http.StatusNoContent)
+	case !ok && r.Method == http.MethodHead:
+		w.WriteHeader(http.StatusNotFound)
+	case !ok:
+		writeError(w, http.StatusNotFound, "NoSuchKey", "The specified key does not exist.")
+	default:
+		w.Header().Set("Content-Type", obj.contentType)
+		w.Header().Set("ETag", `"etag-`+key+`"`)
+		for name, value := range obj.meta {
+			w.Header().Set("X-Amz-Meta-"+name, value)
+		}
+		data := obj.data
+		status := http.StatusOK
+		var start, end int
+		if _, err := fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-%d", &start, &end); err == nil {
+			end = min(end+1, len(data))
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end-1, len(data)))
+			data, status = data[start:end], http.StatusPartialContent
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.WriteHeader(status)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(data)
+		}
+	}
+}
+
+func (f *fakeS3) listBuckets(w http.ResponseWriter) {
+	var body strings.Builder
+	body.WriteString("<ListAllMyBucketsResult><Buckets>")
+	for _, name := range sortedKeys(f.buckets) {
+		fmt.Fprintf(&body, "<Bucket><Name>%s</Name><CreationDate>2024-01-01T00:00:00.000Z</CreationDate></Bucket>", name)
+	}
+	body.WriteString("</Buckets></ListAllMyBucketsResult>")
+	_, _ = io.WriteString(w, body.String())
+}
+
+// listObjects implements ListObjectsV2. Continuation tokens are the last key
+// returned.
+func (f *fakeS3) listObjects(w http.ResponseWriter, query url.Values, bucket map[string]stored) {
+	prefix, delimiter := query.Get("prefix"), query.Get("delimiter")
+	after := max(query.Get("start-after"), query.Get("continuation-token"))
+	maxKeys, _ := strconv.Atoi(query.Get("max-keys"))
+
+	var entries strings.Builder
+	count, last, truncated := 0, "", false
+	seen := map[string]bool{}
+	for _, key := range sortedKeys(bucket) {
+		if !strings.HasPrefix(key, prefix) || key <= after {
+			continue
+		}
+		entry := key
+		if i := strings.Index(key[len(prefix):], delimiter); delimiter != "" && i >= 0 {
+			entry = key[:len(prefix)+i+len(delimiter)]
+			if seen[entry] || entry <= after {
+				continue
+			}
+			seen[entry] = true
+		}
+		if count == maxKeys {
+			truncated = true
+			break
+		}
+		count++
+		last = entry
+		if entry != key {
+			fmt.Fprintf(&entries, "<CommonPrefixes><Prefix>%s</Prefix></CommonPrefixes>", entry)
+			last = entry + "\xff"
+			continue
+		}
+		fmt.Fprintf(&entries, "<Contents><Key>%s</Key><Size>%d</Size></Contents>", key, len(bucket[key].data))
+	}
+
+	next := ""
+	if truncated {
+		next = "<NextContinuationToken>" + xmlEscape(last) + "</NextContinuationToken>"
+	}
+	fmt.Fprintf(w, "<ListBucketResult><IsTruncated>%t</IsTruncated>%s%s</ListBucketResult>", truncated, entries.String(), next)
+}
+
+// verify checks a request signed in headers or presigned in its query by
+// signing it again with the test credentials at the time it claims.
+func verify(r *http.Request) error {
+	query := r.URL.Query()
+	if signature := query.Get("X-Amz-Signature"); signature != "" {
+		signed, err := time.Parse("20060102T150405Z", query.Get("X-Amz-Date"))
+		if err != nil {
+			return err
+		}
+		seconds, _ := strconv.Atoi(query.Get("X-Amz-Expires"))
+		if time.Since(signed) > time.Duration(seconds)*time.Second {
+			return fmt.Errorf("request has expired")
+		}
+
+		unsigned := *r.URL
+		for name := range query {
+			if strings.HasPrefix(name, "X-Amz-") {
+				query.Del(name)
+			}
+		}
+		unsigned.RawQuery = query.Encode()
+		req, err := http.NewRequest(r.Method, "http://"+r.Host+unsigned.RequestURI(), nil)
+		if err != nil {
+			return err
+		}
+
+		signer := awsauth.Signer{Credentials: credentials, Region: region, Service: "s3", Now: func() time.Time { return signed }}
+		presigned, err := signer.Presign(req, time.Duration(seconds)*time.Second)
+		if err != nil {
+			return err
+		}
+		want, _ := url.Parse(presigned)
+		if want.Query().Get("X-Amz-Signature") != signature {
+			return fmt.Errorf("presigned signature mismatch")
+		}
+
+		return nil
+	}
+
+	authorization := r.Header.Get("Authorization")
+	_, signedPart, ok := strings.Cut(authorization, "SignedHeaders=")
+	if !ok {
+		return fmt.Errorf("missing SignedHeaders in %q", authorization)
+	}
+	signedHeaders, _, _ := strings.Cut(signedPart, ",")
+	signed, err := time.Parse("20060102T150405Z", r.Header.Get("X-Amz-Date"))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(r.Method, "http://"+r.Host+r.URL.RequestURI(), bytes.NewReader(nil))
+	if err != nil {
+		return err
+	}
+	for _, name := range strings.Split(signedHeaders, ";") {
+		if name != "host" && name != "x-amz-date" {
+			req.Header.Set(name, r.Header.Get(name))
+		}
+	}
+
+	signer := awsauth.Signer{Credentials: credentials, Region: region, Service: "s3", Now: func() time.Time { return signed }}
+	if err := signer.Sign(req); err != nil {
+		return err
+	}
+	if want := req.Header.Get("Authorization"); want != authorization {
+		return fmt.Errorf("signature mismatch: got %q, want %q", authorization, want)
+	}
+
+	return nil
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, message)
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// setup starts the fake with a media bucket and returns the provider's tools
+// by name.
+func setup(t *testing.T) (*fakeS3, map[string]contracts.Tool) {
+	t.Helper()
+
+	media := map[string]stored{
+		"notes/readme.txt":    {data: []byte("hello, object storage"), contentType: "text/plain", meta: map[string]string{"Owner": "ops"}},
+		"notes/a b+ü.txt":     {data: []byte("spaces"), contentType: "text/plain"},
+		"images/logo.png":     {data: []byte{0x89, 'P', 'N', 'G', 0xff, 0x00}, contentType: "image/png"},
+		"logs/web/app-1.gz":   {data: []byte("1")},
+		"logs/web/app-2.gz":   {data: []byte("2")},
+		"logs/web/other.gz":   {data: []byte("3")},
+		"logs/worker/app-.gz": {data: []byte("4")},
+	}
+	api := &fakeS3{buckets: map[string]map[string]stored{"media": media, "backups": {}}}
+	srv := providerkittest.Serve(t, api)
+
+	provider := s3.New()
+	tools := providerkittest.Setup(t, provider, contracts.ProviderConfig{
+		Settings:   map[string]string{"endpoint": srv.URL},
+		HTTPClient: srv.Client(),
+		Secrets:    providerkittest.Secrets{"minio": accessKeyID + ":" + secretAccessKey},
+	})
+
+	return api, tools
+}
+
+func TestObjectsList_PaginatesAndGroupsPrefixes(t *testing.T) {
+	t.Parallel()
+
+	_, tools := setup(t)
+	ctx := providerkittest.AccountContext(t, minio)
+
+	text, isError := providerkittest.Call(ctx, t, tools["s3_buckets_list"], map[string]any{})
+	require.False(t, isError, text)
+	var buckets pagination.Page[s3.Bucket]
+	require.NoError(t, json.Unmarshal([]byte(text), &buckets))
+	require.Equal(t, "backups", buckets.Items[0].Name)
+
+	var keys []string
+	params := map[string]any{"bucket": "media", "prefix": "logs/", "limit": float64(2)}
+	for {
+		text, isError := providerkittest.Call(ctx, t, tools["s3_objects_list"], params)
+		require.False(t, isError, text)
+
+		var page pagination.Page[s3.Object]
+		require.NoError(t, json.Unmarshal([]byte(text), &page))
+		for _, obj := range page.Items {
+			keys = append(keys, obj.Key)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		params["cursor"] = page.NextCursor
+	}
+	require.Equal(t, []string{"logs/web/app-1.gz", "logs/web/app-2.gz", "logs/web/other.gz", "logs/worker/app-.gz"}, keys)
+
+	text, isError = providerkittest.Call(ctx, t, tools["s3_objects_list"], map[string]any{"bucket": "media", "delimiter": "/"})
+	require.False(t, isError, text)
+	var top pagination.Page[s3.Object]
+	require.NoError(t, json.Unmarshal([]byte(text), &top))
+	require.Equal(t, []s3.Object{{Key: "images/", Prefix: true}, {Key: "logs/", Prefix: true}, {Key: "notes/", Prefix: true}}, top.Items)
+}
+
+func TestObjectsSearch_MatchesAndResumes(t *testing.T) {
+	t.Parallel()
+
+	_, tools := setup(t)
+	ctx := providerkittest.AccountContext(t, minio)
+
+	text, isError := providerkittest.Call(ctx, t, tools["s3_objects_search"], map[string]any{"bucket": "media", "prefix": "logs/", "pattern": "logs/*/app-*.gz"})
+	require.False(t, isError, text)
+	var all struct {
+		Matches   []s3.Object `json:"matches"`
+		Scanned   int         `json:"scanned"`
+		Truncated bool        `json:"truncated"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(text), &all))
+	require.Len(t, all.Matches, 3)
+	require.Equal(t, 4, all.Scanned)
+	require.False(t, all.Truncated)
+
+	text, isError = providerkittest.Call(ctx, t, tools["s3_objects_search"], map[string]any{"bucket": "media", "contains": "APP", "max_scan": float64(2)})
+	require.False(t, isError, text)
+	var first struct {
+		Matches     []s3.Object `json:"matches"`
+		Truncated   bool        `json:"truncated"`
+		ResumeAfter string      `json:"resume_after"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(text), &first))
+	require.True(t, first.Truncated)
+	require.Equal(t, "logs/web/app-1.gz", first.ResumeAfter)
+
+	text, isError = providerkittest.Call(ctx, t, tools["s3_objects_search"], map[string]any{"bucket": "media", "contains": "app", "resume_after": first.ResumeAfter})
+	require.False(t, isError, text)
+	require.Contains(t, text, "logs/web/app-2.gz")
+	require.NotContains(t, text, "logs/web/app-1.gz")
+
+	text, isError = providerkittest.Call(ctx, t, tools["s3_objects_search"], map[string]any{"bucket": "media", "pattern": "[", "contains": "x"})
+	require.True(t, isError)
+	require.Contains(t, text, "invalid pattern")
+}
+
+func TestObjectHeadAndGet(t *testing.T) {
+	t.Parallel()
+
+	_, tools := setup(t)
+	ctx := providerkittest.AccountContext(t, minio)
+
+	text, isError := providerkittest.Call(ctx, t, tools["s3_object_head"], map[string]any{"bucket": "media", "key": "notes/readme.txt"})
+	require.False(t, isError, text)
+	var meta s3.Metadata
+	require.NoError(t, json.Unmarshal([]byte(text), &meta))
+	require.Equal(t, int64(21), meta.Size)
+	require.Equal(t, "text/plain", meta.ContentType)
+	require.Equal(t, map[string]string{"owner": "ops"}, meta.UserMetadata)
+
+	text, isError = providerkittest.Call(ctx, t, tools["s3_object_get"], map[string]any{"bucket": "media", "key": "notes/readme.txt", "max_bytes": float64(5)})
+	require.False(t, isError, text)
+	var got struct {
+		Size      int64  `json:"size"`
+		Truncated bool   `json:"truncated"`
+		Encoding  string `json:"encoding"`
+		Content   string `json:"content"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(text), &got))
+	require.Equal(t, "hello", got.Content)
+	require.Equal(t, int64(21), got.Size)
+	require.True(t, got.Truncated)
+
+	text, isError = providerkittest.Call(ctx, t, tools["s3_object_get"], map[string]any{"bucket": "media", "key": "images/logo.png"})
+	require.False(t, isError, text)
+	require.NoError(t, json.Unmarshal([]byte(text), &got))
+	require.Equal(t, "base64", got.Encoding)
+	require.Equal(t, "iVBOR/8A", got.Content)
+	require.False(t, got.Truncated)
+
+	text, isError = providerkittest.Call(ctx, t, tools["s3_object_get"], map[string]any{"bucket": "media", "key": "notes/a b+ü.txt"})
+	require.False(t, isError, text)
+	require.Contains(t, text, `"content": "spaces"`, "keys needing encoding are signed as sent")
+
+	text, isError = providerkittest.Call(ctx, t, tools["s3_object_head"], map[string]any{"bucket": "media", "key": "missing"})
+	require.True(t, isError)
+	require.Contains(t, text, providerkit.ErrNotFound.Error())
+
+	text, isError = providerkittest.Call(ctx, t, tools["s3_object_get"], map[string]any{"bucket": "nope", "key": "missing"})
+	require.True(t, isError)
+	require.Contains(t, text, "NoSuchBucket")
+}
+
+func TestPresign_URLsWorkUntilTampered(t *testing.T) {
+	t.Parallel()
+
+	_, tools := setup(t)
+	ctx := providerkittest.AccountContext(t, minio)
+
+	text, isError := providerkittest.Call(ctx, t, tools["s3_object_presign"], map[string]any{"bucket": "media", "key": "notes/a b+ü.txt", "expires_seconds": float64(60)})
+	require.False(t, isError, text)
+	var result struct {
+		URL    string `json:"url"`
+		Method string `json:"method"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(text), &result))
+	require.Equal(t, http.MethodGet, result.Method)
+
+	resp, err := http.Get(result.URL) //nolint:noctx // Test request.
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+	require.Equal(t, "spaces", string(body))
+
+	resp, err = http.Get(strings.Replace(result.URL, "X-Amz-Expires=60", "X-Amz-Expires=600", 1)) //nolint:noctx // Test request.
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	_, isError = providerkittest.Call(ctx, t, tools["s3_object_presign"], map[string]any{"bucket": "media", "key": "k", "expires_seconds": float64(8 * 24 * 3600)})
+	require.True(t, isError)
+}
+
+func TestPresign_VirtualHostedOnAWS(t *testing.T) {
+	t.Parallel()
+
+	tools := providerkittest.Setup(t, s3.New(), contracts.ProviderConfig{Secrets: providerkittest.Secrets{"minio": accessKeyID + ":" + secretAccessKey}})
+	presign := tools["s3_object_presign"]
+
+	text, isError := providerkittest.Call(providerkittest.AccountContext(t, minio), t, presign, map[string]any{"bucket": "media", "key": "a/b.txt", "region": "eu-west-1", "method": "PUT"})
+	require.False(t, isError, text)
+	require.Contains(t, text, "https://media.s3.eu-west-1.amazonaws.com/a/b.txt?")
+	require.Contains(t, text, "%2Feu-west-1%2Fs3%2Faws4_request")
+}
+
+func TestObjectDelete_RequiresConfirmation(t *testing.T) {
+	t.Parallel()
+
+	api, tools := setup(t)
+	ctx := providerkittest.AccountContext(t, minio)
+
+	text, isError := providerkittest.Call(ctx, t, tools["s3_object_delete"], map[string]any{"bucket": "media", "key": "notes/readme.txt", "confirm": "yes"})
+	require.True(t, isError)
+	require.Contains(t, text, "not deleted")
+	require.Contains(t, api.buckets["media"], "notes/readme.txt")
+
+	text, isError = providerkittest.Call(ctx, t, tools["s3_object_delete"], map[string]any{"bucket": "media", "key": "notes/readme.txt", "confirm": "notes/readme.txt"})
+	require.False(t, isError, text)
+	require.NotContains(t, api.buckets["media"], "notes/readme.txt")
+}
@@ -0,0 +1,524 @@
+package s3
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/awsauth"
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/idempotency"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	// defaultReadBytes is how much of an object s3_object_get reads by default.
+	defaultReadBytes = 64 << 10
+
+	// maxReadBytes is the most s3_object_get reads in one call.
+	maxReadBytes = 1 << 20
+
+	// defaultPresignExpiry is the default validity of a presigned URL.
+	defaultPresignExpiry = time.Hour
+
+	// defaultSearchScan and maxSearchScan bound how many keys a search reads.
+	defaultSearchScan = 10000
+	maxSearchScan     = 100000
+)
+
+// Bucket is an S3 bucket.
+//
+//nolint:tagliatelle // XML element names match the S3 API.
+type Bucket struct {
+	Name    string `json:"name"    xml:"Name"`
+	Created string `json:"created" xml:"CreationDate"`
+}
+
+// Object is an object, or with Prefix set a common prefix, in a listing.
+//
+//nolint:tagliatelle // XML element names match the S3 API.
+type Object struct {
+	Key          string `json:"key"                     xml:"Key"`
+	Size         int64  `json:"size"                    xml:"Size"`
+	LastModified string `json:"last_modified,omitempty" xml:"LastModified"`
+	ETag         string `json:"etag,omitempty"          xml:"ETag"`
+	StorageClass string `json:"storage_class,omitempty" xml:"StorageClass"`
+	Prefix       bool   `json:"prefix,omitempty"        xml:"-"`
+}
+
+// Metadata describes an object without its content.
+//
+//nolint:tagliatelle // JSON field names follow the other providers.
+type Metadata struct {
+	Bucket       string            `json:"bucket"`
+	Key          string            `json:"key"`
+	Size         int64             `json:"size"`
+	ContentType  string            `json:"content_type,omitempty"`
+	ETag         string            `json:"etag,omitempty"`
+	LastModified string            `json:"last_modified,omitempty"`
+	StorageClass string            `json:"storage_class,omitempty"`
+	VersionID    string            `json:"version_id,omitempty"`
+	UserMetadata map[string]string `json:"user_metadata,omitempty"`
+}
+
+// listObjectsResponse is the body of ListObjectsV2.
+//
+//nolint:tagliatelle // XML element names match the S3 API.
+type listObjectsResponse struct {
+	Contents       []Object `xml:"Contents"`
+	CommonPrefixes []struct {
+		Prefix string `xml:"Prefix"`
+	} `xml:"CommonPrefixes"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// entries returns the objects and common prefixes of a listing in key order.
+func (r listObjectsResponse) entries() []Object {
+	entries := make([]Object, 0, len(r.Contents)+len(r.CommonPrefixes))
+	entries = append(entries, r.Contents...)
+	for _, prefix := range r.CommonPrefixes {
+		entries = append(entries, Object{Key: prefix.Prefix, Prefix: true})
+	}
+	slices.SortFunc(entries, func(a, b Object) int { return strings.Compare(a.Key, b.Key) })
+
+	return entries
+}
+
+// listObjects calls ListObjectsV2.
+func (p *Provider) listObjects(ctx context.Context, region, bucket string, query url.Values) (listObjectsResponse, error) {
+	query.Set("list-type", "2")
+
+	var resp listObjectsResponse
+	err := p.call(ctx, region, object{Bucket: bucket}, http.MethodGet, query, &resp)
+
+	return resp, err
+}
+
+// withRegion adds the optional region argument.
+func withRegion() mcp.ToolOption {
+	return mcp.WithString(providerkit.RegionParam, mcp.Description("Region of the bucket, such as eu-west-1 (default: the account's region)"))
+}
+
+// withObject adds the required bucket and key arguments.
+func withObject() []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithString("bucket", mcp.Required(), mcp.Description("Bucket name")),
+		mcp.WithString("key", mcp.Required(), mcp.Description("Object key")),
+		withRegion(),
+	}
+}
+
+// requireObject reads the bucket and key arguments.
+func requireObject(request mcp.CallToolRequest) (object, error) {
+	bucket, err := request.RequireString("bucket")
+	if err != nil {
+		return object{}, err
+	}
+	key, err := request.RequireString("key")
+	if err != nil {
+		return object{}, err
+	}
+
+	return object{Bucket: bucket, Key: key}, nil
+}
+
+func (p *Provider) listBucketsTool() *providerkit.Tool {
+	tool := mcp.NewTool("s3_buckets_list",
+		mcp.WithDescription("Lists the buckets of the account"),
+		withRegion(),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// ListBuckets returns every bucket at once, so pages are cut from the full list
+		cursors, req, err := providerkit.ParsePage(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		var resp struct {
+			Buckets []Bucket `xml:"Buckets>Bucket"`
+		}
+		if err := p.call(ctx, p.region(ctx, request), object{}, http.MethodGet, nil, &resp); err != nil {
+			return providerkit.Result(nil, err)
+		}
+		if resp.Buckets == nil {
+			resp.Buckets = []Bucket{}
+		}
+
+		return providerkit.Result(pagination.Slice(cursors, req, resp.Buckets), nil)
+	})
+}
+
+func (p *Provider) listObjectsTool() *providerkit.Tool {
+	tool := mcp.NewTool("s3_objects_list",
+		mcp.WithDescription("Lists the objects of a bucket under a prefix. With delimiter \"/\", keys below the next / are grouped into prefix entries, like directories."),
+		mcp.WithString("bucket", mcp.Required(), mcp.Description("Bucket name")),
+		withRegion(),
+		mcp.WithString("prefix", mcp.Description("Only list keys starting with this prefix (optional)")),
+		mcp.WithString("delimiter", mcp.Description("Group keys by this delimiter, usually / (optional)")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		bucket, err := request.RequireString("bucket")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		cursors, req, err := providerkit.ParsePage(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		token, size := req.TokenPage(1, 1000)
+		query := url.Values{"max-keys": {strconv.Itoa(size)}}
+		if prefix := request.GetString("prefix", ""); prefix != "" {
+			query.Set("prefix", prefix)
+		}
+		if delimiter := request.GetString("delimiter", ""); delimiter != "" {
+			query.Set("delimiter", delimiter)
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		resp, err := p.listObjects(ctx, p.region(ctx, request), bucket, query)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+		next := ""
+		if resp.IsTruncated {
+			next = resp.NextContinuationToken
+		}
+
+		return providerkit.Result(pagination.FromToken(cursors, req, size, resp.entries(), next), nil)
+	})
+}
+
+// searchResult is the result of s3_objects_search.
+//
+//nolint:tagliatelle // JSON field names follow the other providers.
+type searchResult struct {
+	Matches     []Object `json:"matches"`
+	Scanned     int      `json:"scanned"`
+	Truncated   bool     `json:"truncated"`
+	ResumeAfter string   `json:"resume_after,omitempty"`
+}
+
+func (p *Provider) searchObjectsTool() *providerkit.Tool {
+	tool := mcp.NewTool("s3_objects_search",
+		mcp.WithDescription("Searches the keys under a prefix for a glob pattern or substring. "+
+			"Keys are scanned in order; when the scan stops early, pass resume_after to continue."),
+		mcp.WithString("bucket", mcp.Required(), mcp.Description("Bucket name")),
+		withRegion(),
+		mcp.WithString("prefix", mcp.Description("Only search keys starting with this prefix (optional)")),
+		mcp.WithString("pattern", mcp.Description("Glob matched against the whole key, such as logs/*/app-*.gz (optional)")),
+		mcp.WithString("contains", mcp.Description("Case-insensitive substring of the key (optional)")),
+		mcp.WithNumber("max_results", mcp.Description("Stop after this many matches (default 100)"), mcp.Min(1), mcp.Max(1000)),
+		mcp.WithNumber("max_scan", mcp.Description(fmt.Sprintf("Stop after scanning this many keys (default %d)", defaultSearchScan)),
+			mcp.Min(1), mcp.Max(maxSearchScan)),
+		mcp.WithString("resume_after", mcp.Description("Continue a search after this key (optional)")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		bucket, err := request.RequireString("bucket")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		pattern := request.GetString("pattern", "")
+		if _, err := path.Match(pattern, ""); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid pattern %q: %v", pattern, err)), nil
+		}
+		contains := strings.ToLower(request.GetString("contains", ""))
+		maxResults := min(max(request.GetInt("max_results", 100), 1), 1000)
+		maxScan := min(max(request.GetInt("max_scan", defaultSearchScan), 1), maxSearchScan)
+
+		match := func(key string) bool {
+			if pattern != "" {
+				if ok, _ := path.Match(pattern, key); !ok {
+					return false
+				}
+			}
+
+			return contains == "" || strings.Contains(strings.ToLower(key), contains)
+		}
+
+		result := searchResult{Matches: []Object{}}
+		region := p.region(ctx, request)
+		progress := contracts.ProgressFromContext(ctx)
+		query := url.Values{}
+		if prefix := request.GetString("prefix", ""); prefix != "" {
+			query.Set("prefix", prefix)
+		}
+		if after := request.GetString("resume_after", ""); after != "" {
+			query.Set("start-after", after)
+		}
+
+	scan:
+		for {
+			query.Set("max-keys", strconv.Itoa(min(maxScan-result.Scanned, 1000)))
+			resp, err := p.listObjects(ctx, region, bucket, query)
+			if err != nil {
+				return providerkit.Result(nil, err)
+			}
+
+			for i, obj := range resp.Contents {
+				result.Scanned++
+				if match(obj.Key) {
+					result.Matches = append(result.Matches, obj)
+				}
+				if len(result.Matches) == maxResults || result.Scanned == maxScan {
+					if i < len(resp.Contents)-1 || resp.IsTruncated {
+						result.Truncated, result.ResumeAfter = true, obj.Key
+					}
+					break scan
+				}
+			}
+			progress.Report(float64(result.Scanned), 0, fmt.Sprintf("scanned %d keys", result.Scanned))
+
+			if !resp.IsTruncated {
+				break
+			}
+			query.Del("start-after")
+			query.Set("continuation-token", resp.NextContinuationToken)
+		}
+
+		return providerkit.Result(result, nil)
+	})
+}
+
+func (p *Provider) headObjectTool() *providerkit.Tool {
+	tool := mcp.NewTool("s3_object_head",
+		append(withObject(),
+			mcp.WithDescription("Returns an object's size, content type, ETag and user metadata without reading it"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(true),
+			format.WithParam(),
+		)...,
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		o, err := requireObject(request)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		resp, err := p.send(ctx, p.region(ctx, request), o, http.MethodHead, nil, nil)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+		resp.Body.Close()
+
+		return providerkit.Result(metadata(o, resp), nil)
+	})
+}
+
+// metadata reads an object's metadata from response headers.
+func metadata(o object, resp *http.Response) Metadata {
+	meta := Metadata{
+		Bucket:       o.Bucket,
+		Key:          o.Key,
+		Size:         resp.ContentLength,
+		ContentType:  resp.Header.Get("Content-Type"),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		StorageClass: resp.Header.Get("X-Amz-Storage-Class"),
+		VersionID:    resp.Header.Get("X-Amz-Version-Id"),
+	}
+	for name, values := range resp.Header {
+		if key, ok := strings.CutPrefix(strings.ToLower(name), "x-amz-meta-"); ok {
+			if meta.UserMetadata == nil {
+				meta.UserMetadata = map[string]string{}
+			}
+			meta.UserMetadata[key] = strings.Join(values, ",")
+		}
+	}
+
+	return meta
+}
+
+// content is the result of s3_object_get.
+//
+//nolint:tagliatelle // JSON field names follow the other providers.
+type content struct {
+	Metadata
+
+	Offset    int64  `json:"offset"`
+	Length    int    `json:"length"`
+	Truncated bool   `json:"truncated"`
+	Encoding  string `json:"encoding"`
+	Content   string `json:"content"`
+}
+
+func (p *Provider) getObjectTool() *providerkit.Tool {
+	tool := mcp.NewTool("s3_object_get",
+		append(withObject(),
+			mcp.WithDescription("Reads an object, or a byte range of it. Text is returned as is and binary content base64-encoded. "+
+				"Larger objects are truncated; read further with offset."),
+			mcp.WithNumber("offset", mcp.Description("First byte to read (default 0)"), mcp.Min(0)),
+			mcp.WithNumber("max_bytes", mcp.Description(fmt.Sprintf("Most bytes to read (default %d, at most %d)", defaultReadBytes, maxReadBytes)),
+				mcp.Min(1), mcp.Max(maxReadBytes)),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(true),
+			format.WithParam(),
+		)...,
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		o, err := requireObject(request)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		offset := int64(max(request.GetInt("offset", 0), 0))
+		limit := int64(min(max(request.GetInt("max_bytes", defaultReadBytes), 1), maxReadBytes))
+
+		header := http.Header{"Range": {fmt.Sprintf("bytes=%d-%d", offset, offset+limit-1)}}
+		resp, err := p.send(ctx, p.region(ctx, request), o, http.MethodGet, nil, header)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+		defer resp.Body.Close()
+
+		data, err := io.ReadAll(io.LimitReader(resp.Body, limit))
+		if err != nil {
+			return providerkit.Result(nil, fmt.Errorf("failed to read s3://%s/%s: %w", o.Bucket, o.Key, err))
+		}
+
+		result := content{Metadata: metadata(o, resp), Offset: offset, Length: len(data)}
+		result.Size = objectSize(resp, offset, len(data))
+		result.Truncated = offset+int64(len(data)) < result.Size
+		if utf8.Valid(data) {
+			result.Encoding, result.Content = "text", string(data)
+		} else {
+			result.Encoding, result.Content = "base64", base64.StdEncoding.EncodeToString(data)
+		}
+
+		return providerkit.Result(result, nil)
+	})
+}
+
+// objectSize returns the full size of an object from a ranged response. A
+// server ignoring the range returns the whole object.
+func objectSize(resp *http.Response, offset int64, read int) int64 {
+	if resp.StatusCode == http.StatusPartialContent {
+		if _, total, ok := strings.Cut(resp.Header.Get("Content-Range"), "/"); ok {
+			if size, err := strconv.ParseInt(total, 10, 64); err == nil {
+				return size
+			}
+		}
+	}
+	if resp.ContentLength >= 0 {
+		return resp.ContentLength
+	}
+
+	return offset + int64(read)
+}
+
+// presigned is the result of s3_object_presign.
+//
+//nolint:tagliatelle // JSON field names follow the other providers.
+type presigned struct {
+	URL       string `json:"url"`
+	Method    string `json:"method"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+func (p *Provider) presignTool() *providerkit.Tool {
+	tool := mcp.NewTool("s3_object_presign",
+		append(withObject(),
+			mcp.WithDescription("Creates a presigned URL that lets anyone holding it download (GET) or upload (PUT) the object until it expires"),
+			mcp.WithString("method", mcp.Description("GET to download or PUT to upload (default GET)"), mcp.Enum(http.MethodGet, http.MethodPut)),
+			mcp.WithNumber("expires_seconds", mcp.Description("Validity in seconds (default 3600, at most 604800)"),
+				mcp.Min(1), mcp.Max(awsauth.MaxPresignExpiry.Seconds())),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			format.WithParam(),
+		)...,
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		o, err := requireObject(request)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		method := request.GetString("method", http.MethodGet)
+		if method != http.MethodGet && method != http.MethodPut {
+			return mcp.NewToolResultError("method must be GET or PUT"), nil
+		}
+		expires := time.Duration(request.GetInt("expires_seconds", int(defaultPresignExpiry.Seconds()))) * time.Second
+
+		creds, err := p.auth.Credentials(ctx)
+		if err != nil {
+			return providerkit.Result(nil, fmt.Errorf("s3: %w", err))
+		}
+		region := p.region(ctx, request)
+		req, err := http.NewRequestWithContext(ctx, method, p.url(region, o).String(), nil)
+		if err != nil {
+			return providerkit.Result(nil, fmt.Errorf("failed to create S3 request: %w", err))
+		}
+
+		now := time.Now().UTC()
+		signer := awsauth.Signer{Credentials: creds, Region: region, Service: "s3", Now: func() time.Time { return now }}
+		signed, err := signer.Presign(req, expires)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return providerkit.Result(presigned{URL: signed, Method: method, ExpiresAt: now.Add(expires).Format(time.RFC3339)}, nil)
+	})
+}
+
+func (p *Provider) deleteObjectTool() *providerkit.Tool {
+	tool := mcp.NewTool("s3_object_delete",
+		append(withObject(),
+			mcp.WithDescription("Deletes an object. This cannot be undone unless the bucket is versioned."),
+			providerkit.WithConfirmParam("the object key"),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(true),
+			idempotency.WithParam(),
+			format.WithParam(),
+		)...,
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		o, err := requireObject(request)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if refused := providerkit.Confirm(request, "deleted", "the object key", o.Key); refused != nil {
+			return refused, nil
+		}
+
+		resp, err := p.send(ctx, p.region(ctx, request), o, http.MethodDelete, nil, nil)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+		resp.Body.Close()
+
+		return providerkit.Result(map[string]string{"bucket": o.Bucket, "key": o.Key, "status": "deleted"}, nil)
+	})
+}
@@ -0,0 +1,182 @@
+package scaleway
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+)
+
+// ServerType is a Scaleway Instance server type with its availability in the
+// zone it was listed for.
+//
+//nolint:tagliatelle // JSON field names match the Scaleway API.
+type ServerType struct {
+	Name         string  `json:"name"`
+	NCPUs        int     `json:"ncpus"`
+	RAM          int64   `json:"ram"`
+	Arch         string  `json:"arch"`
+	GPU          int     `json:"gpu,omitempty"`
+	HourlyPrice  float64 `json:"hourly_price"`
+	MonthlyPrice float64 `json:"monthly_price"`
+	Baremetal    bool    `json:"baremetal,omitempty"`
+	Availability string  `json:"availability,omitempty"`
+}
+
+// Zone is a Scaleway availability zone.
+type Zone struct {
+	Name   string `json:"name"`
+	Region string `json:"region"`
+}
+
+// zones are the Instance zones. The Instance API has no endpoint listing them.
+var zones = []Zone{
+	{Name: "fr-par-1", Region: "fr-par"},
+	{Name: "fr-par-2", Region: "fr-par"},
+	{Name: "fr-par-3", Region: "fr-par"},
+	{Name: "nl-ams-1", Region: "nl-ams"},
+	{Name: "nl-ams-2", Region: "nl-ams"},
+	{Name: "nl-ams-3", Region: "nl-ams"},
+	{Name: "pl-waw-1", Region: "pl-waw"},
+	{Name: "pl-waw-2", Region: "pl-waw"},
+	{Name: "pl-waw-3", Region: "pl-waw"},
+}
+
+// Image is a Scaleway Instance image.
+//
+//nolint:tagliatelle // JSON field names match the Scaleway API.
+type Image struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Arch         string `json:"arch"`
+	Public       bool   `json:"public"`
+	State        string `json:"state"`
+	Zone         string `json:"zone"`
+	CreationDate string `json:"creation_date"`
+}
+
+func (p *Provider) listServerTypesTool() *providerkit.Tool {
+	tool := mcp.NewTool("scaleway_server_types_list",
+		mcp.WithDescription("Lists the Instance server types of a zone with their resources, prices and current availability"),
+		withZone(),
+		mcp.WithString("arch", mcp.Description("Only list types of this architecture (optional)"), mcp.Enum("x86_64", "arm64")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		cursors, req, err := providerkit.ParsePage(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+		types, err := p.serverTypes(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		if arch := request.GetString("arch", ""); arch != "" {
+			types = slices.DeleteFunc(types, func(t ServerType) bool { return t.Arch != arch })
+		}
+
+		return providerkit.Result(pagination.Slice(cursors, req, types), nil)
+	})
+}
+
+// serverTypes fetches the server types of the call's zone, sorted by name,
+// with their availability.
+func (p *Provider) serverTypes(ctx context.Context, request mcp.CallToolRequest) ([]ServerType, error) {
+	path := p.zonePath(ctx, request) + "/products/servers"
+	catalog := map[string]ServerType{}
+	for page := 1; ; page++ {
+		var resp struct {
+			Servers map[string]ServerType `json:"servers"`
+		}
+		total, err := p.fetch(ctx, path, nil, page, maxPageSize, &resp)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(catalog, resp.Servers)
+		if len(resp.Servers) == 0 || page*maxPageSize >= total {
+			break
+		}
+	}
+
+	var availability struct {
+		Servers map[string]struct {
+			Availability string `json:"availability"`
+		} `json:"servers"`
+	}
+	if err := p.api.Do(ctx, http.MethodGet, path+"/availability", url.Values{"per_page": {"100"}}, nil, &availability); err != nil {
+		return nil, err
+	}
+
+	types := make([]ServerType, 0, len(catalog))
+	for _, name := range slices.Sorted(maps.Keys(catalog)) {
+		serverType := catalog[name]
+		serverType.Name = name
+		serverType.Availability = availability.Servers[name].Availability
+		types = append(types, serverType)
+	}
+
+	return types, nil
+}
+
+func (p *Provider) listZonesTool() *providerkit.Tool {
+	tool := mcp.NewTool("scaleway_zones_list",
+		mcp.WithDescription("Lists the Scaleway zones Instance servers can run in"),
+		mcp.WithString("region", mcp.Description("Only list zones of this region, such as fr-par (optional)")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		cursors, req, err := providerkit.ParsePage(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		region := request.GetString("region", "")
+		matching := slices.DeleteFunc(slices.Clone(zones), func(z Zone) bool {
+			return region != "" && z.Region != region
+		})
+
+		return providerkit.Result(pagination.Slice(cursors, req, matching), nil)
+	})
+}
+
+func (p *Provider) listImagesTool() *providerkit.Tool {
+	tool := mcp.NewTool("scaleway_images_list",
+		mcp.WithDescription("Lists the Instance images of a zone, public ones by default"),
+		withZone(),
+		mcp.WithString("name", mcp.Description("Only list images whose name contains this text, such as Ubuntu (optional)")),
+		mcp.WithString("arch", mcp.Description("Only list images of this architecture (optional)"), mcp.Enum("x86_64", "arm64")),
+		mcp.WithBoolean("public", mcp.Description("List public images instead of the project's own (default true)"), mcp.DefaultBool(true)),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		query := url.Values{"public": {strconv.FormatBool(request.GetBool("public", true))}}
+		for _, filter := range []string{"name", "arch"} {
+			if value := request.GetString(filter, ""); value != "" {
+				query.Set(filter, value)
+			}
+		}
+
+		return providerkit.Result(list[Image](ctx, p, request, p.zonePath(ctx, request)+"/images", "images", query))
+	})
+}
@@ -0,0 +1,201 @@
+// Package scaleway implements the Scaleway Instance API provider.
+package scaleway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	// Name is the provider name used in settings and accounts.
+	Name = "scaleway"
+
+	// DefaultAPIURL is the Instance API endpoint, overridden by the api_url
+	// setting.
+	DefaultAPIURL = "https://api.scaleway.com/instance/v1"
+
+	// DefaultTokenRef is the credential used when neither the account nor the
+	// token_ref setting names one, as for the scw CLI.
+	DefaultTokenRef = "env:SCW_SECRET_KEY"
+
+	// DefaultZone is the zone used when neither the call, the account nor the
+	// zone setting names one.
+	DefaultZone = "fr-par-1"
+
+	// maxPageSize is the largest per_page the API accepts.
+	maxPageSize = 100
+)
+
+// ErrNoProject is returned when a server is created without a project.
+var ErrNoProject = errors.New("no project: pass project, or set CLOUD_MCP_SCALEWAY_PROJECT_ID or SCW_DEFAULT_PROJECT_ID")
+
+// Provider is the Scaleway provider.
+type Provider struct {
+	cfg          contracts.ProviderConfig
+	api          *providerkit.API
+	pollInterval time.Duration
+}
+
+// New creates an uninitialized Scaleway provider.
+func New() contracts.Provider {
+	return &Provider{}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return Name
+}
+
+// Initialize configures the Instance API client, which sends each account's
+// secret key as X-Auth-Token.
+func (p *Provider) Initialize(_ context.Context, cfg contracts.ProviderConfig) error {
+	p.cfg = cfg
+	p.pollInterval = providerkit.PollInterval(cfg)
+	p.api = providerkit.NewTokenAPI(cfg, providerkit.TokenAPI{
+		Provider:        Name,
+		DefaultURL:      DefaultAPIURL,
+		DefaultTokenRef: DefaultTokenRef,
+		Header:          "X-Auth-Token",
+		ErrorMessage:    errorMessage,
+	})
+
+	return nil
+}
+
+// Tools returns the Scaleway tools.
+func (p *Provider) Tools() []contracts.Tool {
+	return []contracts.Tool{
+		p.listServersTool(),
+		p.getServerTool(),
+		p.createServerTool(),
+		p.deleteServerTool(),
+		p.serverActionTool(),
+		p.listServerTypesTool(),
+		p.listZonesTool(),
+		p.listImagesTool(),
+	}
+}
+
+// HealthCheck verifies the default account's secret key with a minimal query.
+func (p *Provider) HealthCheck(ctx context.Context) error {
+	ctx = providerkit.DefaultAccountContext(ctx, p.cfg)
+	path := p.zonePath(ctx, mcp.CallToolRequest{}) + "/servers"
+
+	return p.api.Do(ctx, http.MethodGet, path, url.Values{"per_page": {"1"}}, nil, nil)
+}
+
+// Shutdown has nothing to release.
+func (p *Provider) Shutdown(context.Context) error {
+	return nil
+}
+
+// errorMessage extracts the type and message of a Scaleway error response.
+func errorMessage(body []byte) string {
+	var decoded struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &decoded); err != nil || decoded.Message == "" {
+		return ""
+	}
+
+	return strings.Trim(decoded.Type+": "+decoded.Message, ": ")
+}
+
+// zone returns the zone of a call: the zone argument, the account's region, the
+// zone setting or SCW_DEFAULT_ZONE, or DefaultZone.
+func (p *Provider) zone(ctx context.Context, request mcp.CallToolRequest) string {
+	if zone := request.GetString("zone", ""); zone != "" {
+		return zone
+	}
+	if account, ok := contracts.AccountFromContext(ctx); ok && account.Region != "" {
+		return account.Region
+	}
+
+	if zone := p.cfg.Setting("zone", os.Getenv("SCW_DEFAULT_ZONE")); zone != "" {
+		return zone
+	}
+
+	return DefaultZone
+}
+
+// zonePath returns the API path of the call's zone.
+func (p *Provider) zonePath(ctx context.Context, request mcp.CallToolRequest) string {
+	return "/zones/" + url.PathEscape(p.zone(ctx, request))
+}
+
+// project returns the project of a call: the project argument, the project_id
+// setting, or SCW_DEFAULT_PROJECT_ID.
+func (p *Provider) project(request mcp.CallToolRequest) (string, error) {
+	project := request.GetString("project", p.cfg.Setting("project_id", os.Getenv("SCW_DEFAULT_PROJECT_ID")))
+	if project == "" {
+		return "", ErrNoProject
+	}
+
+	return project, nil
+}
+
+// withZone adds the optional zone argument.
+func withZone() mcp.ToolOption {
+	return mcp.WithString("zone", mcp.Description("Zone, such as fr-par-1 (default: the account's region)"))
+}
+
+// fetch fetches one page of a Scaleway collection into resp and returns the
+// total number of items from the X-Total-Count header.
+func (p *Provider) fetch(ctx context.Context, path string, query url.Values, page, size int, resp any) (int, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("page", strconv.Itoa(page))
+	query.Set("per_page", strconv.Itoa(size))
+
+	header, err := p.api.Call(ctx, providerkit.Call{Method: http.MethodGet, Path: path, Query: query, Out: resp})
+	if err != nil {
+		return 0, err
+	}
+	total, _ := strconv.Atoi(header.Get("X-Total-Count"))
+
+	return total, nil
+}
+
+// list fetches one page of a Scaleway collection. key is the name of the
+// collection in the response body.
+func list[T any](ctx context.Context, p *Provider, request mcp.CallToolRequest, path, key string, query url.Values) (pagination.Page[T], error) {
+	cursors, req, err := providerkit.ParsePage(ctx, request)
+	if err != nil {
+		return pagination.Page[T]{}, err
+	}
+
+	page, size := req.ProviderPage(1, maxPageSize)
+	var resp map[string]json.RawMessage
+	total, err := p.fetch(ctx, path, query, page, size, &resp)
+	if err != nil {
+		return pagination.Page[T]{}, err
+	}
+
+	items := []T{}
+	if raw, ok := resp[key]; ok {
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return pagination.Page[T]{}, fmt.Errorf("failed to decode %s: %w", key, err)
+		}
+	}
+
+	return pagination.FromProviderPage(cursors, req, size, items, page*size < total), nil
+}
+
+// stringItems is the item schema of string array arguments.
+var stringItems = map[string]any{"type": "string"}
@@ -0,0 +1,260 @@
+package scaleway_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/internal/providerkit/providerkittest"
+	"github.com/chadit/CloudMCP/internal/providers/scaleway"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const zone = "nl-ams-1"
+
+// edge is the default account, which tool calls run against.
+var edge = contracts.Account{Provider: scaleway.Name, Alias: "edge", Credential: "scw-edge", Default: true}
+
+// fakeAPI is a stub of the Scaleway Instance API in one zone. Tasks advance
+// 50% per poll; tasks on the server "broken", which is also the ID a server
+// created with that name gets, fail.
+type fakeAPI struct {
+	mu       sync.Mutex
+	servers  int
+	tasks    map[string]*scaleway.Task
+	states   map[string]string
+	requests []string
+	bodies   []map[string]any
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+	if r.Header.Get("X-Auth-Token") != "scw-secret" {
+		writeError(w, http.StatusUnauthorized, "denied_authentication", "invalid authentication")
+		return
+	}
+	var body map[string]any
+	if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+		_ = json.Unmarshal(data, &body)
+		f.bodies = append(f.bodies, body)
+	}
+
+	base := "/zones/" + zone
+	path := strings.TrimPrefix(r.URL.Path, base)
+	switch {
+	case !strings.HasPrefix(r.URL.Path, base+"/"):
+		writeError(w, http.StatusNotFound, "not_found", "unknown zone")
+	case r.Method == http.MethodGet && path == "/servers":
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		size, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		start := min((page-1)*size, f.servers)
+		end := min(start+size, f.servers)
+		servers := []scaleway.Server{}
+		for i := start; i < end; i++ {
+			servers = append(servers, scaleway.Server{ID: fmt.Sprintf("srv-%d", i), Name: fmt.Sprintf("edge-%d", i)})
+		}
+		w.Header().Set("X-Total-Count", strconv.Itoa(f.servers))
+		providerkittest.WriteJSON(w, http.StatusOK, map[string]any{"servers": servers})
+	case r.Method == http.MethodPost && path == "/servers":
+		id := "new"
+		if body["name"] == "broken" {
+			id = "broken"
+		}
+		f.states[id] = "stopped"
+		providerkittest.WriteJSON(w, http.StatusCreated, map[string]any{"server": scaleway.Server{ID: id, Name: fmt.Sprint(body["name"]), State: "stopped"}})
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/action"):
+		id := strings.Split(path, "/")[2]
+		task := &scaleway.Task{ID: fmt.Sprintf("task-%d-%s", len(f.tasks)+1, id), Description: fmt.Sprint(body["action"]), Status: "pending"}
+		f.tasks[task.ID] = task
+		f.states[id] = map[string]string{"poweron": "running", "reboot": "running"}[fmt.Sprint(body["action"])]
+		providerkittest.WriteJSON(w, http.StatusAccepted, map[string]any{"task": task})
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/tasks/"):
+		task, ok := f.tasks[strings.TrimPrefix(path, "/tasks/")]
+		if !ok {
+			writeError(w, http.StatusNotFound, "not_found", "task not found")
+			return
+		}
+		task.Progress = min(task.Progress+50, 100)
+		task.Status = "started"
+		if task.Progress == 100 {
+			task.Status = "success"
+			if strings.HasSuffix(task.ID, "-broken") {
+				task.Status = "failure"
+			}
+		}
+		providerkittest.WriteJSON(w, http.StatusOK, map[string]any{"task": task})
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/servers/"):
+		id := strings.TrimPrefix(path, "/servers/")
+		state, ok := f.states[id]
+		if !ok {
+			writeError(w, http.StatusNotFound, "unknown_resource", `"instance_server" not found`)
+			return
+		}
+		providerkittest.WriteJSON(w, http.StatusOK, map[string]any{"server": scaleway.Server{ID: id, State: state, Zone: zone}})
+	case r.Method == http.MethodGet && path == "/products/servers":
+		w.Header().Set("X-Total-Count", "3")
+		providerkittest.WriteJSON(w, http.StatusOK, map[string]any{"servers": map[string]any{
+			"PLAY2-NANO": map[string]any{"ncpus": 1, "arch": "x86_64", "hourly_price": 0.014},
+			"DEV1-S":     map[string]any{"ncpus": 2, "arch": "x86_64", "hourly_price": 0.0088},
+			"AMP2-C1":    map[string]any{"ncpus": 1, "arch": "arm64", "hourly_price": 0.012},
+		}})
+	case r.Method == http.MethodGet && path == "/products/servers/availability":
+		providerkittest.WriteJSON(w, http.StatusOK, map[string]any{"servers": map[string]any{
+			"DEV1-S": map[string]string{"availability": "scarce"}, "PLAY2-NANO": map[string]string{"availability": "available"},
+		}})
+	default:
+		writeError(w, http.StatusNotFound, "not_found", "resource not found")
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, kind, message string) {
+	providerkittest.WriteJSON(w, status, map[string]string{"type": kind, "message": message})
+}
+
+// setup starts a stub API and returns the provider and its tools by name.
+func setup(t *testing.T, servers int) (*fakeAPI, contracts.Provider, map[string]contracts.Tool) {
+	t.Helper()
+
+	api := &fakeAPI{servers: servers, tasks: map[string]*scaleway.Task{}, states: map[string]string{"web": "running", "broken": "running"}}
+	srv := providerkittest.Serve(t, api)
+
+	provider := scaleway.New()
+	tools := providerkittest.Setup(t, provider, contracts.ProviderConfig{
+		Settings:   map[string]string{"api_url": srv.URL, "poll_interval": "1ms", "project_id": "proj-1", "zone": zone},
+		HTTPClient: srv.Client(),
+		Secrets:    providerkittest.Secrets{"scw-edge": "scw-secret"},
+		Accounts:   []contracts.Account{edge},
+	})
+
+	return api, provider, tools
+}
+
+func TestHealthCheck_UsesZoneSetting(t *testing.T) {
+	t.Parallel()
+
+	api, provider, _ := setup(t, 0)
+	require.NoError(t, provider.HealthCheck(t.Context()))
+	require.Equal(t, []string{"GET /zones/" + zone + "/servers"}, api.requests)
+}
+
+func TestServersList_FollowsTotalCount(t *testing.T) {
+	t.Parallel()
+
+	_, _, tools := setup(t, 5)
+	ctx := providerkittest.AccountContext(t, edge)
+
+	var names []string
+	params := map[string]any{"limit": float64(2)}
+	for {
+		text, isError := providerkittest.Call(ctx, t, tools["scaleway_servers_list"], params)
+		require.False(t, isError, text)
+
+		var page pagination.Page[scaleway.Server]
+		require.NoError(t, json.Unmarshal([]byte(text), &page))
+		for _, server := range page.Items {
+			names = append(names, server.Name)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		params["cursor"] = page.NextCursor
+	}
+
+	require.Equal(t, []string{"edge-0", "edge-1", "edge-2", "edge-3", "edge-4"}, names)
+}
+
+func TestServerCreate_PowersOnAndWaits(t *testing.T) {
+	t.Parallel()
+
+	api, _, tools := setup(t, 0)
+	progress := &providerkittest.Progress{}
+	ctx := contracts.WithProgressReporter(providerkittest.AccountContext(t, edge), progress)
+
+	text, isError := providerkittest.Call(ctx, t, tools["scaleway_server_create"], map[string]any{
+		"name": "edge", "commercial_type": "DEV1-S", "image": "img-ubuntu", "tags": []any{"edge"},
+	})
+	require.False(t, isError, text)
+	require.Contains(t, text, `"state": "running"`, "the server is re-read once the task is done")
+	require.Equal(t, []float64{50, 100}, progress.Values())
+	require.Equal(t, map[string]any{
+		"name": "edge", "commercial_type": "DEV1-S", "image": "img-ubuntu", "project": "proj-1",
+		"tags": []any{"edge"}, "dynamic_ip_required": true,
+	}, api.bodies[0])
+	require.Equal(t, map[string]any{"action": "poweron"}, api.bodies[1])
+}
+
+func TestServerCreate_FailedPowerOnKeepsServer(t *testing.T) {
+	t.Parallel()
+
+	_, _, tools := setup(t, 0)
+	ctx := providerkittest.AccountContext(t, edge)
+
+	text, isError := providerkittest.Call(ctx, t, tools["scaleway_server_create"], map[string]any{
+		"name": "broken", "commercial_type": "DEV1-S", "image": "img-ubuntu",
+	})
+	require.False(t, isError, "the server exists, so it is returned rather than an error")
+	require.Contains(t, text, `"id": "broken"`)
+	require.Contains(t, text, providerkit.ErrActionFailed.Error())
+}
+
+func TestServerAction_TaskFailure(t *testing.T) {
+	t.Parallel()
+
+	_, _, tools := setup(t, 0)
+	ctx := providerkittest.AccountContext(t, edge)
+
+	text, isError := providerkittest.Call(ctx, t, tools["scaleway_server_action"], map[string]any{"server_id": "web", "action": "reboot", "wait": false})
+	require.False(t, isError, text)
+	require.Contains(t, text, `"status": "pending"`, "without wait the task is returned as started")
+
+	text, isError = providerkittest.Call(ctx, t, tools["scaleway_server_action"], map[string]any{"server_id": "broken", "action": "poweroff"})
+	require.True(t, isError)
+	require.Contains(t, text, providerkit.ErrActionFailed.Error())
+
+	text, isError = providerkittest.Call(ctx, t, tools["scaleway_server_get"], map[string]any{"server_id": "ghost"})
+	require.True(t, isError)
+	require.Contains(t, text, providerkit.ErrNotFound.Error())
+	require.Contains(t, text, `unknown_resource: "instance_server" not found`)
+}
+
+func TestServerTypesList_MergesAvailability(t *testing.T) {
+	t.Parallel()
+
+	_, _, tools := setup(t, 0)
+
+	text, isError := providerkittest.Call(providerkittest.AccountContext(t, edge), t, tools["scaleway_server_types_list"], map[string]any{"arch": "x86_64"})
+	require.False(t, isError, text)
+
+	var page pagination.Page[scaleway.ServerType]
+	require.NoError(t, json.Unmarshal([]byte(text), &page))
+	require.Len(t, page.Items, 2)
+	require.Equal(t, "DEV1-S", page.Items[0].Name)
+	require.Equal(t, "scarce", page.Items[0].Availability)
+
+	text, isError = providerkittest.Call(providerkittest.AccountContext(t, edge), t, tools["scaleway_zones_list"], map[string]any{"region": "pl-waw", "limit": float64(2)})
+	require.False(t, isError, text)
+	var zones pagination.Page[scaleway.Zone]
+	require.NoError(t, json.Unmarshal([]byte(text), &zones))
+	require.Equal(t, []string{"pl-waw-1", "pl-waw-2"}, []string{zones.Items[0].Name, zones.Items[1].Name})
+	require.NotEmpty(t, zones.NextCursor)
+
+	text, isError = providerkittest.Call(providerkittest.AccountContext(t, edge), t, tools["scaleway_zones_list"], map[string]any{"region": "pl-waw", "cursor": zones.NextCursor})
+	require.False(t, isError, text)
+	zones = pagination.Page[scaleway.Zone]{}
+	require.NoError(t, json.Unmarshal([]byte(text), &zones))
+	require.Len(t, zones.Items, 1)
+	require.Equal(t, "pl-waw-3", zones.Items[0].Name)
+	require.Empty(t, zones.NextCursor)
+}
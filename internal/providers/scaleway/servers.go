@@ -0,0 +1,323 @@
+package scaleway
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/idempotency"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+)
+
+// Server is a Scaleway Instance server.
+//
+//nolint:tagliatelle // JSON field names match the Scaleway API.
+type Server struct {
+	ID               string            `json:"id"`
+	Name             string            `json:"name"`
+	CommercialType   string            `json:"commercial_type"`
+	State            string            `json:"state"`
+	StateDetail      string            `json:"state_detail,omitempty"`
+	Zone             string            `json:"zone"`
+	Project          string            `json:"project"`
+	Arch             string            `json:"arch,omitempty"`
+	Image            *ImageRef         `json:"image,omitempty"`
+	PublicIPs        []PublicIP        `json:"public_ips,omitempty"`
+	PrivateIP        string            `json:"private_ip,omitempty"`
+	Tags             []string          `json:"tags"`
+	Volumes          map[string]Volume `json:"volumes,omitempty"`
+	CreationDate     string            `json:"creation_date"`
+	ModificationDate string            `json:"modification_date,omitempty"`
+}
+
+// ImageRef is the image a server was created from.
+type ImageRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// PublicIP is a public address of a server.
+type PublicIP struct {
+	ID      string `json:"id"`
+	Address string `json:"address"`
+	Family  string `json:"family"`
+	Dynamic bool   `json:"dynamic,omitempty"`
+}
+
+// Volume is a volume attached to a server.
+//
+//nolint:tagliatelle // JSON field names match the Scaleway API.
+type Volume struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Size       int64  `json:"size"`
+	VolumeType string `json:"volume_type"`
+}
+
+// Task is the record of an asynchronous server action.
+//
+//nolint:tagliatelle // JSON field names match the Scaleway API.
+type Task struct {
+	ID           string  `json:"id"`
+	Description  string  `json:"description"`
+	Status       string  `json:"status"`
+	Progress     float64 `json:"progress"`
+	StartedAt    string  `json:"started_at,omitempty"`
+	TerminatedAt string  `json:"terminated_at,omitempty"`
+	HrefFrom     string  `json:"href_from,omitempty"`
+}
+
+// Task statuses.
+const (
+	taskSuccess = "success"
+	taskFailure = "failure"
+)
+
+// serverResponse wraps a server.
+type serverResponse struct {
+	Server Server `json:"server"`
+}
+
+// withServerID adds the required server_id argument.
+func withServerID() mcp.ToolOption {
+	return mcp.WithString("server_id", mcp.Required(), mcp.Description("ID of the server"))
+}
+
+// serverPath returns the API path of the server named by the server_id
+// argument.
+func (p *Provider) serverPath(ctx context.Context, request mcp.CallToolRequest) (string, error) {
+	id, err := request.RequireString("server_id")
+	if err != nil {
+		return "", err
+	}
+
+	return p.zonePath(ctx, request) + "/servers/" + url.PathEscape(id), nil
+}
+
+// runAction starts a server action and by default waits for its task,
+// reporting its progress. The server is returned once the task is done.
+func (p *Provider) runAction(ctx context.Context, request mcp.CallToolRequest, path, action string) (any, error) {
+	var resp struct {
+		Task Task `json:"task"`
+	}
+	if err := p.api.Do(ctx, http.MethodPost, path+"/action", nil, map[string]string{"action": action}, &resp); err != nil {
+		return nil, err
+	}
+	if !request.GetBool(providerkit.WaitParam, true) {
+		return resp.Task, nil
+	}
+
+	task := resp.Task
+	taskPath := p.zonePath(ctx, request) + "/tasks/" + url.PathEscape(task.ID)
+	_, err := providerkit.Wait(ctx, p.pollInterval, func(ctx context.Context) (providerkit.ActionStatus, error) {
+		if task.Status != taskSuccess && task.Status != taskFailure {
+			var polled struct {
+				Task Task `json:"task"`
+			}
+			if err := p.api.Do(ctx, http.MethodGet, taskPath, nil, nil, &polled); err != nil {
+				return providerkit.ActionStatus{}, err
+			}
+			task = polled.Task
+		}
+
+		return providerkit.ActionStatus{
+			Done:    task.Status == taskSuccess || task.Status == taskFailure,
+			Failed:  task.Status == taskFailure,
+			Percent: task.Progress,
+			Message: task.Description + " " + task.Status,
+		}, nil
+	})
+	if err != nil {
+		return task, err
+	}
+
+	var server serverResponse
+	err = p.api.Do(ctx, http.MethodGet, path, nil, nil, &server)
+
+	return server.Server, err
+}
+
+func (p *Provider) listServersTool() *providerkit.Tool {
+	tool := mcp.NewTool("scaleway_servers_list",
+		mcp.WithDescription("Lists the Scaleway Instance servers of a zone"),
+		withZone(),
+		mcp.WithString("name", mcp.Description("Only list servers whose name contains this text (optional)")),
+		mcp.WithString("tag", mcp.Description("Only list servers with this tag (optional)")),
+		mcp.WithString("state", mcp.Description("Only list servers in this state (optional)"),
+			mcp.Enum("running", "stopped", "stopped in place", "starting", "stopping", "locked")),
+		mcp.WithString("project", mcp.Description("Only list servers of this project (optional)")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		query := url.Values{}
+		for arg, param := range map[string]string{"name": "name", "tag": "tags", "state": "state", "project": "project"} {
+			if value := request.GetString(arg, ""); value != "" {
+				query.Set(param, value)
+			}
+		}
+
+		return providerkit.Result(list[Server](ctx, p, request, p.zonePath(ctx, request)+"/servers", "servers", query))
+	})
+}
+
+func (p *Provider) getServerTool() *providerkit.Tool {
+	tool := mcp.NewTool("scaleway_server_get",
+		mcp.WithDescription("Returns a Scaleway Instance server"),
+		withServerID(),
+		withZone(),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		path, err := p.serverPath(ctx, request)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		var resp serverResponse
+		err = p.api.Do(ctx, http.MethodGet, path, nil, nil, &resp)
+
+		return providerkit.Result(resp.Server, err)
+	})
+}
+
+// createServerRequest is the body of a server create call.
+//
+//nolint:tagliatelle // JSON field names match the Scaleway API.
+type createServerRequest struct {
+	Name              string   `json:"name"`
+	CommercialType    string   `json:"commercial_type"`
+	Image             string   `json:"image"`
+	Project           string   `json:"project"`
+	Tags              []string `json:"tags,omitempty"`
+	DynamicIPRequired bool     `json:"dynamic_ip_required"`
+}
+
+func (p *Provider) createServerTool() *providerkit.Tool {
+	tool := mcp.NewTool("scaleway_server_create",
+		mcp.WithDescription("Creates a Scaleway Instance server and by default powers it on and waits until it is running"),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Server name")),
+		mcp.WithString("commercial_type", mcp.Required(), mcp.Description("Server type, such as DEV1-S or PLAY2-NANO")),
+		mcp.WithString("image", mcp.Required(), mcp.Description("Image ID from scaleway_images_list; image IDs differ between zones")),
+		withZone(),
+		mcp.WithString("project", mcp.Description("Project ID (default: the project_id setting)")),
+		mcp.WithArray("tags", mcp.Items(stringItems), mcp.Description("Tags to apply (optional)")),
+		mcp.WithBoolean("public_ip", mcp.Description("Assign a dynamic public IPv4 address (default true)"), mcp.DefaultBool(true)),
+		mcp.WithBoolean("start", mcp.Description("Power the server on after creating it (default true)"), mcp.DefaultBool(true)),
+		providerkit.WithWaitParam(),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		body := createServerRequest{
+			Name:              request.GetString("name", ""),
+			CommercialType:    request.GetString("commercial_type", ""),
+			Image:             request.GetString("image", ""),
+			Tags:              request.GetStringSlice("tags", nil),
+			DynamicIPRequired: request.GetBool("public_ip", true),
+		}
+		if body.Name == "" || body.CommercialType == "" || body.Image == "" {
+			return mcp.NewToolResultError("name, commercial_type and image are required"), nil
+		}
+		project, err := p.project(request)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		body.Project = project
+
+		var resp serverResponse
+		if err := p.api.Do(ctx, http.MethodPost, p.zonePath(ctx, request)+"/servers", nil, body, &resp); err != nil {
+			return providerkit.Result(nil, err)
+		}
+		if !request.GetBool("start", true) {
+			return providerkit.Result(resp.Server, nil)
+		}
+
+		server, err := p.runAction(ctx, request, p.zonePath(ctx, request)+"/servers/"+url.PathEscape(resp.Server.ID), "poweron")
+		if err != nil {
+			// The server exists even though powering it on failed
+			return providerkit.CreatedResult(resp.Server, err)
+		}
+
+		return providerkit.Result(server, nil)
+	})
+}
+
+// deleteResult reports a deleted resource.
+type deleteResult struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+func (p *Provider) deleteServerTool() *providerkit.Tool {
+	tool := mcp.NewTool("scaleway_server_delete",
+		mcp.WithDescription("Deletes a stopped Scaleway Instance server. Power it off first with scaleway_server_action; "+
+			"its volumes and flexible IPs are kept and must be deleted separately."),
+		withServerID(),
+		withZone(),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		path, err := p.serverPath(ctx, request)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		err = p.api.Do(ctx, http.MethodDelete, path, nil, nil, nil)
+
+		return providerkit.Result(deleteResult{ID: request.GetString("server_id", ""), Status: "deleted"}, err)
+	})
+}
+
+// serverActions are the actions of scaleway_server_action.
+var serverActions = []string{"poweron", "poweroff", "reboot", "stop_in_place"}
+
+func (p *Provider) serverActionTool() *providerkit.Tool {
+	tool := mcp.NewTool("scaleway_server_action",
+		mcp.WithDescription("Powers a Scaleway Instance server on or off, reboots it, or stops it in place (keeping its resources reserved) "+
+			"and by default waits for the action to finish"),
+		withServerID(),
+		mcp.WithString("action", mcp.Required(), mcp.Description("Action to run"), mcp.Enum(serverActions...)),
+		withZone(),
+		providerkit.WithWaitParam(),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		path, err := p.serverPath(ctx, request)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		action, err := request.RequireString("action")
+		if err != nil || !slices.Contains(serverActions, action) {
+			return mcp.NewToolResultError("action must be one of " + strings.Join(serverActions, ", ")), nil
+		}
+
+		return providerkit.Result(p.runAction(ctx, request, path, action))
+	})
+}
@@ -0,0 +1,118 @@
+package vultr
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+)
+
+// Plan is a Vultr instance plan.
+//
+//nolint:tagliatelle // JSON field names match the Vultr API.
+type Plan struct {
+	ID          string   `json:"id"`
+	Type        string   `json:"type"`
+	VCPUCount   int      `json:"vcpu_count"`
+	RAM         int      `json:"ram"`
+	Disk        int      `json:"disk"`
+	DiskCount   int      `json:"disk_count,omitempty"`
+	Bandwidth   int      `json:"bandwidth"`
+	MonthlyCost float64  `json:"monthly_cost"`
+	HourlyCost  float64  `json:"hourly_cost,omitempty"`
+	Locations   []string `json:"locations"`
+}
+
+// Region is a Vultr region.
+type Region struct {
+	ID        string   `json:"id"`
+	City      string   `json:"city"`
+	Country   string   `json:"country"`
+	Continent string   `json:"continent"`
+	Options   []string `json:"options,omitempty"`
+}
+
+// OS is an operating system instances can be created with.
+type OS struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Arch   string `json:"arch"`
+	Family string `json:"family"`
+}
+
+func (p *Provider) listPlansTool() *providerkit.Tool {
+	tool := mcp.NewTool("vultr_plans_list",
+		mcp.WithDescription("Lists Vultr instance plans with their resources and monthly cost"),
+		mcp.WithString("type", mcp.Description("Only list plans of this type, such as vc2 (regular) or vhf (high frequency) (optional)")),
+		mcp.WithString("region", mcp.Description("Only list plans available in this region, such as ewr (optional)")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		cursors, req, err := providerkit.ParsePage(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+		plans, err := all[Plan](ctx, p, "/plans", "plans")
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		planType, region := request.GetString("type", ""), request.GetString("region", "")
+		plans = slices.DeleteFunc(plans, func(plan Plan) bool {
+			return (planType != "" && plan.Type != planType) || (region != "" && !slices.Contains(plan.Locations, region))
+		})
+
+		return providerkit.Result(pagination.Slice(cursors, req, plans), nil)
+	})
+}
+
+func (p *Provider) listRegionsTool() *providerkit.Tool {
+	tool := mcp.NewTool("vultr_regions_list",
+		mcp.WithDescription("Lists Vultr regions"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return providerkit.Result(list[Region](ctx, p, request, "/regions", "regions", nil))
+	})
+}
+
+func (p *Provider) listOSTool() *providerkit.Tool {
+	tool := mcp.NewTool("vultr_os_list",
+		mcp.WithDescription("Lists the operating systems Vultr instances can be created with"),
+		mcp.WithString("name", mcp.Description("Only list operating systems whose name contains this text, such as ubuntu (optional)")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		cursors, req, err := providerkit.ParsePage(ctx, request)
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+		systems, err := all[OS](ctx, p, "/os", "os")
+		if err != nil {
+			return providerkit.Result(nil, err)
+		}
+
+		if name := strings.ToLower(request.GetString("name", "")); name != "" {
+			systems = slices.DeleteFunc(systems, func(os OS) bool { return !strings.Contains(strings.ToLower(os.Name), name) })
+		}
+
+		return providerkit.Result(pagination.Slice(cursors, req, systems), nil)
+	})
+}
@@ -0,0 +1,290 @@
+package vultr
+
+import (
+	"context"
+	"encoding/base64"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/idempotency"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+)
+
+// Instance is a Vultr cloud compute instance. The default password returned
+// when an instance is created is deliberately not part of it.
+//
+//nolint:tagliatelle // JSON field names match the Vultr API.
+type Instance struct {
+	ID           string   `json:"id"`
+	Label        string   `json:"label"`
+	Hostname     string   `json:"hostname"`
+	Region       string   `json:"region"`
+	Plan         string   `json:"plan"`
+	OS           string   `json:"os"`
+	OSID         int      `json:"os_id"`
+	VCPUCount    int      `json:"vcpu_count"`
+	RAM          int      `json:"ram"`
+	Disk         int      `json:"disk"`
+	MainIP       string   `json:"main_ip"`
+	V6MainIP     string   `json:"v6_main_ip,omitempty"`
+	Status       string   `json:"status"`
+	PowerStatus  string   `json:"power_status"`
+	ServerStatus string   `json:"server_status"`
+	Tags         []string `json:"tags"`
+	Features     []string `json:"features,omitempty"`
+	DateCreated  string   `json:"date_created"`
+}
+
+// Instance states.
+const (
+	statusActive = "active"
+	powerRunning = "running"
+	powerStopped = "stopped"
+)
+
+// instanceResponse wraps an instance.
+type instanceResponse struct {
+	Instance Instance `json:"instance"`
+}
+
+// withInstanceID adds the required instance_id argument.
+func withInstanceID() mcp.ToolOption {
+	return mcp.WithString("instance_id", mcp.Required(), mcp.Description("ID of the instance"))
+}
+
+// instancePath returns the API path of the instance named by the instance_id
+// argument.
+func instancePath(request mcp.CallToolRequest, suffix string) (string, error) {
+	id, err := request.RequireString("instance_id")
+	if err != nil {
+		return "", err
+	}
+
+	return "/instances/" + url.PathEscape(id) + suffix, nil
+}
+
+// waitPower polls an instance until it is active with the given power status.
+func (p *Provider) waitPower(ctx context.Context, path, power string) (Instance, error) {
+	var resp instanceResponse
+	_, err := providerkit.Wait(ctx, p.pollInterval, func(ctx context.Context) (providerkit.ActionStatus, error) {
+		if err := p.api.Do(ctx, http.MethodGet, path, nil, nil, &resp); err != nil {
+			return providerkit.ActionStatus{}, err
+		}
+
+		instance := resp.Instance
+		return providerkit.ActionStatus{
+			Done:    instance.Status == statusActive && instance.PowerStatus == power,
+			Message: "instance " + instance.Status + ", " + instance.PowerStatus,
+		}, nil
+	})
+
+	return resp.Instance, err
+}
+
+func (p *Provider) listInstancesTool() *providerkit.Tool {
+	tool := mcp.NewTool("vultr_instances_list",
+		mcp.WithDescription("Lists Vultr instances"),
+		mcp.WithString("label", mcp.Description("Only list instances with this label (optional)")),
+		mcp.WithString("tag", mcp.Description("Only list instances with this tag (optional)")),
+		mcp.WithString("region", mcp.Description("Only list instances in this region, such as ewr (optional)")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		query := url.Values{}
+		for _, filter := range []string{"label", "tag", "region"} {
+			if value := request.GetString(filter, ""); value != "" {
+				query.Set(filter, value)
+			}
+		}
+
+		return providerkit.Result(list[Instance](ctx, p, request, "/instances", "instances", query))
+	})
+}
+
+func (p *Provider) getInstanceTool() *providerkit.Tool {
+	tool := mcp.NewTool("vultr_instance_get",
+		mcp.WithDescription("Returns a Vultr instance"),
+		withInstanceID(),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		path, err := instancePath(request, "")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		var resp instanceResponse
+		err = p.api.Do(ctx, http.MethodGet, path, nil, nil, &resp)
+
+		return providerkit.Result(resp.Instance, err)
+	})
+}
+
+// createInstanceRequest is the body of an instance create call.
+//
+//nolint:tagliatelle // JSON field names match the Vultr API.
+type createInstanceRequest struct {
+	Region     string   `json:"region"`
+	Plan       string   `json:"plan"`
+	OSID       int      `json:"os_id,omitempty"`
+	SnapshotID string   `json:"snapshot_id,omitempty"`
+	Label      string   `json:"label,omitempty"`
+	Hostname   string   `json:"hostname,omitempty"`
+	SSHKeyIDs  []string `json:"sshkey_id,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	EnableIPv6 bool     `json:"enable_ipv6,omitempty"`
+	UserData   string   `json:"user_data,omitempty"`
+	Backups    string   `json:"backups"`
+}
+
+func (p *Provider) createInstanceTool() *providerkit.Tool {
+	tool := mcp.NewTool("vultr_instance_create",
+		mcp.WithDescription("Creates a Vultr instance from an OS or snapshot and by default waits until it is running. "+
+			"Vultr generates a root password that is never returned, so pass ssh_key_ids for SSH access."),
+		mcp.WithString("region", mcp.Description("Region ID, such as ewr (default: the account's region)")),
+		mcp.WithString("plan", mcp.Required(), mcp.Description("Plan ID, such as vc2-1c-1gb")),
+		mcp.WithNumber("os_id", mcp.Description("OS ID from vultr_os_list; one of os_id or snapshot_id is required")),
+		mcp.WithString("snapshot_id", mcp.Description("Snapshot to restore instead of installing an OS")),
+		mcp.WithString("label", mcp.Description("Instance label (optional)")),
+		mcp.WithString("hostname", mcp.Description("Hostname (optional)")),
+		mcp.WithArray("ssh_key_ids", mcp.Items(stringItems), mcp.Description("SSH key IDs to install (optional)")),
+		mcp.WithArray("tags", mcp.Items(stringItems), mcp.Description("Tags to apply (optional)")),
+		mcp.WithBoolean("enable_ipv6", mcp.Description("Assign an IPv6 address (default false)")),
+		mcp.WithString("user_data", mcp.Description("Cloud-init user data, as plain text (optional)")),
+		providerkit.WithWaitParam(),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		body := createInstanceRequest{
+			Region:     providerkit.Region(ctx, request, ""),
+			Plan:       request.GetString("plan", ""),
+			OSID:       request.GetInt("os_id", 0),
+			SnapshotID: request.GetString("snapshot_id", ""),
+			Label:      request.GetString("label", ""),
+			Hostname:   request.GetString("hostname", ""),
+			SSHKeyIDs:  request.GetStringSlice("ssh_key_ids", nil),
+			Tags:       request.GetStringSlice("tags", nil),
+			EnableIPv6: request.GetBool("enable_ipv6", false),
+			Backups:    "disabled",
+		}
+		if body.Plan == "" || (body.OSID == 0) == (body.SnapshotID == "") {
+			return mcp.NewToolResultError("plan and exactly one of os_id or snapshot_id are required"), nil
+		}
+		if body.Region == "" {
+			return mcp.NewToolResultError("region is required when the account has no default region"), nil
+		}
+		if userData := request.GetString("user_data", ""); userData != "" {
+			body.UserData = base64.StdEncoding.EncodeToString([]byte(userData))
+		}
+
+		var resp instanceResponse
+		if err := p.api.Do(ctx, http.MethodPost, "/instances", nil, body, &resp); err != nil {
+			return providerkit.Result(nil, err)
+		}
+		if !request.GetBool(providerkit.WaitParam, true) {
+			return providerkit.Result(resp.Instance, nil)
+		}
+
+		instance, err := p.waitPower(ctx, "/instances/"+url.PathEscape(resp.Instance.ID), powerRunning)
+		if instance.ID == "" {
+			// No poll succeeded, so return the instance as created
+			instance = resp.Instance
+		}
+
+		return providerkit.CreatedResult(instance, err)
+	})
+}
+
+// deleteResult reports a deleted resource.
+type deleteResult struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+func (p *Provider) deleteInstanceTool() *providerkit.Tool {
+	tool := mcp.NewTool("vultr_instance_delete",
+		mcp.WithDescription("Deletes a Vultr instance and its local storage. This cannot be undone."),
+		withInstanceID(),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		path, err := instancePath(request, "")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		err = p.api.Do(ctx, http.MethodDelete, path, nil, nil, nil)
+
+		return providerkit.Result(deleteResult{ID: request.GetString("instance_id", ""), Status: "deleted"}, err)
+	})
+}
+
+// instanceActions maps the actions of vultr_instance_action to the power
+// status they wait for. Reboots return to running, so they are not waited on.
+var instanceActions = map[string]string{
+	"start":  powerRunning,
+	"halt":   powerStopped,
+	"reboot": "",
+}
+
+func (p *Provider) instanceActionTool() *providerkit.Tool {
+	tool := mcp.NewTool("vultr_instance_action",
+		mcp.WithDescription("Starts, halts (powers off) or reboots a Vultr instance; start and halt wait for the new power state by default"),
+		withInstanceID(),
+		mcp.WithString("action", mcp.Required(), mcp.Description("Action to run"), mcp.Enum("start", "halt", "reboot")),
+		providerkit.WithWaitParam(),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		idempotency.WithParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		path, err := instancePath(request, "")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		action, err := request.RequireString("action")
+		power, ok := instanceActions[action]
+		if err != nil || !ok {
+			return mcp.NewToolResultError("action must be one of " + strings.Join(slices.Sorted(maps.Keys(instanceActions)), ", ")), nil
+		}
+
+		if err := p.api.Do(ctx, http.MethodPost, path+"/"+action, nil, nil, nil); err != nil {
+			return providerkit.Result(nil, err)
+		}
+		if power == "" || !request.GetBool(providerkit.WaitParam, true) {
+			var resp instanceResponse
+			err := p.api.Do(ctx, http.MethodGet, path, nil, nil, &resp)
+
+			return providerkit.Result(resp.Instance, err)
+		}
+
+		return providerkit.Result(p.waitPower(ctx, path, power))
+	})
+}
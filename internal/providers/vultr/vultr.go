@@ -0,0 +1,178 @@
+// Package vultr implements the Vultr API v2 provider.
+package vultr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	// Name is the provider name used in settings and accounts.
+	Name = "vultr"
+
+	// DefaultAPIURL is the Vultr API endpoint, overridden by the api_url
+	// setting.
+	DefaultAPIURL = "https://api.vultr.com/v2"
+
+	// DefaultTokenRef is the credential used when neither the account nor the
+	// token_ref setting names one, as for vultr-cli.
+	DefaultTokenRef = "env:VULTR_API_KEY"
+
+	// maxPageSize is the largest per_page the API accepts.
+	maxPageSize = 500
+)
+
+// Provider is the Vultr provider.
+type Provider struct {
+	cfg          contracts.ProviderConfig
+	api          *providerkit.API
+	pollInterval time.Duration
+}
+
+// New creates an uninitialized Vultr provider.
+func New() contracts.Provider {
+	return &Provider{}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return Name
+}
+
+// Initialize configures the Vultr API client.
+func (p *Provider) Initialize(_ context.Context, cfg contracts.ProviderConfig) error {
+	p.cfg = cfg
+	p.pollInterval = providerkit.PollInterval(cfg)
+	p.api = providerkit.NewTokenAPI(cfg, providerkit.TokenAPI{
+		Provider:        Name,
+		DefaultURL:      DefaultAPIURL,
+		DefaultTokenRef: DefaultTokenRef,
+		ErrorMessage:    errorMessage,
+	})
+
+	return nil
+}
+
+// Tools returns the Vultr tools.
+func (p *Provider) Tools() []contracts.Tool {
+	return []contracts.Tool{
+		p.listInstancesTool(),
+		p.getInstanceTool(),
+		p.createInstanceTool(),
+		p.deleteInstanceTool(),
+		p.instanceActionTool(),
+		p.listPlansTool(),
+		p.listRegionsTool(),
+		p.listOSTool(),
+	}
+}
+
+// HealthCheck verifies the default account's API key by reading the account.
+func (p *Provider) HealthCheck(ctx context.Context) error {
+	ctx = providerkit.DefaultAccountContext(ctx, p.cfg)
+
+	return p.api.Do(ctx, http.MethodGet, "/account", nil, nil, nil)
+}
+
+// Shutdown has nothing to release.
+func (p *Provider) Shutdown(context.Context) error {
+	return nil
+}
+
+// errorMessage extracts the message of a Vultr error response.
+func errorMessage(body []byte) string {
+	var decoded struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return ""
+	}
+
+	return decoded.Error
+}
+
+// meta is the pagination metadata of list responses.
+type meta struct {
+	Total int `json:"total"`
+	Links struct {
+		Next string `json:"next"`
+	} `json:"links"`
+}
+
+// fetch fetches one page of a Vultr collection. key is the name of the
+// collection in the response body; cursor and size select the page.
+func fetch[T any](ctx context.Context, p *Provider, path, key string, query url.Values, cursor string, size int) ([]T, string, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("per_page", strconv.Itoa(size))
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+
+	var resp map[string]json.RawMessage
+	if err := p.api.Do(ctx, http.MethodGet, path, query, nil, &resp); err != nil {
+		return nil, "", err
+	}
+
+	items := []T{}
+	if raw, ok := resp[key]; ok {
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, "", fmt.Errorf("failed to decode %s: %w", key, err)
+		}
+	}
+	var page meta
+	if raw, ok := resp["meta"]; ok {
+		_ = json.Unmarshal(raw, &page)
+	}
+
+	return items, page.Links.Next, nil
+}
+
+// list fetches one page of a Vultr collection for a list tool.
+func list[T any](ctx context.Context, p *Provider, request mcp.CallToolRequest, path, key string, query url.Values) (pagination.Page[T], error) {
+	cursors, req, err := providerkit.ParsePage(ctx, request)
+	if err != nil {
+		return pagination.Page[T]{}, err
+	}
+
+	cursor, size := req.TokenPage(1, maxPageSize)
+	items, next, err := fetch[T](ctx, p, path, key, query, cursor, size)
+	if err != nil {
+		return pagination.Page[T]{}, err
+	}
+
+	return pagination.FromToken(cursors, req, size, items, next), nil
+}
+
+// all fetches every page of a small collection, such as the plan catalog, so
+// it can be filtered before it is paginated.
+func all[T any](ctx context.Context, p *Provider, path, key string) ([]T, error) {
+	items := []T{}
+	cursor := ""
+	for {
+		page, next, err := fetch[T](ctx, p, path, key, nil, cursor, maxPageSize)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+		if next == "" {
+			return items, nil
+		}
+		cursor = next
+	}
+}
+
+// stringItems is the item schema of string array arguments.
+var stringItems = map[string]any{"type": "string"}
@@ -0,0 +1,241 @@
+package vultr_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/internal/providerkit/providerkittest"
+	"github.com/chadit/CloudMCP/internal/providers/vultr"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+// edge is the default account, which tool calls run against.
+var edge = contracts.Account{Provider: vultr.Name, Alias: "edge", Credential: "vultr-edge", Region: "ewr", Default: true}
+
+// fakeAPI is a stub of the Vultr API v2. Instances reach their requested power
+// state on the second poll, except an instance labelled lost, which cannot be
+// read back once created.
+type fakeAPI struct {
+	mu        sync.Mutex
+	instances int
+	polls     map[string]int
+	power     map[string]string
+	requests  []string
+	bodies    []map[string]any
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+	if r.Header.Get("Authorization") != "Bearer vultr-key" {
+		writeError(w, http.StatusUnauthorized, "Invalid API token.")
+		return
+	}
+	if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+		var body map[string]any
+		_ = json.Unmarshal(data, &body)
+		f.bodies = append(f.bodies, body)
+	}
+
+	path := r.URL.Path
+	switch {
+	case r.Method == http.MethodGet && path == "/account":
+		providerkittest.WriteJSON(w, http.StatusOK, map[string]any{"account": map[string]string{"name": "demo"}})
+	case r.Method == http.MethodGet && path == "/instances":
+		// cursors are opaque base64 strings, as in the real API
+		start := 0
+		if cursor, err := base64.StdEncoding.DecodeString(r.URL.Query().Get("cursor")); err == nil && len(cursor) > 0 {
+			start, _ = strconv.Atoi(strings.TrimPrefix(string(cursor), "next__"))
+		}
+		size, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		end := min(start+size, f.instances)
+		instances := []vultr.Instance{}
+		for i := start; i < end; i++ {
+			instances = append(instances, vultr.Instance{ID: fmt.Sprintf("id-%d", i), Label: fmt.Sprintf("edge-%d", i)})
+		}
+		next := ""
+		if end < f.instances {
+			next = base64.StdEncoding.EncodeToString([]byte("next__" + strconv.Itoa(end)))
+		}
+		providerkittest.WriteJSON(w, http.StatusOK, map[string]any{"instances": instances, "meta": map[string]any{"total": f.instances, "links": map[string]string{"next": next}}})
+	case r.Method == http.MethodPost && path == "/instances" && f.bodies[len(f.bodies)-1]["label"] == "lost":
+		providerkittest.WriteJSON(w, http.StatusAccepted, map[string]any{"instance": map[string]any{
+			"id": "lost", "status": "pending", "power_status": "stopped",
+		}})
+	case r.Method == http.MethodPost && path == "/instances":
+		f.power["new"] = "running"
+		providerkittest.WriteJSON(w, http.StatusAccepted, map[string]any{"instance": map[string]any{
+			"id": "new", "status": "pending", "power_status": "stopped", "default_password": "v3ry-s3cret",
+		}})
+	case r.Method == http.MethodPost && strings.HasPrefix(path, "/instances/"):
+		id, action, _ := strings.Cut(strings.TrimPrefix(path, "/instances/"), "/")
+		f.power[id] = map[string]string{"start": "running", "halt": "stopped", "reboot": "running"}[action]
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/instances/"):
+		id := strings.TrimPrefix(path, "/instances/")
+		target, ok := f.power[id]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Invalid instance-id.")
+			return
+		}
+		f.polls[id]++
+		instance := vultr.Instance{ID: id, Status: "pending", PowerStatus: "stopped", MainIP: "0.0.0.0"}
+		if f.polls[id] >= 2 {
+			instance.Status, instance.PowerStatus, instance.MainIP = "active", target, "203.0.113.20"
+		}
+		providerkittest.WriteJSON(w, http.StatusOK, map[string]any{"instance": instance})
+	case r.Method == http.MethodDelete && strings.HasPrefix(path, "/instances/"):
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet && path == "/plans":
+		providerkittest.WriteJSON(w, http.StatusOK, map[string]any{"plans": []vultr.Plan{
+			{ID: "vc2-1c-1gb", Type: "vc2", Locations: []string{"ewr", "ams"}},
+			{ID: "vhf-1c-1gb", Type: "vhf", Locations: []string{"ewr"}},
+			{ID: "vc2-2c-4gb", Type: "vc2", Locations: []string{"ewr"}},
+		}, "meta": map[string]any{"total": 3, "links": map[string]string{"next": ""}}})
+	default:
+		writeError(w, http.StatusNotFound, "Not found.")
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	providerkittest.WriteJSON(w, status, map[string]any{"error": message, "status": status})
+}
+
+// setup starts a stub API and returns the provider and its tools by name.
+func setup(t *testing.T, instances int) (*fakeAPI, contracts.Provider, map[string]contracts.Tool) {
+	t.Helper()
+
+	api := &fakeAPI{instances: instances, polls: map[string]int{}, power: map[string]string{"web": "running"}}
+	srv := providerkittest.Serve(t, api)
+
+	provider := vultr.New()
+	tools := providerkittest.Setup(t, provider, contracts.ProviderConfig{
+		Settings:   map[string]string{"api_url": srv.URL, "poll_interval": "1ms"},
+		HTTPClient: srv.Client(),
+		Secrets:    providerkittest.Secrets{"vultr-edge": "vultr-key"},
+		Accounts:   []contracts.Account{edge},
+	})
+
+	return api, provider, tools
+}
+
+func TestHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	api, provider, _ := setup(t, 0)
+	require.NoError(t, provider.HealthCheck(t.Context()))
+	require.Equal(t, []string{"GET /account"}, api.requests)
+}
+
+func TestInstancesList_FollowsCursors(t *testing.T) {
+	t.Parallel()
+
+	_, _, tools := setup(t, 5)
+	ctx := providerkittest.AccountContext(t, edge)
+
+	var labels []string
+	params := map[string]any{"limit": float64(2)}
+	for {
+		text, isError := providerkittest.Call(ctx, t, tools["vultr_instances_list"], params)
+		require.False(t, isError, text)
+
+		var page pagination.Page[vultr.Instance]
+		require.NoError(t, json.Unmarshal([]byte(text), &page))
+		for _, instance := range page.Items {
+			labels = append(labels, instance.Label)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		params["cursor"] = page.NextCursor
+	}
+
+	require.Equal(t, []string{"edge-0", "edge-1", "edge-2", "edge-3", "edge-4"}, labels)
+}
+
+func TestInstanceCreate_WaitsUntilRunning(t *testing.T) {
+	t.Parallel()
+
+	api, _, tools := setup(t, 0)
+	progress := &providerkittest.Progress{}
+	ctx := contracts.WithProgressReporter(providerkittest.AccountContext(t, edge), progress)
+
+	text, isError := providerkittest.Call(ctx, t, tools["vultr_instance_create"], map[string]any{
+		"plan": "vc2-1c-1gb", "os_id": float64(2284), "label": "edge", "user_data": "#cloud-config\n",
+	})
+	require.False(t, isError, text)
+	require.Contains(t, text, "203.0.113.20", "the instance is returned once running")
+	require.NotContains(t, text, "v3ry-s3cret", "the default password is never returned")
+	require.Equal(t, []string{"instance pending, stopped", "instance active, running"}, progress.Messages())
+
+	body := api.bodies[0]
+	require.Equal(t, "ewr", body["region"], "the account region is the default")
+	require.Equal(t, base64.StdEncoding.EncodeToString([]byte("#cloud-config\n")), body["user_data"])
+	require.Equal(t, "disabled", body["backups"])
+
+	text, isError = providerkittest.Call(ctx, t, tools["vultr_instance_create"], map[string]any{"plan": "vc2-1c-1gb"})
+	require.True(t, isError)
+	require.Contains(t, text, "exactly one of os_id or snapshot_id")
+}
+
+func TestInstanceCreate_FailedWaitKeepsInstance(t *testing.T) {
+	t.Parallel()
+
+	_, _, tools := setup(t, 0)
+	ctx := providerkittest.AccountContext(t, edge)
+
+	text, isError := providerkittest.Call(ctx, t, tools["vultr_instance_create"], map[string]any{
+		"plan": "vc2-1c-1gb", "os_id": float64(2284), "label": "lost",
+	})
+	require.False(t, isError, "the instance exists, so it is returned rather than an error")
+	require.Contains(t, text, `"id": "lost"`)
+	require.Contains(t, text, "Invalid instance-id.")
+}
+
+func TestInstanceAction_WaitsForPowerState(t *testing.T) {
+	t.Parallel()
+
+	api, _, tools := setup(t, 0)
+	ctx := providerkittest.AccountContext(t, edge)
+
+	text, isError := providerkittest.Call(ctx, t, tools["vultr_instance_action"], map[string]any{"instance_id": "web", "action": "halt"})
+	require.False(t, isError, text)
+	require.Contains(t, text, `"power_status": "stopped"`)
+	require.Equal(t, 2, api.polls["web"])
+
+	text, isError = providerkittest.Call(ctx, t, tools["vultr_instance_action"], map[string]any{"instance_id": "web", "action": "destroy"})
+	require.True(t, isError)
+	require.Contains(t, text, "action must be one of halt, reboot, start")
+
+	text, isError = providerkittest.Call(ctx, t, tools["vultr_instance_get"], map[string]any{"instance_id": "ghost"})
+	require.True(t, isError)
+	require.Contains(t, text, providerkit.ErrNotFound.Error())
+	require.Contains(t, text, "Invalid instance-id.")
+}
+
+func TestPlansList_FiltersByRegionAndType(t *testing.T) {
+	t.Parallel()
+
+	_, _, tools := setup(t, 0)
+
+	text, isError := providerkittest.Call(providerkittest.AccountContext(t, edge), t, tools["vultr_plans_list"], map[string]any{"region": "ewr", "type": "vc2"})
+	require.False(t, isError, text)
+
+	var page pagination.Page[vultr.Plan]
+	require.NoError(t, json.Unmarshal([]byte(text), &page))
+	require.Len(t, page.Items, 2)
+	require.Equal(t, "vc2-2c-4gb", page.Items[1].ID)
+}
@@ -0,0 +1,305 @@
+// Package ratelimit enforces token-bucket limits on tool calls. Limits apply
+// per tool, per provider and per client session, so a runaway agent loop is
+// stopped before it exhausts a cloud account's API quota.
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Scope is the dimension a limit applies to.
+type Scope string
+
+// Limit scopes.
+const (
+	ScopeTool     Scope = "tool"
+	ScopeProvider Scope = "provider"
+	ScopeSession  Scope = "session"
+)
+
+// Wildcard matches every tool or provider without a more specific rule.
+const Wildcard = "*"
+
+// Static errors for err113 compliance.
+var (
+	ErrRateLimited = errors.New("rate limited")
+	ErrInvalidRule = errors.New("invalid rate limit rule")
+)
+
+// Rate is a number of calls per period. The bucket holds Count tokens and
+// refills at Count per Per, so short bursts up to Count are allowed.
+type Rate struct {
+	Count int
+	Per   time.Duration
+}
+
+// String formats the rate as it is written in rules.
+func (r Rate) String() string {
+	switch r.Per {
+	case time.Second:
+		return fmt.Sprintf("%d/s", r.Count)
+	case time.Minute:
+		return fmt.Sprintf("%d/m", r.Count)
+	case time.Hour:
+		return fmt.Sprintf("%d/h", r.Count)
+	default:
+		return fmt.Sprintf("%d/%s", r.Count, r.Per)
+	}
+}
+
+// Rule limits one tool, one provider or each session. Name is the tool or
+// provider name, or Wildcard; it is empty for session rules.
+type Rule struct {
+	Scope Scope
+	Name  string
+	Rate  Rate
+}
+
+// Call identifies a tool call being limited. Provider is empty for built-in tools.
+type Call struct {
+	Tool     string
+	Provider string
+	Session  string
+}
+
+// LimitError reports a call rejected by a limit.
+type LimitError struct {
+	Scope      Scope
+	Key        string
+	Rate       Rate
+	RetryAfter time.Duration
+}
+
+// Error implements error.
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%v: %s limit %s for %s reached, retry after %d seconds",
+		ErrRateLimited, e.Scope, e.Rate, e.Key, e.RetryAfterSeconds())
+}
+
+// Unwrap makes errors.Is(err, ErrRateLimited) hold.
+func (e *LimitError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// RetryAfterSeconds returns the wait rounded up to whole seconds.
+func (e *LimitError) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+// ParseRules parses a comma-separated rule list such as
+// "tool:linode_instance_create=5/m,provider:*=20/s,session=60/m". Periods
+// are s, m, h or a Go duration such as 30s.
+func ParseRules(spec string) ([]Rule, error) {
+	var rules []Rule
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		target, rateText, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q: expected target=rate", ErrInvalidRule, item)
+		}
+
+		rate, err := parseRate(strings.TrimSpace(rateText))
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", item, err)
+		}
+
+		scope, name, _ := strings.Cut(strings.TrimSpace(target), ":")
+		rule := Rule{Scope: Scope(scope), Name: name, Rate: rate}
+		switch rule.Scope {
+		case ScopeTool, ScopeProvider:
+			if rule.Name == "" {
+				return nil, fmt.Errorf("%w: %q: %s rules need a name or %s", ErrInvalidRule, item, scope, Wildcard)
+			}
+		case ScopeSession:
+			if rule.Name != "" {
+				return nil, fmt.Errorf("%w: %q: session rules take no name", ErrInvalidRule, item)
+			}
+		default:
+			return nil, fmt.Errorf("%w: %q: scope must be tool, provider or session", ErrInvalidRule, item)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// parseRate parses "count/period".
+func parseRate(text string) (Rate, error) {
+	countText, period, ok := strings.Cut(text, "/")
+	if !ok {
+		return Rate{}, fmt.Errorf("%w: expected count/period", ErrInvalidRule)
+	}
+
+	count, err := strconv.Atoi(countText)
+	if err != nil || count <= 0 {
+		return Rate{}, fmt.Errorf("%w: count must be a positive integer", ErrInvalidRule)
+	}
+
+	var per time.Duration
+	switch period {
+	case "s":
+		per = time.Second
+	case "m":
+		per = time.Minute
+	case "h":
+		per = time.Hour
+	default:
+		per, err = time.ParseDuration(period)
+		if err != nil || per <= 0 {
+			return Rate{}, fmt.Errorf("%w: period must be s, m, h or a positive duration", ErrInvalidRule)
+		}
+	}
+
+	return Rate{Count: count, Per: per}, nil
+}
+
+// Limiter applies rules to tool calls. It is safe for concurrent use.
+type Limiter struct {
+	rules []Rule
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// NewLimiter creates a limiter enforcing rules.
+func NewLimiter(rules []Rule) *Limiter {
+	return &Limiter{
+		rules:   rules,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Allow takes a token from every bucket that applies to call. If any bucket
+// is empty no tokens are taken and a *LimitError names the limit with the
+// longest wait.
+func (l *Limiter) Allow(call Call) error {
+	if l == nil || len(l.rules) == 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	applied := l.matchLocked(call, now)
+
+	var limited *LimitError
+	for _, a := range applied {
+		if wait := a.bucket.wait(now); wait > 0 && (limited == nil || wait > limited.RetryAfter) {
+			limited = &LimitError{Scope: a.rule.Scope, Key: a.key, Rate: a.rule.Rate, RetryAfter: wait}
+		}
+	}
+	if limited != nil {
+		return limited
+	}
+
+	for _, a := range applied {
+		a.bucket.tokens--
+	}
+
+	return nil
+}
+
+// applied is a rule matched to a call with its bucket.
+type applied struct {
+	rule   Rule
+	key    string
+	bucket *bucket
+}
+
+// matchLocked returns the buckets that apply to call. A named rule takes
+// precedence over a wildcard rule of the same scope. The caller must hold l.mu.
+func (l *Limiter) matchLocked(call Call, now time.Time) []applied {
+	var result []applied
+	for _, scope := range []Scope{ScopeTool, ScopeProvider, ScopeSession} {
+		name := ""
+		switch scope {
+		case ScopeTool:
+			name = call.Tool
+		case ScopeProvider:
+			name = call.Provider
+			if name == "" {
+				continue
+			}
+		case ScopeSession:
+			name = call.Session
+		}
+
+		rule, ok := l.ruleFor(scope, name)
+		if !ok {
+			continue
+		}
+
+		key := string(scope) + ":" + name
+		b, ok := l.buckets[key]
+		if !ok {
+			b = newBucket(rule.Rate, now)
+			l.buckets[key] = b
+		}
+		result = append(result, applied{rule: rule, key: name, bucket: b})
+	}
+
+	return result
+}
+
+// ruleFor finds the rule for a scope and name, preferring an exact name match.
+func (l *Limiter) ruleFor(scope Scope, name string) (Rule, bool) {
+	var wildcard *Rule
+	for i, rule := range l.rules {
+		if rule.Scope != scope {
+			continue
+		}
+		if scope == ScopeSession || rule.Name == name {
+			return rule, true
+		}
+		if rule.Name == Wildcard && wildcard == nil {
+			wildcard = &l.rules[i]
+		}
+	}
+	if wildcard != nil {
+		return *wildcard, true
+	}
+
+	return Rule{}, false
+}
+
+// bucket is a token bucket. Fields are guarded by Limiter.mu.
+type bucket struct {
+	capacity float64
+	perToken time.Duration
+	tokens   float64
+	last     time.Time
+}
+
+func newBucket(rate Rate, now time.Time) *bucket {
+	return &bucket{
+		capacity: float64(rate.Count),
+		perToken: rate.Per / time.Duration(rate.Count),
+		tokens:   float64(rate.Count),
+		last:     now,
+	}
+}
+
+// wait refills the bucket and returns how long until a token is available.
+func (b *bucket) wait(now time.Time) time.Duration {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(b.capacity, b.tokens+float64(elapsed)/float64(b.perToken))
+		b.last = now
+	}
+	if b.tokens >= 1 {
+		return 0
+	}
+
+	return time.Duration((1 - b.tokens) * float64(b.perToken))
+}
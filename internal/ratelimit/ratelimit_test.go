@@ -0,0 +1,103 @@
+package ratelimit_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chadit/CloudMCP/internal/ratelimit"
+)
+
+func newLimiter(t *testing.T, spec string) *ratelimit.Limiter {
+	t.Helper()
+
+	rules, err := ratelimit.ParseRules(spec)
+	require.NoError(t, err)
+
+	return ratelimit.NewLimiter(rules)
+}
+
+func TestParseRules(t *testing.T) {
+	t.Parallel()
+
+	rules, err := ratelimit.ParseRules("tool:create=5/m, provider:*=20/s,session=100/30s")
+	require.NoError(t, err)
+	require.Equal(t, []ratelimit.Rule{
+		{Scope: ratelimit.ScopeTool, Name: "create", Rate: ratelimit.Rate{Count: 5, Per: time.Minute}},
+		{Scope: ratelimit.ScopeProvider, Name: "*", Rate: ratelimit.Rate{Count: 20, Per: time.Second}},
+		{Scope: ratelimit.ScopeSession, Rate: ratelimit.Rate{Count: 100, Per: 30 * time.Second}},
+	}, rules)
+
+	rules, err = ratelimit.ParseRules("")
+	require.NoError(t, err)
+	require.Empty(t, rules)
+
+	for _, invalid := range []string{"tool=5/m", "session:x=5/m", "region:us=5/m", "tool:a=0/m", "tool:a=5/fortnight", "tool:a"} {
+		_, err = ratelimit.ParseRules(invalid)
+		require.ErrorIs(t, err, ratelimit.ErrInvalidRule, invalid)
+	}
+}
+
+func TestLimiter_RejectsWithRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	limiter := newLimiter(t, "tool:create=2/m")
+	call := ratelimit.Call{Tool: "create", Session: "s1"}
+
+	require.NoError(t, limiter.Allow(call))
+	require.NoError(t, limiter.Allow(call))
+
+	err := limiter.Allow(call)
+	require.ErrorIs(t, err, ratelimit.ErrRateLimited)
+
+	var limited *ratelimit.LimitError
+	require.True(t, errors.As(err, &limited))
+	require.Equal(t, ratelimit.ScopeTool, limited.Scope)
+	require.Equal(t, "create", limited.Key)
+	require.InDelta(t, 30, limited.RetryAfterSeconds(), 1, "one token refills every 30 seconds")
+	require.Contains(t, err.Error(), "retry after 30 seconds")
+
+	require.NoError(t, limiter.Allow(ratelimit.Call{Tool: "list", Session: "s1"}), "other tools are unaffected")
+}
+
+func TestLimiter_NamedRuleOverridesWildcard(t *testing.T) {
+	t.Parallel()
+
+	limiter := newLimiter(t, "provider:*=1/h,provider:aws=3/h")
+
+	for range 3 {
+		require.NoError(t, limiter.Allow(ratelimit.Call{Tool: "ec2_list", Provider: "aws"}))
+	}
+	require.Error(t, limiter.Allow(ratelimit.Call{Tool: "ec2_list", Provider: "aws"}))
+
+	require.NoError(t, limiter.Allow(ratelimit.Call{Tool: "linode_list", Provider: "linode"}))
+	require.Error(t, limiter.Allow(ratelimit.Call{Tool: "linode_list", Provider: "linode"}))
+
+	require.NoError(t, limiter.Allow(ratelimit.Call{Tool: "hello"}), "built-in tools have no provider")
+}
+
+func TestLimiter_RejectedCallsTakeNoTokens(t *testing.T) {
+	t.Parallel()
+
+	limiter := newLimiter(t, "tool:create=1/h,session=2/h")
+
+	require.NoError(t, limiter.Allow(ratelimit.Call{Tool: "create", Session: "s1"}))
+	require.Error(t, limiter.Allow(ratelimit.Call{Tool: "create", Session: "s1"}))
+	require.NoError(t, limiter.Allow(ratelimit.Call{Tool: "list", Session: "s1"}), "the rejected call did not use the session token")
+	require.Error(t, limiter.Allow(ratelimit.Call{Tool: "list", Session: "s1"}))
+	require.NoError(t, limiter.Allow(ratelimit.Call{Tool: "list", Session: "s2"}), "sessions are limited separately")
+}
+
+func TestLimiter_Refills(t *testing.T) {
+	t.Parallel()
+
+	limiter := newLimiter(t, "session=1/50ms")
+	call := ratelimit.Call{Tool: "list", Session: "s1"}
+
+	require.NoError(t, limiter.Allow(call))
+	require.Error(t, limiter.Allow(call))
+	time.Sleep(60 * time.Millisecond)
+	require.NoError(t, limiter.Allow(call))
+}
@@ -0,0 +1,209 @@
+// Package results keeps oversized tool results in a bounded, expiring store so
+// the server can return truncated text inline and serve the full payload as an
+// MCP resource the client fetches on demand.
+package results
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// URIPrefix is the resource URI prefix of stored results.
+	URIPrefix = "cloudmcp://results/"
+
+	// URITemplate is the RFC 6570 template matching stored result URIs.
+	URITemplate = URIPrefix + "{id}"
+
+	// DefaultTTL is how long a stored result remains readable.
+	DefaultTTL = 15 * time.Minute
+
+	// DefaultMaxBytes bounds the combined size of stored results.
+	DefaultMaxBytes = 64 << 20
+
+	// DefaultMaxEntries bounds the number of stored results.
+	DefaultMaxEntries = 256
+)
+
+// Static errors for err113 compliance.
+var (
+	ErrTooLarge = errors.New("result exceeds result store capacity")
+	ErrNotFound = errors.New("result not found or expired")
+)
+
+// Entry is a stored result.
+type Entry struct {
+	ID        string
+	Tool      string
+	MIMEType  string
+	Data      []byte
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// URI returns the resource URI of the entry.
+func (e Entry) URI() string {
+	return URIPrefix + e.ID
+}
+
+// Options configures a Store.
+type Options struct {
+	// TTL is how long entries remain readable. Zero selects DefaultTTL.
+	TTL time.Duration
+
+	// MaxBytes bounds the combined payload size. Zero selects DefaultMaxBytes.
+	MaxBytes int
+
+	// MaxEntries bounds the number of entries. Zero selects DefaultMaxEntries.
+	MaxEntries int
+}
+
+// Store is a bounded, expiring in-memory result store. When full, the oldest
+// entries are evicted first. Expired entries are dropped lazily.
+type Store struct {
+	ttl        time.Duration
+	maxBytes   int
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*Entry
+	order   []string
+	size    int
+}
+
+// NewStore creates an empty store.
+func NewStore(opts Options) *Store {
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultTTL
+	}
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = DefaultMaxBytes
+	}
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = DefaultMaxEntries
+	}
+
+	return &Store{
+		ttl:        opts.TTL,
+		maxBytes:   opts.MaxBytes,
+		maxEntries: opts.MaxEntries,
+		entries:    make(map[string]*Entry),
+	}
+}
+
+// Put stores a payload produced by the named tool and returns its entry.
+func (s *Store) Put(tool, mimeType string, data []byte) (Entry, error) {
+	if len(data) > s.maxBytes {
+		return Entry{}, fmt.Errorf("%w: %d bytes", ErrTooLarge, len(data))
+	}
+
+	id, err := newID()
+	if err != nil {
+		return Entry{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.expireLocked(now)
+	for len(s.order) > 0 && (len(s.order) >= s.maxEntries || s.size+len(data) > s.maxBytes) {
+		s.removeLocked(s.order[0])
+	}
+
+	entry := &Entry{
+		ID:        id,
+		Tool:      tool,
+		MIMEType:  mimeType,
+		Data:      data,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.ttl),
+	}
+	s.entries[id] = entry
+	s.order = append(s.order, id)
+	s.size += len(data)
+
+	return *entry, nil
+}
+
+// Get returns the entry with the given ID if it exists and has not expired.
+func (s *Store) Get(id string) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expireLocked(time.Now())
+	entry, ok := s.entries[id]
+	if !ok {
+		return Entry{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+
+	return *entry, nil
+}
+
+// Len returns the number of live entries.
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expireLocked(time.Now())
+	return len(s.order)
+}
+
+// TTL returns how long entries remain readable.
+func (s *Store) TTL() time.Duration {
+	return s.ttl
+}
+
+// expireLocked drops expired entries. Entries share one TTL, so insertion
+// order is expiry order.
+func (s *Store) expireLocked(now time.Time) {
+	for len(s.order) > 0 {
+		entry := s.entries[s.order[0]]
+		if now.Before(entry.ExpiresAt) {
+			return
+		}
+		s.removeLocked(entry.ID)
+	}
+}
+
+// removeLocked deletes an entry. The caller must hold s.mu.
+func (s *Store) removeLocked(id string) {
+	entry, ok := s.entries[id]
+	if !ok {
+		return
+	}
+
+	delete(s.entries, id)
+	s.size -= len(entry.Data)
+	for i, orderedID := range s.order {
+		if orderedID == id {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// IDFromURI extracts the entry ID from a result resource URI.
+func IDFromURI(uri string) (string, bool) {
+	id, ok := strings.CutPrefix(uri, URIPrefix)
+	if !ok || id == "" || strings.Contains(id, "/") {
+		return "", false
+	}
+
+	return id, true
+}
+
+// newID returns a random entry identifier.
+func newID() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate result id: %w", err)
+	}
+
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,87 @@
+package results_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chadit/CloudMCP/internal/results"
+)
+
+func TestStore_PutAndGet(t *testing.T) {
+	t.Parallel()
+
+	store := results.NewStore(results.Options{})
+
+	entry, err := store.Put("inventory", "application/json", []byte(`{"items":[]}`))
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(entry.URI(), results.URIPrefix))
+
+	id, ok := results.IDFromURI(entry.URI())
+	require.True(t, ok)
+	require.Equal(t, entry.ID, id)
+
+	got, err := store.Get(id)
+	require.NoError(t, err)
+	require.Equal(t, "inventory", got.Tool)
+	require.Equal(t, "application/json", got.MIMEType)
+	require.JSONEq(t, `{"items":[]}`, string(got.Data))
+
+	_, err = store.Get("missing")
+	require.ErrorIs(t, err, results.ErrNotFound)
+}
+
+func TestStore_EvictsOldestWhenFull(t *testing.T) {
+	t.Parallel()
+
+	store := results.NewStore(results.Options{MaxBytes: 10, MaxEntries: 2})
+
+	first, err := store.Put("t", "text/plain", []byte("aaaa"))
+	require.NoError(t, err)
+	second, err := store.Put("t", "text/plain", []byte("bbbb"))
+	require.NoError(t, err)
+
+	// Exceeds the byte budget, so the oldest entry is evicted.
+	third, err := store.Put("t", "text/plain", []byte("cccc"))
+	require.NoError(t, err)
+	_, err = store.Get(first.ID)
+	require.ErrorIs(t, err, results.ErrNotFound)
+
+	// Exceeds the entry budget, so the next oldest is evicted.
+	_, err = store.Put("t", "text/plain", []byte("d"))
+	require.NoError(t, err)
+	_, err = store.Get(second.ID)
+	require.ErrorIs(t, err, results.ErrNotFound)
+	_, err = store.Get(third.ID)
+	require.NoError(t, err)
+	require.Equal(t, 2, store.Len())
+
+	_, err = store.Put("t", "text/plain", []byte(strings.Repeat("x", 11)))
+	require.ErrorIs(t, err, results.ErrTooLarge)
+}
+
+func TestStore_Expires(t *testing.T) {
+	t.Parallel()
+
+	store := results.NewStore(results.Options{TTL: 20 * time.Millisecond})
+
+	entry, err := store.Put("t", "text/plain", []byte("short lived"))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		_, err := store.Get(entry.ID)
+		return err != nil
+	}, time.Second, 5*time.Millisecond)
+	require.Zero(t, store.Len())
+}
+
+func TestIDFromURI_RejectsForeignURIs(t *testing.T) {
+	t.Parallel()
+
+	for _, uri := range []string{"", "cloudmcp://results/", "cloudmcp://other/abc", "cloudmcp://results/a/b", "file:///etc/passwd"} {
+		_, ok := results.IDFromURI(uri)
+		require.False(t, ok, uri)
+	}
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+// accountMiddleware selects the account a provider tool call runs against:
+// the call's account argument if given, otherwise the session's active
+// account. The account is attached to the context and the argument consumed.
+func (s *Server) accountMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		provider, ok := s.toolProviders[request.Params.Name]
+		if !ok {
+			return next(ctx, request)
+		}
+
+		args := request.GetArguments()
+		alias, _ := args[contracts.AccountParam].(string)
+		if _, present := args[contracts.AccountParam]; present {
+			stripped := maps.Clone(args)
+			delete(stripped, contracts.AccountParam)
+			request.Params.Arguments = stripped
+		}
+
+		if alias != "" {
+			account, err := s.accounts.Get(provider, alias)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return next(contracts.WithAccount(ctx, account), request)
+		}
+
+		session := ""
+		if clientSession := server.ClientSessionFromContext(ctx); clientSession != nil {
+			session = clientSession.SessionID()
+		}
+		if account, ok := s.accounts.Active(session, provider); ok {
+			ctx = contracts.WithAccount(ctx, account)
+		}
+
+		return next(ctx, request)
+	}
+}
+
+// withAccountParam adds the optional account argument to a provider tool's
+// input schema.
+func withAccountParam(definition mcp.Tool) (mcp.Tool, error) {
+	property := map[string]any{
+		"type":        "string",
+		"description": "Account alias to use for this call instead of the session's active account (see list_accounts)",
+	}
+
+	if definition.RawInputSchema == nil {
+		if definition.InputSchema.Properties == nil {
+			definition.InputSchema.Properties = make(map[string]any)
+		}
+		definition.InputSchema.Properties[contracts.AccountParam] = property
+		return definition, nil
+	}
+
+	var schema map[string]any
+	if err := json.Unmarshal(definition.RawInputSchema, &schema); err != nil {
+		return mcp.Tool{}, fmt.Errorf("failed to parse input schema: %w", err)
+	}
+	properties, _ := schema["properties"].(map[string]any)
+	if properties == nil {
+		properties = make(map[string]any)
+	}
+	properties[contracts.AccountParam] = property
+	schema["properties"] = properties
+
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return mcp.Tool{}, fmt.Errorf("failed to marshal input schema: %w", err)
+	}
+	definition.RawInputSchema = raw
+
+	return definition, nil
+}
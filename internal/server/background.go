@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/jobs"
+)
+
+// ErrToolFailed is recorded on background jobs whose tool returned an error result.
+var ErrToolFailed = errors.New("tool failed")
+
+// backgroundMiddleware runs calls as background jobs when their standard
+// background argument is true, returning the job ID at once. Only tools that
+// advertise the argument, such as those that wait for provider actions, can
+// run in the background. The job keeps the call's context values, so it runs
+// against the same account and credentials. Jobs bypass the redact
+// middleware, so their progress and outcome are redacted before the job
+// manager records them. The argument is consumed here.
+func (s *Server) backgroundMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		raw, present := args[jobs.Param]
+		if !present {
+			return next(ctx, request)
+		}
+
+		stripped := maps.Clone(args)
+		delete(stripped, jobs.Param)
+		request.Params.Arguments = stripped
+
+		if background, _ := raw.(bool); !background || !s.backgroundTools[request.Params.Name] {
+			return next(ctx, request)
+		}
+
+		// The call returns before the job makes progress, so progress is
+		// recorded on the job for job_status instead of sent to the client.
+		request.Params.Meta = nil
+		job, err := s.jobs.Submit(ctx, request.Params.Name, func(ctx context.Context) (any, error) {
+			return s.redactOutcome(jobResult(next(s.redactProgress(ctx), request)))
+		})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return jobs.StartedResult(job), nil
+	}
+}
+
+// jobResult converts the result of a tool run as a job into the job's
+// outcome: the tool's structured data, or its text. Error results fail the job.
+func jobResult(result *mcp.CallToolResult, err error) (any, error) {
+	if err != nil || result == nil {
+		return nil, err
+	}
+
+	data, structured := format.Take(result)
+	text := ""
+	for _, content := range result.Content {
+		if textContent, ok := content.(mcp.TextContent); ok {
+			text += textContent.Text
+		}
+	}
+
+	switch {
+	case result.IsError:
+		return nil, fmt.Errorf("%w: %s", ErrToolFailed, text)
+	case structured:
+		return data, nil
+	default:
+		return text, nil
+	}
+}
+
+// advertisesBackground reports whether a tool definition declares the
+// standard background argument.
+func advertisesBackground(definition mcp.Tool) bool {
+	_, ok := definition.InputSchema.Properties[jobs.Param]
+	return ok
+}
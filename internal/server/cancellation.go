@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// methodNotificationCancelled is the MCP method a client uses to abandon a request.
+const methodNotificationCancelled = "notifications/cancelled"
+
+// inflightCall is a tool call that is currently executing.
+type inflightCall struct {
+	tool      string
+	cancel    context.CancelFunc
+	cancelled bool
+}
+
+// inflightCalls tracks executing tool calls by JSON-RPC request id so that
+// client cancellations can reach the handler context.
+type inflightCalls struct {
+	mu    sync.Mutex
+	calls map[string]*inflightCall
+}
+
+func newInflightCalls() *inflightCalls {
+	return &inflightCalls{calls: make(map[string]*inflightCall)}
+}
+
+// add registers a call; it returns false if the id is already in flight.
+func (c *inflightCalls) add(key, tool string, cancel context.CancelFunc) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.calls[key]; exists {
+		return false
+	}
+	c.calls[key] = &inflightCall{tool: tool, cancel: cancel}
+
+	return true
+}
+
+// remove forgets a finished call.
+func (c *inflightCalls) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.calls, key)
+}
+
+// cancel cancels the call's context and returns the tool name, or false if
+// no such call is in flight.
+func (c *inflightCalls) cancel(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	call, ok := c.calls[key]
+	if !ok {
+		return "", false
+	}
+	call.cancelled = true
+	call.cancel()
+
+	return call.tool, true
+}
+
+// cancelledByClient reports whether the client cancelled the call.
+func (c *inflightCalls) cancelledByClient(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	call, ok := c.calls[key]
+	return ok && call.cancelled
+}
+
+// handleCancelled processes notifications/cancelled by cancelling the matching
+// in-flight tool call. Notifications for unknown or finished requests are
+// expected due to latency and are only logged.
+func (s *Server) handleCancelled(_ context.Context, notification mcp.JSONRPCNotification) {
+	requestID, ok := notification.Params.AdditionalFields["requestId"]
+	if !ok {
+		log.Printf("Ignoring cancellation without requestId")
+		return
+	}
+
+	reason, _ := notification.Params.AdditionalFields["reason"].(string)
+	if reason == "" {
+		reason = "no reason given"
+	}
+
+	key := requestKey(requestID)
+	tool, found := s.calls.cancel(key)
+	if !found {
+		log.Printf("Ignoring cancellation for request %s: not in flight", key)
+		return
+	}
+
+	log.Printf("Cancelled tool call %s (request %s): %s", tool, key, reason)
+}
+
+// requestKey normalizes a JSON-RPC id, raw or already decoded, to a comparable
+// key so that the id of a request and of its cancellation always match.
+func requestKey(id any) string {
+	if raw, ok := id.(json.RawMessage); ok {
+		var decoded any
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			return string(raw)
+		}
+		id = decoded
+	}
+
+	data, err := json.Marshal(id)
+	if err != nil {
+		return ""
+	}
+
+	return string(data)
+}
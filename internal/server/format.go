@@ -0,0 +1,57 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/chadit/CloudMCP/internal/format"
+)
+
+// formatMiddleware renders structured tool results in the format requested by
+// the standard "format" argument. The argument is consumed here and never
+// reaches the handler, so it cannot affect pagination cursors. It is still
+// part of the idempotency fingerprint, since that middleware runs first, so
+// repeating a key with another format is rejected like any other change.
+func (s *Server) formatMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		raw, present := args[format.Param]
+		name, isString := raw.(string)
+		if present && raw != nil && !isString {
+			return mcp.NewToolResultError(fmt.Sprintf("%v: %v", format.ErrUnsupportedFormat, raw)), nil
+		}
+
+		outputFormat, err := format.Parse(name)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		if present {
+			stripped := maps.Clone(args)
+			delete(stripped, format.Param)
+			request.Params.Arguments = stripped
+		}
+
+		result, err := next(ctx, request)
+		if err != nil || result == nil {
+			return result, err
+		}
+
+		data, structured := format.Take(result)
+		if !structured || result.IsError {
+			return result, nil
+		}
+
+		text, err := format.Render(data, outputFormat)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		result.Content = []mcp.Content{mcp.NewTextContent(text)}
+
+		return result, nil
+	}
+}
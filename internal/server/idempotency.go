@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/chadit/CloudMCP/internal/config"
+	"github.com/chadit/CloudMCP/internal/idempotency"
+)
+
+// idempotencyMiddleware runs calls that carry an idempotency key at most once
+// per key, arguments and account within the configured window. It runs inside
+// account resolution, so the same key used after switch_account acts on the
+// new account. The key is consumed here; handlers can read it from the
+// context to forward it upstream.
+func (s *Server) idempotencyMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		key, ok, err := idempotency.KeyFrom(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if !ok {
+			return next(ctx, request)
+		}
+
+		stripped := maps.Clone(args)
+		delete(stripped, idempotency.Param)
+		request.Params.Arguments = stripped
+
+		result, err := s.idempotency.Do(idempotency.WithKey(ctx, key), request.Params.Name, key, stripped,
+			func(ctx context.Context) (*mcp.CallToolResult, error) {
+				return next(ctx, request)
+			},
+		)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return result, nil
+	}
+}
+
+// newIdempotencyStore creates the idempotency store selected by the configuration.
+func newIdempotencyStore(cfg *config.Config) (idempotency.Store, error) {
+	switch cfg.IdempotencyStore {
+	case "", config.IdempotencyStoreMemory:
+		return idempotency.NewMemoryStore(), nil
+	case config.IdempotencyStoreFile:
+		if cfg.IdempotencyFile == "" {
+			return nil, fmt.Errorf("%w: file store needs an idempotency file", config.ErrInvalidIdempotencyStore)
+		}
+		return idempotency.NewFileStore(cfg.IdempotencyFile)
+	default:
+		return nil, fmt.Errorf("%w: %q", config.ErrInvalidIdempotencyStore, cfg.IdempotencyStore)
+	}
+}
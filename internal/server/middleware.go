@@ -0,0 +1,23 @@
+package server
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/chadit/CloudMCP/internal/jobs"
+	"github.com/chadit/CloudMCP/internal/pagination"
+)
+
+// contextMiddleware attaches server-scoped services to the handler context:
+// the job manager for moving work into the background and the cursor codec
+// used by list tools.
+func (s *Server) contextMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = jobs.WithManager(ctx, s.jobs)
+		ctx = pagination.WithCodec(ctx, s.cursors)
+
+		return next(ctx, request)
+	}
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/chadit/CloudMCP/internal/config"
+	"github.com/chadit/CloudMCP/internal/results"
+)
+
+// offloadMiddleware truncates tool results whose text exceeds the configured
+// inline budget. The full text is kept in the result store and the truncated
+// result ends with a note naming the resource URI that serves it.
+func (s *Server) offloadMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, request)
+		if err != nil || result == nil {
+			return result, err
+		}
+
+		budget := s.maxResultBytes()
+		var texts []string
+		var others []mcp.Content
+		size := 0
+		for _, content := range result.Content {
+			if text, ok := content.(mcp.TextContent); ok {
+				texts = append(texts, text.Text)
+				size += len(text.Text)
+				continue
+			}
+			others = append(others, content)
+		}
+		if size <= budget {
+			return result, nil
+		}
+
+		full := strings.Join(texts, "\n")
+		entry, err := s.results.Put(request.Params.Name, mimeTypeOf(full), []byte(full))
+		if err != nil {
+			log.Printf("Failed to store full result of %s: %v", request.Params.Name, err)
+			note := fmt.Sprintf("[Output truncated to %d of %d bytes; the full result is too large to store.]", budget, len(full))
+			return truncatedResult(result, truncateUTF8(full, budget), note, others), nil
+		}
+
+		note := fmt.Sprintf(
+			"[Output truncated to %d of %d bytes. The full result is available as resource %s for %s.]",
+			budget, len(full), entry.URI(), s.results.TTL(),
+		)
+		return truncatedResult(result, truncateUTF8(full, budget), note, others), nil
+	}
+}
+
+// readResult serves stored tool results as resources.
+func (s *Server) readResult(_ context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	id, ok := results.IDFromURI(request.Params.URI)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", results.ErrNotFound, request.Params.URI)
+	}
+
+	entry, err := s.results.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      entry.URI(),
+			MIMEType: entry.MIMEType,
+			Text:     string(entry.Data),
+		},
+	}, nil
+}
+
+// maxResultBytes returns the inline text budget for tool results.
+func (s *Server) maxResultBytes() int {
+	if s.config.MaxResultBytes > 0 {
+		return s.config.MaxResultBytes
+	}
+
+	return config.DefaultMaxResultBytes
+}
+
+// truncatedResult rebuilds result with the truncated text and note in place
+// of its original text content. Non-text content is preserved.
+func truncatedResult(result *mcp.CallToolResult, text, note string, others []mcp.Content) *mcp.CallToolResult {
+	content := make([]mcp.Content, 0, len(others)+2)
+	content = append(content, mcp.NewTextContent(text), mcp.NewTextContent(note))
+	content = append(content, others...)
+
+	return &mcp.CallToolResult{Result: result.Result, Content: content, IsError: result.IsError}
+}
+
+// truncateUTF8 cuts s to at most limit bytes without splitting a rune.
+func truncateUTF8(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+
+	for limit > 0 && !utf8.RuneStart(s[limit]) {
+		limit--
+	}
+
+	return s[:limit]
+}
+
+// mimeTypeOf guesses the MIME type of a stored result.
+func mimeTypeOf(text string) string {
+	if json.Valid([]byte(text)) {
+		return "application/json"
+	}
+
+	return "text/plain"
+}
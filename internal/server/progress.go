@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/chadit/CloudMCP/internal/config"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+// methodNotificationProgress is the MCP method used for progress notifications.
+const methodNotificationProgress = "notifications/progress"
+
+// progressReporter sends throttled notifications/progress messages for a single tool call.
+type progressReporter struct {
+	ctx      context.Context
+	mcp      *server.MCPServer
+	token    mcp.ProgressToken
+	interval time.Duration
+
+	mu       sync.Mutex
+	lastSent time.Time
+	done     bool
+}
+
+// Report implements contracts.ProgressReporter. Updates arriving faster than the
+// configured interval are dropped, except the final one (progress >= total) which
+// is always delivered so clients never miss completion.
+func (r *progressReporter) Report(progress, total float64, message string) {
+	final := total > 0 && progress >= total
+
+	r.mu.Lock()
+	now := time.Now()
+	if r.done || (!final && !r.lastSent.IsZero() && now.Sub(r.lastSent) < r.interval) {
+		r.mu.Unlock()
+		return
+	}
+	r.lastSent = now
+	r.done = final
+	r.mu.Unlock()
+
+	params := map[string]any{
+		"progressToken": r.token,
+		"progress":      progress,
+	}
+	if total > 0 {
+		params["total"] = total
+	}
+	if message != "" {
+		params["message"] = message
+	}
+
+	if err := r.mcp.SendNotificationToClient(r.ctx, methodNotificationProgress, params); err != nil {
+		log.Printf("Failed to send progress notification: %v", err)
+	}
+}
+
+// progressMiddleware attaches a ProgressReporter to the handler context when the
+// client supplied a progressToken with the tool call.
+func (s *Server) progressMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if request.Params.Meta == nil || request.Params.Meta.ProgressToken == nil {
+			return next(ctx, request)
+		}
+
+		reporter := &progressReporter{
+			ctx:      ctx,
+			mcp:      s.mcp,
+			token:    request.Params.Meta.ProgressToken,
+			interval: s.progressInterval(),
+		}
+
+		return next(contracts.WithProgressReporter(ctx, reporter), request)
+	}
+}
+
+// progressInterval returns the configured progress throttle interval.
+func (s *Server) progressInterval() time.Duration {
+	if s.config.ProgressInterval > 0 {
+		return s.config.ProgressInterval
+	}
+
+	return config.DefaultProgressInterval
+}
@@ -0,0 +1,298 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/chadit/CloudMCP/internal/httpclient"
+	"github.com/chadit/CloudMCP/internal/tools"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+const (
+	// providerInitTimeout bounds a provider's Initialize call.
+	providerInitTimeout = 30 * time.Second
+
+	// providerHealthTimeout bounds a provider's HealthCheck call.
+	providerHealthTimeout = 10 * time.Second
+
+	// providerShutdownTimeout bounds a provider's Shutdown call.
+	providerShutdownTimeout = 10 * time.Second
+)
+
+// Static errors for err113 compliance.
+var (
+	ErrProviderNil       = errors.New("provider cannot be nil")
+	ErrProviderNameEmpty = errors.New("provider name cannot be empty")
+	ErrProviderDuplicate = errors.New("provider already added")
+	ErrProviderPanicked  = errors.New("provider panicked")
+	ErrProviderStarted   = errors.New("providers are already initialized")
+	ErrDuplicateTool     = errors.New("tool is already registered")
+)
+
+// Provider lifecycle states reported by the health tool.
+const (
+	providerPending  = "pending"
+	providerReady    = "ready"
+	providerFailed   = "failed"
+	providerShutDown = "shut_down"
+)
+
+// providerEntry tracks one provider through its lifecycle.
+type providerEntry struct {
+	provider contracts.Provider
+
+	mu    sync.Mutex
+	state string
+	err   string
+	tools []string
+}
+
+// AddProvider adds a provider to be initialized when the server starts serving.
+func (s *Server) AddProvider(provider contracts.Provider) error {
+	if provider == nil {
+		return ErrProviderNil
+	}
+	if provider.Name() == "" {
+		return ErrProviderNameEmpty
+	}
+
+	s.providersMu.Lock()
+	defer s.providersMu.Unlock()
+
+	if s.providersStarted {
+		return ErrProviderStarted
+	}
+	for _, entry := range s.providers {
+		if entry.provider.Name() == provider.Name() {
+			return fmt.Errorf("%w: %s", ErrProviderDuplicate, provider.Name())
+		}
+	}
+
+	s.providers = append(s.providers, &providerEntry{provider: provider, state: providerPending})
+
+	return nil
+}
+
+// initializeProviders initializes all providers concurrently, then registers
+// the tools of those that succeeded in the order they were added. Failures are
+// logged and recorded; they do not affect other providers.
+func (s *Server) initializeProviders(ctx context.Context) {
+	s.providersMu.Lock()
+	if s.providersStarted {
+		s.providersMu.Unlock()
+		return
+	}
+	s.providersStarted = true
+	entries := s.providers
+	s.providersMu.Unlock()
+
+	errs := make([]error, len(entries))
+	var wg sync.WaitGroup
+	for i, entry := range entries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = s.initializeProvider(ctx, entry.provider)
+		}()
+	}
+	wg.Wait()
+
+	for i, entry := range entries {
+		name := entry.provider.Name()
+		err := errs[i]
+		if err == nil {
+			if err = s.registerProviderTools(entry); err != nil {
+				// Initialized but unusable: release what Initialize acquired
+				if shutdownErr := shutdownProvider(entry.provider); shutdownErr != nil {
+					log.Printf("Provider %s failed to shut down: %v", name, shutdownErr)
+				}
+			}
+		}
+		if err != nil {
+			log.Printf("Provider %s failed to initialize: %v", name, err)
+			entry.setState(providerFailed, err)
+			continue
+		}
+
+		entry.setState(providerReady, nil)
+		log.Printf("Provider %s initialized with %d tools", name, len(entry.tools))
+
+		if discoverer, ok := entry.provider.(contracts.AccountDiscoverer); ok {
+			added := s.accounts.Add(name, discoverer.DiscoveredAccounts())
+			log.Printf("Provider %s discovered %d accounts", name, len(added))
+		}
+	}
+}
+
+// initializeProvider runs a provider's Initialize with a timeout, turning a
+// panic into an error.
+func (s *Server) initializeProvider(ctx context.Context, provider contracts.Provider) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, providerInitTimeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrProviderPanicked, r)
+		}
+	}()
+
+	return provider.Initialize(ctx, contracts.ProviderConfig{
+		Settings: s.config.ProviderSettings[provider.Name()],
+		HTTPClient: httpclient.New(httpclient.Options{
+			Breakers: s.breakers,
+		}),
+		Secrets:  s.secrets,
+		Accounts: s.accounts.List(provider.Name()),
+	})
+}
+
+// registerProviderTools registers an initialized provider's tools. Every
+// definition is built and checked before any tool is added, so a provider
+// whose tools fail, or whose tool names are taken, leaves none registered.
+func (s *Server) registerProviderTools(entry *providerEntry) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrProviderPanicked, r)
+		}
+	}()
+
+	name := entry.provider.Name()
+	providerTools := entry.provider.Tools()
+
+	taken := make(map[string]bool, len(s.tools)+len(providerTools))
+	for _, tool := range s.tools {
+		taken[tool.Name()] = true
+	}
+	prepared := make([]preparedTool, 0, len(providerTools))
+	for _, tool := range providerTools {
+		p, err := prepareTool(tool, name)
+		if err != nil {
+			return err
+		}
+		if taken[p.name] {
+			return fmt.Errorf("%w: %s", ErrDuplicateTool, p.name)
+		}
+		taken[p.name] = true
+		prepared = append(prepared, p)
+	}
+
+	for _, p := range prepared {
+		s.addTool(p, name)
+		entry.tools = append(entry.tools, p.name)
+	}
+
+	return nil
+}
+
+// shutdownProviders shuts down initialized providers in reverse order.
+func (s *Server) shutdownProviders() {
+	s.providersMu.Lock()
+	entries := s.providers
+	s.providersMu.Unlock()
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if entry.currentState() != providerReady {
+			continue
+		}
+
+		name := entry.provider.Name()
+		if err := shutdownProvider(entry.provider); err != nil {
+			log.Printf("Provider %s failed to shut down: %v", name, err)
+		}
+		entry.setState(providerShutDown, nil)
+	}
+}
+
+// shutdownProvider runs a provider's Shutdown with a timeout, turning a panic
+// into an error.
+func shutdownProvider(provider contracts.Provider) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), providerShutdownTimeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrProviderPanicked, r)
+		}
+	}()
+
+	return provider.Shutdown(ctx)
+}
+
+// providerHealth checks every provider concurrently and reports the results
+// in the order providers were added.
+func (s *Server) providerHealth(ctx context.Context) []tools.ProviderHealth {
+	s.providersMu.Lock()
+	entries := s.providers
+	s.providersMu.Unlock()
+
+	report := make([]tools.ProviderHealth, len(entries))
+	var wg sync.WaitGroup
+	for i, entry := range entries {
+		entry.mu.Lock()
+		report[i] = tools.ProviderHealth{
+			Name:  entry.provider.Name(),
+			State: entry.state,
+			Error: entry.err,
+			Tools: len(entry.tools),
+		}
+		entry.mu.Unlock()
+
+		if report[i].State != providerReady {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := checkProvider(ctx, entry.provider); err != nil {
+				report[i].Error = err.Error()
+				return
+			}
+			report[i].Healthy = true
+		}()
+	}
+	wg.Wait()
+
+	return report
+}
+
+// checkProvider runs a provider's HealthCheck with a timeout, turning a panic
+// into an error.
+func checkProvider(ctx context.Context, provider contracts.Provider) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, providerHealthTimeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrProviderPanicked, r)
+		}
+	}()
+
+	return provider.HealthCheck(ctx)
+}
+
+// setState records a lifecycle transition.
+func (e *providerEntry) setState(state string, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.state = state
+	e.err = ""
+	if err != nil {
+		e.err = err.Error()
+	}
+}
+
+// currentState returns the lifecycle state.
+func (e *providerEntry) currentState() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.state
+}
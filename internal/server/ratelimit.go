@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/chadit/CloudMCP/internal/ratelimit"
+)
+
+// rateLimitMetaKey carries the structured rate limit error on rejected results.
+const rateLimitMetaKey = "cloudmcp/error"
+
+// rateLimitMiddleware rejects tool calls that exceed the configured per-tool,
+// per-provider or per-session token buckets. Rejections are logged and
+// returned as error results naming the limit and when to retry.
+func (s *Server) rateLimitMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		call := ratelimit.Call{
+			Tool:     request.Params.Name,
+			Provider: s.toolProviders[request.Params.Name],
+		}
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			call.Session = session.SessionID()
+		}
+
+		err := s.limiter.Allow(call)
+		var limited *ratelimit.LimitError
+		if !errors.As(err, &limited) {
+			return next(ctx, request)
+		}
+
+		log.Printf("Rate limited call to %s (session %s): %v", call.Tool, call.Session, limited)
+
+		result := mcp.NewToolResultError(limited.Error())
+		result.Meta = map[string]any{
+			rateLimitMetaKey: map[string]any{
+				"code":                "rate_limited",
+				"scope":               limited.Scope,
+				"key":                 limited.Key,
+				"limit":               limited.Rate.String(),
+				"retry_after_seconds": limited.RetryAfterSeconds(),
+			},
+		}
+
+		return result, nil
+	}
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/chadit/CloudMCP/internal/credentials"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+// redactMiddleware replaces resolved secret values in tool result text, so a
+// provider echoing a token back cannot leak it to the client.
+func (s *Server) redactMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, request)
+		if err != nil || result == nil {
+			return result, err
+		}
+
+		redactor := s.secrets.Redactor()
+		for i, content := range result.Content {
+			if text, ok := content.(mcp.TextContent); ok {
+				text.Text = redactor.Redact(text.Text)
+				result.Content[i] = text
+			}
+		}
+
+		return result, nil
+	}
+}
+
+// redactOutcome replaces resolved secret values in the outcome of a
+// background job, which is persisted with the job rather than returned
+// through redactMiddleware. Structured results are redacted string by string
+// after a JSON round trip, so a secret is matched in its unescaped form.
+func (s *Server) redactOutcome(result any, err error) (any, error) {
+	redactor := s.secrets.Redactor()
+	if err != nil {
+		return nil, redactedError{err: err, message: redactor.Redact(err.Error())}
+	}
+
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode result: %w", err)
+	}
+
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+
+	return redactValue(redactor, value), nil
+}
+
+// redactValue redacts every string in a decoded JSON value.
+func redactValue(redactor *credentials.Redactor, value any) any {
+	switch v := value.(type) {
+	case string:
+		return redactor.Redact(v)
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(redactor, item)
+		}
+		return v
+	case map[string]any:
+		redacted := make(map[string]any, len(v))
+		for key, item := range v {
+			redacted[redactor.Redact(key)] = redactValue(redactor, item)
+		}
+		return redacted
+	default:
+		return v
+	}
+}
+
+// redactedError carries a redacted message while keeping the original error
+// for errors.Is, so cancelled jobs are still recognized.
+type redactedError struct {
+	err     error
+	message string
+}
+
+func (e redactedError) Error() string { return e.message }
+func (e redactedError) Unwrap() error { return e.err }
+
+// redactProgress returns ctx with a progress reporter that redacts messages
+// before passing them to the reporter already attached to ctx.
+func (s *Server) redactProgress(ctx context.Context) context.Context {
+	return contracts.WithProgressReporter(ctx, redactingProgress{
+		next:     contracts.ProgressFromContext(ctx),
+		redactor: s.secrets.Redactor(),
+	})
+}
+
+// redactingProgress redacts progress messages.
+type redactingProgress struct {
+	next     contracts.ProgressReporter
+	redactor *credentials.Redactor
+}
+
+func (p redactingProgress) Report(progress, total float64, message string) {
+	p.next.Report(progress, total, p.redactor.Redact(message))
+}
+
+// LogWriter returns a writer that redacts resolved secret values before
+// writing to w, for use as the log output.
+func (s *Server) LogWriter(w io.Writer) io.Writer {
+	return s.secrets.Redactor().Writer(w)
+}
@@ -3,29 +3,62 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
+	"os"
+	"sync"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 
+	"github.com/chadit/CloudMCP/internal/accounts"
 	"github.com/chadit/CloudMCP/internal/config"
+	"github.com/chadit/CloudMCP/internal/credentials"
+	"github.com/chadit/CloudMCP/internal/httpclient"
+	"github.com/chadit/CloudMCP/internal/idempotency"
+	"github.com/chadit/CloudMCP/internal/jobs"
+	"github.com/chadit/CloudMCP/internal/pagination"
+	"github.com/chadit/CloudMCP/internal/ratelimit"
+	"github.com/chadit/CloudMCP/internal/results"
 	"github.com/chadit/CloudMCP/internal/tools"
 	"github.com/chadit/CloudMCP/pkg/contracts"
 )
 
 // Server represents a minimal CloudMCP server with simple tools.
 type Server struct {
-	config *config.Config
-	mcp    *server.MCPServer
-	tools  []contracts.Tool
+	config      *config.Config
+	mcp         *server.MCPServer
+	tools       []contracts.Tool
+	calls       *inflightCalls
+	jobs        *jobs.Manager
+	cursors     *pagination.Codec
+	results     *results.Store
+	idempotency *idempotency.Guard
+	breakers    *httpclient.Breakers
+	limiter     *ratelimit.Limiter
+	accounts    *accounts.Registry
+	secrets     *credentials.Resolver
+
+	// toolProviders maps provider tool names to the provider that owns them.
+	toolProviders map[string]string
+
+	// backgroundTools holds the tools that can run as background jobs.
+	backgroundTools map[string]bool
+
+	providersMu      sync.Mutex
+	providers        []*providerEntry
+	providersStarted bool
 }
 
 // Static errors for err113 compliance.
 var (
 	ErrConfigNil             = errors.New("config cannot be nil")
 	ErrExecuteNotImplemented = errors.New("execute method not implemented for wrapper")
+	ErrToolNil               = errors.New("tool cannot be nil")
+	ErrToolNameEmpty         = errors.New("tool name cannot be empty")
 )
 
 // New creates a new minimal CloudMCP server with hello and version tools.
@@ -34,20 +67,88 @@ func New(cfg *config.Config) (*Server, error) {
 		return nil, ErrConfigNil
 	}
 
-	// Create MCP server
-	mcpServer := server.NewMCPServer(
-		cfg.ServerName,
-		"0.1.0",
-		server.WithToolCapabilities(true),
-	)
+	// Create the idempotency store before anything that needs shutting down
+	idempotencyStore, err := newIdempotencyStore(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create idempotency store: %w", err)
+	}
+
+	// Load the cloud account registry
+	accountRegistry, err := accounts.Load(cfg.AccountsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load accounts: %w", err)
+	}
+
+	// Parse tool call rate limits
+	rateLimits, err := ratelimit.ParseRules(cfg.RateLimits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rate limits: %w", err)
+	}
+
+	// Create background job manager
+	jobManager, err := jobs.NewManager(jobs.Options{
+		Workers: cfg.JobWorkers,
+		Path:    cfg.JobsFile,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job manager: %w", err)
+	}
+
+	// Create the signing codec for list tool cursors
+	cursors, err := pagination.NewCodec([]byte(cfg.CursorKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cursor codec: %w", err)
+	}
 
 	// Create server instance
 	s := &Server{
-		config: cfg,
-		mcp:    mcpServer,
-		tools:  make([]contracts.Tool, 0),
+		config:  cfg,
+		tools:   make([]contracts.Tool, 0),
+		calls:   newInflightCalls(),
+		jobs:    jobManager,
+		cursors: cursors,
+		results: results.NewStore(results.Options{
+			TTL:      cfg.ResultTTL,
+			MaxBytes: cfg.ResultStoreBytes,
+		}),
+		idempotency: idempotency.NewGuard(idempotencyStore, cfg.IdempotencyWindow),
+		breakers:    httpclient.NewBreakers(0, 0),
+		limiter:     ratelimit.NewLimiter(rateLimits),
+		accounts:    accountRegistry,
+		secrets: credentials.NewResolver(credentials.Options{
+			Path:       cfg.CredentialsFile,
+			Passphrase: cfg.CredentialsPassphrase,
+			KeyFile:    cfg.CredentialsKeyFile,
+		}),
+
+		toolProviders:   make(map[string]string),
+		backgroundTools: make(map[string]bool),
 	}
 
+	// Create MCP server with the tool dispatch middleware chain, outermost first
+	s.mcp = server.NewMCPServer(
+		cfg.ServerName,
+		"0.1.0",
+		server.WithToolCapabilities(true),
+		server.WithResourceCapabilities(false, false),
+		server.WithToolHandlerMiddleware(s.offloadMiddleware),
+		server.WithToolHandlerMiddleware(s.accountMiddleware),
+		server.WithToolHandlerMiddleware(s.idempotencyMiddleware),
+		server.WithToolHandlerMiddleware(s.rateLimitMiddleware),
+		server.WithToolHandlerMiddleware(s.redactMiddleware),
+		server.WithToolHandlerMiddleware(s.formatMiddleware),
+		server.WithToolHandlerMiddleware(s.backgroundMiddleware),
+		server.WithToolHandlerMiddleware(s.contextMiddleware),
+		server.WithToolHandlerMiddleware(s.progressMiddleware),
+	)
+	s.mcp.AddNotificationHandler(methodNotificationCancelled, s.handleCancelled)
+	s.mcp.AddResourceTemplate(
+		mcp.NewResourceTemplate(results.URITemplate, "Tool result",
+			mcp.WithTemplateDescription("Full text of a tool result that was truncated inline"),
+		),
+		s.readResult,
+	)
+
 	// Register simple tools
 	if err := s.registerTools(); err != nil {
 		return nil, fmt.Errorf("failed to register tools: %w", err)
@@ -78,8 +179,27 @@ func (tw *toolWrapper) Execute(_ context.Context, _ map[string]any) (*mcp.CallTo
 	return nil, ErrExecuteNotImplemented
 }
 
-// Start starts the minimal CloudMCP server.
-func (s *Server) Start(_ context.Context) error {
+// Start starts the minimal CloudMCP server on stdin/stdout and shuts it down
+// once the client disconnects or the context is cancelled.
+func (s *Server) Start(ctx context.Context) error {
+	defer s.Shutdown()
+
+	return s.Serve(ctx, os.Stdin, os.Stdout)
+}
+
+// Shutdown stops background work owned by the server. Running jobs are
+// cancelled and recorded as interrupted, then providers are shut down in
+// reverse order of initialization.
+func (s *Server) Shutdown() {
+	s.jobs.Close()
+	s.shutdownProviders()
+}
+
+// Serve initializes providers, then runs the MCP protocol over the given reader
+// and writer until the input is exhausted or the context is cancelled.
+func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	s.initializeProviders(ctx)
+
 	log.Printf("Starting CloudMCP minimal server with %d tools", len(s.tools))
 
 	// Log registered tools
@@ -89,7 +209,105 @@ func (s *Server) Start(_ context.Context) error {
 
 	// Start MCP server (blocks until context is cancelled or error occurs)
 	log.Printf("CloudMCP server started successfully")
-	return server.ServeStdio(s.mcp)
+	if err := newStdioTransport(s, out).run(ctx, in); err != nil {
+		return fmt.Errorf("stdio transport: %w", err)
+	}
+
+	return nil
+}
+
+// RegisterTool registers a contracts.Tool with the MCP server. The tool's
+// Execute method receives the call arguments and a context carrying request
+// scoped values such as the progress reporter. The context is cancelled when
+// the client sends notifications/cancelled for the call or the server stops.
+func (s *Server) RegisterTool(tool contracts.Tool) error {
+	return s.registerTool(tool, "")
+}
+
+// registerTool registers a tool, owned by provider if not empty. Provider tools
+// also accept the optional account argument.
+func (s *Server) registerTool(tool contracts.Tool, provider string) error {
+	prepared, err := prepareTool(tool, provider)
+	if err != nil {
+		return err
+	}
+	s.addTool(prepared, provider)
+
+	return nil
+}
+
+// preparedTool is a validated tool with the definition advertised for it.
+type preparedTool struct {
+	tool       contracts.Tool
+	name       string
+	definition mcp.Tool
+}
+
+// prepareTool validates a tool and builds its definition, with the account
+// argument if it is owned by a provider. Nothing is registered.
+func prepareTool(tool contracts.Tool, provider string) (preparedTool, error) {
+	if tool == nil {
+		return preparedTool{}, ErrToolNil
+	}
+	name := tool.Name()
+	if name == "" {
+		return preparedTool{}, ErrToolNameEmpty
+	}
+
+	definition, err := toolDefinition(tool)
+	if err == nil && provider != "" {
+		definition, err = withAccountParam(definition)
+	}
+	if err != nil {
+		return preparedTool{}, fmt.Errorf("tool %s: %w", name, err)
+	}
+
+	return preparedTool{tool: tool, name: name, definition: definition}, nil
+}
+
+// addTool registers a prepared tool. It cannot fail, so a provider's tools
+// are registered all or none.
+func (s *Server) addTool(prepared preparedTool, provider string) {
+	if provider != "" {
+		s.toolProviders[prepared.name] = provider
+	}
+	if advertisesBackground(prepared.definition) {
+		s.backgroundTools[prepared.name] = true
+	}
+
+	tool := prepared.tool
+	s.mcp.AddTool(prepared.definition, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return tool.Execute(ctx, request.GetArguments())
+	})
+	s.tools = append(s.tools, tool)
+}
+
+// definer is implemented by tools that carry a complete MCP definition,
+// including annotations, rather than just a schema.
+type definer interface {
+	Definition() mcp.Tool
+}
+
+// toolDefinition converts a contracts.Tool into the mcp.Tool advertised to clients.
+func toolDefinition(tool contracts.Tool) (mcp.Tool, error) {
+	if d, ok := tool.(definer); ok {
+		return d.Definition(), nil
+	}
+
+	switch schema := tool.InputSchema().(type) {
+	case nil:
+		return mcp.NewTool(tool.Name(), mcp.WithDescription(tool.Description())), nil
+	case mcp.ToolInputSchema:
+		return mcp.Tool{Name: tool.Name(), Description: tool.Description(), InputSchema: schema}, nil
+	case json.RawMessage:
+		return mcp.NewToolWithRawSchema(tool.Name(), tool.Description(), schema), nil
+	default:
+		raw, err := json.Marshal(schema)
+		if err != nil {
+			return mcp.Tool{}, fmt.Errorf("failed to marshal input schema: %w", err)
+		}
+		return mcp.NewToolWithRawSchema(tool.Name(), tool.Description(), raw), nil
+	}
 }
 
 // GetToolCount returns the number of registered tools.
@@ -111,5 +329,37 @@ func (s *Server) registerTools() error {
 	// Create a wrapper tool to maintain interface compatibility
 	s.tools = append(s.tools, &toolWrapper{tool: versionTool})
 
+	// Create and register background job tools
+	for _, newTool := range []func(*jobs.Manager) (mcp.Tool, func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)){
+		tools.NewJobsListTool,
+		tools.NewJobStatusTool,
+		tools.NewJobResultTool,
+		tools.NewJobCancelTool,
+	} {
+		jobTool, jobHandler := newTool(s.jobs)
+		s.mcp.AddTool(jobTool, jobHandler)
+		s.tools = append(s.tools, &toolWrapper{tool: jobTool})
+	}
+
+	// Create and register HTTP diagnostics tool
+	diagnosticsTool, diagnosticsHandler := tools.NewHTTPDiagnosticsTool(s.breakers)
+	s.mcp.AddTool(diagnosticsTool, diagnosticsHandler)
+	s.tools = append(s.tools, &toolWrapper{tool: diagnosticsTool})
+
+	// Create and register account tools
+	for _, newTool := range []func(*accounts.Registry) (mcp.Tool, func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)){
+		tools.NewListAccountsTool,
+		tools.NewSwitchAccountTool,
+	} {
+		accountTool, accountHandler := newTool(s.accounts)
+		s.mcp.AddTool(accountTool, accountHandler)
+		s.tools = append(s.tools, &toolWrapper{tool: accountTool})
+	}
+
+	// Create and register provider health tool
+	healthTool, healthHandler := tools.NewProvidersHealthTool(s.providerHealth)
+	s.mcp.AddTool(healthTool, healthHandler)
+	s.tools = append(s.tools, &toolWrapper{tool: healthTool})
+
 	return nil
 }
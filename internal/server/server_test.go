@@ -0,0 +1,877 @@
+package server_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chadit/CloudMCP/internal/config"
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/providerkit"
+	"github.com/chadit/CloudMCP/internal/ratelimit"
+	"github.com/chadit/CloudMCP/internal/server"
+	"github.com/chadit/CloudMCP/pkg/contracts"
+)
+
+// funcTool is a contracts.Tool backed by a function, used to exercise the dispatch path.
+type funcTool struct {
+	name string
+	fn   func(ctx context.Context, params map[string]any) (*mcp.CallToolResult, error)
+}
+
+func (f *funcTool) Name() string        { return f.name }
+func (f *funcTool) Description() string { return "test tool " + f.name }
+func (f *funcTool) InputSchema() any    { return nil }
+
+func (f *funcTool) Execute(ctx context.Context, params map[string]any) (*mcp.CallToolResult, error) {
+	return f.fn(ctx, params)
+}
+
+// testClient drives a server over in-memory pipes.
+type testClient struct {
+	t        *testing.T
+	stdin    *io.PipeWriter
+	messages chan map[string]any
+}
+
+func startServer(t *testing.T, srv *server.Server) *testClient {
+	t.Helper()
+
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Serve(ctx, inReader, outWriter)
+		_ = outWriter.Close()
+	}()
+
+	client := &testClient{t: t, stdin: inWriter, messages: make(chan map[string]any, 256)}
+	go func() {
+		scanner := bufio.NewScanner(outReader)
+		for scanner.Scan() {
+			var message map[string]any
+			if err := json.Unmarshal(scanner.Bytes(), &message); err == nil {
+				client.messages <- message
+			}
+		}
+		close(client.messages)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		_ = inWriter.Close()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Error("server did not shut down")
+		}
+	})
+
+	client.send(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      0,
+		"method":  "initialize",
+		"params": map[string]any{
+			"protocolVersion": mcp.LATEST_PROTOCOL_VERSION,
+			"clientInfo":      map[string]any{"name": "test", "version": "1.0.0"},
+			"capabilities":    map[string]any{},
+		},
+	})
+	client.response(0)
+	client.send(map[string]any{"jsonrpc": "2.0", "method": "notifications/initialized"})
+
+	return client
+}
+
+func (c *testClient) send(message map[string]any) {
+	c.t.Helper()
+
+	data, err := json.Marshal(message)
+	require.NoError(c.t, err)
+	_, err = c.stdin.Write(append(data, '\n'))
+	require.NoError(c.t, err)
+}
+
+func (c *testClient) callTool(id int, name string, args map[string]any, meta map[string]any) {
+	c.t.Helper()
+
+	params := map[string]any{"name": name, "arguments": args}
+	if meta != nil {
+		params["_meta"] = meta
+	}
+	c.send(map[string]any{"jsonrpc": "2.0", "id": id, "method": "tools/call", "params": params})
+}
+
+// response waits for the response with the given id and returns any
+// notifications received before it.
+func (c *testClient) response(id int) (map[string]any, []map[string]any) {
+	c.t.Helper()
+
+	var notifications []map[string]any
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case message, ok := <-c.messages:
+			require.True(c.t, ok, "server closed output before response %d", id)
+			if _, isResponse := message["id"]; !isResponse {
+				notifications = append(notifications, message)
+				continue
+			}
+			if responseID, _ := message["id"].(float64); int(responseID) == id {
+				return message, notifications
+			}
+		case <-timeout:
+			c.t.Fatalf("timed out waiting for response %d", id)
+		}
+	}
+}
+
+func newTestServer(t *testing.T, cfg *config.Config, tools ...contracts.Tool) *server.Server {
+	t.Helper()
+
+	srv, err := server.New(cfg)
+	require.NoError(t, err)
+	for _, tool := range tools {
+		require.NoError(t, srv.RegisterTool(tool))
+	}
+
+	return srv
+}
+
+func TestRegisterTool_Validation(t *testing.T) {
+	srv := newTestServer(t, &config.Config{ServerName: "test"})
+
+	require.ErrorIs(t, srv.RegisterTool(nil), server.ErrToolNil)
+	require.ErrorIs(t, srv.RegisterTool(&funcTool{}), server.ErrToolNameEmpty)
+
+	count := srv.GetToolCount()
+	require.NoError(t, srv.RegisterTool(&funcTool{name: "noop"}))
+	require.Equal(t, count+1, srv.GetToolCount())
+}
+
+func TestProgress_ThrottledNotifications(t *testing.T) {
+	const steps = 100
+
+	tool := &funcTool{name: "slow", fn: func(ctx context.Context, _ map[string]any) (*mcp.CallToolResult, error) {
+		reporter := contracts.ProgressFromContext(ctx)
+		for i := 1; i <= steps; i++ {
+			reporter.Report(float64(i), steps, "working")
+		}
+		return mcp.NewToolResultText("done"), nil
+	}}
+
+	srv := newTestServer(t, &config.Config{ServerName: "test", ProgressInterval: time.Hour}, tool)
+	client := startServer(t, srv)
+
+	client.callTool(1, "slow", nil, map[string]any{"progressToken": "tok-1"})
+	response, notifications := client.response(1)
+	require.Contains(t, response, "result")
+
+	// Notifications are delivered asynchronously; collect any stragglers.
+	deadline := time.After(200 * time.Millisecond)
+collect:
+	for {
+		select {
+		case message := <-client.messages:
+			notifications = append(notifications, message)
+		case <-deadline:
+			break collect
+		}
+	}
+
+	var progress []map[string]any
+	for _, notification := range notifications {
+		if notification["method"] == "notifications/progress" {
+			params, ok := notification["params"].(map[string]any)
+			require.True(t, ok)
+			progress = append(progress, params)
+		}
+	}
+
+	// The first update and the final update pass the throttle; everything in between is dropped.
+	require.Len(t, progress, 2)
+	require.Equal(t, "tok-1", progress[0]["progressToken"])
+	require.InDelta(t, 1, progress[0]["progress"], 0)
+	require.InDelta(t, steps, progress[1]["progress"], 0)
+	require.InDelta(t, steps, progress[1]["total"], 0)
+	require.Equal(t, "working", progress[1]["message"])
+}
+
+func TestProgress_NoTokenIsNoop(t *testing.T) {
+	tool := &funcTool{name: "quiet", fn: func(ctx context.Context, _ map[string]any) (*mcp.CallToolResult, error) {
+		contracts.ProgressFromContext(ctx).Report(1, 1, "done")
+		return mcp.NewToolResultText("ok"), nil
+	}}
+
+	srv := newTestServer(t, &config.Config{ServerName: "test"}, tool)
+	client := startServer(t, srv)
+
+	client.callTool(1, "quiet", nil, nil)
+	response, notifications := client.response(1)
+	require.Contains(t, response, "result")
+	require.Empty(t, notifications)
+}
+
+func TestCancellation_BlockedHandlerReturnsPromptly(t *testing.T) {
+	started := make(chan struct{})
+	returned := make(chan error, 1)
+
+	tool := &funcTool{name: "block", fn: func(ctx context.Context, _ map[string]any) (*mcp.CallToolResult, error) {
+		close(started)
+		select {
+		case <-ctx.Done():
+			returned <- ctx.Err()
+			return nil, ctx.Err()
+		case <-time.After(30 * time.Second):
+			returned <- nil
+			return mcp.NewToolResultText("finished"), nil
+		}
+	}}
+
+	srv := newTestServer(t, &config.Config{ServerName: "test"}, tool)
+	client := startServer(t, srv)
+
+	client.callTool(7, "block", nil, nil)
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("tool never started")
+	}
+
+	// The read loop must stay responsive while the tool is blocked.
+	client.callTool(8, "hello", map[string]any{"name": "Concurrent"}, nil)
+	response, _ := client.response(8)
+	require.Contains(t, response, "result")
+
+	client.send(map[string]any{
+		"jsonrpc": "2.0",
+		"method":  "notifications/cancelled",
+		"params":  map[string]any{"requestId": 7, "reason": "user aborted"},
+	})
+
+	select {
+	case err := <-returned:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-time.After(2 * time.Second):
+		t.Fatal("blocked handler did not return after cancellation")
+	}
+
+	// A cancelled request gets no response; the next request is answered normally.
+	client.callTool(9, "hello", nil, nil)
+	response, _ = client.response(9)
+	require.Contains(t, response, "result")
+}
+
+func TestCancellation_UnknownRequestIgnored(t *testing.T) {
+	srv := newTestServer(t, &config.Config{ServerName: "test"})
+	client := startServer(t, srv)
+
+	client.send(map[string]any{
+		"jsonrpc": "2.0",
+		"method":  "notifications/cancelled",
+		"params":  map[string]any{"requestId": "missing"},
+	})
+
+	client.callTool(1, "hello", nil, nil)
+	response, _ := client.response(1)
+	require.Contains(t, response, "result")
+}
+
+func TestOffload_TruncatesAndServesFullResult(t *testing.T) {
+	full := strings.Repeat("0123456789", 10)
+	tool := &funcTool{name: "big", fn: func(context.Context, map[string]any) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(full), nil
+	}}
+
+	srv := newTestServer(t, &config.Config{ServerName: "test", MaxResultBytes: 25}, tool)
+	client := startServer(t, srv)
+
+	client.callTool(1, "big", nil, nil)
+	response, _ := client.response(1)
+	texts := resultTexts(t, response)
+	require.Len(t, texts, 2)
+	require.Equal(t, full[:25], texts[0])
+	require.Contains(t, texts[1], "truncated to 25 of 100 bytes")
+
+	uri := regexp.MustCompile(`cloudmcp://results/[0-9a-f]+`).FindString(texts[1])
+	require.NotEmpty(t, uri, "note names the resource URI")
+
+	client.send(map[string]any{"jsonrpc": "2.0", "id": 2, "method": "resources/read", "params": map[string]any{"uri": uri}})
+	response, _ = client.response(2)
+	result, ok := response["result"].(map[string]any)
+	require.True(t, ok, "resources/read failed: %v", response)
+	contents, ok := result["contents"].([]any)
+	require.True(t, ok)
+	require.Len(t, contents, 1)
+	resource, ok := contents[0].(map[string]any)
+	require.True(t, ok)
+	require.Equal(t, full, resource["text"])
+	require.Equal(t, "text/plain", resource["mimeType"])
+
+	client.send(map[string]any{"jsonrpc": "2.0", "id": 3, "method": "resources/read", "params": map[string]any{"uri": "cloudmcp://results/unknown"}})
+	response, _ = client.response(3)
+	require.Contains(t, response, "error")
+}
+
+func TestOffload_SmallResultsUntouched(t *testing.T) {
+	srv := newTestServer(t, &config.Config{ServerName: "test"})
+	client := startServer(t, srv)
+
+	client.callTool(1, "hello", nil, nil)
+	response, _ := client.response(1)
+	require.Len(t, resultTexts(t, response), 1)
+}
+
+func TestFormat_RendersStructuredResults(t *testing.T) {
+	var received map[string]any
+	tool := &funcTool{name: "things", fn: func(_ context.Context, params map[string]any) (*mcp.CallToolResult, error) {
+		received = params
+		return format.NewResult([]map[string]any{{"name": "a", "size": 1}, {"name": "b", "size": 2}})
+	}}
+
+	srv := newTestServer(t, &config.Config{ServerName: "test"}, tool)
+	client := startServer(t, srv)
+
+	client.callTool(1, "things", map[string]any{"format": "csv", "region": "us-east"}, nil)
+	response, _ := client.response(1)
+	require.Equal(t, []string{"name,size\na,1\nb,2"}, resultTexts(t, response))
+	require.Equal(t, map[string]any{"region": "us-east"}, received, "format argument is consumed by the server")
+
+	result, ok := response["result"].(map[string]any)
+	require.True(t, ok)
+	require.NotContains(t, result, "_meta", "structured data is not sent to the client")
+
+	client.callTool(2, "version", map[string]any{"format": "yaml"}, nil)
+	response, _ = client.response(2)
+	texts := resultTexts(t, response)
+	require.Len(t, texts, 1)
+	require.Contains(t, texts[0], "version: ")
+
+	client.callTool(3, "things", map[string]any{"format": "xml"}, nil)
+	response, _ = client.response(3)
+	result, ok = response["result"].(map[string]any)
+	require.True(t, ok)
+	require.Equal(t, true, result["isError"])
+	require.Contains(t, resultTexts(t, response)[0], "unsupported format")
+}
+
+func TestIdempotency_RepeatedKeyReturnsFirstResult(t *testing.T) {
+	var runs int
+	var received map[string]any
+	tool := &funcTool{name: "create", fn: func(_ context.Context, params map[string]any) (*mcp.CallToolResult, error) {
+		runs++
+		received = params
+		return mcp.NewToolResultText(fmt.Sprintf("created %d", runs)), nil
+	}}
+
+	srv := newTestServer(t, &config.Config{ServerName: "test"}, tool)
+	client := startServer(t, srv)
+
+	args := map[string]any{"name": "web-1", "idempotency_key": "k1"}
+	client.callTool(1, "create", args, nil)
+	first, _ := client.response(1)
+	client.callTool(2, "create", args, nil)
+	second, _ := client.response(2)
+
+	require.Equal(t, 1, runs)
+	require.Equal(t, []string{"created 1"}, resultTexts(t, second))
+	require.Equal(t, resultTexts(t, first), resultTexts(t, second))
+	require.Equal(t, map[string]any{"name": "web-1"}, received, "idempotency key is consumed by the server")
+
+	client.callTool(3, "create", map[string]any{"name": "web-2", "idempotency_key": "k1"}, nil)
+	response, _ := client.response(3)
+	require.Contains(t, resultTexts(t, response)[0], "different arguments")
+	require.Equal(t, 1, runs)
+}
+
+func TestRateLimit_ReturnsStructuredError(t *testing.T) {
+	srv := newTestServer(t, &config.Config{ServerName: "test", RateLimits: "tool:hello=1/h"})
+	client := startServer(t, srv)
+
+	client.callTool(1, "hello", nil, nil)
+	response, _ := client.response(1)
+	require.Contains(t, resultTexts(t, response)[0], "Hello")
+
+	client.callTool(2, "hello", nil, nil)
+	response, _ = client.response(2)
+	require.Contains(t, resultTexts(t, response)[0], "retry after 3600 seconds")
+
+	result, ok := response["result"].(map[string]any)
+	require.True(t, ok)
+	require.Equal(t, true, result["isError"])
+	meta, ok := result["_meta"].(map[string]any)
+	require.True(t, ok, "structured error in %v", result)
+	detail, ok := meta["cloudmcp/error"].(map[string]any)
+	require.True(t, ok)
+	require.Equal(t, "rate_limited", detail["code"])
+	require.InDelta(t, 3600, detail["retry_after_seconds"], 0)
+
+	client.callTool(3, "version", nil, nil)
+	response, _ = client.response(3)
+	require.NotContains(t, resultTexts(t, response)[0], "rate limited")
+}
+
+func TestNew_InvalidRateLimits(t *testing.T) {
+	_, err := server.New(&config.Config{ServerName: "test", RateLimits: "tool=5"})
+	require.ErrorIs(t, err, ratelimit.ErrInvalidRule)
+}
+
+// fakeProvider is a contracts.Provider with scripted behavior.
+type fakeProvider struct {
+	name      string
+	initErr   error
+	healthErr error
+	tools     []contracts.Tool
+	settings  map[string]string
+	shutdowns *[]string
+}
+
+func (p *fakeProvider) Name() string { return p.name }
+
+func (p *fakeProvider) Initialize(_ context.Context, cfg contracts.ProviderConfig) error {
+	if p.name == "panicky" {
+		panic("boom")
+	}
+	p.settings = cfg.Settings
+	return p.initErr
+}
+
+func (p *fakeProvider) Tools() []contracts.Tool           { return p.tools }
+func (p *fakeProvider) HealthCheck(context.Context) error { return p.healthErr }
+func (p *fakeProvider) Shutdown(context.Context) error {
+	*p.shutdowns = append(*p.shutdowns, p.name)
+	return nil
+}
+
+func TestProviders_LifecycleAndHealth(t *testing.T) {
+	var shutdowns []string
+	echo := func(name string) contracts.Tool {
+		return &funcTool{name: name, fn: func(context.Context, map[string]any) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText(name + " ok"), nil
+		}}
+	}
+	first := &fakeProvider{name: "first", tools: []contracts.Tool{echo("first_list")}, shutdowns: &shutdowns}
+	broken := &fakeProvider{name: "broken", initErr: errors.New("bad credentials"), tools: []contracts.Tool{echo("broken_list")}, shutdowns: &shutdowns}
+	panicky := &fakeProvider{name: "panicky", shutdowns: &shutdowns}
+	clash := &fakeProvider{name: "clash", tools: []contracts.Tool{echo("hello")}, shutdowns: &shutdowns}
+	last := &fakeProvider{name: "last", healthErr: errors.New("api unreachable"), tools: []contracts.Tool{echo("last_list")}, shutdowns: &shutdowns}
+
+	srv := newTestServer(t, &config.Config{
+		ServerName:       "test",
+		ProviderSettings: map[string]map[string]string{"first": {"region": "us-east"}},
+	})
+	for _, provider := range []contracts.Provider{first, broken, panicky, clash, last} {
+		require.NoError(t, srv.AddProvider(provider))
+	}
+	require.ErrorIs(t, srv.AddProvider(&fakeProvider{name: "first"}), server.ErrProviderDuplicate)
+
+	client := startServer(t, srv)
+	require.Equal(t, map[string]string{"region": "us-east"}, first.settings)
+
+	client.callTool(1, "last_list", nil, nil)
+	response, _ := client.response(1)
+	require.Equal(t, []string{"last_list ok"}, resultTexts(t, response), "a failed provider does not block the ones after it")
+
+	client.callTool(2, "broken_list", nil, nil)
+	response, _ = client.response(2)
+	require.Contains(t, response, "error", "tools of a failed provider are not registered")
+
+	client.callTool(3, "providers_health", map[string]any{"format": "json-compact"}, nil)
+	response, _ = client.response(3)
+	var health []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(resultTexts(t, response)[0]), &health))
+	require.Len(t, health, 5)
+	require.Equal(t, map[string]any{"name": "first", "state": "ready", "healthy": true, "tools": float64(1)}, health[0])
+	require.Equal(t, "failed", health[1]["state"])
+	require.Equal(t, "bad credentials", health[1]["error"])
+	require.Contains(t, health[2]["error"], "panicked")
+	require.Contains(t, health[3]["error"], server.ErrDuplicateTool.Error())
+	require.Equal(t, "ready", health[4]["state"])
+	require.Equal(t, false, health[4]["healthy"])
+	require.Equal(t, "api unreachable", health[4]["error"])
+
+	srv.Shutdown()
+	require.Equal(t, []string{"clash", "last", "first"}, shutdowns,
+		"clash is released when its tools fail to register; ready providers stop in reverse order")
+}
+
+// schemaTool is a funcTool with a scripted input schema.
+type schemaTool struct {
+	funcTool
+	schema func() any
+}
+
+func (t *schemaTool) InputSchema() any { return t.schema() }
+
+func TestProviders_FailedToolsLeaveNoneRegistered(t *testing.T) {
+	ok := func(name string) contracts.Tool {
+		return &funcTool{name: name, fn: func(context.Context, map[string]any) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText(name + " ok"), nil
+		}}
+	}
+	invalid := &schemaTool{funcTool: funcTool{name: "invalid_get"}, schema: func() any { return json.RawMessage(`{"type":`) }}
+	panicking := &schemaTool{funcTool: funcTool{name: "panicking_get"}, schema: func() any { panic("schema unavailable") }}
+
+	var shutdowns []string
+	srv := newTestServer(t, &config.Config{ServerName: "test"})
+	require.NoError(t, srv.AddProvider(&fakeProvider{name: "invalid", tools: []contracts.Tool{ok("invalid_list"), invalid}, shutdowns: &shutdowns}))
+	require.NoError(t, srv.AddProvider(&fakeProvider{name: "panicking", tools: []contracts.Tool{ok("panicking_list"), panicking}, shutdowns: &shutdowns}))
+	require.NoError(t, srv.AddProvider(&fakeProvider{name: "fine", tools: []contracts.Tool{ok("fine_list")}, shutdowns: &shutdowns}))
+	count := srv.GetToolCount()
+	client := startServer(t, srv)
+	require.Equal(t, count+1, srv.GetToolCount(), "only the working provider's tool is added")
+
+	for id, name := range []string{"invalid_list", "panicking_list"} {
+		client.callTool(id+1, name, nil, nil)
+		response, _ := client.response(id + 1)
+		require.Contains(t, response, "error", "%s is not left registered", name)
+	}
+
+	client.callTool(3, "providers_health", map[string]any{"format": "json-compact"}, nil)
+	response, _ := client.response(3)
+	var health []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(resultTexts(t, response)[0]), &health))
+	require.Equal(t, "failed", health[0]["state"])
+	require.Contains(t, health[0]["error"], "invalid_get")
+	require.Equal(t, "failed", health[1]["state"])
+	require.Contains(t, health[1]["error"], "panicked")
+	require.Equal(t, "ready", health[2]["state"])
+}
+
+func TestAccounts_SessionSwitchAndOverride(t *testing.T) {
+	accountsFile := filepath.Join(t.TempDir(), "accounts.yaml")
+	require.NoError(t, os.WriteFile(accountsFile, []byte(`accounts:
+  - {provider: cloud, alias: prod, region: us-east, default: true}
+  - {provider: cloud, alias: staging, region: eu-west}
+`), 0o600))
+
+	whoami := &funcTool{name: "cloud_whoami", fn: func(ctx context.Context, params map[string]any) (*mcp.CallToolResult, error) {
+		account, ok := contracts.AccountFromContext(ctx)
+		if !ok {
+			return mcp.NewToolResultError("no account selected"), nil
+		}
+		if _, leaked := params["account"]; leaked {
+			return mcp.NewToolResultError("account argument reached the handler"), nil
+		}
+		return mcp.NewToolResultText(account.Alias + "@" + account.Region), nil
+	}}
+	var shutdowns []string
+	srv := newTestServer(t, &config.Config{ServerName: "test", AccountsFile: accountsFile})
+	require.NoError(t, srv.AddProvider(&fakeProvider{name: "cloud", tools: []contracts.Tool{whoami}, shutdowns: &shutdowns}))
+	client := startServer(t, srv)
+
+	client.send(map[string]any{"jsonrpc": "2.0", "id": 1, "method": "tools/list"})
+	response, _ := client.response(1)
+	require.Contains(t, fmt.Sprint(response), "Account alias to use for this call", "provider tools advertise the account argument")
+
+	client.callTool(2, "cloud_whoami", nil, nil)
+	response, _ = client.response(2)
+	require.Equal(t, []string{"prod@us-east"}, resultTexts(t, response))
+
+	client.callTool(3, "switch_account", map[string]any{"provider": "cloud", "alias": "staging"}, nil)
+	response, _ = client.response(3)
+	require.Contains(t, resultTexts(t, response)[0], `"active": true`)
+
+	client.callTool(4, "cloud_whoami", nil, nil)
+	response, _ = client.response(4)
+	require.Equal(t, []string{"staging@eu-west"}, resultTexts(t, response))
+
+	client.callTool(5, "cloud_whoami", map[string]any{"account": "prod"}, nil)
+	response, _ = client.response(5)
+	require.Equal(t, []string{"prod@us-east"}, resultTexts(t, response), "the argument overrides the session account")
+
+	client.callTool(6, "cloud_whoami", map[string]any{"account": "qa"}, nil)
+	response, _ = client.response(6)
+	require.Contains(t, resultTexts(t, response)[0], "available: prod, staging")
+
+	client.callTool(7, "list_accounts", map[string]any{"format": "csv"}, nil)
+	response, _ = client.response(7)
+	require.Equal(t, []string{"provider,alias,region,default,active\ncloud,prod,us-east,true,false\ncloud,staging,eu-west,false,true"}, resultTexts(t, response))
+}
+
+func TestIdempotency_ScopedToSwitchedAccount(t *testing.T) {
+	accountsFile := filepath.Join(t.TempDir(), "accounts.yaml")
+	require.NoError(t, os.WriteFile(accountsFile, []byte(`accounts:
+  - {provider: cloud, alias: prod, default: true}
+  - {provider: cloud, alias: staging}
+`), 0o600))
+
+	runs := 0
+	create := &funcTool{name: "cloud_create", fn: func(ctx context.Context, _ map[string]any) (*mcp.CallToolResult, error) {
+		runs++
+		account, _ := contracts.AccountFromContext(ctx)
+		return mcp.NewToolResultText(fmt.Sprintf("created in %s", account.Alias)), nil
+	}}
+	var shutdowns []string
+	srv := newTestServer(t, &config.Config{ServerName: "test", AccountsFile: accountsFile})
+	require.NoError(t, srv.AddProvider(&fakeProvider{name: "cloud", tools: []contracts.Tool{create}, shutdowns: &shutdowns}))
+	client := startServer(t, srv)
+
+	args := map[string]any{"name": "web-1", "idempotency_key": "k1"}
+	client.callTool(1, "cloud_create", args, nil)
+	response, _ := client.response(1)
+	require.Equal(t, []string{"created in prod"}, resultTexts(t, response))
+
+	client.callTool(2, "switch_account", map[string]any{"provider": "cloud", "alias": "staging"}, nil)
+	client.response(2)
+
+	client.callTool(3, "cloud_create", args, nil)
+	response, _ = client.response(3)
+	require.Equal(t, []string{"created in staging"}, resultTexts(t, response), "the key does not return the previous account's result")
+	require.Equal(t, 2, runs)
+
+	client.callTool(4, "cloud_create", map[string]any{"name": "web-1", "idempotency_key": "k1", "account": "prod"}, nil)
+	response, _ = client.response(4)
+	require.Equal(t, []string{"created in prod"}, resultTexts(t, response))
+	require.Equal(t, 2, runs, "repeats on the first account still return its result")
+}
+
+// resizeTool is a provider tool that waits for a resize action, polled until
+// release is closed, and reports the account it ran against.
+func resizeTool(release <-chan struct{}) contracts.Tool {
+	definition := mcp.NewTool("cloud_server_resize",
+		mcp.WithString("size", mcp.Required()),
+		providerkit.WithWaitParam(),
+		format.WithParam(),
+	)
+
+	return providerkit.NewTool(definition, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		account, ok := contracts.AccountFromContext(ctx)
+		if !ok {
+			return mcp.NewToolResultError("no account selected"), nil
+		}
+		if _, leaked := request.GetArguments()["background"]; leaked {
+			return mcp.NewToolResultError("background argument reached the handler"), nil
+		}
+
+		polls := 0
+		_, err := providerkit.Wait(ctx, time.Millisecond, func(context.Context) (providerkit.ActionStatus, error) {
+			polls++
+			select {
+			case <-release:
+				return providerkit.ActionStatus{Done: true, Message: "resized"}, nil
+			default:
+				return providerkit.ActionStatus{Percent: min(float64(polls), 99), Message: "resizing"}, nil
+			}
+		})
+
+		return providerkit.Result(map[string]string{"account": account.Alias, "size": request.GetString("size", "")}, err)
+	})
+}
+
+func TestBackground_WaitRunsAsJobForCallAccount(t *testing.T) {
+	accountsFile := filepath.Join(t.TempDir(), "accounts.yaml")
+	require.NoError(t, os.WriteFile(accountsFile, []byte(`accounts:
+  - {provider: cloud, alias: prod, default: true}
+  - {provider: cloud, alias: staging}
+`), 0o600))
+
+	release := make(chan struct{})
+	var shutdowns []string
+	srv := newTestServer(t, &config.Config{ServerName: "test", AccountsFile: accountsFile})
+	require.NoError(t, srv.AddProvider(&fakeProvider{name: "cloud", tools: []contracts.Tool{resizeTool(release)}, shutdowns: &shutdowns}))
+	client := startServer(t, srv)
+
+	client.callTool(1, "cloud_server_resize", map[string]any{"size": "large", "background": true, "account": "staging"},
+		map[string]any{"progressToken": "resize"})
+	response, notifications := client.response(1)
+	text := resultTexts(t, response)[0]
+	require.Contains(t, text, "Started background job")
+	require.Empty(t, notifications, "progress goes to the job, not the returned call")
+	jobID := regexp.MustCompile(`job_[0-9a-f]+`).FindString(text)
+	require.NotEmpty(t, jobID)
+
+	status := func(id int) map[string]any {
+		client.callTool(id, "job_status", map[string]any{"job_id": jobID}, nil)
+		response, _ := client.response(id)
+		var job map[string]any
+		require.NoError(t, json.Unmarshal([]byte(resultTexts(t, response)[0]), &job))
+		return job
+	}
+	id := 2
+	require.Eventually(t, func() bool {
+		id++
+		job := status(id)
+		return job["state"] == "running" && job["message"] == "resizing"
+	}, 5*time.Second, 5*time.Millisecond, "the job keeps waiting after the call returned")
+
+	close(release)
+	require.Eventually(t, func() bool {
+		id++
+		return status(id)["state"] == "succeeded"
+	}, 5*time.Second, 5*time.Millisecond)
+
+	client.callTool(100, "job_result", map[string]any{"job_id": jobID}, nil)
+	response, _ = client.response(100)
+	require.JSONEq(t, `{"account":"staging","size":"large"}`, resultTexts(t, response)[0],
+		"the job runs against the call's account")
+
+	client.callTool(101, "cloud_server_resize", map[string]any{"size": "small", "background": false}, nil)
+	response, _ = client.response(101)
+	require.JSONEq(t, `{"account":"prod","size":"small"}`, resultTexts(t, response)[0], "without background the call waits inline")
+}
+
+func TestBackground_OnlyForToolsThatWait(t *testing.T) {
+	var received map[string]any
+	tool := &funcTool{name: "quick", fn: func(_ context.Context, params map[string]any) (*mcp.CallToolResult, error) {
+		received = params
+		return mcp.NewToolResultText("done"), nil
+	}}
+	srv := newTestServer(t, &config.Config{ServerName: "test"}, tool)
+	client := startServer(t, srv)
+
+	client.callTool(1, "quick", map[string]any{"background": true}, nil)
+	response, _ := client.response(1)
+	require.Equal(t, []string{"done"}, resultTexts(t, response))
+	require.Empty(t, received, "the argument is consumed by the server")
+}
+
+// discoveringProvider finds accounts in its own configuration.
+type discoveringProvider struct {
+	fakeProvider
+	discovered []contracts.Account
+}
+
+func (p *discoveringProvider) DiscoveredAccounts() []contracts.Account { return p.discovered }
+
+func TestAccounts_DiscoveredByProvider(t *testing.T) {
+	accountsFile := filepath.Join(t.TempDir(), "accounts.yaml")
+	require.NoError(t, os.WriteFile(accountsFile, []byte(`accounts:
+  - {provider: cluster, alias: prod, region: pinned}
+`), 0o600))
+
+	whoami := &funcTool{name: "cluster_whoami", fn: func(ctx context.Context, _ map[string]any) (*mcp.CallToolResult, error) {
+		account, _ := contracts.AccountFromContext(ctx)
+		return mcp.NewToolResultText(account.Alias + "@" + account.Region), nil
+	}}
+	var shutdowns []string
+	srv := newTestServer(t, &config.Config{ServerName: "test", AccountsFile: accountsFile})
+	require.NoError(t, srv.AddProvider(&discoveringProvider{
+		fakeProvider: fakeProvider{name: "cluster", tools: []contracts.Tool{whoami}, shutdowns: &shutdowns},
+		discovered:   []contracts.Account{{Alias: "prod"}, {Alias: "kind", Default: true}},
+	}))
+	client := startServer(t, srv)
+
+	client.callTool(1, "cluster_whoami", nil, nil)
+	response, _ := client.response(1)
+	require.Equal(t, []string{"prod@pinned"}, resultTexts(t, response), "the configured account stays the default")
+
+	client.callTool(2, "cluster_whoami", map[string]any{"account": "kind"}, nil)
+	response, _ = client.response(2)
+	require.Equal(t, []string{"kind@"}, resultTexts(t, response))
+}
+
+// leakyProvider resolves its token at startup and has a tool that echoes it.
+type leakyProvider struct {
+	fakeProvider
+	token contracts.Secret
+}
+
+func (p *leakyProvider) Initialize(ctx context.Context, cfg contracts.ProviderConfig) error {
+	token, err := cfg.Secrets.Resolve(ctx, "env:TEST_LEAKY_TOKEN")
+	p.token = token
+	return err
+}
+
+func (p *leakyProvider) Tools() []contracts.Tool {
+	echo := &funcTool{name: "leaky_echo", fn: func(context.Context, map[string]any) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("token is " + p.token.Reveal()), nil
+	}}
+	wait := providerkit.NewTool(mcp.NewTool("leaky_wait", providerkit.WithWaitParam()),
+		func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			contracts.ProgressFromContext(ctx).Report(1, 2, "using "+p.token.Reveal())
+			return providerkit.Result(map[string]any{"tokens": []string{"token is " + p.token.Reveal()}}, nil)
+		})
+
+	return []contracts.Tool{echo, wait}
+}
+
+func TestSecrets_RedactedFromToolOutput(t *testing.T) {
+	t.Setenv("TEST_LEAKY_TOKEN", "super-secret-token")
+
+	var shutdowns []string
+	srv := newTestServer(t, &config.Config{ServerName: "test"})
+	require.NoError(t, srv.AddProvider(&leakyProvider{fakeProvider: fakeProvider{name: "leaky", shutdowns: &shutdowns}}))
+	client := startServer(t, srv)
+
+	client.callTool(1, "leaky_echo", nil, nil)
+	response, _ := client.response(1)
+	require.Equal(t, []string{"token is [REDACTED]"}, resultTexts(t, response))
+
+	var logs strings.Builder
+	_, err := srv.LogWriter(&logs).Write([]byte("calling API with super-secret-token\n"))
+	require.NoError(t, err)
+	require.Equal(t, "calling API with [REDACTED]\n", logs.String())
+}
+
+func TestSecrets_RedactedFromPersistedJobs(t *testing.T) {
+	t.Setenv("TEST_LEAKY_TOKEN", "super-secret-token")
+
+	jobsFile := filepath.Join(t.TempDir(), "jobs.json")
+	var shutdowns []string
+	srv := newTestServer(t, &config.Config{ServerName: "test", JobsFile: jobsFile})
+	require.NoError(t, srv.AddProvider(&leakyProvider{fakeProvider: fakeProvider{name: "leaky", shutdowns: &shutdowns}}))
+	client := startServer(t, srv)
+
+	client.callTool(1, "leaky_wait", map[string]any{"background": true}, nil)
+	response, _ := client.response(1)
+	jobID := regexp.MustCompile(`job_[0-9a-f]+`).FindString(resultTexts(t, response)[0])
+	require.NotEmpty(t, jobID)
+
+	id := 1
+	require.Eventually(t, func() bool {
+		id++
+		client.callTool(id, "job_status", map[string]any{"job_id": jobID}, nil)
+		response, _ := client.response(id)
+		return strings.Contains(resultTexts(t, response)[0], `"succeeded"`)
+	}, 5*time.Second, 5*time.Millisecond)
+
+	persisted, err := os.ReadFile(jobsFile)
+	require.NoError(t, err)
+	require.NotContains(t, string(persisted), "super-secret-token")
+
+	var jobs []struct {
+		Message string          `json:"message"`
+		Result  json.RawMessage `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(persisted, &jobs))
+	require.Len(t, jobs, 1)
+	require.Equal(t, "using [REDACTED]", jobs[0].Message)
+	require.JSONEq(t, `{"tokens":["token is [REDACTED]"]}`, string(jobs[0].Result))
+}
+
+// resultTexts returns the text content items of a tools/call response.
+func resultTexts(t *testing.T, response map[string]any) []string {
+	t.Helper()
+
+	result, ok := response["result"].(map[string]any)
+	require.True(t, ok, "expected result in %v", response)
+	content, ok := result["content"].([]any)
+	require.True(t, ok)
+
+	var texts []string
+	for _, item := range content {
+		entry, ok := item.(map[string]any)
+		require.True(t, ok)
+		if entry["type"] == "text" {
+			text, _ := entry["text"].(string)
+			texts = append(texts, text)
+		}
+	}
+
+	return texts
+}
@@ -0,0 +1,274 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// stdioSessionID identifies the single client connected over stdio.
+const stdioSessionID = "stdio"
+
+// stdioSession is the client session for the stdio transport. Stdio only ever
+// has one client, so one session lives for the duration of Serve.
+type stdioSession struct {
+	notifications chan mcp.JSONRPCNotification
+	initialized   atomic.Bool
+	loggingLevel  atomic.Value
+	clientInfo    atomic.Value
+}
+
+var (
+	_ server.ClientSession         = (*stdioSession)(nil)
+	_ server.SessionWithLogging    = (*stdioSession)(nil)
+	_ server.SessionWithClientInfo = (*stdioSession)(nil)
+)
+
+func newStdioSession() *stdioSession {
+	return &stdioSession{notifications: make(chan mcp.JSONRPCNotification, 100)}
+}
+
+func (s *stdioSession) SessionID() string {
+	return stdioSessionID
+}
+
+func (s *stdioSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+func (s *stdioSession) Initialize() {
+	s.loggingLevel.Store(mcp.LoggingLevelError)
+	s.initialized.Store(true)
+}
+
+func (s *stdioSession) Initialized() bool {
+	return s.initialized.Load()
+}
+
+func (s *stdioSession) SetLogLevel(level mcp.LoggingLevel) {
+	s.loggingLevel.Store(level)
+}
+
+func (s *stdioSession) GetLogLevel() mcp.LoggingLevel {
+	if level, ok := s.loggingLevel.Load().(mcp.LoggingLevel); ok {
+		return level
+	}
+	return mcp.LoggingLevelError
+}
+
+func (s *stdioSession) GetClientInfo() mcp.Implementation {
+	if info, ok := s.clientInfo.Load().(mcp.Implementation); ok {
+		return info
+	}
+	return mcp.Implementation{}
+}
+
+func (s *stdioSession) SetClientInfo(clientInfo mcp.Implementation) {
+	s.clientInfo.Store(clientInfo)
+}
+
+// stdioTransport speaks newline-delimited JSON-RPC over a reader and writer.
+// Unlike the mcp-go stdio server it dispatches tool calls concurrently, so the
+// read loop stays free to receive notifications/cancelled for in-flight calls.
+type stdioTransport struct {
+	server  *Server
+	session *stdioSession
+	out     io.Writer
+	writeMu sync.Mutex
+	calls   sync.WaitGroup
+}
+
+// envelope holds the JSON-RPC fields needed to route an incoming message.
+type envelope struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params struct {
+		Name string `json:"name"`
+	} `json:"params"`
+}
+
+// readResult is one line (or terminal error) read from the input.
+type readResult struct {
+	line []byte
+	err  error
+}
+
+func newStdioTransport(s *Server, out io.Writer) *stdioTransport {
+	return &stdioTransport{server: s, session: newStdioSession(), out: out}
+}
+
+// run serves messages until the input is exhausted or ctx is cancelled. On
+// end of input it waits for in-flight tool calls so their responses are still
+// written; on cancellation those calls are cancelled first.
+func (t *stdioTransport) run(ctx context.Context, in io.Reader) error {
+	if err := t.server.mcp.RegisterSession(ctx, t.session); err != nil {
+		return fmt.Errorf("register session: %w", err)
+	}
+	defer t.server.mcp.UnregisterSession(ctx, t.session.SessionID())
+	defer t.server.accounts.Forget(t.session.SessionID())
+
+	ctx = t.server.mcp.WithContext(ctx, t.session)
+	callCtx, cancelCalls := context.WithCancel(ctx)
+	defer cancelCalls()
+
+	stopNotifications := make(chan struct{})
+	notificationsDone := make(chan struct{})
+	go func() {
+		defer close(notificationsDone)
+		t.forwardNotifications(stopNotifications)
+	}()
+
+	lines := make(chan readResult)
+	go t.readLines(ctx, in, lines)
+
+	var runErr error
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			cancelCalls()
+			break loop
+		case result := <-lines:
+			if result.line != nil {
+				t.dispatch(ctx, callCtx, result.line)
+			}
+			if result.err != nil {
+				if !errors.Is(result.err, io.EOF) {
+					runErr = fmt.Errorf("read input: %w", result.err)
+					cancelCalls()
+				}
+				break loop
+			}
+		}
+	}
+
+	t.calls.Wait()
+	close(stopNotifications)
+	<-notificationsDone
+
+	return runErr
+}
+
+// readLines feeds non-empty input lines to the read loop.
+func (t *stdioTransport) readLines(ctx context.Context, in io.Reader, lines chan<- readResult) {
+	reader := bufio.NewReader(in)
+	for {
+		line, err := reader.ReadBytes('\n')
+		line = bytes.TrimSpace(line)
+
+		result := readResult{err: err}
+		if len(line) > 0 {
+			result.line = line
+		}
+		if result.line == nil && result.err == nil {
+			continue
+		}
+
+		select {
+		case lines <- result:
+		case <-ctx.Done():
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// dispatch routes one message. Tool calls run on their own goroutine with a
+// cancellable context; everything else is handled inline to preserve ordering.
+func (t *stdioTransport) dispatch(ctx, callCtx context.Context, line []byte) {
+	var env envelope
+	if err := json.Unmarshal(line, &env); err != nil {
+		t.write(mcp.NewJSONRPCError(mcp.NewRequestId(nil), mcp.PARSE_ERROR, "Parse error", nil))
+		return
+	}
+
+	if env.Method != string(mcp.MethodToolsCall) || !hasRequestID(env.ID) {
+		t.handle(ctx, line)
+		return
+	}
+
+	key := requestKey(env.ID)
+	toolCtx, cancel := context.WithCancel(callCtx)
+	if !t.server.calls.add(key, env.Params.Name, cancel) {
+		cancel()
+		t.write(mcp.NewJSONRPCError(mcp.NewRequestId(nil), mcp.INVALID_REQUEST, "Duplicate request id "+key, nil))
+		return
+	}
+
+	t.calls.Add(1)
+	go func() {
+		defer t.calls.Done()
+		defer cancel()
+		defer t.server.calls.remove(key)
+
+		response := t.server.mcp.HandleMessage(toolCtx, json.RawMessage(line))
+
+		// The client has abandoned a cancelled request, so no response is sent.
+		if t.server.calls.cancelledByClient(key) {
+			return
+		}
+		if response != nil {
+			t.write(response)
+		}
+	}()
+}
+
+// handle processes a non tool-call message synchronously.
+func (t *stdioTransport) handle(ctx context.Context, line []byte) {
+	if response := t.server.mcp.HandleMessage(ctx, json.RawMessage(line)); response != nil {
+		t.write(response)
+	}
+}
+
+// forwardNotifications writes queued session notifications until stop is
+// closed, then flushes whatever is still buffered.
+func (t *stdioTransport) forwardNotifications(stop <-chan struct{}) {
+	for {
+		select {
+		case notification := <-t.session.notifications:
+			t.write(notification)
+		case <-stop:
+			for {
+				select {
+				case notification := <-t.session.notifications:
+					t.write(notification)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// write serializes a message as a single line. Writes from concurrent tool
+// calls and notifications are serialized so lines never interleave.
+func (t *stdioTransport) write(message mcp.JSONRPCMessage) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		log.Printf("Failed to marshal message: %v", err)
+		return
+	}
+
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	if _, err := t.out.Write(append(data, '\n')); err != nil {
+		log.Printf("Failed to write message: %v", err)
+	}
+}
+
+// hasRequestID reports whether a raw JSON-RPC id identifies a request.
+func hasRequestID(id json.RawMessage) bool {
+	return len(id) > 0 && !bytes.Equal(id, []byte("null"))
+}
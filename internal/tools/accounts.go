@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/chadit/CloudMCP/internal/accounts"
+	"github.com/chadit/CloudMCP/internal/format"
+)
+
+// accountSummary is an account as listed by list_accounts.
+type accountSummary struct {
+	Provider   string `json:"provider"`
+	Alias      string `json:"alias"`
+	Region     string `json:"region,omitempty"`
+	Credential string `json:"credential,omitempty"`
+	Default    bool   `json:"default"`
+	Active     bool   `json:"active"`
+}
+
+// NewListAccountsTool creates a tool listing configured accounts and which one
+// is active for each provider in the caller's session.
+func NewListAccountsTool(registry *accounts.Registry) (mcp.Tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)) {
+	tool := mcp.NewTool("list_accounts",
+		mcp.WithDescription("Lists configured cloud accounts with their default region and marks the account active for each provider in this session"),
+		mcp.WithString("provider",
+			mcp.Description("Only list accounts of this provider (optional)"),
+		),
+		mcp.WithReadOnlyHintAnnotation(true),
+		format.WithParam(),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		session := sessionID(ctx)
+		list := registry.List(request.GetString("provider", ""))
+
+		summaries := make([]accountSummary, 0, len(list))
+		for _, account := range list {
+			active, _ := registry.Active(session, account.Provider)
+			summaries = append(summaries, accountSummary{
+				Provider:   account.Provider,
+				Alias:      account.Alias,
+				Region:     account.Region,
+				Credential: account.Credential,
+				Default:    account.Default,
+				Active:     active.Alias == account.Alias,
+			})
+		}
+
+		return format.NewResult(summaries)
+	}
+
+	return tool, handler
+}
+
+// NewSwitchAccountTool creates a tool setting the session's active account for
+// a provider. Provider tools use it unless a call passes its own account.
+func NewSwitchAccountTool(registry *accounts.Registry) (mcp.Tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)) {
+	tool := mcp.NewTool("switch_account",
+		mcp.WithDescription("Sets the account this session uses for a provider's tools; a tool call can still override it with its account argument"),
+		mcp.WithString("provider",
+			mcp.Required(),
+			mcp.Description("Provider name, as shown by list_accounts"),
+		),
+		mcp.WithString("alias",
+			mcp.Required(),
+			mcp.Description("Account alias to make active"),
+		),
+		format.WithParam(),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		provider, err := request.RequireString("provider")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		alias, err := request.RequireString("alias")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		account, err := registry.Switch(sessionID(ctx), provider, alias)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return format.NewResult(accountSummary{
+			Provider:   account.Provider,
+			Alias:      account.Alias,
+			Region:     account.Region,
+			Credential: account.Credential,
+			Default:    account.Default,
+			Active:     true,
+		})
+	}
+
+	return tool, handler
+}
+
+// sessionID returns the ID of the client session making the call.
+func sessionID(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+
+	return ""
+}
@@ -0,0 +1,26 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/httpclient"
+)
+
+// NewHTTPDiagnosticsTool creates a tool reporting the circuit breaker state of
+// every provider API host the server has talked to.
+func NewHTTPDiagnosticsTool(breakers *httpclient.Breakers) (mcp.Tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)) {
+	tool := mcp.NewTool("http_diagnostics",
+		mcp.WithDescription("Shows circuit breaker state, request, failure and retry counts for each provider API host"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		format.WithParam(),
+	)
+
+	handler := func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return format.NewResult(breakers.Snapshot())
+	}
+
+	return tool, handler
+}
@@ -0,0 +1,183 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+	"github.com/chadit/CloudMCP/internal/jobs"
+	"github.com/chadit/CloudMCP/internal/pagination"
+)
+
+// jobSummary is the list view of a job; results are fetched with job_result.
+//
+//nolint:tagliatelle // JSON field names maintain API compatibility with snake_case.
+type jobSummary struct {
+	ID       string     `json:"id"`
+	Kind     string     `json:"kind"`
+	State    jobs.State `json:"state"`
+	Progress float64    `json:"progress,omitempty"`
+	Total    float64    `json:"total,omitempty"`
+	Message  string     `json:"message,omitempty"`
+	Error    string     `json:"error,omitempty"`
+	Created  string     `json:"created_at"`
+}
+
+// NewJobsListTool creates a tool that lists background jobs.
+func NewJobsListTool(manager *jobs.Manager) (mcp.Tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)) {
+	tool := mcp.NewTool("jobs_list",
+		mcp.WithDescription("Lists background jobs, newest first"),
+		mcp.WithString("state",
+			mcp.Description("Only return jobs in this state (optional)"),
+			mcp.Enum(string(jobs.StatePending), string(jobs.StateRunning), string(jobs.StateSucceeded),
+				string(jobs.StateFailed), string(jobs.StateCancelled)),
+		),
+		pagination.WithParams(),
+		format.WithParam(),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		cursors := pagination.FromContext(ctx)
+		if cursors == nil {
+			return mcp.NewToolResultError(pagination.ErrNoCodec.Error()), nil
+		}
+
+		page, err := cursors.Parse("jobs_list", request.GetArguments())
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		// The cursor holds the position of the last job returned rather than
+		// an offset, so jobs started or pruned between calls do not shift pages.
+		listed, err := manager.ListAfter(page.State.Token)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		state := jobs.State(request.GetString("state", ""))
+		result := pagination.Page[jobSummary]{Items: make([]jobSummary, 0)}
+		var last jobs.Job
+		for _, job := range listed {
+			if state != "" && job.State != state {
+				continue
+			}
+			if len(result.Items) == page.Limit {
+				result.NextCursor = cursors.Next(page, pagination.State{Token: last.Position()})
+				break
+			}
+			result.Items = append(result.Items, summarizeJob(job))
+			last = job
+		}
+
+		return format.NewResult(result)
+	}
+
+	return tool, handler
+}
+
+// NewJobStatusTool creates a tool that reports the state and progress of a job.
+func NewJobStatusTool(manager *jobs.Manager) (mcp.Tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)) {
+	tool := mcp.NewTool("job_status",
+		mcp.WithDescription("Returns the state and progress of a background job"),
+		mcp.WithString("job_id",
+			mcp.Required(),
+			mcp.Description("ID of the job returned when it was started"),
+		),
+		format.WithParam(),
+	)
+
+	handler := func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id, err := request.RequireString("job_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		job, err := manager.Get(id)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return format.NewResult(summarizeJob(job))
+	}
+
+	return tool, handler
+}
+
+// NewJobResultTool creates a tool that returns the result of a finished job.
+func NewJobResultTool(manager *jobs.Manager) (mcp.Tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)) {
+	tool := mcp.NewTool("job_result",
+		mcp.WithDescription("Returns the result of a finished background job"),
+		mcp.WithString("job_id",
+			mcp.Required(),
+			mcp.Description("ID of the job returned when it was started"),
+		),
+	)
+
+	handler := func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id, err := request.RequireString("job_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		job, err := manager.Get(id)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		switch job.State {
+		case jobs.StateSucceeded:
+			return mcp.NewToolResultText(string(job.Result)), nil
+		case jobs.StateFailed, jobs.StateCancelled:
+			return mcp.NewToolResultError(fmt.Sprintf("job %s %s: %s", job.ID, job.State, job.Error)), nil
+		default:
+			return mcp.NewToolResultError(fmt.Sprintf("%v: %s is %s", jobs.ErrJobNotFinished, job.ID, job.State)), nil
+		}
+	}
+
+	return tool, handler
+}
+
+// NewJobCancelTool creates a tool that cancels a pending or running job.
+func NewJobCancelTool(manager *jobs.Manager) (mcp.Tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)) {
+	tool := mcp.NewTool("job_cancel",
+		mcp.WithDescription("Cancels a pending or running background job"),
+		mcp.WithString("job_id",
+			mcp.Required(),
+			mcp.Description("ID of the job to cancel"),
+		),
+		format.WithParam(),
+	)
+
+	handler := func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id, err := request.RequireString("job_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		job, err := manager.Cancel(id)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return format.NewResult(summarizeJob(job))
+	}
+
+	return tool, handler
+}
+
+// summarizeJob converts a job snapshot into its list view.
+func summarizeJob(job jobs.Job) jobSummary {
+	return jobSummary{
+		ID:       job.ID,
+		Kind:     job.Kind,
+		State:    job.State,
+		Progress: job.Progress,
+		Total:    job.Total,
+		Message:  job.Message,
+		Error:    job.Error,
+		Created:  job.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,34 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/chadit/CloudMCP/internal/format"
+)
+
+// ProviderHealth is the health of one provider as reported by providers_health.
+type ProviderHealth struct {
+	Name    string `json:"name"`
+	State   string `json:"state"`
+	Healthy bool   `json:"healthy"`
+	Tools   int    `json:"tools"`
+	Error   string `json:"error,omitempty"`
+}
+
+// NewProvidersHealthTool creates a tool reporting the lifecycle state and a
+// live health check of every configured provider.
+func NewProvidersHealthTool(check func(ctx context.Context) []ProviderHealth) (mcp.Tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)) {
+	tool := mcp.NewTool("providers_health",
+		mcp.WithDescription("Checks each configured cloud provider and reports whether it initialized, how many tools it registered and whether it is currently healthy"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		format.WithParam(),
+	)
+
+	handler := func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return format.NewResult(check(ctx))
+	}
+
+	return tool, handler
+}
@@ -2,10 +2,10 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 
 	"github.com/mark3labs/mcp-go/mcp"
 
+	"github.com/chadit/CloudMCP/internal/format"
 	"github.com/chadit/CloudMCP/internal/version"
 )
 
@@ -13,19 +13,20 @@ import (
 func NewVersionTool() (mcp.Tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)) {
 	tool := mcp.NewTool("version",
 		mcp.WithDescription("Returns CloudMCP server version and build information"),
+		format.WithParam(),
 	)
 
 	handler := func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		versionInfo := version.Get()
 
-		// Return as formatted JSON for readability
-		jsonResponse, err := json.MarshalIndent(versionInfo, "", "  ")
+		// Return structured data; the server renders the requested format
+		result, err := format.NewResult(versionInfo)
 		if err != nil {
 			// Fallback to simple string format
 			return mcp.NewToolResultText(versionInfo.String()), nil
 		}
 
-		return mcp.NewToolResultText(string(jsonResponse)), nil
+		return result, nil
 	}
 
 	return tool, handler
@@ -0,0 +1,56 @@
+package contracts
+
+import (
+	"context"
+)
+
+// AccountParam is the optional argument every provider tool accepts to pick
+// the account a call runs against.
+const AccountParam = "account"
+
+// Account is a configured cloud account a provider tool can act on.
+type Account struct {
+	// Provider is the name of the provider the account belongs to.
+	Provider string `json:"provider" yaml:"provider"`
+
+	// Alias names the account within its provider, such as "prod" or "staging".
+	Alias string `json:"alias" yaml:"alias"`
+
+	// Credential is a reference to the account's secret, never the secret itself.
+	Credential string `json:"credential,omitempty" yaml:"credential,omitempty"`
+
+	// Region is the default region for calls that do not name one.
+	Region string `json:"region,omitempty" yaml:"region,omitempty"`
+
+	// Default marks the account used when a session has not switched accounts.
+	Default bool `json:"default,omitempty" yaml:"default,omitempty"`
+
+	// Settings holds provider-specific options of the account, such as the
+	// kubeconfig context of a Kubernetes account.
+	Settings map[string]string `json:"settings,omitempty" yaml:"settings,omitempty"`
+}
+
+// Setting returns the named account setting, or fallback if it is unset or empty.
+func (a Account) Setting(name, fallback string) string {
+	if value := a.Settings[name]; value != "" {
+		return value
+	}
+
+	return fallback
+}
+
+// accountKey is the context key for the account of the current call.
+type accountKey struct{}
+
+// WithAccount returns a copy of ctx carrying the account of the current call.
+func WithAccount(ctx context.Context, account Account) context.Context {
+	return context.WithValue(ctx, accountKey{}, account)
+}
+
+// AccountFromContext returns the account selected for the current call. It
+// reports false when no account is configured for the tool's provider, in
+// which case the provider falls back to its own settings.
+func AccountFromContext(ctx context.Context) (Account, bool) {
+	account, ok := ctx.Value(accountKey{}).(Account)
+	return account, ok
+}
@@ -0,0 +1,37 @@
+package contracts
+
+import (
+	"context"
+)
+
+// ProgressReporter reports incremental progress for a long-running tool call.
+// Implementations must be safe for concurrent use and must never block the caller.
+type ProgressReporter interface {
+	// Report publishes the current progress. Total may be zero when unknown,
+	// and message is an optional human-readable status line.
+	Report(progress, total float64, message string)
+}
+
+// progressKey is the context key for the active ProgressReporter.
+type progressKey struct{}
+
+// noopProgress discards all progress updates.
+type noopProgress struct{}
+
+func (noopProgress) Report(_, _ float64, _ string) {}
+
+// WithProgressReporter returns a copy of ctx carrying the given reporter.
+func WithProgressReporter(ctx context.Context, reporter ProgressReporter) context.Context {
+	return context.WithValue(ctx, progressKey{}, reporter)
+}
+
+// ProgressFromContext returns the reporter attached to ctx. When the caller did
+// not request progress updates a no-op reporter is returned, so tools can always
+// report unconditionally.
+func ProgressFromContext(ctx context.Context) ProgressReporter {
+	if reporter, ok := ctx.Value(progressKey{}).(ProgressReporter); ok && reporter != nil {
+		return reporter
+	}
+
+	return noopProgress{}
+}
@@ -1,10 +1,12 @@
 // Package contracts defines core interfaces for CloudMCP tool integration.
-// This package provides the Tool interface contract that all MCP tools must implement,
-// enabling a pluggable architecture for cloud provider extensions.
+// This package provides the Tool interface contract that all MCP tools must implement
+// and the Provider interface that groups a cloud provider's tools under a managed
+// lifecycle, enabling a pluggable architecture for cloud provider extensions.
 package contracts
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -23,3 +25,62 @@ type Tool interface {
 	// Execute handles the actual tool execution with the provided parameters.
 	Execute(ctx context.Context, params map[string]any) (*mcp.CallToolResult, error)
 }
+
+// ProviderConfig is passed to a provider when it is initialized.
+type ProviderConfig struct {
+	// Settings holds the provider's CLOUD_MCP_<NAME>_* environment variables,
+	// keyed by the lower-case suffix: CLOUD_MCP_LINODE_API_URL becomes "api_url".
+	Settings map[string]string
+
+	// HTTPClient is the shared client providers use for API calls. It retries
+	// throttled and failed requests and trips a circuit breaker per host.
+	HTTPClient *http.Client
+
+	// Secrets resolves credential references. Resolved values are redacted
+	// from tool output and logs.
+	Secrets SecretResolver
+
+	// Accounts are the configured accounts of this provider.
+	Accounts []Account
+}
+
+// Setting returns the named setting, or fallback if it is unset or empty.
+func (c ProviderConfig) Setting(name, fallback string) string {
+	if value := c.Settings[name]; value != "" {
+		return value
+	}
+
+	return fallback
+}
+
+// Provider is a cloud provider integration contributing a set of tools. The
+// server initializes providers at startup, registers their tools, reports
+// their health and shuts them down in reverse order when it stops.
+type Provider interface {
+	// Name returns the provider name, used in settings and logs.
+	Name() string
+
+	// Initialize prepares the provider, for example by validating credentials.
+	// A provider that fails to initialize contributes no tools; other providers
+	// are unaffected.
+	Initialize(ctx context.Context, cfg ProviderConfig) error
+
+	// Tools returns the provider's tools. It is called once after a successful
+	// Initialize.
+	Tools() []Tool
+
+	// HealthCheck reports whether the provider can currently serve requests.
+	HealthCheck(ctx context.Context) error
+
+	// Shutdown releases the provider's resources.
+	Shutdown(ctx context.Context) error
+}
+
+// AccountDiscoverer is implemented by providers that find accounts in their
+// own configuration, such as the contexts of a kubeconfig. After the provider
+// initializes, the server adds the accounts it discovered to the configured
+// ones, which take precedence for the same alias.
+type AccountDiscoverer interface {
+	// DiscoveredAccounts returns the accounts found, at most one marked default.
+	DiscoveredAccounts() []Account
+}
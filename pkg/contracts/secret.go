@@ -0,0 +1,52 @@
+package contracts
+
+import (
+	"context"
+)
+
+// redacted replaces secret values wherever they are formatted.
+const redacted = "[REDACTED]"
+
+// Secret is a sensitive value such as an API token. Formatting or encoding a
+// Secret prints [REDACTED]; Reveal returns the value for use in API calls.
+type Secret string
+
+// String implements fmt.Stringer without exposing the value.
+func (Secret) String() string { return redacted }
+
+// GoString implements fmt.GoStringer without exposing the value.
+func (Secret) GoString() string { return redacted }
+
+// MarshalJSON encodes the secret as [REDACTED].
+func (Secret) MarshalJSON() ([]byte, error) { return []byte(`"` + redacted + `"`), nil }
+
+// MarshalText encodes the secret as [REDACTED].
+func (Secret) MarshalText() ([]byte, error) { return []byte(redacted), nil }
+
+// Reveal returns the secret value.
+func (s Secret) Reveal() string { return string(s) }
+
+// SecretResolver resolves credential references, such as an account's
+// credential, to secret values.
+type SecretResolver interface {
+	// Resolve returns the secret a reference names. References are the name of
+	// an entry in the encrypted credential store or env:NAME for an
+	// environment variable.
+	Resolve(ctx context.Context, ref string) (Secret, error)
+}
+
+// SecretRegistry is implemented by resolvers that redact what they resolve.
+// Providers that read secrets from elsewhere, such as a kubeconfig or a
+// shared credentials file, register them so they are redacted as well.
+type SecretRegistry interface {
+	// Register adds a secret to redact from tool output.
+	Register(secret Secret)
+}
+
+// RegisterSecret registers secret with resolver if it is a SecretRegistry.
+// Empty secrets are skipped.
+func RegisterSecret(resolver SecretResolver, secret Secret) {
+	if registry, ok := resolver.(SecretRegistry); ok && secret != "" {
+		registry.Register(secret)
+	}
+}